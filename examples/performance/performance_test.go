@@ -6,6 +6,7 @@ import (
 	"deepneat/neat/network"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"math/rand"
 	"testing"
 )
 
@@ -153,3 +154,123 @@ func logNetworkActivationPath(net *network.Network, t *testing.T) string {
 	require.NoError(t, err)
 	return buf.String()
 }
+
+// the number of samples in the batch used by solvers benchmarks
+const samplesCount = 1000
+
+// buildSamples is to create the batch of random inputs for the network
+func buildSamples(net *network.Network, count int) [][]float64 {
+	inputsCount := 0
+	for _, node := range net.BaseNodes() {
+		if node.NeuronType == network.InputNeuron {
+			inputsCount++
+		}
+	}
+	rng := rand.New(rand.NewSource(42))
+	samples := make([][]float64, count)
+	for i := range samples {
+		samples[i] = make([]float64, inputsCount)
+		for j := range samples[i] {
+			samples[i][j] = rng.Float64()*2.0 - 1.0
+		}
+	}
+	return samples
+}
+
+// evaluateSamples is to evaluate each sample by the solver with given number of forward steps
+func evaluateSamples(solver network.Solver, samples [][]float64, steps int) ([][]float64, error) {
+	outputs := make([][]float64, len(samples))
+	for i, sample := range samples {
+		if _, err := solver.Flush(); err != nil {
+			return nil, err
+		}
+		if err := solver.LoadSensors(sample); err != nil {
+			return nil, err
+		}
+		if _, err := solver.ForwardSteps(steps); err != nil {
+			return nil, err
+		}
+		outputs[i] = solver.ReadOutputs()
+	}
+	return outputs, nil
+}
+
+// evaluateBatch is to evaluate all samples at once by the batch solver with given number of forward steps
+func evaluateBatch(solver network.BatchSolver, samples [][]float64, steps int) ([][]float64, error) {
+	if _, err := solver.Flush(); err != nil {
+		return nil, err
+	}
+	if err := solver.LoadSensorsBatch(samples); err != nil {
+		return nil, err
+	}
+	if _, err := solver.ForwardSteps(steps); err != nil {
+		return nil, err
+	}
+	return solver.ReadOutputsBatch(), nil
+}
+
+func TestBatchNetworkSolver_FromGenome(t *testing.T) {
+	genomes := map[string]string{"recurrent": genomeStr, "feed-forward": genomeStrSimple}
+	for name, str := range genomes {
+		net, err := buildNetworkFromGenome(str)
+		require.NoError(t, err, name)
+		samples := buildSamples(net, 100)
+
+		fast, err := net.FastNetworkSolver()
+		require.NoError(t, err, name)
+		expected, err := evaluateSamples(fast, samples, 10)
+		require.NoError(t, err, name)
+
+		batch, err := net.BatchNetworkSolver()
+		require.NoError(t, err, name)
+		outputs, err := evaluateBatch(batch, samples, 10)
+		require.NoError(t, err, name)
+		for i := range expected {
+			assert.InDeltaSlice(t, expected[i], outputs[i], 1e-9, "%s: outputs mismatch at sample: %d", name, i)
+		}
+	}
+}
+
+func benchmarkSolver(b *testing.B, str string, batched bool) {
+	net, err := buildNetworkFromGenome(str)
+	require.NoError(b, err)
+	samples := buildSamples(net, samplesCount)
+	depth, err := net.MaxActivationDepth()
+	require.NoError(b, err)
+
+	var solver network.Solver
+	if batched {
+		solver, err = net.BatchNetworkSolver()
+	} else {
+		solver, err = net.FastNetworkSolver()
+	}
+	require.NoError(b, err)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if batched {
+			_, err = evaluateBatch(solver.(network.BatchSolver), samples, depth)
+		} else {
+			_, err = evaluateSamples(solver, samples, depth)
+		}
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFastNetworkSolver_FeedForward(b *testing.B) {
+	benchmarkSolver(b, genomeStrSimple, false)
+}
+
+func BenchmarkBatchNetworkSolver_FeedForward(b *testing.B) {
+	benchmarkSolver(b, genomeStrSimple, true)
+}
+
+func BenchmarkFastNetworkSolver_Recurrent(b *testing.B) {
+	benchmarkSolver(b, genomeStr, false)
+}
+
+func BenchmarkBatchNetworkSolver_Recurrent(b *testing.B) {
+	benchmarkSolver(b, genomeStr, true)
+}
@@ -36,8 +36,11 @@ func (e *cartPoleGenerationEvaluator) GenerationEvaluate(ctx context.Context, po
 		return neat.ErrNEATOptionsNotFound
 	}
 	// Evaluate each organism on a test
+	evaluate := func(_ context.Context, organism *genetics.Organism) (bool, error) {
+		return OrganismEvaluate(organism, options, e.WinBalancingSteps, e.RandomStart)
+	}
 	for _, org := range pop.Organisms {
-		res, err := OrganismEvaluate(org, e.WinBalancingSteps, e.RandomStart)
+		res, err := experiment.EvaluateOrganism(ctx, org, options, evaluate)
 		if err != nil {
 			return err
 		}
@@ -7,26 +7,21 @@ import (
 	"deepneat/neat"
 	"deepneat/neat/genetics"
 	"fmt"
-	"sync"
+	"math/rand"
 )
 
 type cartPoleParallelGenerationEvaluator struct {
 	cartPoleGenerationEvaluator
-}
 
-type parallelEvaluationResult struct {
-	genomeId int
-	fitness  float64
-	error    float64
-	winner   bool
-	err      error
+	// The function to evaluate each organism, if not set organisms are evaluated locally
+	evaluate experiment.OrganismEvaluateFunc
 }
 
 // NewCartPoleParallelGenerationEvaluator is to create generations evaluator for single-pole balancing experiment.
 // This experiment performs evolution on single pole balancing task in order to produce appropriate genome.
 func NewCartPoleParallelGenerationEvaluator(outDir string, randomStart bool, winBalanceSteps int) experiment.GenerationEvaluator {
 	return &cartPoleParallelGenerationEvaluator{
-		cartPoleGenerationEvaluator{
+		cartPoleGenerationEvaluator: cartPoleGenerationEvaluator{
 			OutputPath:        outDir,
 			RandomStart:       randomStart,
 			WinBalancingSteps: winBalanceSteps,
@@ -34,6 +29,18 @@ func NewCartPoleParallelGenerationEvaluator(outDir string, randomStart bool, win
 	}
 }
 
+// NewCartPoleDistributedGenerationEvaluator is to create generations evaluator for single-pole balancing experiment,
+// which evaluates organisms concurrently by provided function, e.g., dispatching them to the remote workers with
+// distributed.Coordinator. The results are stored into output directory as by the local evaluator.
+func NewCartPoleDistributedGenerationEvaluator(outDir string, evaluate experiment.OrganismEvaluateFunc) experiment.GenerationEvaluator {
+	return &cartPoleParallelGenerationEvaluator{
+		cartPoleGenerationEvaluator: cartPoleGenerationEvaluator{
+			OutputPath: outDir,
+		},
+		evaluate: evaluate,
+	}
+}
+
 // GenerationEvaluate evaluates one epoch for given population and prints results into output directory if any.
 func (e *cartPoleParallelGenerationEvaluator) GenerationEvaluate(ctx context.Context, pop *genetics.Population, epoch *experiment.Generation) error {
 	options, ok := neat.FromContext(ctx)
@@ -41,75 +48,22 @@ func (e *cartPoleParallelGenerationEvaluator) GenerationEvaluate(ctx context.Con
 		return neat.ErrNEATOptionsNotFound
 	}
 
-	organismMapping := make(map[int]*genetics.Organism)
-
-	popSize := len(pop.Organisms)
-	resChan := make(chan parallelEvaluationResult, popSize)
-	// The wait group to wait for all GO routines
-	var wg sync.WaitGroup
-
-	// Evaluate each organism in generation
-	for _, org := range pop.Organisms {
-		if _, ok = organismMapping[org.Genotype.Id]; ok {
-			return fmt.Errorf("organism with %d already exists in mapping", org.Genotype.Id)
+	// Evaluate all organisms concurrently
+	evaluate := e.evaluate
+	if evaluate == nil {
+		// the random numbers generator of options can not be shared by concurrent evaluations, thus each organism
+		// gets its own one seeded from the random stream of the generation
+		seed := options.Rand().Int63()
+		evaluate = func(_ context.Context, organism *genetics.Organism) (bool, error) {
+			orgOptions := options.WithRandSource(rand.New(rand.NewSource(seed + int64(organism.Genotype.Id))))
+			return OrganismEvaluate(organism, orgOptions, e.WinBalancingSteps, e.RandomStart)
 		}
-		organismMapping[org.Genotype.Id] = org
-		wg.Add(1)
-
-		// run in separate GO thread
-		go func(organism *genetics.Organism, resChan chan<- parallelEvaluationResult, wg *sync.WaitGroup) {
-			defer wg.Done()
-
-			// create simulator and evaluate
-			winner, err := OrganismEvaluate(organism, e.WinBalancingSteps, e.RandomStart)
-			if err != nil {
-				resChan <- parallelEvaluationResult{err: err}
-				return
-			}
-
-			// create result
-			result := parallelEvaluationResult{
-				genomeId: organism.Genotype.Id,
-				fitness:  organism.Fitness,
-				error:    organism.Error,
-				winner:   winner,
-			}
-			resChan <- result
-
-		}(org, resChan, &wg)
 	}
-
-	// wait for evaluation results
-	wg.Wait()
-	close(resChan)
-
-	for result := range resChan {
-		if result.err != nil {
-			return result.err
-		}
-		// find and update original organism
-		org, ok := organismMapping[result.genomeId]
-		if ok {
-			org.Fitness = result.fitness
-			org.Error = result.error
-		} else {
-			return fmt.Errorf("organism not found in mapping for id: %d", result.genomeId)
-		}
-
-		if result.winner && (epoch.Champion == nil || org.Fitness > epoch.Champion.Fitness) {
-			// This will be winner in Markov case
-			epoch.Solved = true
-			epoch.WinnerNodes = len(org.Genotype.Nodes)
-			epoch.WinnerGenes = org.Genotype.Extrons()
-			epoch.WinnerEvals = options.PopSize*epoch.Id + org.Genotype.Id
-			epoch.Champion = org
-			org.IsWinner = true
-		}
+	evaluator := experiment.NewParallelGenerationEvaluator(evaluate, 0)
+	if err := evaluator.GenerationEvaluate(ctx, pop, epoch); err != nil {
+		return err
 	}
 
-	// Fill statistics about current epoch
-	epoch.FillPopulationStatistics(pop)
-
 	// Only print to file every print_every generation
 	if epoch.Solved || epoch.Id%options.PrintEvery == 0 {
 		if _, err := utils.WritePopulationPlain(e.OutputPath, pop, epoch); err != nil {
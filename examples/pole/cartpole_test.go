@@ -4,6 +4,7 @@ import (
 	"deepneat/examples/utils"
 	experiment2 "deepneat/experiment"
 	"deepneat/neat"
+	"deepneat/neat/genetics"
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -87,3 +88,22 @@ func TestCartPoleGenerationEvaluator_GenerationEvaluate(t *testing.T) {
 
 	assert.NotZero(t, solvedTrials, "Failed to solve at least one trial. Need to be checked what was going wrong")
 }
+
+func TestOrganismEvaluate_randomStart(t *testing.T) {
+	opts, startGenome, err := utils.LoadOptionsAndGenome("../../data/pole1_150.neat", "../../data/pole1startgenes")
+	require.NoError(t, err)
+
+	evaluate := func(seed int64) []float64 {
+		org, err := genetics.NewOrganism(0, startGenome, 0)
+		require.NoError(t, err)
+		options := opts.WithRandSource(rand.New(rand.NewSource(seed)))
+		_, err = OrganismEvaluate(org, options, 1000, true)
+		require.NoError(t, err)
+		require.Len(t, org.Behavior, 1)
+		return org.Behavior
+	}
+
+	// the random start state is drawn from the random numbers generator of options
+	assert.Equal(t, evaluate(0), evaluate(0))
+	assert.NotEqual(t, evaluate(0), evaluate(1))
+}
@@ -3,6 +3,7 @@ package pole
 import (
 	"deepneat/neat"
 	"deepneat/neat/genetics"
+	neatmath "deepneat/neat/math"
 	"deepneat/neat/network"
 	"fmt"
 	"math"
@@ -11,19 +12,39 @@ import (
 
 const twelveDegrees = 12.0 * math.Pi / 180.0
 
-// OrganismEvaluate evaluates provided organism for cart pole balancing task
-func OrganismEvaluate(organism *genetics.Organism, winnerBalancingSteps int, randomStart bool) (bool, error) {
+// OrganismEvaluate evaluates provided organism for cart pole balancing task. The phenotype of organism is activated by
+// the plastic network solver if plasticity rule is set in the options, which can be nil. The random start state of the
+// cart is drawn from the random numbers generator of the options, or from the global one if options is nil.
+func OrganismEvaluate(organism *genetics.Organism, options *neat.Options, winnerBalancingSteps int, randomStart bool) (bool, error) {
 	phenotype, err := organism.Phenotype()
 	if err != nil {
 		return false, err
 	}
+	solver, err := organism.PhenotypeSolver(options)
+	if err != nil {
+		return false, err
+	}
+	if _, plastic := solver.(*network.PlasticNetworkSolver); plastic {
+		// restore the weights of phenotype adapted during the run
+		defer func() {
+			_, _ = solver.Flush()
+		}()
+	}
+
+	rng := neatmath.GlobalRand
+	if options != nil {
+		rng = options.Rand()
+	}
 
 	// Try to balance a pole now
-	if fitness, err := runCart(phenotype, winnerBalancingSteps, randomStart); err != nil {
+	fitness, cartPosition, err := runCart(phenotype, solver, winnerBalancingSteps, randomStart, rng)
+	// the final cart position characterizes organism's behavior for novelty search, it is recorded even if the run
+	// failed, because novelty search requires behavior of each organism
+	organism.Behavior = []float64{cartPosition}
+	if err != nil {
 		return false, nil
-	} else {
-		organism.Fitness = float64(fitness)
 	}
+	organism.Fitness = float64(fitness)
 
 	if neat.LogLevel == neat.LogLevelDebug {
 		neat.DebugLog(fmt.Sprintf("Organism #%3d\tfitness: %f", organism.Genotype.Id, organism.Fitness))
@@ -51,18 +72,19 @@ func OrganismEvaluate(organism *genetics.Organism, winnerBalancingSteps int, ran
 	return organism.IsWinner, nil
 }
 
-// runCart runs the cart emulation and return number of emulation steps pole was balanced
-func runCart(net *network.Network, winnerBalancingSteps int, randomStart bool) (steps int, err error) {
-	var x float64        /* cart position, meters */
+// runCart runs the cart emulation and return number of emulation steps pole was balanced and the final cart position.
+// The provided solver is used to activate the network and the provided random numbers generator to set up random start
+// state.
+func runCart(net *network.Network, solver network.Solver, winnerBalancingSteps int, randomStart bool, rng *rand.Rand) (steps int, x float64, err error) {
 	var xDot float64     /* cart velocity */
 	var theta float64    /* pole angle, radians */
 	var thetaDot float64 /* pole angular velocity */
 	if randomStart {
 		/*set up random start state*/
-		x = float64(rand.Int31()%4800)/1000.0 - 2.4
-		xDot = float64(rand.Int31()%2000)/1000.0 - 1
-		theta = float64(rand.Int31()%400)/1000.0 - .2
-		thetaDot = float64(rand.Int31()%3000)/1000.0 - 1.5
+		x = float64(rng.Int31()%4800)/1000.0 - 2.4
+		xDot = float64(rng.Int31()%2000)/1000.0 - 1
+		theta = float64(rng.Int31()%400)/1000.0 - .2
+		thetaDot = float64(rng.Int31()%3000)/1000.0 - 1.5
 	}
 
 	netDepth, err := net.MaxActivationDepthWithCap(0) // The max depth of the network to be activated
@@ -71,7 +93,7 @@ func runCart(net *network.Network, winnerBalancingSteps int, randomStart bool) (
 			"Failed to estimate maximal depth of the network with loop.\nUsing default depth: %d", netDepth))
 	} else if netDepth == 0 {
 		// possibly disconnected - return minimal fitness score
-		return 1, nil
+		return 1, x, nil
 	}
 
 	in := make([]float64, 5)
@@ -82,19 +104,19 @@ func runCart(net *network.Network, winnerBalancingSteps int, randomStart bool) (
 		in[2] = (xDot + .75) / 1.5
 		in[3] = (theta + twelveDegrees) / .41
 		in[4] = (thetaDot + 1.0) / 2.0
-		if err = net.LoadSensors(in); err != nil {
-			return 0, err
+		if err = solver.LoadSensors(in); err != nil {
+			return 0, x, err
 		}
 
 		/*-- activate the network based on the input --*/
-		if res, err := net.ForwardSteps(netDepth); !res {
+		if res, err := solver.ForwardSteps(netDepth); !res {
 			//If it loops, exit returning only fitness of 1 step
 			neat.DebugLog(fmt.Sprintf("Failed to activate Network, reason: %s", err))
-			return 1, nil
+			return 1, x, nil
 		}
 		/*-- decide which way to push via which output unit is greater --*/
 		action := 1
-		if net.Outputs[0].Activation > net.Outputs[1].Activation {
+		if outputs := solver.ReadOutputs(); outputs[0] > outputs[1] {
 			action = 0
 		}
 		/*--- Apply action to the simulated cart-pole ---*/
@@ -102,10 +124,10 @@ func runCart(net *network.Network, winnerBalancingSteps int, randomStart bool) (
 
 		/*--- Check for failure.  If so, return steps ---*/
 		if x < -2.4 || x > 2.4 || theta < -twelveDegrees || theta > twelveDegrees {
-			return steps, nil
+			return steps, x, nil
 		}
 	}
-	return steps, nil
+	return steps, x, nil
 }
 
 // doAction was taken directly from the pole simulator written by Richard Sutton and Charles Anderson.
@@ -40,8 +40,11 @@ func (e *cartDoublePoleGenerationEvaluator) GenerationEvaluate(ctx context.Conte
 	cartPole.generalizationTest = false
 
 	// Evaluate each organism on a test
+	evaluate := func(_ context.Context, organism *genetics.Organism) (bool, error) {
+		return OrganismEvaluate(organism, options, cartPole, e.ActionType)
+	}
 	for _, org := range pop.Organisms {
-		winner, err := OrganismEvaluate(org, cartPole, e.ActionType)
+		winner, err := experiment.EvaluateOrganism(ctx, org, options, evaluate)
 		if err != nil {
 			return err
 		}
@@ -61,7 +64,7 @@ func (e *cartDoublePoleGenerationEvaluator) GenerationEvaluate(ctx context.Conte
 	if !e.Markov {
 		epoch.Solved = false
 		// evaluate generalization tests
-		if champion, err := EvaluateOrganismGeneralization(pop.Species, cartPole, e.ActionType); err != nil {
+		if champion, err := EvaluateOrganismGeneralization(pop.Species, options, cartPole, e.ActionType); err != nil {
 			return err
 		} else if champion.IsWinner {
 			epoch.Solved = true
@@ -7,25 +7,19 @@ import (
 	"deepneat/neat"
 	"deepneat/neat/genetics"
 	"fmt"
-	"sync"
 )
 
 type cartDoublePoleParallelGenerationEvaluator struct {
 	cartDoublePoleGenerationEvaluator
-}
 
-type parallelEvaluationResult struct {
-	genomeId int
-	fitness  float64
-	error    float64
-	winner   bool
-	err      error
+	// The function to evaluate each organism, if not set organisms are evaluated locally
+	evaluate experiment.OrganismEvaluateFunc
 }
 
 // NewCartDoublePoleParallelGenerationEvaluator is the generations evaluator for double-pole balancing experiment: both Markov and non-Markov versions
 func NewCartDoublePoleParallelGenerationEvaluator(outDir string, markov bool, actionType ActionType) experiment.GenerationEvaluator {
 	return &cartDoublePoleParallelGenerationEvaluator{
-		cartDoublePoleGenerationEvaluator{
+		cartDoublePoleGenerationEvaluator: cartDoublePoleGenerationEvaluator{
 			OutputPath: outDir,
 			Markov:     markov,
 			ActionType: actionType,
@@ -33,84 +27,37 @@ func NewCartDoublePoleParallelGenerationEvaluator(outDir string, markov bool, ac
 	}
 }
 
+// NewCartDoublePoleDistributedGenerationEvaluator is the generations evaluator for Markov double-pole balancing
+// experiment, which evaluates organisms concurrently by provided function, e.g., dispatching them to the remote workers
+// with distributed.Coordinator. The non-Markov version is not supported, because its generalization test is run
+// locally.
+func NewCartDoublePoleDistributedGenerationEvaluator(outDir string, evaluate experiment.OrganismEvaluateFunc) experiment.GenerationEvaluator {
+	return &cartDoublePoleParallelGenerationEvaluator{
+		cartDoublePoleGenerationEvaluator: cartDoublePoleGenerationEvaluator{
+			OutputPath: outDir,
+			Markov:     true,
+		},
+		evaluate: evaluate,
+	}
+}
+
 func (e *cartDoublePoleParallelGenerationEvaluator) GenerationEvaluate(ctx context.Context, pop *genetics.Population, epoch *experiment.Generation) error {
 	options, ok := neat.FromContext(ctx)
 	if !ok {
 		return neat.ErrNEATOptionsNotFound
 	}
-
-	organismMapping := make(map[int]*genetics.Organism)
-
-	popSize := len(pop.Organisms)
-	resChan := make(chan parallelEvaluationResult, popSize)
-	// The wait group to wait for all GO routines
-	var wg sync.WaitGroup
-
-	// Evaluate each organism in generation
-	for _, org := range pop.Organisms {
-		if _, ok = organismMapping[org.Genotype.Id]; ok {
-			return fmt.Errorf("organism with %d already exists in mapping", org.Genotype.Id)
-		}
-		organismMapping[org.Genotype.Id] = org
-		wg.Add(1)
-
-		// run in separate GO thread
-		go func(organism *genetics.Organism, actionType ActionType, resChan chan<- parallelEvaluationResult, wg *sync.WaitGroup) {
-			defer wg.Done()
-
+	// Evaluate all organisms concurrently
+	evaluate := e.evaluate
+	if evaluate == nil {
+		evaluate = func(_ context.Context, organism *genetics.Organism) (bool, error) {
 			// create simulator and evaluate
-			cartPole := NewCartPole(e.Markov)
-			cartPole.nonMarkovLong = false
-			cartPole.generalizationTest = false
-
-			winner, err := OrganismEvaluate(organism, cartPole, actionType)
-			if err != nil {
-				resChan <- parallelEvaluationResult{err: err}
-				return
-			}
-
-			// create result
-			result := parallelEvaluationResult{
-				genomeId: organism.Genotype.Id,
-				fitness:  organism.Fitness,
-				error:    organism.Error,
-				winner:   winner,
-			}
-			resChan <- result
-
-		}(org, e.ActionType, resChan, &wg)
-	}
-
-	// wait for evaluation results
-	wg.Wait()
-	close(resChan)
-
-	for result := range resChan {
-		if result.err != nil {
-			return result.err
-		}
-		// find and update original organism
-		org, ok := organismMapping[result.genomeId]
-		if ok {
-			org.Fitness = result.fitness
-			org.Error = result.error
-		} else {
-			return fmt.Errorf("organism not found in mapping for id: %d", result.genomeId)
-		}
-
-		if result.winner && (epoch.Champion == nil || org.Fitness > epoch.Champion.Fitness) {
-			// This will be winner in Markov case
-			epoch.Solved = true
-			epoch.WinnerNodes = len(org.Genotype.Nodes)
-			epoch.WinnerGenes = org.Genotype.Extrons()
-			epoch.WinnerEvals = options.PopSize*epoch.Id + org.Genotype.Id
-			epoch.Champion = org
-			org.IsWinner = true
+			return OrganismEvaluate(organism, options, NewCartPole(e.Markov), e.ActionType)
 		}
 	}
-
-	// Fill statistics about current epoch
-	epoch.FillPopulationStatistics(pop)
+	evaluator := experiment.NewParallelGenerationEvaluator(evaluate, 0)
+	if err := evaluator.GenerationEvaluate(ctx, pop, epoch); err != nil {
+		return err
+	}
 
 	if epoch.Solved {
 		// print winner organism's statistics
@@ -63,7 +63,16 @@ func NewCartPole(markov bool) *CartDoublePole {
 	}
 }
 
-func (p *CartDoublePole) evalNet(net *network.Network, actionType ActionType) (steps float64, err error) {
+// networkActivator is the network solver which activates the network until all its outputs are active. It is
+// implemented by network.Network and network.PlasticNetworkSolver.
+type networkActivator interface {
+	network.Solver
+	// ActivateSteps Attempts to activate the network given number of steps before returning error
+	ActivateSteps(maxSteps int) (bool, error)
+}
+
+// evalNet is to evaluate the network activating it by provided solver
+func (p *CartDoublePole) evalNet(net *network.Network, solver networkActivator, actionType ActionType) (steps float64, err error) {
 	nonMarkovMax := nonMarkovGeneralizationMaxSteps
 	if p.nonMarkovLong {
 		nonMarkovMax = nonMarkovLongMaxSteps
@@ -97,19 +106,19 @@ func (p *CartDoublePole) evalNet(net *network.Network, actionType ActionType) (s
 			input[5] = (p.state[5] + 1.0) / 2.0
 			input[6] = 0.5
 
-			if err = net.LoadSensors(input); err != nil {
+			if err = solver.LoadSensors(input); err != nil {
 				return 0, err
 			}
 
 			/*-- activate the network based on the input --*/
-			if res, err := net.ActivateSteps(netDepth); err != nil {
+			if res, err := solver.ActivateSteps(netDepth); err != nil {
 				neat.DebugLog(fmt.Sprintf("Failed to activate Network, reason: %s", err))
 				return 0, err
 			} else if !res {
 				// If it loops, exit returning only fitness of 1 step
 				return 1.0, nil
 			}
-			action := net.Outputs[0].Activation
+			action := solver.ReadOutputs()[0]
 			if actionType == DiscreteAction {
 				// make action values discrete
 				if action < 0.5 {
@@ -137,13 +146,13 @@ func (p *CartDoublePole) evalNet(net *network.Network, actionType ActionType) (s
 			input[2] = p.state[4] / 0.52
 			input[3] = 1.0
 
-			err = net.LoadSensors(input)
+			err = solver.LoadSensors(input)
 			if err != nil {
 				return 0, err
 			}
 
 			/*-- activate the network based on the input --*/
-			if res, err := net.ActivateSteps(netDepth); err != nil {
+			if res, err := solver.ActivateSteps(netDepth); err != nil {
 				neat.WarnLog(fmt.Sprintf("Failed to activate Network, reason: %s", err))
 				return 0, err
 			} else if !res {
@@ -151,7 +160,7 @@ func (p *CartDoublePole) evalNet(net *network.Network, actionType ActionType) (s
 				return 0.0001, nil
 			}
 
-			action := net.Outputs[0].Activation
+			action := solver.ReadOutputs()[0]
 			if actionType == DiscreteAction {
 				// make action values discrete
 				if action < 0.5 {
@@ -329,17 +338,34 @@ func (p *CartDoublePole) resetState() {
 	p.balancedTimeSteps = 0 // Always count # of balanced time steps
 }
 
-// OrganismEvaluate method evaluates fitness of the organism for cart double pole-balancing task
-func OrganismEvaluate(organism *genetics.Organism, cartPole *CartDoublePole, actionType ActionType) (winner bool, err error) {
+// OrganismEvaluate method evaluates fitness of the organism for cart double pole-balancing task. The phenotype of
+// organism is activated by the plastic network solver if plasticity rule is set in the options, which can be nil.
+func OrganismEvaluate(organism *genetics.Organism, options *neat.Options, cartPole *CartDoublePole, actionType ActionType) (winner bool, err error) {
 	// Try to balance a pole now
 	phenotype, err := organism.Phenotype()
 	if err != nil {
 		return false, err
 	}
-	organism.Fitness, err = cartPole.evalNet(phenotype, actionType)
+	solver, err := organism.PhenotypeSolver(options)
+	if err != nil {
+		return false, err
+	}
+	activator, ok := solver.(networkActivator)
+	if !ok {
+		return false, fmt.Errorf("unsupported network solver: %T", solver)
+	}
+	if _, plastic := solver.(*network.PlasticNetworkSolver); plastic {
+		// restore the weights of phenotype adapted during the run
+		defer func() {
+			_, _ = solver.Flush()
+		}()
+	}
+	organism.Fitness, err = cartPole.evalNet(phenotype, activator, actionType)
 	if err != nil {
 		return false, err
 	}
+	// the final positions of cart and poles characterize organism's behavior for novelty search
+	organism.Behavior = []float64{cartPole.state[0], cartPole.state[2], cartPole.state[4]}
 
 	if neat.LogLevel == neat.LogLevelDebug {
 		neat.DebugLog(fmt.Sprintf("Organism #%3d\tfitness: %f", organism.Genotype.Id, organism.Fitness))
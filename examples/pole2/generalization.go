@@ -19,7 +19,7 @@ import (
 // and its angular velocity ∆θ2/∆t are set to zero. The GS is then defined as the number of successful runs
 // from the 625 initial conditions and an individual is defined as a solution if it reaches a generalization
 // score of 200 or more.
-func EvaluateOrganismGeneralization(species []*genetics.Species, cartPole *CartDoublePole, actionType ActionType) (*genetics.Organism, error) {
+func EvaluateOrganismGeneralization(species []*genetics.Species, options *neat.Options, cartPole *CartDoublePole, actionType ActionType) (*genetics.Organism, error) {
 	// Sort the species by max organism fitness in descending order - the highest fitness first
 	sortedSpecies := make([]*genetics.Species, len(species))
 	copy(sortedSpecies, species)
@@ -53,6 +53,11 @@ func EvaluateOrganismGeneralization(species []*genetics.Species, cartPole *CartD
 	// the organism champion
 	champion := currSpecies.FindChampion()
 	championFitness := champion.Fitness
+	// the behavior recorded during generation evaluation is kept for novelty search
+	championBehavior := champion.Behavior
+	defer func() {
+		champion.Behavior = championBehavior
+	}()
 	championPhenotype, err := champion.Phenotype()
 	if err != nil {
 		return nil, err
@@ -62,7 +67,7 @@ func EvaluateOrganismGeneralization(species []*genetics.Species, cartPole *CartD
 	cartPole.nonMarkovLong = true
 	cartPole.generalizationTest = false
 
-	longRunPassed, err := OrganismEvaluate(champion, cartPole, actionType)
+	longRunPassed, err := OrganismEvaluate(champion, options, cartPole, actionType)
 	if err != nil {
 		return nil, err
 	}
@@ -95,7 +100,7 @@ func EvaluateOrganismGeneralization(species []*genetics.Species, cartPole *CartD
 							return nil, err
 						}
 
-						if generalized, err := OrganismEvaluate(champion, cartPole, actionType); generalized {
+						if generalized, err := OrganismEvaluate(champion, options, cartPole, actionType); generalized {
 							generalizationScore++
 
 							if neat.LogLevel == neat.LogLevelDebug {
@@ -12,6 +12,8 @@ import (
 	"deepneat/experiment/utils"
 	"deepneat/neat"
 	"deepneat/neat/genetics"
+	"deepneat/neat/network"
+	"errors"
 	"fmt"
 	"math"
 )
@@ -45,8 +47,11 @@ func (e *xorGenerationEvaluator) GenerationEvaluate(ctx context.Context, pop *ge
 		return neat.ErrNEATOptionsNotFound
 	}
 	// Evaluate each organism on a test
+	evaluate := func(_ context.Context, organism *genetics.Organism) (bool, error) {
+		return e.orgEvaluate(organism, options)
+	}
 	for _, org := range pop.Organisms {
-		res, err := e.orgEvaluate(org)
+		res, err := experiment.EvaluateOrganism(ctx, org, options, evaluate)
 		if err != nil {
 			return err
 		}
@@ -107,13 +112,28 @@ func (e *xorGenerationEvaluator) GenerationEvaluate(ctx context.Context, pop *ge
 			neat.InfoLog(fmt.Sprintf("Generation #%d winner's phenome Cytoscape JSON graph dumped to: %s\n",
 				epoch.Id, orgPath))
 		}
+
+		// Prints the winner organism's ancestry if lineage tracking is enabled
+		if pop.Lineage != nil {
+			lineageFile := "xor_winner_lineage"
+			if lineagePath, err := utils.WriteLineageJSON(lineageFile, e.OutputPath, pop.Lineage, org, epoch); err != nil {
+				neat.ErrorLog(fmt.Sprintf("Failed to dump winner organism's lineage JSON, reason: %s\n", err))
+			} else {
+				neat.InfoLog(fmt.Sprintf("Generation #%d winner's lineage JSON dumped to: %s\n", epoch.Id, lineagePath))
+			}
+			if lineagePath, err := utils.WriteLineageDOT(lineageFile, e.OutputPath, pop.Lineage, org, epoch); err != nil {
+				neat.ErrorLog(fmt.Sprintf("Failed to dump winner organism's lineage DOT graph, reason: %s\n", err))
+			} else {
+				neat.InfoLog(fmt.Sprintf("Generation #%d winner's lineage DOT graph dumped to: %s\n", epoch.Id, lineagePath))
+			}
+		}
 	}
 
 	return nil
 }
 
 // orgEvaluate evaluates fitness of the provided organism
-func (e *xorGenerationEvaluator) orgEvaluate(organism *genetics.Organism) (bool, error) {
+func (e *xorGenerationEvaluator) orgEvaluate(organism *genetics.Organism, options *neat.Options) (bool, error) {
 	// The four possible input combinations to xor
 	// The first number is for biasing
 	in := [][]float64{
@@ -127,6 +147,20 @@ func (e *xorGenerationEvaluator) orgEvaluate(organism *genetics.Organism) (bool,
 		return false, err
 	}
 
+	if options.GradientTuningMode.IsEnabled() {
+		// fine-tune the weights of feed-forward network before evaluation, the networks with loops are evaluated as is
+		targets := [][]float64{{0.0}, {1.0}, {1.0}, {0.0}}
+		if _, err = organism.FineTune(in, targets, options); err != nil && !errors.Is(err, network.ErrNetNotDifferentiable) {
+			return false, err
+		}
+	}
+
+	// The solver adapting weights of the phenotype at runtime if plasticity enabled, the phenotype itself otherwise
+	solver, err := organism.PhenotypeSolver(options)
+	if err != nil {
+		return false, err
+	}
+
 	netDepth, err := phenotype.MaxActivationDepthWithCap(0) // The max depth of the network to be activated
 	if err != nil {
 		neat.WarnLog(fmt.Sprintf(
@@ -134,30 +168,32 @@ func (e *xorGenerationEvaluator) orgEvaluate(organism *genetics.Organism) (bool,
 			organism.Genotype, netDepth))
 	}
 	neat.DebugLog(fmt.Sprintf("Network depth: %d for organism: %d\n", netDepth, organism.Genotype.Id))
+	// The four outputs, which also characterize organism's behavior for novelty search
+	out := make([]float64, 4)
+	organism.Behavior = out
 	if netDepth == 0 {
 		neat.DebugLog(fmt.Sprintf("ALERT: Network depth is ZERO for Genome: %s", organism.Genotype))
 		return false, nil
 	}
 
-	success := false          // Check for successful activation
-	out := make([]float64, 4) // The four outputs
+	success := false // Check for successful activation
 
 	// Load and activate the network on each input
 	for count := 0; count < 4; count++ {
-		if err = phenotype.LoadSensors(in[count]); err != nil {
+		if err = solver.LoadSensors(in[count]); err != nil {
 			neat.ErrorLog(fmt.Sprintf("Failed to load sensors: %s", err))
 			return false, err
 		}
 
 		// Use depth to ensure full relaxation
-		if success, err = phenotype.ForwardSteps(netDepth); err != nil {
+		if success, err = solver.ForwardSteps(netDepth); err != nil {
 			neat.ErrorLog(fmt.Sprintf("Failed to activate network: %s", err))
 			return false, err
 		}
-		out[count] = phenotype.Outputs[0].Activation
+		out[count] = solver.ReadOutputs()[0]
 
 		// Flush network for subsequent use
-		if _, err = phenotype.Flush(); err != nil {
+		if _, err = solver.Flush(); err != nil {
 			neat.ErrorLog(fmt.Sprintf("Failed to flush network: %s", err))
 			return false, err
 		}
@@ -137,3 +137,112 @@ func TestXOR_disconnected(t *testing.T) {
 	meanAge /= count
 	t.Logf("Mean best organisms: complexity=%.1f, diversity=%.1f, age=%.1f", meanComplexity, meanDiversity, meanAge)
 }
+
+// The XOR integration test with gradient fine-tuning of the feed-forward phenotypes
+func TestXOR_gradientTuning(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short Unit Test mode.")
+	}
+
+	// the numbers will be different every time we run.
+	rand.Seed(time.Now().Unix())
+
+	outDirPath, contextPath, genomePath := "../../out/XOR_gradient_test", "../../data/xor.neat", "../../data/xorstartgenes"
+
+	for _, mode := range []neat.GradientTuningMode{neat.GradientTuningLamarckian, neat.GradientTuningBaldwinian} {
+		opts, startGenome, err := utils.LoadOptionsAndGenome(contextPath, genomePath)
+		neat.LogLevel = neat.LogLevelInfo
+		require.NoError(t, err)
+
+		err = utils.CreateOutputDir(outDirPath)
+		require.NoError(t, err, "Failed to create output directory")
+
+		opts.NumRuns = 10
+		opts.GradientTuningMode = mode
+		opts.GradientTuningEpochs = 20
+		opts.GradientOptimizer = neat.GradientOptimizerAdam
+		opts.GradientLearningRate = 0.1
+		require.NoError(t, opts.Validate())
+		experiment := experiment2.Experiment{
+			Id:     0,
+			Trials: make(experiment2.Trials, opts.NumRuns),
+		}
+		err = experiment.Execute(opts.NeatContext(), startGenome, NewXORGenerationEvaluator(outDirPath), nil)
+		require.NoError(t, err, "Failed to perform XOR experiment with %s gradient tuning", mode)
+
+		_, _, avgEvals, _ := experiment.AvgWinnerStatistics()
+		maxEvals := float64(opts.PopSize * opts.NumGenerations)
+		assert.True(t, avgEvals < maxEvals, mode)
+		t.Logf("%s: avg_evals: %.1f, solved trials: %d\n", mode, avgEvals, experiment.TrialsSolved())
+	}
+}
+
+// The XOR integration test with phenotypes activated by the plastic network solver
+func TestXOR_plasticity(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short Unit Test mode.")
+	}
+
+	outDirPath, contextPath, genomePath := "../../out/XOR_plasticity_test", "../../data/xor.neat", "../../data/xorstartgenes"
+
+	opts, startGenome, err := utils.LoadOptionsAndGenome(contextPath, genomePath)
+	neat.LogLevel = neat.LogLevelInfo
+	require.NoError(t, err)
+
+	err = utils.CreateOutputDir(outDirPath)
+	require.NoError(t, err, "Failed to create output directory")
+
+	opts.NumRuns = 3
+	opts.PlasticityRule = neat.PlasticityRuleOja
+	opts.PlasticWeightBound = 8.0
+	require.NoError(t, opts.Validate())
+	experiment := experiment2.Experiment{
+		Id:       0,
+		Trials:   make(experiment2.Trials, opts.NumRuns),
+		RandSeed: 42,
+	}
+	err = experiment.Execute(opts.NeatContext(), startGenome, NewXORGenerationEvaluator(outDirPath), nil)
+	require.NoError(t, err, "Failed to perform XOR experiment with plasticity")
+
+	for _, trial := range experiment.Trials {
+		require.NotEmpty(t, trial.Generations)
+		assert.True(t, trial.ChampionsFitness().Max() > 0, "trial: %d", trial.Id)
+	}
+	t.Logf("plasticity: solved trials: %d\n", experiment.TrialsSolved())
+}
+
+// The XOR integration test with fitness blended with novelty of the outputs recorded as behavior of organisms
+func TestXOR_noveltySearch(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short Unit Test mode.")
+	}
+
+	outDirPath, contextPath, genomePath := "../../out/XOR_novelty_test", "../../data/xor.neat", "../../data/xorstartgenes"
+
+	opts, startGenome, err := utils.LoadOptionsAndGenome(contextPath, genomePath)
+	neat.LogLevel = neat.LogLevelInfo
+	require.NoError(t, err)
+
+	err = utils.CreateOutputDir(outDirPath)
+	require.NoError(t, err, "Failed to create output directory")
+
+	opts.NumRuns = 3
+	opts.NoveltySearchMode = neat.NoveltySearchModeBlend
+	opts.NoveltyKNearest = 15
+	opts.NoveltyArchiveThreshold = 0.5
+	opts.NoveltyFitnessBlend = 0.2
+	require.NoError(t, opts.Validate())
+	experiment := experiment2.Experiment{
+		Id:       0,
+		Trials:   make(experiment2.Trials, opts.NumRuns),
+		RandSeed: 42,
+	}
+	err = experiment.Execute(opts.NeatContext(), startGenome, NewXORGenerationEvaluator(outDirPath), nil)
+	require.NoError(t, err, "Failed to perform XOR experiment with novelty search")
+
+	for _, trial := range experiment.Trials {
+		require.NotEmpty(t, trial.Generations)
+		assert.True(t, trial.ChampionsFitness().Max() > 0, "trial: %d", trial.Id)
+	}
+	t.Logf("novelty search: solved trials: %d\n", experiment.TrialsSolved())
+}
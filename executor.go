@@ -6,36 +6,68 @@ import (
 	"deepneat/examples/pole2"
 	"deepneat/examples/xor"
 	"deepneat/experiment"
+	"deepneat/experiment/distributed"
+	"deepneat/experiment/utils"
 	"deepneat/neat"
 	"deepneat/neat/genetics"
+	neatmath "deepneat/neat/math"
 	"flag"
 	"fmt"
 	"log"
 	"math/rand"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 )
 
 // The experiment runner boilerplate code
 func main() {
+	// the subcommand to create seed genome
+	if len(os.Args) > 1 && os.Args[1] == "seed" {
+		createSeedGenome(os.Args[2:])
+		return
+	}
+	// the subcommand to run distributed evaluation worker
+	if len(os.Args) > 1 && os.Args[1] == "worker" {
+		runEvaluationWorker(os.Args[2:])
+		return
+	}
+
 	var outDirPath = flag.String("out", "./out", "The output directory to store results.")
 	var contextPath = flag.String("context", "./data/xor.neat", "The execution context configuration file.")
 	var genomePath = flag.String("genome", "./data/xorstartgenes", "The seed genome to start with.")
 	var experimentName = flag.String("experiment", "XOR", "The name of experiment to run. [XOR, cart_pole, cart_2pole_markov, cart_2pole_non-markov]")
 	var trialsCount = flag.Int("trials", 0, "The number of trials for experiment. Overrides the one set in configuration.")
 	var logLevel = flag.String("log_level", "", "The logger level to be used. Overrides the one set in configuration.")
-	var randSeed = flag.Int64("seed", 0, "The seed for random number generator")
+	var randSeed = flag.Int64("seed", 0, "The seed for random number generator. If not set, the current time is used.")
+	var resume = flag.Bool("resume", false, "Resume experiment from the last checkpoint stored in the output directory.")
+	var workers = flag.String("workers", "", "The comma separated base URLs of workers to evaluate organisms remotely. The workers must be started with the worker subcommand. Supported by the cart_pole and cart_2pole_markov experiments and their parallel versions.")
 
 	flag.Parse()
 
+	var distributedExp distributedExperiment
+	if len(*workers) > 0 {
+		var ok bool
+		if distributedExp, ok = distributedExperiments[*experimentName]; !ok {
+			log.Fatalf("Experiment: %s does not support distributed evaluation, supported experiments: %s",
+				*experimentName, strings.Join(distributedExperimentNames(), ", "))
+		}
+	}
+
 	// Seed the random-number generator with current time so that
 	// the numbers will be different every time we run.
 	seed := time.Now().Unix()
-	if randSeed != nil {
-		seed = *randSeed
-	}
+	flag.Visit(func(f *flag.Flag) {
+		// the explicitly set seed is used even if it is zero
+		if f.Name == "seed" {
+			seed = *randSeed
+		}
+	})
 	rand.Seed(seed)
 
 	// Load NEAT options
@@ -58,7 +90,17 @@ func main() {
 
 	// Check if output dir exists
 	outDir := *outDirPath
-	if _, err := os.Stat(outDir); err == nil {
+	checkpointPath := filepath.Join(outDir, experiment.CheckpointFileName)
+	var checkpoint *experiment.Checkpoint
+	if *resume {
+		// load checkpoint and keep the output directory intact to continue experiment
+		if checkpoint, err = experiment.ReadCheckpointFromFile(checkpointPath); err != nil {
+			log.Fatalf("Failed to read checkpoint from file: [%s], reason: %s", checkpointPath, err)
+		}
+		log.Printf("Resuming experiment from trial: %d, generation: %d\n", checkpoint.TrialId, checkpoint.GenerationId)
+		// continue with the random seed of the interrupted experiment
+		seed = checkpoint.RandSeed
+	} else if _, err := os.Stat(outDir); err == nil {
 		// backup it
 		backUpDir := fmt.Sprintf("%s-%s", outDir, time.Now().Format("2006-01-02T15_04_05"))
 		// clear it
@@ -83,11 +125,16 @@ func main() {
 		}
 	}
 
+	// All stochastic decisions of the NEAT algorithm are made using random streams derived from this seed for each
+	// generation, thus the run can be reproduced and resumed
+	neatOptions.RandSource = rand.New(rand.NewSource(seed))
+
 	// create experiment
 	exp := experiment.Experiment{
-		Id:       0,
-		Trials:   make(experiment.Trials, neatOptions.NumRuns),
-		RandSeed: seed,
+		Id:             0,
+		Trials:         make(experiment.Trials, neatOptions.NumRuns),
+		RandSeed:       seed,
+		CheckpointPath: checkpointPath,
 	}
 	var generationEvaluator experiment.GenerationEvaluator
 	switch *experimentName {
@@ -116,9 +163,25 @@ func main() {
 	errChan := make(chan error)
 	ctx, cancel := context.WithCancel(context.Background())
 
+	if len(*workers) > 0 {
+		// evaluate organisms by the remote workers with evaluator registered under experiment name, the results are
+		// stored into output directory by the experiment's own generation evaluator
+		coordinator := distributed.NewCoordinator(*experimentName, strings.Split(*workers, ","), nil)
+		if coordinator.CheckHealth(ctx) == 0 {
+			log.Fatalf("No healthy workers with evaluator: %s found at: %s", *experimentName, *workers)
+		}
+		go coordinator.RunHealthChecks(ctx, workersHealthCheckInterval)
+		generationEvaluator = distributedExp.generationEvaluator(outDir, coordinator.EvaluateOrganism)
+	}
+
 	// run experiment in the separate GO routine
 	go func() {
-		if err = exp.Execute(neat.NewContext(ctx, neatOptions), startGenome, generationEvaluator, nil); err != nil {
+		if checkpoint != nil {
+			err = exp.Resume(neat.NewContext(ctx, neatOptions), startGenome, checkpoint, generationEvaluator, nil)
+		} else {
+			err = exp.Execute(neat.NewContext(ctx, neatOptions), startGenome, generationEvaluator, nil)
+		}
+		if err != nil {
 			errChan <- err
 		} else {
 			errChan <- nil
@@ -173,4 +236,180 @@ func main() {
 	} else if err = exp.WriteNPZ(npzResFile); err != nil {
 		log.Fatal("Failed to save experiment results as NPZ file", err)
 	}
+
+	// Save species history to be used for speciation plot
+	//
+	speciesResPath := fmt.Sprintf("%s/%s_species.csv", outDir, *experimentName)
+	if speciesResFile, err := os.Create(speciesResPath); err != nil {
+		log.Fatalf("Failed to create file for species history: [%s], reason: %s", speciesResPath, err)
+	} else if err = exp.WriteSpeciesCSV(speciesResFile); err != nil {
+		log.Fatal("Failed to save species history as CSV file", err)
+	}
+	speciesEventsPath := fmt.Sprintf("%s/%s_species_events.csv", outDir, *experimentName)
+	if speciesEventsFile, err := os.Create(speciesEventsPath); err != nil {
+		log.Fatalf("Failed to create file for species events: [%s], reason: %s", speciesEventsPath, err)
+	} else if err = exp.WriteSpeciesEventsCSV(speciesEventsFile); err != nil {
+		log.Fatal("Failed to save species events as CSV file", err)
+	}
+
+	// Save genomes of the hall of fame if kept
+	//
+	if exp.HallOfFame != nil {
+		if _, err = utils.WriteHallOfFame(outDir, exp.HallOfFame); err != nil {
+			log.Fatal("Failed to save hall of fame genomes", err)
+		}
+	}
+}
+
+// createSeedGenome is to create seed genome from the input/output specification and save it into the file.
+// Usage: executor seed -inputs 2 -outputs 1 -bias -hidden 3,2 -scheme full -out ./data/seed.yml
+func createSeedGenome(args []string) {
+	seedFlags := flag.NewFlagSet("seed", flag.ExitOnError)
+	var outPath = seedFlags.String("out", "./data/seed_genome.yml", "The file to store seed genome. The YAML encoding is used for .yml/.yaml files, plain text otherwise.")
+	var contextPath = seedFlags.String("context", "", "The execution context configuration file with node activators of the hidden nodes. If not set, the steepened sigmoid is used.")
+	var inputs = seedFlags.Int("inputs", 0, "The number of input nodes.")
+	var outputs = seedFlags.Int("outputs", 0, "The number of output nodes.")
+	var bias = seedFlags.Bool("bias", false, "Add the bias node.")
+	var hidden = seedFlags.String("hidden", "", "The comma separated sizes of the hidden layers, e.g. 3,2.")
+	var scheme = seedFlags.String("scheme", string(genetics.SeedConnectionFull), "The connection scheme. [full, sparse, fs-neat]")
+	var connectionProb = seedFlags.Float64("prob", 0.5, "The probability of connection for the sparse scheme.")
+	var randSeed = seedFlags.Int64("seed", 0, "The seed for random number generator. If not set, the current time is used.")
+	_ = seedFlags.Parse(args)
+
+	seed := time.Now().Unix()
+	seedFlags.Visit(func(f *flag.Flag) {
+		// the explicitly set seed is used even if it is zero
+		if f.Name == "seed" {
+			seed = *randSeed
+		}
+	})
+	neatOptions := &neat.Options{
+		NodeActivators:     []neatmath.NodeActivationType{neatmath.SigmoidSteepenedActivation},
+		NodeActivatorsProb: []float64{1.0},
+	}
+	if len(*contextPath) > 0 {
+		var err error
+		if neatOptions, err = neat.ReadNeatOptionsFromFile(*contextPath); err != nil {
+			log.Fatal("Failed to load NEAT options: ", err)
+		}
+	}
+	neatOptions.RandSource = rand.New(rand.NewSource(seed))
+
+	spec := genetics.SeedGenomeSpec{
+		NumInputs:      *inputs,
+		NumOutputs:     *outputs,
+		Bias:           *bias,
+		Scheme:         genetics.SeedConnectionScheme(*scheme),
+		ConnectionProb: *connectionProb,
+	}
+	if len(*hidden) > 0 {
+		for _, size := range strings.Split(*hidden, ",") {
+			layerSize, err := strconv.Atoi(strings.TrimSpace(size))
+			if err != nil {
+				log.Fatalf("Failed to parse hidden layer size: [%s], reason: %s", size, err)
+			}
+			spec.HiddenLayers = append(spec.HiddenLayers, layerSize)
+		}
+	}
+	genome, err := genetics.NewSeedGenome(1, spec, neatOptions)
+	if err != nil {
+		log.Fatal("Failed to create seed genome: ", err)
+	}
+
+	encoding := genetics.PlainGenomeEncoding
+	if strings.HasSuffix(*outPath, "yml") || strings.HasSuffix(*outPath, "yaml") {
+		encoding = genetics.YAMLGenomeEncoding
+	}
+	genomeFile, err := os.Create(*outPath)
+	if err != nil {
+		log.Fatalf("Failed to create seed genome file: [%s], reason: %s", *outPath, err)
+	}
+	defer func() {
+		_ = genomeFile.Close()
+	}()
+	writer, err := genetics.NewGenomeWriter(genomeFile, encoding)
+	if err != nil {
+		log.Fatal("Failed to create genome writer: ", err)
+	}
+	if err = writer.WriteGenome(genome); err != nil {
+		log.Fatal("Failed to write seed genome: ", err)
+	}
+	fmt.Printf(">>> Seed genome with %d nodes and %d genes saved to: %s\n", len(genome.Nodes), len(genome.Genes), *outPath)
+}
+
+// The interval between health checks of the remote workers
+const workersHealthCheckInterval = 10 * time.Second
+
+// distributedExperiment is the experiment which organisms can be evaluated by the remote workers
+type distributedExperiment struct {
+	// The function to create organism evaluator registered by workers under the experiment name
+	evaluator func(options *neat.Options) experiment.OrganismEvaluateFunc
+	// The function to create generation evaluator dispatching organisms to workers by provided function
+	generationEvaluator func(outDir string, evaluate experiment.OrganismEvaluateFunc) experiment.GenerationEvaluator
+}
+
+var (
+	cartPoleDistributed = distributedExperiment{
+		evaluator: func(options *neat.Options) experiment.OrganismEvaluateFunc {
+			return func(_ context.Context, organism *genetics.Organism) (bool, error) {
+				return pole.OrganismEvaluate(organism, options, 1500000, true)
+			}
+		},
+		generationEvaluator: pole.NewCartPoleDistributedGenerationEvaluator,
+	}
+	cartDoublePoleDistributed = distributedExperiment{
+		evaluator: func(options *neat.Options) experiment.OrganismEvaluateFunc {
+			return func(_ context.Context, organism *genetics.Organism) (bool, error) {
+				return pole2.OrganismEvaluate(organism, options, pole2.NewCartPole(true), pole2.ContinuousAction)
+			}
+		},
+		generationEvaluator: pole2.NewCartDoublePoleDistributedGenerationEvaluator,
+	}
+
+	// The experiments supporting distributed evaluation by name
+	distributedExperiments = map[string]distributedExperiment{
+		"cart_pole":                  cartPoleDistributed,
+		"cart_pole_parallel":         cartPoleDistributed,
+		"cart_2pole_markov":          cartDoublePoleDistributed,
+		"cart_2pole_markov_parallel": cartDoublePoleDistributed,
+	}
+)
+
+// distributedExperimentNames is to get sorted names of experiments supporting distributed evaluation
+func distributedExperimentNames() []string {
+	names := make([]string, 0, len(distributedExperiments))
+	for name := range distributedExperiments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// runEvaluationWorker is to run the worker evaluating organisms of all experiments supporting distributed evaluation
+// sent by the executor started with -workers flag.
+// Usage: executor worker -address 127.0.0.1:8080 -context ./data/pole1_150.neat
+func runEvaluationWorker(args []string) {
+	workerFlags := flag.NewFlagSet("worker", flag.ExitOnError)
+	var address = workerFlags.String("address", "127.0.0.1:0", "The address to listen on. If port is zero, any available one is used.")
+	var contextPath = workerFlags.String("context", "", "The execution context configuration file to be provided to evaluators.")
+	_ = workerFlags.Parse(args)
+
+	var neatOptions *neat.Options
+	if len(*contextPath) > 0 {
+		var err error
+		if neatOptions, err = neat.ReadNeatOptionsFromFile(*contextPath); err != nil {
+			log.Fatal("Failed to load NEAT options: ", err)
+		}
+	}
+
+	worker := distributed.NewWorker(neatOptions)
+	for name, exp := range distributedExperiments {
+		worker.Register(name, exp.evaluator(neatOptions))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := distributed.ListenAndServe(ctx, *address, worker, os.Stdout); err != nil {
+		log.Fatal("Failed to run worker: ", err)
+	}
 }
@@ -0,0 +1,163 @@
+package experiment
+
+import (
+	"deepneat/neat/genetics"
+	"encoding/gob"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CheckpointFileName is the default name of the file in the output directory to store experiment checkpoints
+const CheckpointFileName = "checkpoint.dat"
+
+// Checkpoint holds the state of the running experiment which is sufficient to resume it later from the exact
+// trial and generation.
+type Checkpoint struct {
+	// The ID of the trial in progress
+	TrialId int
+	// The ID of the next generation to be evaluated in the trial in progress
+	GenerationId int
+	// The random seed of the experiment used to derive random streams of the generations
+	RandSeed int64
+	// The trial in progress with all generations evaluated so far
+	Trial Trial
+	// The population of organisms to be evaluated in the next generation
+	Population *genetics.Population
+	// The trials completed before the trial in progress
+	Trials Trials
+}
+
+// Write is to write encoded checkpoint data into provided writer
+func (c *Checkpoint) Write(w io.Writer) error {
+	enc := gob.NewEncoder(w)
+	return c.Encode(enc)
+}
+
+// Encode Encodes checkpoint with GOB encoding
+func (c *Checkpoint) Encode(enc *gob.Encoder) error {
+	if err := enc.Encode(c.TrialId); err != nil {
+		return err
+	}
+	if err := enc.Encode(c.GenerationId); err != nil {
+		return err
+	}
+	if err := enc.Encode(c.RandSeed); err != nil {
+		return err
+	}
+	if err := encodeTrialWithDuration(enc, &c.Trial); err != nil {
+		return err
+	}
+
+	// encode completed trials
+	if err := enc.Encode(len(c.Trials)); err != nil {
+		return err
+	}
+	for i := range c.Trials {
+		if err := encodeTrialWithDuration(enc, &c.Trials[i]); err != nil {
+			return err
+		}
+	}
+
+	// encode population with its hall of fame if any
+	return c.Population.Encode(enc)
+}
+
+// ReadCheckpoint is to read checkpoint data from provided reader and decodes it
+func ReadCheckpoint(r io.Reader) (*Checkpoint, error) {
+	dec := gob.NewDecoder(r)
+	c := &Checkpoint{}
+	if err := c.Decode(dec); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ReadCheckpointFromFile is to read checkpoint data from the file at the given path
+func ReadCheckpointFromFile(path string) (*Checkpoint, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	return ReadCheckpoint(file)
+}
+
+// Decode Decodes checkpoint data
+func (c *Checkpoint) Decode(dec *gob.Decoder) error {
+	if err := dec.Decode(&c.TrialId); err != nil {
+		return errors.Wrap(err, "failed to decode TrialId")
+	}
+	if err := dec.Decode(&c.GenerationId); err != nil {
+		return errors.Wrap(err, "failed to decode GenerationId")
+	}
+	if err := dec.Decode(&c.RandSeed); err != nil {
+		return errors.Wrap(err, "failed to decode RandSeed")
+	}
+	if err := decodeTrialWithDuration(dec, &c.Trial); err != nil {
+		return errors.Wrap(err, "failed to decode trial in progress")
+	}
+
+	// decode completed trials
+	var tNum int
+	if err := dec.Decode(&tNum); err != nil {
+		return errors.Wrap(err, "failed to decode number of trials")
+	}
+	c.Trials = make(Trials, tNum)
+	for i := 0; i < tNum; i++ {
+		if err := decodeTrialWithDuration(dec, &c.Trials[i]); err != nil {
+			return errors.Wrapf(err, "failed to decode trial: %d", i)
+		}
+	}
+
+	// decode population with its hall of fame if any
+	c.Population = &genetics.Population{}
+	if err := c.Population.Decode(dec); err != nil {
+		return errors.Wrap(err, "failed to decode population")
+	}
+	return nil
+}
+
+// writeCheckpoint is to store checkpoint of the running experiment into the file at CheckpointPath. The checkpoint is
+// first written into temporary file which then replaces the previous one to avoid corrupted checkpoint if process
+// terminated while writing.
+func (e *Experiment) writeCheckpoint(checkpoint *Checkpoint) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(e.CheckpointPath), filepath.Base(e.CheckpointPath)+".*")
+	if err != nil {
+		return err
+	}
+	if err = checkpoint.Write(tmpFile); err != nil {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmpFile.Name())
+		return err
+	}
+	if err = tmpFile.Close(); err != nil {
+		_ = os.Remove(tmpFile.Name())
+		return err
+	}
+	return os.Rename(tmpFile.Name(), e.CheckpointPath)
+}
+
+func encodeTrialWithDuration(enc *gob.Encoder, t *Trial) error {
+	if err := t.Encode(enc); err != nil {
+		return err
+	}
+	return enc.Encode(t.Duration)
+}
+
+func decodeTrialWithDuration(dec *gob.Decoder, t *Trial) error {
+	if err := t.Decode(dec); err != nil {
+		return err
+	}
+	var duration time.Duration
+	if err := dec.Decode(&duration); err != nil {
+		return err
+	}
+	t.Duration = duration
+	return nil
+}
@@ -0,0 +1,160 @@
+package experiment
+
+import (
+	"bytes"
+	"context"
+	"deepneat/neat"
+	"deepneat/neat/genetics"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// complexityGenerationEvaluator assigns fitness proportional to the genome size and collects population statistics
+type complexityGenerationEvaluator struct {
+	evaluations int
+}
+
+func (e *complexityGenerationEvaluator) GenerationEvaluate(_ context.Context, pop *genetics.Population, epoch *Generation) error {
+	for _, org := range pop.Organisms {
+		org.Fitness = float64(len(org.Genotype.Genes) + len(org.Genotype.Nodes))
+	}
+	epoch.FillPopulationStatistics(pop)
+	e.evaluations++
+	return nil
+}
+
+func TestCheckpoint_Write_Read(t *testing.T) {
+	pop, _ := buildTestPopulation(t)
+	checkpoint := Checkpoint{
+		TrialId:      1,
+		GenerationId: 3,
+		RandSeed:     42,
+		Trial:        *buildTestTrial(1, 3),
+		Population:   pop,
+		Trials:       Trials{*buildTestTrial(0, 5)},
+	}
+	checkpoint.Trials[0].Duration = 100
+
+	var buf bytes.Buffer
+	err := checkpoint.Write(&buf)
+	require.NoError(t, err, "failed to write checkpoint")
+
+	restored, err := ReadCheckpoint(&buf)
+	require.NoError(t, err, "failed to read checkpoint")
+	assert.Equal(t, checkpoint.TrialId, restored.TrialId)
+	assert.Equal(t, checkpoint.GenerationId, restored.GenerationId)
+	assert.Equal(t, checkpoint.RandSeed, restored.RandSeed)
+	assert.Len(t, restored.Trial.Generations, len(checkpoint.Trial.Generations))
+	require.Len(t, restored.Trials, 1)
+	assert.Len(t, restored.Trials[0].Generations, 5)
+	assert.EqualValues(t, 100, restored.Trials[0].Duration)
+	require.NotNil(t, restored.Population)
+	assert.Len(t, restored.Population.Organisms, len(pop.Organisms))
+	assert.Len(t, restored.Population.Species, len(pop.Species))
+}
+
+func TestExperiment_Resume(t *testing.T) {
+	genome, err := readTestGenome()
+	require.NoError(t, err, "failed to read XOR genome")
+	opts, err := neat.ReadNeatOptionsFromFile(xorConfigPath)
+	require.NoError(t, err, "failed to read NEAT options")
+	opts.NumRuns = 2
+	opts.NumGenerations = 6
+	opts.CheckpointEvery = 4
+	ctx := neat.NewContext(context.Background(), opts)
+
+	checkpointPath := filepath.Join(t.TempDir(), CheckpointFileName)
+	exp := Experiment{
+		Id:             0,
+		CheckpointPath: checkpointPath,
+	}
+	err = exp.Execute(ctx, genome, &complexityGenerationEvaluator{}, nil)
+	require.NoError(t, err, "failed to execute experiment")
+
+	// the last checkpoint was stored in the second trial
+	_, err = os.Stat(checkpointPath)
+	require.NoError(t, err, "checkpoint file not found")
+	checkpoint, err := ReadCheckpointFromFile(checkpointPath)
+	require.NoError(t, err, "failed to read checkpoint")
+	assert.Equal(t, 1, checkpoint.TrialId)
+	assert.Equal(t, 4, checkpoint.GenerationId)
+	assert.Len(t, checkpoint.Trial.Generations, 4)
+	assert.Len(t, checkpoint.Trials, 1)
+
+	// resume experiment from checkpoint
+	resumed := Experiment{
+		Id: 0,
+	}
+	evaluator := &complexityGenerationEvaluator{}
+	err = resumed.Resume(ctx, genome, checkpoint, evaluator, nil)
+	require.NoError(t, err, "failed to resume experiment")
+	assert.Equal(t, opts.NumGenerations-checkpoint.GenerationId, evaluator.evaluations)
+	require.Len(t, resumed.Trials, opts.NumRuns)
+	for i, trial := range resumed.Trials {
+		assert.Len(t, trial.Generations, opts.NumGenerations, "trial: %d", i)
+		assert.Equal(t, i, trial.Id)
+	}
+}
+
+func TestExperiment_Resume_reproducible(t *testing.T) {
+	genome, err := readTestGenome()
+	require.NoError(t, err, "failed to read XOR genome")
+	opts, err := neat.ReadNeatOptionsFromFile(xorConfigPath)
+	require.NoError(t, err, "failed to read NEAT options")
+	opts.NumRuns = 1
+	opts.NumGenerations = 8
+	opts.HallOfFameSize = 5
+	ctx := neat.NewContext(context.Background(), opts)
+
+	// run uninterrupted experiment
+	expected := Experiment{RandSeed: 42}
+	err = expected.Execute(ctx, genome, &complexityGenerationEvaluator{}, nil)
+	require.NoError(t, err, "failed to execute experiment")
+
+	// run experiment storing checkpoint and resume it with different seed provided
+	opts.CheckpointEvery = 5
+	checkpointPath := filepath.Join(t.TempDir(), CheckpointFileName)
+	interrupted := Experiment{RandSeed: 42, CheckpointPath: checkpointPath}
+	err = interrupted.Execute(ctx, genome, &complexityGenerationEvaluator{}, nil)
+	require.NoError(t, err, "failed to execute experiment")
+	checkpoint, err := ReadCheckpointFromFile(checkpointPath)
+	require.NoError(t, err, "failed to read checkpoint")
+	assert.EqualValues(t, 42, checkpoint.RandSeed)
+	require.NotNil(t, checkpoint.Population.HallOfFame)
+	assert.Equal(t, opts.HallOfFameSize, checkpoint.Population.HallOfFame.Len())
+
+	resumed := Experiment{RandSeed: 1}
+	err = resumed.Resume(ctx, genome, checkpoint, &complexityGenerationEvaluator{}, nil)
+	require.NoError(t, err, "failed to resume experiment")
+	assert.EqualValues(t, 42, resumed.RandSeed)
+
+	require.Len(t, resumed.Trials, 1)
+	require.Len(t, resumed.Trials[0].Generations, opts.NumGenerations)
+	for i, generation := range expected.Trials[0].Generations {
+		actual := resumed.Trials[0].Generations[i]
+		assert.Equal(t, generation.Fitness, actual.Fitness, "fitness mismatch at generation: %d", i)
+		assert.Equal(t, generation.Complexity, actual.Complexity, "complexity mismatch at generation: %d", i)
+	}
+
+	// the hall of fame restored from checkpoint and updated further
+	require.NotNil(t, resumed.HallOfFame)
+	assert.Same(t, checkpoint.Population.HallOfFame, resumed.HallOfFame, "experiment must read hall of fame of population")
+	require.Equal(t, expected.HallOfFame.Len(), resumed.HallOfFame.Len())
+	for i, member := range expected.HallOfFame.Members() {
+		actual := resumed.HallOfFame.Members()[i]
+		assert.Equal(t, member.Hash, actual.Hash, "hall of fame member: %d", i)
+		assert.Equal(t, member.Organism.Fitness, actual.Organism.Fitness, "hall of fame member: %d", i)
+	}
+}
+
+func TestExperiment_Resume_emptyCheckpoint(t *testing.T) {
+	opts, err := neat.ReadNeatOptionsFromFile(xorConfigPath)
+	require.NoError(t, err, "failed to read NEAT options")
+	exp := Experiment{}
+	err = exp.Resume(neat.NewContext(context.Background(), opts), nil, &Checkpoint{}, &complexityGenerationEvaluator{}, nil)
+	assert.Error(t, err)
+}
@@ -31,17 +31,18 @@ type TrialRunObserver interface {
 	EpochEvaluated(trial *Trial, epoch *Generation)
 }
 
-// Returns appropriate executor type from given context
-func epochExecutorForContext(ctx context.Context) (genetics.PopulationEpochExecutor, error) {
+// Returns appropriate executor type from given context with provided stagnation policy, which can be nil to use
+// the default one
+func epochExecutorForContext(ctx context.Context, stagnation genetics.Stagnation) (genetics.PopulationEpochExecutor, error) {
 	options, ok := neat.FromContext(ctx)
 	if !ok {
 		return nil, neat.ErrNEATOptionsNotFound
 	}
 	switch options.EpochExecutorType {
 	case neat.EpochExecutorTypeSequential:
-		return &genetics.SequentialPopulationEpochExecutor{}, nil
+		return &genetics.SequentialPopulationEpochExecutor{Stagnation: stagnation}, nil
 	case neat.EpochExecutorTypeParallel:
-		return &genetics.ParallelPopulationEpochExecutor{}, nil
+		return &genetics.ParallelPopulationEpochExecutor{Stagnation: stagnation}, nil
 	default:
 		return nil, errors.New("unsupported epoch executor type requested")
 	}
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const alwaysErrorText = "always be failing"
@@ -28,7 +29,7 @@ func (e ErrorReader) Read(_ []byte) (n int, err error) {
 
 func Test_epochExecutorForContext_wrongContext(t *testing.T) {
 	ctx := context.Background()
-	_, err := epochExecutorForContext(ctx)
+	_, err := epochExecutorForContext(ctx, nil)
 	assert.Error(t, err, neat.ErrNEATOptionsNotFound.Error())
 }
 
@@ -36,7 +37,7 @@ func Test_epochExecutorForContext_wrongExecutorType(t *testing.T) {
 	options := neat.Options{}
 	options.EpochExecutorType = "not existing"
 	ctx := neat.NewContext(context.Background(), &options)
-	evaluator, err := epochExecutorForContext(ctx)
+	evaluator, err := epochExecutorForContext(ctx, nil)
 	assert.Error(t, err, "unsupported epoch executor type requested")
 	assert.Nil(t, evaluator)
 }
@@ -51,16 +52,19 @@ func Test_epochExecutorForContext(t *testing.T) {
 		options := neat.Options{}
 		options.EpochExecutorType = tc
 		ctx := neat.NewContext(context.Background(), &options)
-		evaluator, err := epochExecutorForContext(ctx)
+		stagnation := genetics.NewDefaultStagnation()
+		evaluator, err := epochExecutorForContext(ctx, stagnation)
 		assert.NoError(t, err)
 		assert.NotNil(t, evaluator)
 		switch tc {
 		case neat.EpochExecutorTypeSequential:
-			_, ok := evaluator.(*genetics.SequentialPopulationEpochExecutor)
-			assert.True(t, ok)
+			executor, ok := evaluator.(*genetics.SequentialPopulationEpochExecutor)
+			require.True(t, ok)
+			assert.Equal(t, stagnation, executor.Stagnation)
 		case neat.EpochExecutorTypeParallel:
-			_, ok := evaluator.(*genetics.ParallelPopulationEpochExecutor)
-			assert.True(t, ok)
+			executor, ok := evaluator.(*genetics.ParallelPopulationEpochExecutor)
+			require.True(t, ok)
+			assert.Equal(t, stagnation, executor.Stagnation)
 		}
 	}
 }
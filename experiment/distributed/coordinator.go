@@ -0,0 +1,283 @@
+package distributed
+
+import (
+	"bytes"
+	"context"
+	"deepneat/experiment"
+	"deepneat/neat"
+	"deepneat/neat/genetics"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// workerState The state of the remote worker as seen by coordinator
+type workerState struct {
+	// The base URL of the worker
+	url string
+	// The flag to indicate whether worker passed the last health check and didn't lose any work since
+	healthy bool
+}
+
+// permanentError The error of evaluation request which would fail on any worker, thus it should not be re-dispatched
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Coordinator dispatches evaluation of organisms to the remote workers. The workers are selected in round-robin
+// order among the healthy ones. If worker fails to return evaluation results, it is marked as unhealthy and the
+// organism is re-dispatched to another worker. The unhealthy workers are returned to service after passing the
+// health check.
+type Coordinator struct {
+	// The name of evaluator registered by workers to be used for organisms evaluation
+	Evaluator string
+	// The maximal number of workers tried to evaluate each organism before giving up
+	MaxAttempts int
+
+	// The HTTP client to send requests to workers
+	client *http.Client
+	// The known workers
+	workers []*workerState
+	// The index of the next worker to try
+	next int
+
+	mutex sync.Mutex
+}
+
+// NewCoordinator is to create new coordinator dispatching organisms to the workers at given base URLs to be evaluated
+// by the named evaluator. If client is nil, the default HTTP client is used. All workers are considered unhealthy until
+// the first health check.
+func NewCoordinator(evaluator string, workerURLs []string, client *http.Client) *Coordinator {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	c := &Coordinator{
+		Evaluator:   evaluator,
+		MaxAttempts: len(workerURLs),
+		client:      client,
+		workers:     make([]*workerState, len(workerURLs)),
+	}
+	for i, url := range workerURLs {
+		c.workers[i] = &workerState{url: url}
+	}
+	return c
+}
+
+// CheckHealth is to check health of all workers concurrently. The worker is healthy if it responds and has the
+// evaluator of this coordinator registered. Returns the number of healthy workers.
+func (c *Coordinator) CheckHealth(ctx context.Context) int {
+	results := make([]bool, len(c.workers))
+	var wg sync.WaitGroup
+	for i, worker := range c.workers {
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+			if err := c.checkWorker(ctx, url); err != nil {
+				neat.WarnLog(fmt.Sprintf("Worker at [%s] is unhealthy, reason: %s", url, err))
+			} else {
+				results[i] = true
+			}
+		}(i, worker.url)
+	}
+	wg.Wait()
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	healthy := 0
+	for i, worker := range c.workers {
+		worker.healthy = results[i]
+		if worker.healthy {
+			healthy++
+		}
+	}
+	return healthy
+}
+
+// RunHealthChecks is to check health of workers with given interval until context is canceled
+func (c *Coordinator) RunHealthChecks(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.CheckHealth(ctx)
+		}
+	}
+}
+
+// HealthyWorkers is to get base URLs of currently healthy workers
+func (c *Coordinator) HealthyWorkers() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	urls := make([]string, 0, len(c.workers))
+	for _, worker := range c.workers {
+		if worker.healthy {
+			urls = append(urls, worker.url)
+		}
+	}
+	return urls
+}
+
+// EvaluateOrganism is to evaluate given organism by one of the healthy workers and store results in the organism.
+// Returns true if organism is a winner. It can be used as experiment.OrganismEvaluateFunc.
+func (c *Coordinator) EvaluateOrganism(ctx context.Context, organism *genetics.Organism) (bool, error) {
+	request, err := NewEvaluationRequest(c.Evaluator, organism)
+	if err != nil {
+		return false, err
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return false, err
+	}
+
+	var lastErr error = ErrNoHealthyWorkers
+	for attempt := 0; attempt < max(c.MaxAttempts, 1); attempt++ {
+		worker := c.nextHealthyWorker()
+		if worker == nil {
+			// the workers may have recovered since the last check
+			if c.CheckHealth(ctx) == 0 {
+				return false, ErrNoHealthyWorkers
+			}
+			if worker = c.nextHealthyWorker(); worker == nil {
+				return false, ErrNoHealthyWorkers
+			}
+		}
+
+		result, err := c.evaluate(ctx, worker.url, body)
+		if err == nil && result.GenomeId != organism.Genotype.Id {
+			// the worker is misbehaving, its results can not be trusted
+			err = fmt.Errorf("%w: expected genome ID: %d, got: %d",
+				ErrResultMismatch, organism.Genotype.Id, result.GenomeId)
+		}
+		if err == nil {
+			if len(result.Failure) > 0 {
+				return false, errors.New(result.Failure)
+			}
+			result.apply(organism)
+			return result.Winner, nil
+		}
+		var permanentErr *permanentError
+		if ctx.Err() != nil || errors.As(err, &permanentErr) {
+			return false, err
+		}
+		// the work is lost, try another worker
+		neat.WarnLog(fmt.Sprintf("Worker at [%s] failed to evaluate organism with genome ID: %d, reason: %s",
+			worker.url, organism.Genotype.Id, err))
+		c.markUnhealthy(worker)
+		lastErr = err
+	}
+	return false, fmt.Errorf("failed to evaluate organism after %d attempts: %w", max(c.MaxAttempts, 1), lastErr)
+}
+
+// nextHealthyWorker is to select the next healthy worker in round-robin order or nil if there is no one
+func (c *Coordinator) nextHealthyWorker() *workerState {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for i := 0; i < len(c.workers); i++ {
+		worker := c.workers[(c.next+i)%len(c.workers)]
+		if worker.healthy {
+			c.next = (c.next + i + 1) % len(c.workers)
+			return worker
+		}
+	}
+	return nil
+}
+
+func (c *Coordinator) markUnhealthy(worker *workerState) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	worker.healthy = false
+}
+
+// checkWorker is to request health status of the worker at given URL
+func (c *Coordinator) checkWorker(ctx context.Context, url string) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url+HealthPath, nil)
+	if err != nil {
+		return err
+	}
+	response, err := c.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status: %s", response.Status)
+	}
+	var status HealthStatus
+	if err = json.NewDecoder(response.Body).Decode(&status); err != nil {
+		return err
+	}
+	for _, name := range status.Evaluators {
+		if name == c.Evaluator {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownEvaluator, c.Evaluator)
+}
+
+// evaluate is to send the evaluation request to the worker at given URL
+func (c *Coordinator) evaluate(ctx context.Context, url string, body []byte) (*EvaluationResult, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url+EvaluatePath, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	response, err := c.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = response.Body.Close() }()
+	if response.StatusCode >= http.StatusBadRequest && response.StatusCode < http.StatusInternalServerError {
+		message, _ := io.ReadAll(response.Body)
+		return nil, &permanentError{err: fmt.Errorf("evaluation request rejected with status: %s, reason: %s",
+			response.Status, bytes.TrimSpace(message))}
+	} else if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", response.Status)
+	}
+	var result EvaluationResult
+	if err = json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GenerationEvaluator is the generation evaluator dispatching organisms to the remote workers through the coordinator.
+// The number of organisms evaluated concurrently is defined by the EvaluationWorkers of the NEAT options and should
+// match the total capacity of workers.
+type GenerationEvaluator struct {
+	// The coordinator dispatching organisms to workers
+	coordinator *Coordinator
+	// The evaluator running dispatch concurrently
+	evaluator *experiment.ParallelGenerationEvaluator
+}
+
+// NewGenerationEvaluator is to create new generation evaluator using given coordinator. The timeout limits each
+// evaluation of organism including all re-dispatches, the zero value means no timeout.
+func NewGenerationEvaluator(coordinator *Coordinator, timeout time.Duration) *GenerationEvaluator {
+	return &GenerationEvaluator{
+		coordinator: coordinator,
+		evaluator:   experiment.NewParallelGenerationEvaluator(coordinator.EvaluateOrganism, timeout),
+	}
+}
+
+// GenerationEvaluate checks health of workers and evaluates all organisms of the population by them
+func (e *GenerationEvaluator) GenerationEvaluate(ctx context.Context, pop *genetics.Population, epoch *experiment.Generation) error {
+	if e.coordinator.CheckHealth(ctx) == 0 {
+		return ErrNoHealthyWorkers
+	}
+	return e.evaluator.GenerationEvaluate(ctx, pop, epoch)
+}
@@ -0,0 +1,138 @@
+package distributed
+
+import (
+	"deepneat/experiment"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoordinator_EvaluateOrganism_redispatch(t *testing.T) {
+	lost := httptest.NewServer(newTestWorker())
+	alive := httptest.NewServer(newTestWorker())
+	defer alive.Close()
+	ctx, pop := createTestPopulation(t, 6)
+
+	coordinator := NewCoordinator(testEvaluator, []string{lost.URL, alive.URL}, nil)
+	require.Equal(t, 2, coordinator.CheckHealth(ctx))
+
+	// the worker is lost after health check
+	lost.Close()
+	for _, org := range pop.Organisms {
+		_, err := coordinator.EvaluateOrganism(ctx, org)
+		require.NoError(t, err, "organism must be re-dispatched to alive worker")
+		assert.NotZero(t, org.Fitness)
+	}
+	assert.Equal(t, []string{alive.URL}, coordinator.HealthyWorkers())
+
+	// no workers left
+	alive.Close()
+	_, err := coordinator.EvaluateOrganism(ctx, pop.Organisms[0])
+	assert.Error(t, err)
+	assert.Empty(t, coordinator.HealthyWorkers())
+	_, err = coordinator.EvaluateOrganism(ctx, pop.Organisms[0])
+	assert.ErrorIs(t, err, ErrNoHealthyWorkers)
+}
+
+func TestCoordinator_EvaluateOrganism_resultMismatch(t *testing.T) {
+	// the worker returning results of another genome
+	worker := newTestWorker()
+	misbehaving := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != EvaluatePath {
+			worker.ServeHTTP(rw, r)
+			return
+		}
+		recorder := httptest.NewRecorder()
+		worker.ServeHTTP(recorder, r)
+		var result EvaluationResult
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&result))
+		result.GenomeId++
+		writeJSON(rw, result)
+	}))
+	defer misbehaving.Close()
+	alive := httptest.NewServer(newTestWorker())
+	ctx, pop := createTestPopulation(t, 2)
+
+	coordinator := NewCoordinator(testEvaluator, []string{misbehaving.URL, alive.URL}, nil)
+	require.Equal(t, 2, coordinator.CheckHealth(ctx))
+
+	// the results of misbehaving worker are rejected and organism is re-dispatched
+	org := pop.Organisms[0]
+	_, err := coordinator.EvaluateOrganism(ctx, org)
+	require.NoError(t, err)
+	remoteFitness := org.Fitness
+	_, err = evaluateTestOrganism(ctx, org)
+	require.NoError(t, err)
+	assert.Equal(t, org.Fitness, remoteFitness)
+	assert.Equal(t, []string{alive.URL}, coordinator.HealthyWorkers())
+
+	// only misbehaving worker left
+	alive.Close()
+	require.Equal(t, 1, coordinator.CheckHealth(ctx))
+	org = pop.Organisms[1]
+	org.Fitness = 0
+	_, err = coordinator.EvaluateOrganism(ctx, org)
+	assert.ErrorIs(t, err, ErrResultMismatch)
+	assert.Zero(t, org.Fitness, "results of another genome must not be applied")
+}
+
+func TestCoordinator_CheckHealth_unknownEvaluator(t *testing.T) {
+	server := httptest.NewServer(newTestWorker())
+	defer server.Close()
+	ctx, pop := createTestPopulation(t, 1)
+
+	coordinator := NewCoordinator("unknown", []string{server.URL}, nil)
+	assert.Equal(t, 0, coordinator.CheckHealth(ctx))
+
+	evaluator := NewGenerationEvaluator(coordinator, 0)
+	err := evaluator.GenerationEvaluate(ctx, pop, &experiment.Generation{})
+	assert.ErrorIs(t, err, ErrNoHealthyWorkers)
+}
+
+func TestGenerationEvaluator_localWorkers(t *testing.T) {
+	workers, err := StartLocalWorkers(3, func(_ int) *exec.Cmd {
+		cmd := exec.Command(os.Args[0], "-test.run=^$")
+		cmd.Env = append(os.Environ(), testWorkerEnv+"=1")
+		return cmd
+	})
+	require.NoError(t, err, "failed to start local workers")
+	defer workers.Stop()
+	require.Len(t, workers.URLs, 3)
+
+	ctx, pop := createTestPopulation(t, 12)
+	coordinator := NewCoordinator(testEvaluator, workers.URLs, nil)
+	evaluator := NewGenerationEvaluator(coordinator, 0)
+
+	epoch := experiment.Generation{Id: 1}
+	err = evaluator.GenerationEvaluate(ctx, pop, &epoch)
+	require.NoError(t, err)
+	assert.Len(t, coordinator.HealthyWorkers(), 3)
+
+	// check results and statistics are the same as with local evaluation
+	expectedFitness := make([]float64, len(pop.Organisms))
+	for i, org := range pop.Organisms {
+		expectedFitness[i] = org.Fitness
+		winner, err := evaluateTestOrganism(ctx, org)
+		require.NoError(t, err)
+		assert.Equal(t, expectedFitness[i], org.Fitness)
+		assert.Equal(t, winner, org.IsWinner)
+	}
+	assert.True(t, epoch.Solved)
+	assert.Equal(t, 3, epoch.Champion.Genotype.Id)
+
+	// the work of killed worker is re-dispatched
+	require.NoError(t, workers.Kill(1))
+	for i, org := range pop.Organisms {
+		org.Fitness = 0
+		_, err = coordinator.EvaluateOrganism(ctx, org)
+		require.NoError(t, err)
+		assert.Equal(t, expectedFitness[i], org.Fitness)
+	}
+	assert.Equal(t, []string{workers.URLs[0], workers.URLs[2]}, coordinator.HealthyWorkers())
+}
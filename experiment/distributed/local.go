@@ -0,0 +1,105 @@
+package distributed
+
+import (
+	"bufio"
+	"deepneat/neat"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// LocalWorkerStartTimeout is the maximal time to wait for the local worker process to become ready
+const LocalWorkerStartTimeout = 30 * time.Second
+
+// LocalWorkers is the harness running worker processes on the local host, which can stand in for remote hosts
+type LocalWorkers struct {
+	// The base URLs of started workers
+	URLs []string
+
+	// The commands of running worker processes
+	commands []*exec.Cmd
+}
+
+// StartLocalWorkers is to start given number of worker processes using commands created by provided factory. Each
+// process must print the line with WorkerReadyPrefix followed by its base URL to the standard output when ready,
+// as ListenAndServe does. If any process fails to start, all already started ones are stopped.
+func StartLocalWorkers(count int, command func(index int) *exec.Cmd) (*LocalWorkers, error) {
+	workers := &LocalWorkers{
+		URLs:     make([]string, 0, count),
+		commands: make([]*exec.Cmd, 0, count),
+	}
+	for i := 0; i < count; i++ {
+		url, cmd, err := startLocalWorker(command(i))
+		if err != nil {
+			workers.Stop()
+			return nil, fmt.Errorf("failed to start local worker: %d, reason: %w", i, err)
+		}
+		workers.URLs = append(workers.URLs, url)
+		workers.commands = append(workers.commands, cmd)
+	}
+	return workers, nil
+}
+
+// startLocalWorker is to start the worker process and wait until it reports its base URL
+func startLocalWorker(cmd *exec.Cmd) (string, *exec.Cmd, error) {
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", nil, err
+	}
+	if err = cmd.Start(); err != nil {
+		return "", nil, err
+	}
+
+	urlChan := make(chan string, 1)
+	go func() {
+		defer close(urlChan)
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			if line := scanner.Text(); strings.HasPrefix(line, WorkerReadyPrefix) {
+				urlChan <- strings.TrimPrefix(line, WorkerReadyPrefix)
+				// keep draining the output to not block the worker
+				_, _ = io.Copy(io.Discard, stdout)
+				return
+			}
+		}
+	}()
+
+	select {
+	case url, ok := <-urlChan:
+		if ok {
+			return url, cmd, nil
+		}
+		err = errors.New("worker process exited before ready")
+	case <-time.After(LocalWorkerStartTimeout):
+		err = fmt.Errorf("worker process is not ready after: %s", LocalWorkerStartTimeout)
+	}
+	_ = cmd.Process.Kill()
+	_ = cmd.Wait()
+	return "", nil, err
+}
+
+// Kill is to kill the worker process with given index, e.g., to simulate the lost host
+func (l *LocalWorkers) Kill(index int) error {
+	cmd := l.commands[index]
+	if cmd == nil {
+		return nil
+	}
+	l.commands[index] = nil
+	if err := cmd.Process.Kill(); err != nil {
+		return err
+	}
+	_ = cmd.Wait()
+	return nil
+}
+
+// Stop is to kill all running worker processes
+func (l *LocalWorkers) Stop() {
+	for i := range l.commands {
+		if err := l.Kill(i); err != nil {
+			neat.WarnLog(fmt.Sprintf("Failed to stop local worker: %d, reason: %s", i, err))
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Package distributed provides evaluation of organisms by the worker processes running on other hosts. The coordinator
+// sends the genome of each organism encoded in plain text format to one of the workers over HTTP. The worker evaluates
+// the organism with the evaluator registered under requested name and returns the results back.
+package distributed
+
+import (
+	"bytes"
+	"deepneat/neat/genetics"
+	"errors"
+	"strings"
+)
+
+const (
+	// HealthPath is the path of the worker endpoint reporting its health and registered evaluators
+	HealthPath = "/health"
+	// EvaluatePath is the path of the worker endpoint evaluating single organism
+	EvaluatePath = "/evaluate"
+)
+
+var (
+	// ErrNoHealthyWorkers The error to be raised when there is no healthy worker to dispatch the evaluation to
+	ErrNoHealthyWorkers = errors.New("no healthy workers available")
+	// ErrUnknownEvaluator The error to be raised when worker has no evaluator registered under requested name
+	ErrUnknownEvaluator = errors.New("unknown evaluator")
+	// ErrResultMismatch The error to be raised when worker returns the results of another organism than was sent
+	ErrResultMismatch = errors.New("evaluation result does not match organism")
+)
+
+// EvaluationRequest is the request to evaluate one organism sent by coordinator to the worker
+type EvaluationRequest struct {
+	// The name of registered evaluator to use
+	Evaluator string `json:"evaluator"`
+	// The generation of the organism
+	Generation int `json:"generation"`
+	// The genome of the organism encoded in plain text format
+	Genome string `json:"genome"`
+}
+
+// EvaluationResult is the results of organism evaluation returned by the worker
+type EvaluationResult struct {
+	// The ID of the evaluated genome
+	GenomeId int `json:"genome_id"`
+	// The fitness and error of the organism
+	Fitness float64 `json:"fitness"`
+	Error   float64 `json:"error"`
+	// The flag to indicate whether organism is a winner
+	Winner bool `json:"winner"`
+	// The behavior and objectives of the organism if recorded by evaluator
+	Behavior   []float64 `json:"behavior,omitempty"`
+	Objectives []float64 `json:"objectives,omitempty"`
+	// The reason of evaluation failure if any
+	Failure string `json:"failure,omitempty"`
+}
+
+// HealthStatus is the status reported by the healthy worker
+type HealthStatus struct {
+	// The names of evaluators registered by the worker
+	Evaluators []string `json:"evaluators"`
+}
+
+// NewEvaluationRequest is to create request to evaluate given organism by the named evaluator
+func NewEvaluationRequest(evaluator string, organism *genetics.Organism) (*EvaluationRequest, error) {
+	buf := bytes.NewBuffer(nil)
+	writer, err := genetics.NewGenomeWriter(buf, genetics.PlainGenomeEncoding)
+	if err != nil {
+		return nil, err
+	}
+	if err = writer.WriteGenome(organism.Genotype); err != nil {
+		return nil, err
+	}
+	return &EvaluationRequest{
+		Evaluator:  evaluator,
+		Generation: organism.Generation,
+		Genome:     buf.String(),
+	}, nil
+}
+
+// Organism is to create the organism from the genome encoded in this request
+func (r *EvaluationRequest) Organism() (*genetics.Organism, error) {
+	reader, err := genetics.NewGenomeReader(strings.NewReader(r.Genome), genetics.PlainGenomeEncoding)
+	if err != nil {
+		return nil, err
+	}
+	genome, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	return genetics.NewOrganism(0, genome, r.Generation)
+}
+
+// apply is to copy evaluation results to the given organism
+func (r *EvaluationResult) apply(organism *genetics.Organism) {
+	organism.Fitness = r.Fitness
+	organism.Error = r.Error
+	organism.Behavior = r.Behavior
+	organism.Objectives = r.Objectives
+}
@@ -0,0 +1,150 @@
+package distributed
+
+import (
+	"context"
+	"deepneat/experiment"
+	"deepneat/neat"
+	"deepneat/neat/genetics"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// WorkerReadyPrefix is the prefix of the line printed by the worker process when it is ready to accept requests. It
+// is followed by the base URL of the worker.
+const WorkerReadyPrefix = "worker listening on "
+
+// Worker is the HTTP handler evaluating organisms sent by the coordinator with registered evaluators
+type Worker struct {
+	// The NEAT options to be provided to evaluators within context, can be nil
+	options *neat.Options
+	// The registered evaluators by name
+	evaluators map[string]experiment.OrganismEvaluateFunc
+	// The handler of worker endpoints
+	mux *http.ServeMux
+
+	mutex sync.RWMutex
+}
+
+// NewWorker is to create new worker providing given NEAT options to the evaluators
+func NewWorker(options *neat.Options) *Worker {
+	w := &Worker{
+		options:    options,
+		evaluators: make(map[string]experiment.OrganismEvaluateFunc),
+		mux:        http.NewServeMux(),
+	}
+	w.mux.HandleFunc(HealthPath, w.handleHealth)
+	w.mux.HandleFunc(EvaluatePath, w.handleEvaluate)
+	return w
+}
+
+// Register is to register the organism evaluation function under given name
+func (w *Worker) Register(name string, evaluate experiment.OrganismEvaluateFunc) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.evaluators[name] = evaluate
+}
+
+// ServeHTTP implements http.Handler
+func (w *Worker) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	w.mux.ServeHTTP(rw, r)
+}
+
+func (w *Worker) handleHealth(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.mutex.RLock()
+	status := HealthStatus{Evaluators: make([]string, 0, len(w.evaluators))}
+	for name := range w.evaluators {
+		status.Evaluators = append(status.Evaluators, name)
+	}
+	w.mutex.RUnlock()
+	sort.Strings(status.Evaluators)
+	writeJSON(rw, status)
+}
+
+func (w *Worker) handleEvaluate(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var request EvaluationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(rw, fmt.Sprintf("failed to decode evaluation request: %s", err), http.StatusBadRequest)
+		return
+	}
+	w.mutex.RLock()
+	evaluate, ok := w.evaluators[request.Evaluator]
+	w.mutex.RUnlock()
+	if !ok {
+		http.Error(rw, fmt.Sprintf("%s: %s", ErrUnknownEvaluator, request.Evaluator), http.StatusBadRequest)
+		return
+	}
+	organism, err := request.Organism()
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("failed to decode organism: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	if w.options != nil {
+		ctx = neat.NewContext(ctx, w.options)
+	}
+	result := EvaluationResult{GenomeId: organism.Genotype.Id}
+	if result.Winner, err = safeEvaluate(ctx, evaluate, organism); err != nil {
+		result.Failure = err.Error()
+	} else {
+		result.Fitness = organism.Fitness
+		result.Error = organism.Error
+		result.Behavior = organism.Behavior
+		result.Objectives = organism.Objectives
+	}
+	writeJSON(rw, result)
+}
+
+// safeEvaluate is to run evaluation function recovering from panics
+func safeEvaluate(ctx context.Context, evaluate experiment.OrganismEvaluateFunc, organism *genetics.Organism) (winner bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			winner, err = false, fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return evaluate(ctx, organism)
+}
+
+// ListenAndServe is to start serving worker endpoints at given address until context is canceled. When worker is
+// ready to accept requests, the line with WorkerReadyPrefix followed by the base URL of the worker is printed to the
+// provided output. The port of the address can be zero to select any available one.
+func ListenAndServe(ctx context.Context, address string, worker *Worker, out io.Writer) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Handler: worker}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+	if _, err = fmt.Fprintf(out, "%shttp://%s\n", WorkerReadyPrefix, listener.Addr()); err != nil {
+		_ = listener.Close()
+		return err
+	}
+	if err = server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func writeJSON(rw http.ResponseWriter, value interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(value); err != nil {
+		neat.ErrorLog(fmt.Sprintf("Failed to write response, reason: %s", err))
+	}
+}
@@ -0,0 +1,147 @@
+package distributed
+
+import (
+	"bytes"
+	"context"
+	"deepneat/neat"
+	"deepneat/neat/genetics"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	xorConfigPath = "../../data/xor_test.neat.yml"
+
+	// the environment variable to run the test binary as worker process
+	testWorkerEnv = "DEEPNEAT_DISTRIBUTED_TEST_WORKER"
+
+	testEvaluator  = "genes"
+	panicEvaluator = "panic"
+)
+
+func TestMain(m *testing.M) {
+	if os.Getenv(testWorkerEnv) != "" {
+		if err := ListenAndServe(context.Background(), "127.0.0.1:0", newTestWorker(), os.Stdout); err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// evaluateTestOrganism is the deterministic organism evaluator for tests
+func evaluateTestOrganism(_ context.Context, organism *genetics.Organism) (bool, error) {
+	organism.Fitness = float64(len(organism.Genotype.Genes)) + organism.Genotype.Genes[0].Link.ConnectionWeight
+	organism.Error = float64(organism.Genotype.Id)
+	organism.Objectives = []float64{organism.Fitness, -organism.Error}
+	return organism.Genotype.Id == 3, nil
+}
+
+func newTestWorker() *Worker {
+	worker := NewWorker(nil)
+	worker.Register(testEvaluator, evaluateTestOrganism)
+	worker.Register(panicEvaluator, func(_ context.Context, _ *genetics.Organism) (bool, error) {
+		panic("evaluation panicked")
+	})
+	return worker
+}
+
+// createTestPopulation is to create population of organisms to be sent to workers. The organisms are spawned from the
+// fully connected seed genome with XOR inputs and outputs. The returned context holds the NEAT options required by the
+// generation evaluator.
+func createTestPopulation(t *testing.T, popSize int) (context.Context, *genetics.Population) {
+	opts, err := neat.ReadNeatOptionsFromFile(xorConfigPath)
+	require.NoError(t, err, "failed to read NEAT options")
+	opts.PopSize = popSize
+	spec := genetics.SeedGenomeSpec{NumInputs: 2, NumOutputs: 1, Bias: true, Scheme: genetics.SeedConnectionFull}
+	genome, err := genetics.NewSeedGenome(1, spec, opts)
+	require.NoError(t, err, "failed to create seed genome")
+	pop, err := genetics.NewPopulation(genome, opts)
+	require.NoError(t, err, "failed to create population")
+	return neat.NewContext(context.Background(), opts), pop
+}
+
+func TestWorker_evaluate(t *testing.T) {
+	server := httptest.NewServer(newTestWorker())
+	defer server.Close()
+	ctx, pop := createTestPopulation(t, 5)
+
+	coordinator := NewCoordinator(testEvaluator, []string{server.URL}, nil)
+	require.Equal(t, 1, coordinator.CheckHealth(ctx))
+	for _, org := range pop.Organisms {
+		winner, err := coordinator.EvaluateOrganism(ctx, org)
+		require.NoError(t, err)
+
+		expected, err := genetics.NewOrganism(0, org.Genotype, org.Generation)
+		require.NoError(t, err)
+		expectedWinner, err := evaluateTestOrganism(ctx, expected)
+		require.NoError(t, err)
+		assert.Equal(t, expectedWinner, winner)
+		assert.Equal(t, expected.Fitness, org.Fitness)
+		assert.Equal(t, expected.Error, org.Error)
+		assert.Equal(t, expected.Objectives, org.Objectives)
+	}
+}
+
+func TestWorker_failures(t *testing.T) {
+	server := httptest.NewServer(newTestWorker())
+	defer server.Close()
+	ctx, pop := createTestPopulation(t, 1)
+
+	// health lists registered evaluators
+	response, err := http.Get(server.URL + HealthPath)
+	require.NoError(t, err)
+	var status HealthStatus
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&status))
+	_ = response.Body.Close()
+	assert.Equal(t, []string{testEvaluator, panicEvaluator}, status.Evaluators)
+
+	// panic is reported as organism failure and worker stays healthy
+	coordinator := NewCoordinator(panicEvaluator, []string{server.URL}, nil)
+	require.Equal(t, 1, coordinator.CheckHealth(ctx))
+	_, err = coordinator.EvaluateOrganism(ctx, pop.Organisms[0])
+	assert.ErrorContains(t, err, "evaluation panicked")
+	assert.Len(t, coordinator.HealthyWorkers(), 1)
+
+	// unknown evaluator is rejected
+	request, err := NewEvaluationRequest("unknown", pop.Organisms[0])
+	require.NoError(t, err)
+	body, err := json.Marshal(request)
+	require.NoError(t, err)
+	response, err = http.Post(server.URL+EvaluatePath, "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	_ = response.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+
+	// wrong method
+	response, err = http.Get(server.URL + EvaluatePath)
+	require.NoError(t, err)
+	_ = response.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, response.StatusCode)
+}
+
+func TestEvaluationRequest_Organism(t *testing.T) {
+	_, pop := createTestPopulation(t, 1)
+	org := pop.Organisms[0]
+	org.Generation = 7
+
+	request, err := NewEvaluationRequest(testEvaluator, org)
+	require.NoError(t, err)
+	decoded, err := request.Organism()
+	require.NoError(t, err)
+	assert.Equal(t, 7, decoded.Generation)
+	assert.Equal(t, org.Genotype.Id, decoded.Genotype.Id)
+	assert.Len(t, decoded.Genotype.Nodes, len(org.Genotype.Nodes))
+	assert.Len(t, decoded.Genotype.Genes, len(org.Genotype.Genes))
+	for i, gene := range org.Genotype.Genes {
+		assert.Equal(t, gene.Link.ConnectionWeight, decoded.Genotype.Genes[i].Link.ConnectionWeight)
+	}
+}
@@ -0,0 +1,133 @@
+package experiment
+
+import (
+	"container/list"
+	"context"
+	"deepneat/neat/genetics"
+	"sync"
+)
+
+// CachedEvaluation is the results of organism evaluation stored in the EvaluationCache
+type CachedEvaluation struct {
+	// The fitness and error of the evaluated organism
+	Fitness float64
+	Error   float64
+	// The flag to indicate whether organism is a winner
+	Winner bool
+	// The behavior and objectives of the organism if recorded by evaluator
+	Behavior   []float64
+	Objectives []float64
+}
+
+// apply is to copy cached results into the given organism
+func (c *CachedEvaluation) apply(organism *genetics.Organism) {
+	organism.Fitness = c.Fitness
+	organism.Error = c.Error
+	organism.Behavior = append([]float64(nil), c.Behavior...)
+	organism.Objectives = append([]float64(nil), c.Objectives...)
+}
+
+// cacheEntry the entry of the cache list
+type cacheEntry struct {
+	hash       uint64
+	evaluation *CachedEvaluation
+}
+
+// EvaluationCache is the least recently used (LRU) cache of organism evaluation results keyed by the canonical hash
+// of the genome. It allows skipping re-evaluation of organisms with unchanged genomes, such as the super-champion
+// clones and the species champions copied into the next generation. It should only be used when evaluation is
+// deterministic, i.e., the same genome always gets the same fitness. It is safe for concurrent use.
+type EvaluationCache struct {
+	// The maximal number of cached evaluations
+	capacity int
+	// The cache entries by genome hash
+	entries map[uint64]*list.Element
+	// The entries from the most to the least recently used
+	order *list.List
+	// The total numbers of cache hits and misses
+	hits, misses int
+
+	mutex sync.Mutex
+}
+
+// NewEvaluationCache is to create new evaluation cache holding up to capacity evaluations. If capacity is not
+// positive, the cache holds nothing.
+func NewEvaluationCache(capacity int) *EvaluationCache {
+	return &EvaluationCache{
+		capacity: capacity,
+		entries:  make(map[uint64]*list.Element),
+		order:    list.New(),
+	}
+}
+
+// Get is to get the cached evaluation results of the genome with given hash
+func (c *EvaluationCache) Get(hash uint64) (*CachedEvaluation, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if element, ok := c.entries[hash]; ok {
+		c.order.MoveToFront(element)
+		c.hits++
+		return element.Value.(*cacheEntry).evaluation, true
+	}
+	c.misses++
+	return nil, false
+}
+
+// Put is to store evaluation results of the genome with given hash evicting the least recently used entry if cache is
+// full.
+func (c *EvaluationCache) Put(hash uint64, evaluation *CachedEvaluation) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.capacity <= 0 {
+		return
+	}
+	if element, ok := c.entries[hash]; ok {
+		element.Value.(*cacheEntry).evaluation = evaluation
+		c.order.MoveToFront(element)
+		return
+	}
+	if c.order.Len() >= c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).hash)
+	}
+	c.entries[hash] = c.order.PushFront(&cacheEntry{hash: hash, evaluation: evaluation})
+}
+
+// Len is to get the number of cached evaluations
+func (c *EvaluationCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.order.Len()
+}
+
+// Stats is to get the total numbers of cache hits and misses
+func (c *EvaluationCache) Stats() (hits, misses int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.hits, c.misses
+}
+
+// Evaluate is to evaluate given organism with provided function unless the results for the identical genome are
+// cached. The successful evaluation results are stored in the cache. Returns true as the second value if results
+// were taken from the cache.
+func (c *EvaluationCache) Evaluate(ctx context.Context, organism *genetics.Organism, evaluate OrganismEvaluateFunc) (winner, hit bool, err error) {
+	hash := organism.Genotype.Hash()
+	if cached, ok := c.Get(hash); ok {
+		cached.apply(organism)
+		return cached.Winner, true, nil
+	}
+	if winner, err = evaluate(ctx, organism); err != nil {
+		return false, false, err
+	}
+	if ctx.Err() == nil {
+		c.Put(hash, &CachedEvaluation{
+			Fitness:    organism.Fitness,
+			Error:      organism.Error,
+			Winner:     winner,
+			Behavior:   append([]float64(nil), organism.Behavior...),
+			Objectives: append([]float64(nil), organism.Objectives...),
+		})
+	}
+	return winner, false, nil
+}
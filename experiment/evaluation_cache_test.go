@@ -0,0 +1,120 @@
+package experiment
+
+import (
+	"context"
+	"deepneat/neat/genetics"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluationCache_GetPut(t *testing.T) {
+	cache := NewEvaluationCache(2)
+	cache.Put(1, &CachedEvaluation{Fitness: 1})
+	cache.Put(2, &CachedEvaluation{Fitness: 2})
+
+	// access the first one to make the second one the least recently used
+	evaluation, ok := cache.Get(1)
+	require.True(t, ok)
+	assert.Equal(t, 1.0, evaluation.Fitness)
+
+	cache.Put(3, &CachedEvaluation{Fitness: 3})
+	assert.Equal(t, 2, cache.Len())
+	_, ok = cache.Get(2)
+	assert.False(t, ok, "the least recently used must be evicted")
+	evaluation, ok = cache.Get(3)
+	require.True(t, ok)
+	assert.Equal(t, 3.0, evaluation.Fitness)
+
+	// update existing
+	cache.Put(3, &CachedEvaluation{Fitness: 4})
+	evaluation, ok = cache.Get(3)
+	require.True(t, ok)
+	assert.Equal(t, 4.0, evaluation.Fitness)
+	assert.Equal(t, 2, cache.Len())
+
+	hits, misses := cache.Stats()
+	assert.Equal(t, 3, hits)
+	assert.Equal(t, 1, misses)
+}
+
+func TestEvaluationCache_zeroCapacity(t *testing.T) {
+	cache := NewEvaluationCache(0)
+	cache.Put(1, &CachedEvaluation{Fitness: 1})
+	assert.Equal(t, 0, cache.Len())
+	_, ok := cache.Get(1)
+	assert.False(t, ok)
+}
+
+func TestEvaluationCache_Evaluate(t *testing.T) {
+	_, pop := createTestPopulation(t, 2, 1)
+	org, clone := pop.Organisms[0], pop.Organisms[1]
+	clone.Genotype = org.Genotype
+
+	evaluations := 0
+	evaluate := func(_ context.Context, organism *genetics.Organism) (bool, error) {
+		evaluations++
+		organism.Fitness = 10
+		organism.Error = 0.5
+		organism.Objectives = []float64{1, 2}
+		return true, nil
+	}
+	cache := NewEvaluationCache(10)
+	winner, hit, err := cache.Evaluate(context.Background(), org, evaluate)
+	require.NoError(t, err)
+	assert.True(t, winner)
+	assert.False(t, hit)
+
+	winner, hit, err = cache.Evaluate(context.Background(), clone, evaluate)
+	require.NoError(t, err)
+	assert.True(t, winner)
+	assert.True(t, hit)
+	assert.Equal(t, 1, evaluations)
+	assert.Equal(t, 10.0, clone.Fitness)
+	assert.Equal(t, 0.5, clone.Error)
+	assert.Equal(t, []float64{1, 2}, clone.Objectives)
+
+	// the failed evaluations are not cached
+	errFailed := errors.New("evaluation failed")
+	_, pop = createTestPopulation(t, 1, 1)
+	for i := 0; i < 2; i++ {
+		_, hit, err = cache.Evaluate(context.Background(), pop.Organisms[0], func(_ context.Context, _ *genetics.Organism) (bool, error) {
+			return false, errFailed
+		})
+		assert.ErrorIs(t, err, errFailed)
+		assert.False(t, hit)
+	}
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestParallelGenerationEvaluator_GenerationEvaluate_cache(t *testing.T) {
+	// evaluate sequentially to have deterministic hits of clones
+	ctx, pop := createTestPopulation(t, 6, 1)
+	// the clones of the first organism
+	pop.Organisms[3].Genotype = pop.Organisms[0].Genotype
+	pop.Organisms[5].Genotype = pop.Organisms[0].Genotype
+
+	evaluator := NewParallelGenerationEvaluator(func(_ context.Context, organism *genetics.Organism) (bool, error) {
+		organism.Fitness = float64(organism.Genotype.Id)
+		return false, nil
+	}, 0)
+	evaluator.Cache = NewEvaluationCache(100)
+
+	epoch := Generation{Id: 1}
+	err := evaluator.GenerationEvaluate(ctx, pop, &epoch)
+	require.NoError(t, err)
+	assert.Equal(t, 4, epoch.CacheMisses)
+	assert.Equal(t, 2, epoch.CacheHits)
+	for _, org := range pop.Organisms {
+		assert.Equal(t, float64(org.Genotype.Id), org.Fitness)
+	}
+
+	// all genomes are cached now
+	epoch = Generation{Id: 2}
+	err = evaluator.GenerationEvaluate(ctx, pop, &epoch)
+	require.NoError(t, err)
+	assert.Equal(t, 0, epoch.CacheMisses)
+	assert.Equal(t, 6, epoch.CacheHits)
+}
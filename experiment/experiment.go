@@ -23,6 +23,15 @@ type Experiment struct {
 	// It is used to normalize fitness score value used in efficiency score calculation. If this value
 	// is not set the fitness score will not be normalized during efficiency score estimation.
 	MaxFitnessScore float64
+	// The path to the file to periodically store checkpoints of the running experiment into. If not set, no
+	// checkpoints will be stored. The checkpoints frequency is defined by neat.Options.CheckpointEvery
+	CheckpointPath string
+	// The most fit distinct genomes found by the population of the last executed trial. It refers to the hall of fame
+	// kept by the population when neat.Options.HallOfFameSize is set.
+	HallOfFame *genetics.HallOfFame
+	// The policy of handling the stagnation of species and population, and of preserving the most fit organisms. If
+	// not set, the genetics.DefaultStagnation configured by the NEAT options is used.
+	Stagnation genetics.Stagnation
 }
 
 // AvgTrialDuration Calculates average duration of experiment's trial. Returns EmptyDuration for experiment with no trials.
@@ -407,6 +416,12 @@ func (e *Experiment) Decode(dec *gob.Decoder) error {
 // - trial_[0...n]_epoch_best_fitnesses - the best fitness scores per epoch per trial
 // the same for AGE and COMPLEXITY per epoch per trial
 // - trial_[0...n]_epoch_diversity - the number of species per epoch per trial
+// - trial_[0...n]_species_ids - the IDs of all species per trial
+// - trial_[0...n]_species_sizes - the sizes of species (columns in order of IDs) per epoch (rows) per trial
+// - trial_[0...n]_species_history - the rows of generation ID, species ID, size, max fitness, average fitness,
+// expected offspring, age, and age of last improvement of each species per epoch per trial
+// - trial_[0...n]_species_events - the rows of generation ID, species ID, and event (1 birth, -1 extinction) per trial
+// The species data is only written for trials with species statistics recorded.
 func (e *Experiment) WriteNPZ(w io.Writer) error {
 	// write general statistics
 	trialsFitness, trialsAges, trialsComplexity := e.fitnessAgeComplexityMat()
@@ -448,10 +463,36 @@ func (e *Experiment) WriteNPZ(w io.Writer) error {
 		if err := out.Write(fmt.Sprintf("trial_%d_epoch_diversity", i), t.Diversity()); err != nil {
 			return err
 		}
+		if err := writeSpeciesNPZ(out, i, &t); err != nil {
+			return err
+		}
 	}
 	return out.Close()
 }
 
+// writeSpeciesNPZ is to write species history of the trial with given index if recorded
+func writeSpeciesNPZ(out *npz.Writer, index int, t *Trial) error {
+	sizes, ids := t.SpeciesSizes()
+	if sizes == nil {
+		return nil
+	}
+	if err := out.Write(fmt.Sprintf("trial_%d_species_ids", index), ids); err != nil {
+		return err
+	}
+	if err := out.Write(fmt.Sprintf("trial_%d_species_sizes", index), sizes); err != nil {
+		return err
+	}
+	if err := out.Write(fmt.Sprintf("trial_%d_species_history", index), t.speciesHistoryMat()); err != nil {
+		return err
+	}
+	if events := t.speciesEventsMat(); events != nil {
+		if err := out.Write(fmt.Sprintf("trial_%d_species_events", index), events); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (e *Experiment) fitnessAgeComplexityMat() (trialsFitness, trialsAges, trialsComplexity *mat.Dense) {
 	trialsFitness = mat.NewDense(len(e.Trials), 2, nil)    // mean, var
 	trialsAges = mat.NewDense(len(e.Trials), 2, nil)       // mean, var
@@ -5,11 +5,27 @@ import (
 	"deepneat/neat"
 	"deepneat/neat/genetics"
 	"fmt"
+	"math/rand"
 	"time"
 )
 
 // Execute is to run specific experiment using provided startGenome and specific evaluator for each epoch of the experiment
 func (e *Experiment) Execute(ctx context.Context, startGenome *genetics.Genome, evaluator GenerationEvaluator, trialObserver TrialRunObserver) error {
+	return e.execute(ctx, startGenome, nil, evaluator, trialObserver)
+}
+
+// Resume is to continue execution of the experiment from the exact trial and generation stored in the provided checkpoint.
+// The startGenome is used to spawn populations of the trials that were not started before checkpoint was made. The random
+// seed of the experiment is restored from the checkpoint, thus the resumed experiment continues exactly as it would
+// without interruption.
+func (e *Experiment) Resume(ctx context.Context, startGenome *genetics.Genome, checkpoint *Checkpoint, evaluator GenerationEvaluator, trialObserver TrialRunObserver) error {
+	if checkpoint == nil || checkpoint.Population == nil {
+		return fmt.Errorf("can not resume experiment from empty checkpoint")
+	}
+	return e.execute(ctx, startGenome, checkpoint, evaluator, trialObserver)
+}
+
+func (e *Experiment) execute(ctx context.Context, startGenome *genetics.Genome, checkpoint *Checkpoint, evaluator GenerationEvaluator, trialObserver TrialRunObserver) error {
 	opts, found := neat.FromContext(ctx)
 	if !found {
 		return neat.ErrNEATOptionsNotFound
@@ -19,19 +35,47 @@ func (e *Experiment) Execute(ctx context.Context, startGenome *genetics.Genome,
 		e.Trials = make(Trials, opts.NumRuns)
 	}
 
-	for run := 0; run < opts.NumRuns; run++ {
+	startRun := 0
+	if checkpoint != nil {
+		if checkpoint.TrialId >= opts.NumRuns {
+			return fmt.Errorf("checkpoint trial: %d is out of experiment trials range: %d",
+				checkpoint.TrialId, opts.NumRuns)
+		}
+		startRun = checkpoint.TrialId
+		copy(e.Trials, checkpoint.Trials)
+		e.RandSeed = checkpoint.RandSeed
+	}
+
+	for run := startRun; run < opts.NumRuns; run++ {
 		trialStartTime := time.Now()
 
-		neat.InfoLog("\n>>>>> Spawning new population ")
-		pop, err := genetics.NewPopulation(startGenome, opts)
-		if err != nil {
-			neat.InfoLog("Failed to spawn new population from start genome")
-			return err
+		var pop *genetics.Population
+		var trial Trial
+		startGeneration := 0
+		if checkpoint != nil && run == checkpoint.TrialId {
+			neat.InfoLog(fmt.Sprintf("\n>>>>> Resuming population from checkpoint at generation: %d ",
+				checkpoint.GenerationId))
+			pop = checkpoint.Population
+			trial = checkpoint.Trial
+			startGeneration = checkpoint.GenerationId
+			// account the time spent in this trial before checkpoint
+			trialStartTime = trialStartTime.Add(-checkpoint.Trial.Duration)
 		} else {
-			neat.InfoLog("OK <<<<<")
+			neat.InfoLog("\n>>>>> Spawning new population ")
+			var err error
+			if pop, err = genetics.NewPopulation(startGenome, e.randOptions(opts, run, -1)); err != nil {
+				neat.InfoLog("Failed to spawn new population from start genome")
+				return err
+			} else {
+				neat.InfoLog("OK <<<<<")
+			}
+			// start new trial
+			trial = Trial{
+				Id: run,
+			}
 		}
 		neat.InfoLog(">>>>> Verifying spawned population ")
-		_, err = pop.Verify()
+		_, err := pop.Verify()
 		if err != nil {
 			neat.ErrorLog("\n!!!!! Population verification failed !!!!!")
 			return err
@@ -40,21 +84,16 @@ func (e *Experiment) Execute(ctx context.Context, startGenome *genetics.Genome,
 		}
 
 		// create appropriate population's epoch executor
-		epochExecutor, err := epochExecutorForContext(ctx)
+		epochExecutor, err := epochExecutorForContext(ctx, e.Stagnation)
 		if err != nil {
 			return err
 		}
 
-		// start new trial
-		trial := Trial{
-			Id: run,
-		}
-
 		if trialObserver != nil {
 			trialObserver.TrialRunStarted(&trial) // optional
 		}
 
-		for generationId := 0; generationId < opts.NumGenerations; generationId++ {
+		for generationId := startGeneration; generationId < opts.NumGenerations; generationId++ {
 			// check if context was canceled
 			select {
 			case <-ctx.Done():
@@ -63,27 +102,41 @@ func (e *Experiment) Execute(ctx context.Context, startGenome *genetics.Genome,
 			}
 
 			neat.InfoLog(fmt.Sprintf(">>>>> Generation:%3d\tRun: %d\n", generationId, run))
+			genCtx := e.randContext(ctx, opts, run, generationId)
 			generation := Generation{
 				Id:      generationId,
 				TrialId: run,
 			}
 			genStartTime := time.Now()
-			err = evaluator.GenerationEvaluate(ctx, pop, &generation)
+			err = evaluator.GenerationEvaluate(genCtx, pop, &generation)
 			if err != nil {
 				neat.InfoLog(fmt.Sprintf("!!!!! Generation [%d] evaluation failed !!!!!\n", generationId))
 				return err
 			}
 			generation.Executed = time.Now()
+			if generation.CacheHits+generation.CacheMisses > 0 {
+				neat.DebugLog(fmt.Sprintf("Evaluation cache hits: %d, misses: %d\n",
+					generation.CacheHits, generation.CacheMisses))
+			}
 
 			// Turnover population of organisms to the next epoch if appropriate
-			if !generation.Solved {
+			if generation.Solved {
+				// the hall of fame of population is updated by the epoch, which is not executed for the winner generation
+				if err = pop.UpdateHallOfFame(generationId, opts); err != nil {
+					return err
+				}
+			} else {
 				neat.DebugLog(">>>>> start next generation")
-				err = epochExecutor.NextEpoch(ctx, generationId, pop)
+				// keep evaluated species to get offspring allotted to them, because extinct ones are removed
+				evaluatedSpecies := append([]*genetics.Species(nil), pop.Species...)
+				err = epochExecutor.NextEpoch(genCtx, generationId, pop)
 				if err != nil {
 					neat.InfoLog(fmt.Sprintf("!!!!! Epoch execution failed in generation [%d] !!!!!\n", generationId))
 					return err
 				}
+				generation.fillExpectedOffspring(evaluatedSpecies)
 			}
+			e.HallOfFame = pop.HallOfFame
 
 			// Set generation duration, which also includes preparation for the next epoch
 			generation.Duration = generation.Executed.Sub(genStartTime)
@@ -104,6 +157,24 @@ func (e *Experiment) Execute(ctx context.Context, startGenome *genetics.Genome,
 				}
 				break
 			}
+
+			// store checkpoint if appropriate
+			if len(e.CheckpointPath) > 0 && opts.CheckpointEvery > 0 && (generationId+1)%opts.CheckpointEvery == 0 {
+				trial.Duration = time.Since(trialStartTime)
+				checkpoint := Checkpoint{
+					TrialId:      run,
+					GenerationId: generationId + 1,
+					RandSeed:     e.RandSeed,
+					Trial:        trial,
+					Population:   pop,
+					Trials:       e.Trials[:run],
+				}
+				if err = e.writeCheckpoint(&checkpoint); err != nil {
+					neat.ErrorLog(fmt.Sprintf("!!!!! Failed to store checkpoint at generation [%d] !!!!!\n", generationId))
+					return err
+				}
+				neat.InfoLog(fmt.Sprintf(">>>>> Checkpoint stored at generation [%d] to: %s\n", generationId, e.CheckpointPath))
+			}
 		}
 		// holds trial duration
 		trial.Duration = time.Since(trialStartTime)
@@ -119,3 +190,35 @@ func (e *Experiment) Execute(ctx context.Context, startGenome *genetics.Genome,
 
 	return nil
 }
+
+// randContext is to get the context with NEAT options holding the random source specific to the given trial and
+// generation of the experiment. If the random seed of the experiment is not set, the provided context is returned as is.
+func (e *Experiment) randContext(ctx context.Context, opts *neat.Options, trialId, generationId int) context.Context {
+	if e.RandSeed == 0 {
+		return ctx
+	}
+	return neat.NewContext(ctx, e.randOptions(opts, trialId, generationId))
+}
+
+// randOptions is to get NEAT options with the random source specific to the given trial and generation of the
+// experiment. The random source is seeded with value derived from the experiment random seed, the trial ID, and the
+// generation ID, thus the random stream of each generation doesn't depend on how many random numbers were consumed
+// before, and the experiment can be resumed from any checkpoint with exactly the same random streams. If the random
+// seed of the experiment is not set, the provided options are returned as is.
+func (e *Experiment) randOptions(opts *neat.Options, trialId, generationId int) *neat.Options {
+	if e.RandSeed == 0 {
+		return opts
+	}
+	seed := mixSeed(uint64(e.RandSeed))
+	seed = mixSeed(seed ^ uint64(trialId))
+	seed = mixSeed(seed ^ uint64(generationId))
+	return opts.WithRandSource(rand.New(rand.NewSource(int64(seed))))
+}
+
+// mixSeed is the SplitMix64 finalizer used to derive well distributed seeds from the sequential values
+func mixSeed(z uint64) uint64 {
+	z += 0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
@@ -127,3 +127,40 @@ func TestExperiment_Execute_evaluation_error(t *testing.T) {
 	genEvaluator.AssertNumberOfCalls(t, "GenerationEvaluate", 1)
 	genEvaluator.AssertExpectations(t)
 }
+
+func TestExperiment_Execute_speciesHistory(t *testing.T) {
+	exp := Experiment{
+		Id: 0,
+	}
+	genome, err := readTestGenome()
+	require.NoError(t, err, "failed to read XOR genome")
+	opts, err := neat.ReadNeatOptionsFromFile(xorConfigPath)
+	require.NoError(t, err, "failed to read NEAT options")
+	opts.NumRuns = 1
+	opts.NumGenerations = 5
+	ctx := neat.NewContext(context.Background(), opts)
+
+	genEvaluator := &MockedGenerationEvaluator{}
+	genEvaluator.On("GenerationEvaluate", ctx, mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		pop, epoch := args.Get(1).(*genetics.Population), args.Get(2).(*Generation)
+		for _, org := range pop.Organisms {
+			org.Fitness = float64(org.Genotype.Id%10 + 1)
+		}
+		epoch.FillPopulationStatistics(pop)
+	})
+
+	err = exp.Execute(ctx, genome, genEvaluator, nil)
+	require.NoError(t, err, "failed to execute experiment")
+	require.Len(t, exp.Trials, 1)
+	require.Len(t, exp.Trials[0].Generations, opts.NumGenerations)
+	for _, g := range exp.Trials[0].Generations {
+		require.Len(t, g.Species, g.Diversity)
+		size, offspring := 0, 0
+		for _, stats := range g.Species {
+			size += stats.Size
+			offspring += stats.ExpectedOffspring
+		}
+		assert.Equal(t, opts.PopSize, size, "wrong population size in generation: %d", g.Id)
+		assert.Equal(t, opts.PopSize, offspring, "wrong offspring in generation: %d", g.Id)
+	}
+}
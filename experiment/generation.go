@@ -20,8 +20,12 @@ type Generation struct {
 	Executed time.Time
 	// The elapsed time between generation execution start and finish
 	Duration time.Duration
-	// The best organism of the best species (probably successful solver if Solved flag set).
+	// The best organism of the best species (probably successful solver if Solved flag set). With noisy fitness, it's
+	// the estimated champion, which fitness can be lucky.
 	Champion *genetics.Organism
+	// The most fit organism among re-evaluated elites, which fitness estimates accumulated samples from several
+	// generations, or nil if there are no re-evaluated organisms. With noisy fitness, it's the validated champion.
+	ValidatedChampion *genetics.Organism
 	// The flag to indicate whether experiment was solved in this epoch
 	Solved bool
 
@@ -34,6 +38,14 @@ type Generation struct {
 
 	// The number of species in population at the end of this epoch
 	Diversity int
+	// The compatibility threshold used to speciate population evaluated in this epoch
+	CompatThreshold float64
+	// The statistics of every species in population at the end of this epoch
+	Species []SpeciesStats
+
+	// The objectives of organisms in the Pareto front of population if organisms have objectives recorded. It can be
+	// used to plot trade-off between objectives, e.g., solution size and score.
+	ParetoFront []Floats
 
 	// The number of evaluations done before winner (champion solver) found
 	WinnerEvals int
@@ -42,6 +54,11 @@ type Generation struct {
 	// The numbers of genes (links) in the genome of the winner (champion solver) or zero if not solved
 	WinnerGenes int
 
+	// The number of organisms which got evaluation results from the evaluation cache
+	CacheHits int
+	// The number of organisms evaluated due to missing results in the evaluation cache
+	CacheMisses int
+
 	// The ID of Trial this Generation was evaluated in
 	TrialId int
 }
@@ -50,11 +67,15 @@ type Generation struct {
 func (g *Generation) FillPopulationStatistics(pop *genetics.Population) {
 	maxFitness := float64(math.MinInt64)
 	g.Diversity = len(pop.Species)
+	g.CompatThreshold = pop.SpeciationCompatThreshold
 	g.Age = make(Floats, g.Diversity)
 	g.Complexity = make(Floats, g.Diversity)
 	g.Fitness = make(Floats, g.Diversity)
+	g.Species = make([]SpeciesStats, g.Diversity)
+	g.ValidatedChampion = nil
 	for i, currSpecies := range pop.Species {
 		g.Age[i] = float64(currSpecies.Age)
+		g.Species[i] = newSpeciesStats(currSpecies)
 		// sort organisms from current species by fitness to have most fit first
 		sort.Sort(sort.Reverse(currSpecies.Organisms))
 		g.Complexity[i] = float64(organismComplexity(currSpecies.Organisms[0]))
@@ -67,6 +88,26 @@ func (g *Generation) FillPopulationStatistics(pop *genetics.Population) {
 				g.Champion = currSpecies.Organisms[0]
 			}
 		}
+		// the organisms are sorted, thus the first re-evaluated is the most fit in species
+		for _, org := range currSpecies.Organisms {
+			if org.Reevaluations > 0 {
+				if g.ValidatedChampion == nil || org.Fitness > g.ValidatedChampion.Fitness {
+					g.ValidatedChampion = org
+				}
+				break
+			}
+		}
+	}
+
+	// store objectives of the Pareto front if available
+	g.ParetoFront = nil
+	if len(pop.Organisms) > 0 && len(pop.Organisms[0].Objectives) > 0 {
+		if front, err := genetics.ParetoFront(pop.Organisms); err == nil {
+			g.ParetoFront = make([]Floats, len(front))
+			for i, org := range front {
+				g.ParetoFront[i] = append(Floats(nil), org.Objectives...)
+			}
+		}
 	}
 }
 
@@ -126,6 +167,29 @@ func (g *Generation) Encode(enc *gob.Encoder) error {
 	if err := enc.EncodeValue(reflect.ValueOf(g.TrialId)); err != nil {
 		return err
 	}
+	if err := enc.Encode(g.ParetoFront); err != nil {
+		return err
+	}
+	if err := enc.EncodeValue(reflect.ValueOf(g.CacheHits)); err != nil {
+		return err
+	}
+	if err := enc.EncodeValue(reflect.ValueOf(g.CacheMisses)); err != nil {
+		return err
+	}
+	if err := enc.Encode(g.Species); err != nil {
+		return err
+	}
+	if err := enc.EncodeValue(reflect.ValueOf(g.CompatThreshold)); err != nil {
+		return err
+	}
+	if err := enc.Encode(g.ValidatedChampion != nil); err != nil {
+		return err
+	}
+	if g.ValidatedChampion != nil {
+		if err := encodeOrganism(enc, g.ValidatedChampion); err != nil {
+			return err
+		}
+	}
 
 	// encode best organism
 	if g.Champion != nil {
@@ -152,6 +216,12 @@ func encodeOrganism(enc *gob.Encoder, org *genetics.Organism) error {
 	if err := enc.Encode(org.Error); err != nil {
 		return err
 	}
+	if err := enc.Encode(org.FitnessVariance); err != nil {
+		return err
+	}
+	if err := enc.Encode(org.Reevaluations); err != nil {
+		return err
+	}
 
 	// encode organism genome
 	if org.Genotype != nil {
@@ -207,6 +277,32 @@ func (g *Generation) Decode(dec *gob.Decoder) error {
 	if err := dec.Decode(&g.TrialId); err != nil {
 		return errors.Wrap(err, "failed to decode TrialId")
 	}
+	if err := dec.Decode(&g.ParetoFront); err != nil {
+		return errors.Wrap(err, "failed to decode ParetoFront")
+	}
+	if err := dec.Decode(&g.CacheHits); err != nil {
+		return errors.Wrap(err, "failed to decode CacheHits")
+	}
+	if err := dec.Decode(&g.CacheMisses); err != nil {
+		return errors.Wrap(err, "failed to decode CacheMisses")
+	}
+	if err := dec.Decode(&g.Species); err != nil {
+		return errors.Wrap(err, "failed to decode Species")
+	}
+	if err := dec.Decode(&g.CompatThreshold); err != nil {
+		return errors.Wrap(err, "failed to decode CompatThreshold")
+	}
+	var validated bool
+	if err := dec.Decode(&validated); err != nil {
+		return errors.Wrap(err, "failed to decode ValidatedChampion presence")
+	}
+	if validated {
+		if org, err := decodeOrganism(dec); err != nil {
+			return err
+		} else {
+			g.ValidatedChampion = org
+		}
+	}
 
 	// decode organism
 	if org, err := decodeOrganism(dec); err != nil {
@@ -234,6 +330,12 @@ func decodeOrganism(dec *gob.Decoder) (*genetics.Organism, error) {
 	if err := dec.Decode(&org.Error); err != nil {
 		return nil, errors.Wrap(err, "failed to decode Generation")
 	}
+	if err := dec.Decode(&org.FitnessVariance); err != nil {
+		return nil, errors.Wrap(err, "failed to decode FitnessVariance")
+	}
+	if err := dec.Decode(&org.Reevaluations); err != nil {
+		return nil, errors.Wrap(err, "failed to decode Reevaluations")
+	}
 
 	// decode organism genome
 	var genId int
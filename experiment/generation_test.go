@@ -37,6 +37,8 @@ func TestGeneration_FillPopulationStatistics(t *testing.T) {
 	gen.FillPopulationStatistics(pop)
 	expectedSpecies := 5
 	assert.Equal(t, expectedSpecies, gen.Diversity, "wrong diversity")
+	assert.Equal(t, pop.SpeciationCompatThreshold, gen.CompatThreshold)
+	assert.NotZero(t, gen.CompatThreshold)
 	assert.Equal(t, expectedSpecies, len(gen.Fitness))
 	assert.Equal(t, expectedSpecies, len(gen.Age))
 	assert.EqualValues(t, Floats{1, 1, 1, 1, 1}, gen.Age)
@@ -44,6 +46,45 @@ func TestGeneration_FillPopulationStatistics(t *testing.T) {
 	assert.EqualValues(t, Floats{11, 25, 36, 32, 35}, gen.Complexity)
 	assert.NotNil(t, gen.Champion)
 	assert.Equal(t, maxFitness, gen.Champion.Fitness)
+	assert.Nil(t, gen.ValidatedChampion, "no re-evaluated organisms")
+}
+
+func TestGeneration_FillPopulationStatistics_validatedChampion(t *testing.T) {
+	rand.Seed(42)
+	pop, maxFitness := buildTestPopulation(t)
+	var expected *genetics.Organism
+	for i, org := range pop.Organisms {
+		if i%3 == 0 && org.Fitness < maxFitness {
+			org.Reevaluations = 1
+			if expected == nil || org.Fitness > expected.Fitness {
+				expected = org
+			}
+		}
+	}
+	require.NotNil(t, expected)
+	gen := Generation{Id: 1, TrialId: 1}
+	gen.FillPopulationStatistics(pop)
+	assert.Equal(t, maxFitness, gen.Champion.Fitness)
+	assert.Same(t, expected, gen.ValidatedChampion)
+}
+
+func TestGeneration_FillPopulationStatistics_paretoFront(t *testing.T) {
+	rand.Seed(42)
+	pop, _ := buildTestPopulation(t)
+	for i, org := range pop.Organisms {
+		org.Objectives = []float64{float64(i % 10), -float64(i % 10)}
+	}
+	gen := Generation{
+		Id:      1,
+		TrialId: 1,
+	}
+	gen.FillPopulationStatistics(pop)
+	// each organism with unique objectives is non-dominated
+	assert.Len(t, gen.ParetoFront, len(pop.Organisms))
+	for _, objectives := range gen.ParetoFront {
+		assert.Len(t, objectives, 2)
+		assert.Equal(t, objectives[0], -objectives[1])
+	}
 }
 
 func createGenerationWith(fitness Floats, ages Floats, complexities Floats) *Generation {
@@ -158,10 +199,21 @@ func buildTestGenerationWithDuration(genId int, fitness float64, duration time.D
 	epoch.WinnerNodes = testWinnerNodes
 	epoch.WinnerGenes = testWinnerGenes
 	epoch.Duration = duration
+	epoch.ParetoFront = []Floats{{fitness, -float64(testWinnerNodes)}, {1.0, -1.0}}
+	epoch.CacheHits = 3
+	epoch.CacheMisses = 7
+	epoch.CompatThreshold = 3.5
+	epoch.Species = []SpeciesStats{
+		{Id: 1, Size: 10, MaxFitness: fitness, AvgFitness: fitness / 2, ExpectedOffspring: 12, Age: 3, AgeOfLastImprovement: 2},
+		{Id: 4, Size: 5, MaxFitness: 1.0, AvgFitness: 0.5, Age: 1, AgeOfLastImprovement: 1},
+	}
 
 	genome := buildTestGenome(genId)
-	org := genetics.Organism{Fitness: fitness, Genotype: genome, Generation: genId, IsWinner: true}
+	org := genetics.Organism{Fitness: fitness, Genotype: genome, Generation: genId, IsWinner: true, FitnessVariance: 0.25}
 	epoch.Champion = &org
+	validated := genetics.Organism{Fitness: fitness / 2, Genotype: buildTestGenome(genId), Generation: genId - 1,
+		FitnessVariance: 0.5, Reevaluations: 2}
+	epoch.ValidatedChampion = &validated
 
 	return &epoch
 }
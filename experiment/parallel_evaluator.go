@@ -0,0 +1,246 @@
+package experiment
+
+import (
+	"context"
+	"deepneat/neat"
+	"deepneat/neat/genetics"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// OrganismEvaluateFunc is the function to evaluate the given organism. It should set fitness and error of the organism
+// and return true if the organism is a winner, i.e., solves the task. The provided context is canceled when the
+// evaluation timeout expires and the function should return as soon as possible in this case.
+type OrganismEvaluateFunc func(ctx context.Context, organism *genetics.Organism) (bool, error)
+
+// OrganismEvaluationError is the error returned when evaluation of the particular organism failed, timed out or
+// panicked.
+type OrganismEvaluationError struct {
+	// The ID of the genome of the failed organism
+	GenomeId int
+	// The cause of failure
+	Err error
+}
+
+func (e *OrganismEvaluationError) Error() string {
+	return fmt.Sprintf("failed to evaluate organism with genome ID: %d, reason: %s", e.GenomeId, e.Err)
+}
+
+func (e *OrganismEvaluationError) Unwrap() error {
+	return e.Err
+}
+
+// ParallelGenerationEvaluator is the generation evaluator running the provided organism evaluation function on the
+// bounded pool of workers. The number of workers is defined by the EvaluationWorkers of the NEAT options. Each
+// evaluation of organism runs within its own context, which is canceled after Timeout if set. The panics of evaluation
+// function are recovered and reported as errors of the corresponding organisms. After all organisms are evaluated,
+// the winners are marked, the fittest winner becomes the champion of the generation, and the population statistics
+// are collected. If Cache is set, the organisms with genomes identical to the already evaluated ones get the cached
+// results, and the numbers of cache hits and misses are stored in the generation statistics. If the fitness is noisy,
+// each organism is evaluated EvaluationRepeats times of the NEAT options and its fitness is aggregated from the
+// samples, see EvaluateOrganism.
+type ParallelGenerationEvaluator struct {
+	// The function to evaluate each organism
+	Evaluate OrganismEvaluateFunc
+	// The maximal duration of each evaluation of organism, i.e., the organism evaluated repeatedly gets this time for
+	// every repeat. The evaluation ignoring the context is abandoned after timeout. Zero value means no timeout.
+	Timeout time.Duration
+	// If set, the failed organisms get zero fitness and their errors are logged, otherwise the errors of all failed
+	// organisms are returned joined after all organisms are evaluated.
+	TolerateFailures bool
+	// The optional cache of evaluation results, it should only be set if evaluation is deterministic
+	Cache *EvaluationCache
+}
+
+// NewParallelGenerationEvaluator is to create new parallel generation evaluator with given organism evaluation
+// function and timeout.
+func NewParallelGenerationEvaluator(evaluate OrganismEvaluateFunc, timeout time.Duration) *ParallelGenerationEvaluator {
+	return &ParallelGenerationEvaluator{
+		Evaluate: evaluate,
+		Timeout:  timeout,
+	}
+}
+
+// GenerationEvaluate evaluates all organisms of the population concurrently and fills statistics of the given epoch.
+func (e *ParallelGenerationEvaluator) GenerationEvaluate(ctx context.Context, pop *genetics.Population, epoch *Generation) error {
+	options, ok := neat.FromContext(ctx)
+	if !ok {
+		return neat.ErrNEATOptionsNotFound
+	}
+	workers := options.EvaluationWorkers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	workers = min(workers, len(pop.Organisms))
+	if options.IsFitnessNoisy() && e.Cache != nil {
+		return errors.New("evaluation cache can not be used with noisy fitness")
+	}
+
+	winners := make([]bool, len(pop.Organisms))
+	hits := make([]bool, len(pop.Organisms))
+	errs := make([]error, len(pop.Organisms))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				winners[i], hits[i], errs[i] = e.evaluateOrganism(ctx, pop.Organisms[i], options)
+			}
+		}()
+	}
+
+	// dispatch organisms to workers until all evaluated or parent context canceled
+	var dispatchErr error
+dispatch:
+	for i := range pop.Organisms {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			dispatchErr = ctx.Err()
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if dispatchErr != nil {
+		return dispatchErr
+	}
+
+	// check failures
+	failures := make([]error, 0)
+	epoch.CacheHits, epoch.CacheMisses = 0, 0
+	for i, org := range pop.Organisms {
+		if e.Cache != nil {
+			if hits[i] {
+				epoch.CacheHits++
+			} else {
+				epoch.CacheMisses++
+			}
+		}
+		if errs[i] == nil {
+			continue
+		}
+		if e.TolerateFailures {
+			neat.WarnLog(errs[i].Error())
+			org.Fitness = 0
+			winners[i] = false
+		} else {
+			failures = append(failures, errs[i])
+		}
+	}
+	if len(failures) > 0 {
+		return errors.Join(failures...)
+	}
+
+	// find the fittest winner in the order of organisms in population
+	for i, org := range pop.Organisms {
+		if !winners[i] {
+			continue
+		}
+		org.IsWinner = true
+		if epoch.Champion == nil || org.Fitness > epoch.Champion.Fitness {
+			epoch.Solved = true
+			epoch.WinnerNodes = len(org.Genotype.Nodes)
+			epoch.WinnerGenes = org.Genotype.Extrons()
+			epoch.WinnerEvals = options.PopSize*epoch.Id + org.Genotype.Id
+			epoch.Champion = org
+		}
+	}
+
+	// Fill statistics about current epoch
+	epoch.FillPopulationStatistics(pop)
+
+	return nil
+}
+
+// evaluateOrganism is to evaluate given organism recovering from panics. Returns true as the second value if results
+// were taken from the cache.
+func (e *ParallelGenerationEvaluator) evaluateOrganism(ctx context.Context, organism *genetics.Organism, opts *neat.Options) (winner, hit bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			winner, hit, err = false, false, &OrganismEvaluationError{GenomeId: organism.Genotype.Id, Err: fmt.Errorf("panic: %v", r)}
+		}
+	}()
+
+	if e.Cache != nil {
+		winner, hit, err = e.Cache.Evaluate(ctx, organism, e.evaluateWithTimeout)
+	} else {
+		winner, err = EvaluateOrganism(ctx, organism, opts, e.evaluateWithTimeout)
+	}
+	if err != nil {
+		return false, false, &OrganismEvaluationError{GenomeId: organism.Genotype.Id, Err: err}
+	}
+	return winner, hit, nil
+}
+
+// evaluateWithTimeout is to run the evaluation function within its own context, which is canceled after Timeout if set.
+// The evaluation function may ignore the context, thus it is run in a separate goroutine, and the worker is released
+// with the context error as soon as the context is done without waiting for the evaluation to return. The abandoned
+// evaluation keeps running in the background until it returns and its results are discarded.
+func (e *ParallelGenerationEvaluator) evaluateWithTimeout(ctx context.Context, organism *genetics.Organism) (bool, error) {
+	if e.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.Timeout)
+		defer cancel()
+	}
+
+	type evaluationResult struct {
+		winner bool
+		err    error
+	}
+	// buffered to let the abandoned evaluation goroutine exit
+	resChan := make(chan evaluationResult, 1)
+	go func() {
+		var res evaluationResult
+		defer func() {
+			if r := recover(); r != nil {
+				res = evaluationResult{err: fmt.Errorf("panic: %v", r)}
+			}
+			resChan <- res
+		}()
+		res.winner, res.err = e.Evaluate(ctx, organism)
+	}()
+
+	select {
+	case res := <-resChan:
+		if res.err == nil {
+			// the timed out result is not accepted
+			res.err = ctx.Err()
+		}
+		return res.winner, res.err
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
+
+// EvaluateOrganism is to evaluate the organism with given function taking into account the noisy fitness settings of
+// the NEAT options. It should be used by all generation evaluators. If the fitness is not noisy, the organism is
+// evaluated once. Otherwise, it is evaluated EvaluationRepeats times and its fitness is aggregated from the samples
+// of these evaluations and the samples inherited from its elite ancestors. The organism is the winner only if it won
+// in all evaluations. Its error is averaged over evaluations, and the behavior and objectives of the last evaluation
+// are kept.
+func EvaluateOrganism(ctx context.Context, organism *genetics.Organism, opts *neat.Options, evaluate OrganismEvaluateFunc) (bool, error) {
+	if !opts.IsFitnessNoisy() {
+		return evaluate(ctx, organism)
+	}
+	repeats := max(opts.EvaluationRepeats, 1)
+	samples := make([]float64, repeats)
+	winner, totalError := true, 0.0
+	for i := 0; i < repeats; i++ {
+		won, err := evaluate(ctx, organism)
+		if err != nil {
+			return false, err
+		}
+		samples[i] = organism.Fitness
+		totalError += organism.Error
+		winner = winner && won
+	}
+	organism.Error = totalError / float64(repeats)
+	organism.AddFitnessSamples(samples, opts.FitnessAggregation)
+	return winner, nil
+}
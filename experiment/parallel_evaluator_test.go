@@ -0,0 +1,307 @@
+package experiment
+
+import (
+	"context"
+	"deepneat/neat"
+	"deepneat/neat/genetics"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createTestPopulation is to create population of given size from the XOR genome and the context with NEAT options
+func createTestPopulation(t *testing.T, popSize, workers int) (context.Context, *genetics.Population) {
+	genome, err := readTestGenome()
+	require.NoError(t, err, "failed to read XOR genome")
+	opts, err := neat.ReadNeatOptionsFromFile(xorConfigPath)
+	require.NoError(t, err, "failed to read NEAT options")
+	opts.PopSize = popSize
+	opts.EvaluationWorkers = workers
+	pop, err := genetics.NewPopulation(genome, opts)
+	require.NoError(t, err, "failed to create population")
+	return neat.NewContext(context.Background(), opts), pop
+}
+
+func TestParallelGenerationEvaluator_GenerationEvaluate(t *testing.T) {
+	ctx, pop := createTestPopulation(t, 20, 3)
+
+	var running, maxRunning int32
+	evaluator := NewParallelGenerationEvaluator(func(_ context.Context, organism *genetics.Organism) (bool, error) {
+		current := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			observed := atomic.LoadInt32(&maxRunning)
+			if current <= observed || atomic.CompareAndSwapInt32(&maxRunning, observed, current) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+
+		organism.Fitness = float64(organism.Genotype.Id)
+		return organism.Genotype.Id%5 == 0, nil
+	}, time.Second)
+
+	epoch := Generation{Id: 2}
+	err := evaluator.GenerationEvaluate(ctx, pop, &epoch)
+	require.NoError(t, err)
+	assert.True(t, maxRunning <= 3, "too many concurrent evaluations: %d", maxRunning)
+
+	// the fittest winner is the champion
+	var expected *genetics.Organism
+	for _, org := range pop.Organisms {
+		assert.Equal(t, float64(org.Genotype.Id), org.Fitness)
+		assert.Equal(t, org.Genotype.Id%5 == 0, org.IsWinner)
+		if org.IsWinner && (expected == nil || org.Fitness > expected.Fitness) {
+			expected = org
+		}
+	}
+	require.NotNil(t, expected)
+	assert.True(t, epoch.Solved)
+	assert.Equal(t, expected, epoch.Champion)
+	assert.Equal(t, len(expected.Genotype.Nodes), epoch.WinnerNodes)
+	assert.Equal(t, expected.Genotype.Extrons(), epoch.WinnerGenes)
+	assert.Equal(t, 20*2+expected.Genotype.Id, epoch.WinnerEvals)
+	assert.Equal(t, len(pop.Species), epoch.Diversity)
+	assert.Len(t, epoch.Fitness, len(pop.Species))
+}
+
+func TestParallelGenerationEvaluator_GenerationEvaluate_failures(t *testing.T) {
+	ctx, pop := createTestPopulation(t, 10, 4)
+	failedId, panickedId := pop.Organisms[2].Genotype.Id, pop.Organisms[7].Genotype.Id
+	errFailed := errors.New("evaluation failed")
+
+	evaluator := NewParallelGenerationEvaluator(func(_ context.Context, organism *genetics.Organism) (bool, error) {
+		switch organism.Genotype.Id {
+		case failedId:
+			return false, errFailed
+		case panickedId:
+			panic("evaluation panicked")
+		}
+		organism.Fitness = 1.0
+		return false, nil
+	}, 0)
+
+	epoch := Generation{Id: 1}
+	err := evaluator.GenerationEvaluate(ctx, pop, &epoch)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errFailed)
+	var orgErr *OrganismEvaluationError
+	require.ErrorAs(t, err, &orgErr)
+	assert.Equal(t, failedId, orgErr.GenomeId)
+	assert.Contains(t, err.Error(), "evaluation panicked")
+	assert.Nil(t, epoch.Champion, "statistics must not be collected")
+
+	// tolerate failures
+	evaluator.TolerateFailures = true
+	err = evaluator.GenerationEvaluate(ctx, pop, &epoch)
+	require.NoError(t, err)
+	for i, org := range pop.Organisms {
+		if i == 2 || i == 7 {
+			assert.Equal(t, 0.0, org.Fitness)
+		} else {
+			assert.Equal(t, 1.0, org.Fitness)
+		}
+	}
+	assert.False(t, epoch.Solved)
+	assert.NotNil(t, epoch.Champion)
+}
+
+func TestParallelGenerationEvaluator_GenerationEvaluate_timeout(t *testing.T) {
+	ctx, pop := createTestPopulation(t, 6, 0)
+	waitingId, sleepingId := pop.Organisms[1].Genotype.Id, pop.Organisms[4].Genotype.Id
+
+	evaluator := NewParallelGenerationEvaluator(func(ctx context.Context, organism *genetics.Organism) (bool, error) {
+		switch organism.Genotype.Id {
+		case waitingId:
+			// respects the context
+			<-ctx.Done()
+			return false, ctx.Err()
+		case sleepingId:
+			// ignores the context
+			time.Sleep(50 * time.Millisecond)
+		}
+		return true, nil
+	}, 10*time.Millisecond)
+
+	err := evaluator.GenerationEvaluate(ctx, pop, &Generation{})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), fmt.Sprintf("genome ID: %d,", waitingId))
+	assert.Contains(t, err.Error(), fmt.Sprintf("genome ID: %d,", sleepingId))
+}
+
+func TestParallelGenerationEvaluator_GenerationEvaluate_timeoutNonCooperative(t *testing.T) {
+	ctx, pop := createTestPopulation(t, 4, 2)
+	timeout := 20 * time.Millisecond
+
+	// ignores the context and blocks much longer than timeout
+	evaluator := NewParallelGenerationEvaluator(func(_ context.Context, _ *genetics.Organism) (bool, error) {
+		time.Sleep(time.Second)
+		return true, nil
+	}, timeout)
+
+	start := time.Now()
+	err := evaluator.GenerationEvaluate(ctx, pop, &Generation{})
+	elapsed := time.Since(start)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	// two workers are released after timeout to evaluate the rest of organisms
+	assert.Less(t, elapsed, 10*timeout, "the generation must finish close to timeout")
+}
+
+func TestParallelGenerationEvaluator_GenerationEvaluate_timeoutRepeated(t *testing.T) {
+	ctx, pop := createTestPopulation(t, 4, 0)
+	opts, _ := neat.FromContext(ctx)
+	opts.EvaluationRepeats = 4
+
+	// the timeout is applied to each repeat rather than to all repeats combined
+	evaluator := NewParallelGenerationEvaluator(func(ctx context.Context, organism *genetics.Organism) (bool, error) {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(20 * time.Millisecond):
+		}
+		organism.Fitness = 1.0
+		return false, nil
+	}, 50*time.Millisecond)
+
+	err := evaluator.GenerationEvaluate(ctx, pop, &Generation{})
+	require.NoError(t, err)
+	for _, org := range pop.Organisms {
+		assert.Len(t, org.FitnessSamples, 4)
+	}
+}
+
+func TestParallelGenerationEvaluator_GenerationEvaluate_noOptions(t *testing.T) {
+	_, pop := createTestPopulation(t, 2, 1)
+	evaluator := NewParallelGenerationEvaluator(func(_ context.Context, _ *genetics.Organism) (bool, error) {
+		return false, nil
+	}, 0)
+	err := evaluator.GenerationEvaluate(context.Background(), pop, &Generation{})
+	assert.ErrorIs(t, err, neat.ErrNEATOptionsNotFound)
+}
+
+func TestParallelGenerationEvaluator_GenerationEvaluate_repeated(t *testing.T) {
+	testCases := []struct {
+		aggregation neat.FitnessAggregation
+		expected    float64
+	}{
+		{aggregation: "", expected: 2.0},
+		{aggregation: neat.FitnessAggregationMean, expected: 2.0},
+		{aggregation: neat.FitnessAggregationMedian, expected: 1.0},
+		{aggregation: neat.FitnessAggregationWorst, expected: 0.0},
+	}
+	for _, tc := range testCases {
+		ctx, pop := createTestPopulation(t, 10, 3)
+		opts, _ := neat.FromContext(ctx)
+		opts.EvaluationRepeats = 3
+		opts.FitnessAggregation = tc.aggregation
+
+		// the organism gets fitness samples: 0, 1, 5 and wins only in some evaluations
+		counts := make([]int32, len(pop.Organisms))
+		indexes := make(map[*genetics.Organism]int, len(pop.Organisms))
+		for i, org := range pop.Organisms {
+			indexes[org] = i
+		}
+		evaluator := NewParallelGenerationEvaluator(func(_ context.Context, organism *genetics.Organism) (bool, error) {
+			count := atomic.AddInt32(&counts[indexes[organism]], 1)
+			organism.Fitness = []float64{0, 1, 5}[count-1]
+			organism.Error = float64(count)
+			return organism.Genotype.Id%2 == 0 || count < 3, nil
+		}, time.Second)
+
+		epoch := Generation{Id: 1}
+		err := evaluator.GenerationEvaluate(ctx, pop, &epoch)
+		require.NoError(t, err, tc.aggregation)
+		for _, org := range pop.Organisms {
+			assert.Equal(t, tc.expected, org.Fitness, tc.aggregation)
+			assert.Equal(t, []float64{0, 1, 5}, org.FitnessSamples, tc.aggregation)
+			assert.InDelta(t, 7.0, org.FitnessVariance, 1e-9, tc.aggregation)
+			assert.Equal(t, 2.0, org.Error, tc.aggregation)
+			assert.Equal(t, org.Genotype.Id%2 == 0, org.IsWinner, "must win in all evaluations")
+			assert.Zero(t, org.Reevaluations, tc.aggregation)
+		}
+		assert.Nil(t, epoch.ValidatedChampion)
+	}
+}
+
+func TestParallelGenerationEvaluator_GenerationEvaluate_reevaluatedElites(t *testing.T) {
+	ctx, pop := createTestPopulation(t, 10, 3)
+	opts, _ := neat.FromContext(ctx)
+	opts.ReevaluateElites = true
+
+	// the organisms with samples inherited from elite ancestors
+	elite := pop.Organisms[3]
+	elite.FitnessSamples, elite.Reevaluations = []float64{10, 20}, 1
+
+	evaluator := NewParallelGenerationEvaluator(func(_ context.Context, organism *genetics.Organism) (bool, error) {
+		organism.Fitness = 3.0
+		return false, nil
+	}, time.Second)
+
+	epoch := Generation{Id: 2}
+	err := evaluator.GenerationEvaluate(ctx, pop, &epoch)
+	require.NoError(t, err)
+	assert.Equal(t, 11.0, elite.Fitness)
+	assert.Equal(t, []float64{10, 20, 3}, elite.FitnessSamples)
+	assert.Equal(t, 2, elite.Reevaluations)
+	for _, org := range pop.Organisms {
+		if org != elite {
+			assert.Equal(t, 3.0, org.Fitness)
+			assert.Equal(t, []float64{3}, org.FitnessSamples)
+			assert.Zero(t, org.Reevaluations)
+		}
+	}
+	assert.Same(t, elite, epoch.Champion)
+	assert.Same(t, elite, epoch.ValidatedChampion)
+}
+
+func TestParallelGenerationEvaluator_GenerationEvaluate_noisyWithCache(t *testing.T) {
+	ctx, pop := createTestPopulation(t, 10, 3)
+	opts, _ := neat.FromContext(ctx)
+	opts.EvaluationRepeats = 2
+
+	evaluator := NewParallelGenerationEvaluator(func(_ context.Context, organism *genetics.Organism) (bool, error) {
+		return false, nil
+	}, time.Second)
+	evaluator.Cache = NewEvaluationCache(10)
+	err := evaluator.GenerationEvaluate(ctx, pop, &Generation{Id: 1})
+	assert.Error(t, err)
+}
+
+func TestEvaluateOrganism(t *testing.T) {
+	ctx, pop := createTestPopulation(t, 1, 0)
+	opts, _ := neat.FromContext(ctx)
+	org := pop.Organisms[0]
+	calls := 0
+	evaluate := func(_ context.Context, organism *genetics.Organism) (bool, error) {
+		calls++
+		organism.Fitness = float64(calls)
+		return true, nil
+	}
+
+	// evaluated once if fitness is not noisy
+	winner, err := EvaluateOrganism(ctx, org, opts, evaluate)
+	require.NoError(t, err)
+	assert.True(t, winner)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 1.0, org.Fitness)
+	assert.Empty(t, org.FitnessSamples)
+
+	// the samples inherited from elite ancestor are aggregated with new ones
+	opts.ReevaluateElites = true
+	opts.EvaluationRepeats = 2
+	org.FitnessSamples = []float64{9}
+	winner, err = EvaluateOrganism(ctx, org, opts, evaluate)
+	require.NoError(t, err)
+	assert.True(t, winner)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []float64{9, 2, 3}, org.FitnessSamples)
+	assert.Equal(t, 14.0/3.0, org.Fitness)
+	assert.Equal(t, 1, org.Reevaluations)
+}
@@ -0,0 +1,231 @@
+package experiment
+
+import (
+	"deepneat/neat/genetics"
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// SpeciesStats the statistics of one species in the population evaluated in particular generation
+type SpeciesStats struct {
+	// The ID of the species
+	Id int
+	// The number of organisms in the species
+	Size int
+	// The maximal and average fitness of organisms in the species
+	MaxFitness float64
+	AvgFitness float64
+	// The number of offspring allotted to the species in the reproduction following the evaluation or zero if there
+	// was no reproduction, e.g., when the generation solved the problem
+	ExpectedOffspring int
+	// The age of the species
+	Age int
+	// The age of the species when its fitness was improved the last time
+	AgeOfLastImprovement int
+}
+
+// newSpeciesStats is to collect statistics of given species
+func newSpeciesStats(species *genetics.Species) SpeciesStats {
+	stats := SpeciesStats{
+		Id:                   species.Id,
+		Size:                 len(species.Organisms),
+		Age:                  species.Age,
+		AgeOfLastImprovement: species.AgeOfLastImprovement,
+	}
+	if stats.Size > 0 {
+		fitness := make(Floats, stats.Size)
+		for i, org := range species.Organisms {
+			fitness[i] = org.Fitness
+		}
+		stats.MaxFitness = fitness.Max()
+		stats.AvgFitness = fitness.Mean()
+	}
+	return stats
+}
+
+// SpeciesEventType the type of the event in the species history
+type SpeciesEventType string
+
+const (
+	// SpeciesBirth the species appeared in the population for the first time
+	SpeciesBirth SpeciesEventType = "birth"
+	// SpeciesExtinction the species disappeared from the population
+	SpeciesExtinction SpeciesEventType = "extinction"
+)
+
+// SpeciesEvent the birth or extinction of the species
+type SpeciesEvent struct {
+	// The ID of the first generation in which species was present (birth) or absent (extinction)
+	Generation int
+	// The ID of the species
+	SpeciesId int
+	// The type of event
+	Type SpeciesEventType
+}
+
+// fillExpectedOffspring is to set the number of offspring allotted to the species during reproduction. The provided
+// species must be the ones of the population evaluated in this generation and are matched with statistics by ID.
+func (g *Generation) fillExpectedOffspring(species []*genetics.Species) {
+	offspring := make(map[int]int, len(species))
+	for _, sp := range species {
+		offspring[sp.Id] = sp.ExpectedOffspring
+	}
+	for i := range g.Species {
+		g.Species[i].ExpectedOffspring = offspring[g.Species[i].Id]
+	}
+}
+
+// SpeciesEvents is to find births and extinctions of species by comparing consecutive generations of this trial. All
+// species of the first generation are considered born in it.
+func (t *Trial) SpeciesEvents() []SpeciesEvent {
+	events := make([]SpeciesEvent, 0)
+	alive := make(map[int]bool)
+	for _, g := range t.Generations {
+		present := make(map[int]bool, len(g.Species))
+		for _, stats := range g.Species {
+			present[stats.Id] = true
+			if !alive[stats.Id] {
+				events = append(events, SpeciesEvent{Generation: g.Id, SpeciesId: stats.Id, Type: SpeciesBirth})
+			}
+		}
+		extinct := make([]int, 0)
+		for id := range alive {
+			if !present[id] {
+				extinct = append(extinct, id)
+			}
+		}
+		sort.Ints(extinct)
+		for _, id := range extinct {
+			events = append(events, SpeciesEvent{Generation: g.Id, SpeciesId: id, Type: SpeciesExtinction})
+		}
+		alive = present
+	}
+	return events
+}
+
+// SpeciesSizes is to get the sizes of species per generation in this trial, which is the data of the speciation
+// plot. The rows of returned matrix are generations and the columns are species in order of returned IDs. If there
+// are no species statistics recorded, the nil matrix is returned.
+func (t *Trial) SpeciesSizes() (*mat.Dense, Floats) {
+	columns := make(map[int]int)
+	for _, g := range t.Generations {
+		for _, stats := range g.Species {
+			columns[stats.Id] = 0
+		}
+	}
+	if len(columns) == 0 {
+		return nil, nil
+	}
+	ids := make([]int, 0, len(columns))
+	for id := range columns {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	speciesIds := make(Floats, len(ids))
+	for i, id := range ids {
+		columns[id] = i
+		speciesIds[i] = float64(id)
+	}
+
+	sizes := mat.NewDense(len(t.Generations), len(ids), nil)
+	for row, g := range t.Generations {
+		for _, stats := range g.Species {
+			sizes.Set(row, columns[stats.Id], float64(stats.Size))
+		}
+	}
+	return sizes, speciesIds
+}
+
+// speciesHistoryMat is to get statistics of species per generation in this trial as matrix with rows: generation ID,
+// species ID, size, max fitness, average fitness, expected offspring, age, and age of last improvement. If there are
+// no species statistics recorded, the nil is returned.
+func (t *Trial) speciesHistoryMat() *mat.Dense {
+	rows := make([]float64, 0)
+	for _, g := range t.Generations {
+		for _, stats := range g.Species {
+			rows = append(rows, float64(g.Id), float64(stats.Id), float64(stats.Size), stats.MaxFitness,
+				stats.AvgFitness, float64(stats.ExpectedOffspring), float64(stats.Age), float64(stats.AgeOfLastImprovement))
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return mat.NewDense(len(rows)/8, 8, rows)
+}
+
+// speciesEventsMat is to get species events of this trial as matrix with rows: generation ID, species ID, and event
+// type encoded as 1 for birth and -1 for extinction. If there are no events, the nil is returned.
+func (t *Trial) speciesEventsMat() *mat.Dense {
+	events := t.SpeciesEvents()
+	if len(events) == 0 {
+		return nil
+	}
+	eventsMat := mat.NewDense(len(events), 3, nil)
+	for i, event := range events {
+		eventType := 1.0
+		if event.Type == SpeciesExtinction {
+			eventType = -1.0
+		}
+		eventsMat.SetRow(i, []float64{float64(event.Generation), float64(event.SpeciesId), eventType})
+	}
+	return eventsMat
+}
+
+// WriteSpeciesCSV is to write statistics of species per generation of all trials in CSV format
+func (e *Experiment) WriteSpeciesCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	header := []string{"trial", "generation", "species_id", "size", "max_fitness", "avg_fitness",
+		"expected_offspring", "age", "age_of_last_improvement"}
+	if err := out.Write(header); err != nil {
+		return err
+	}
+	for _, t := range e.Trials {
+		for _, g := range t.Generations {
+			for _, stats := range g.Species {
+				record := []string{
+					strconv.Itoa(t.Id),
+					strconv.Itoa(g.Id),
+					strconv.Itoa(stats.Id),
+					strconv.Itoa(stats.Size),
+					strconv.FormatFloat(stats.MaxFitness, 'g', -1, 64),
+					strconv.FormatFloat(stats.AvgFitness, 'g', -1, 64),
+					strconv.Itoa(stats.ExpectedOffspring),
+					strconv.Itoa(stats.Age),
+					strconv.Itoa(stats.AgeOfLastImprovement),
+				}
+				if err := out.Write(record); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// WriteSpeciesEventsCSV is to write births and extinctions of species in all trials in CSV format
+func (e *Experiment) WriteSpeciesEventsCSV(w io.Writer) error {
+	out := csv.NewWriter(w)
+	if err := out.Write([]string{"trial", "generation", "species_id", "event"}); err != nil {
+		return err
+	}
+	for _, t := range e.Trials {
+		for _, event := range t.SpeciesEvents() {
+			record := []string{
+				strconv.Itoa(t.Id),
+				strconv.Itoa(event.Generation),
+				strconv.Itoa(event.SpeciesId),
+				string(event.Type),
+			}
+			if err := out.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
@@ -0,0 +1,170 @@
+package experiment
+
+import (
+	"bytes"
+	"deepneat/neat/genetics"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/sbinet/npyio/npz"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gonum.org/v1/gonum/mat"
+)
+
+func TestGeneration_FillPopulationStatistics_species(t *testing.T) {
+	rand.Seed(42)
+	pop, _ := buildTestPopulation(t)
+	gen := Generation{Id: 1, TrialId: 1}
+	gen.FillPopulationStatistics(pop)
+	require.Len(t, gen.Species, len(pop.Species))
+	size := 0
+	for i, stats := range gen.Species {
+		sp := pop.Species[i]
+		assert.Equal(t, sp.Id, stats.Id)
+		assert.Equal(t, len(sp.Organisms), stats.Size)
+		assert.Equal(t, sp.Organisms[0].Fitness, stats.MaxFitness)
+		assert.True(t, stats.AvgFitness <= stats.MaxFitness)
+		assert.Equal(t, sp.Age, stats.Age)
+		assert.Equal(t, sp.AgeOfLastImprovement, stats.AgeOfLastImprovement)
+		assert.Zero(t, stats.ExpectedOffspring)
+		size += stats.Size
+	}
+	assert.Equal(t, len(pop.Organisms), size)
+}
+
+func TestGeneration_fillExpectedOffspring(t *testing.T) {
+	gen := Generation{Species: []SpeciesStats{{Id: 1}, {Id: 2}, {Id: 3}}}
+	species := []*genetics.Species{
+		{Id: 3, ExpectedOffspring: 7},
+		{Id: 1, ExpectedOffspring: 5},
+	}
+	gen.fillExpectedOffspring(species)
+	assert.Equal(t, 5, gen.Species[0].ExpectedOffspring)
+	assert.Equal(t, 0, gen.Species[1].ExpectedOffspring, "not reproduced species")
+	assert.Equal(t, 7, gen.Species[2].ExpectedOffspring)
+}
+
+func TestTrial_SpeciesEvents(t *testing.T) {
+	trial := buildTestSpeciesTrial()
+	expected := []SpeciesEvent{
+		{Generation: 0, SpeciesId: 1, Type: SpeciesBirth},
+		{Generation: 0, SpeciesId: 2, Type: SpeciesBirth},
+		{Generation: 1, SpeciesId: 3, Type: SpeciesBirth},
+		{Generation: 2, SpeciesId: 4, Type: SpeciesBirth},
+		{Generation: 2, SpeciesId: 1, Type: SpeciesExtinction},
+		{Generation: 2, SpeciesId: 3, Type: SpeciesExtinction},
+	}
+	assert.EqualValues(t, expected, trial.SpeciesEvents())
+}
+
+func TestTrial_SpeciesEvents_emptyEpochs(t *testing.T) {
+	trial := Trial{Id: 1}
+	assert.Empty(t, trial.SpeciesEvents())
+}
+
+func TestTrial_SpeciesSizes(t *testing.T) {
+	trial := buildTestSpeciesTrial()
+	sizes, ids := trial.SpeciesSizes()
+	require.NotNil(t, sizes)
+	assert.EqualValues(t, Floats{1, 2, 3, 4}, ids)
+	expected := mat.NewDense(3, 4, []float64{
+		10, 20, 0, 0,
+		5, 15, 10, 0,
+		0, 22, 0, 8,
+	})
+	assert.EqualValues(t, expected, sizes)
+}
+
+func TestTrial_SpeciesSizes_emptyEpochs(t *testing.T) {
+	trial := Trial{Id: 1, Generations: Generations{{Id: 0}}}
+	sizes, ids := trial.SpeciesSizes()
+	assert.Nil(t, sizes)
+	assert.Nil(t, ids)
+}
+
+func TestExperiment_WriteSpeciesCSV(t *testing.T) {
+	ex := Experiment{Id: 1, Trials: Trials{*buildTestSpeciesTrial()}}
+	var buff bytes.Buffer
+	err := ex.WriteSpeciesCSV(&buff)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buff.String()), "\n")
+	require.Len(t, lines, 8)
+	assert.Equal(t, "trial,generation,species_id,size,max_fitness,avg_fitness,expected_offspring,age,age_of_last_improvement", lines[0])
+	assert.Equal(t, "1,0,1,10,1.5,1.25,6,1,1", lines[1])
+	assert.Equal(t, "1,2,4,8,4.5,3,10,1,1", lines[7])
+}
+
+func TestExperiment_WriteSpeciesEventsCSV(t *testing.T) {
+	ex := Experiment{Id: 1, Trials: Trials{*buildTestSpeciesTrial()}}
+	var buff bytes.Buffer
+	err := ex.WriteSpeciesEventsCSV(&buff)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buff.String()), "\n")
+	require.Len(t, lines, 7)
+	assert.Equal(t, "trial,generation,species_id,event", lines[0])
+	assert.Equal(t, "1,0,1,birth", lines[1])
+	assert.Equal(t, "1,2,3,extinction", lines[6])
+}
+
+func TestExperiment_WriteNPZ_species(t *testing.T) {
+	ex := Experiment{Id: 1, Trials: Trials{*buildTestSpeciesTrial(), {Id: 2, Generations: Generations{{Id: 0}}}}}
+	var buff bytes.Buffer
+	err := ex.WriteNPZ(&buff)
+	require.NoError(t, err)
+
+	r, err := npz.NewReader(bytes.NewReader(buff.Bytes()), int64(buff.Len()))
+	require.NoError(t, err)
+
+	expectedSizes, expectedIds := ex.Trials[0].SpeciesSizes()
+	ids := Floats{}
+	err = r.Read("trial_0_species_ids", &ids)
+	assert.NoError(t, err)
+	assert.EqualValues(t, expectedIds, ids)
+
+	sizes := &mat.Dense{}
+	err = r.Read("trial_0_species_sizes", sizes)
+	assert.NoError(t, err)
+	assert.EqualValues(t, expectedSizes, sizes)
+
+	history := &mat.Dense{}
+	err = r.Read("trial_0_species_history", history)
+	assert.NoError(t, err)
+	assert.EqualValues(t, ex.Trials[0].speciesHistoryMat(), history)
+	assert.Equal(t, []float64{2, 4, 8, 4.5, 3, 10, 1, 1}, history.RawRowView(6))
+
+	events := &mat.Dense{}
+	err = r.Read("trial_0_species_events", events)
+	assert.NoError(t, err)
+	rows, _ := events.Dims()
+	assert.Equal(t, 6, rows)
+	assert.Equal(t, []float64{2, 3, -1}, events.RawRowView(5))
+
+	// no species data for trial without species statistics
+	assert.NotContains(t, r.Keys(), "trial_1_species_sizes")
+	assert.NoError(t, r.Close())
+}
+
+func buildTestSpeciesTrial() *Trial {
+	return &Trial{
+		Id: 1,
+		Generations: Generations{
+			{Id: 0, Species: []SpeciesStats{
+				{Id: 1, Size: 10, MaxFitness: 1.5, AvgFitness: 1.25, ExpectedOffspring: 6, Age: 1, AgeOfLastImprovement: 1},
+				{Id: 2, Size: 20, MaxFitness: 2.5, AvgFitness: 2.0, ExpectedOffspring: 24, Age: 1, AgeOfLastImprovement: 1},
+			}},
+			{Id: 1, Species: []SpeciesStats{
+				{Id: 1, Size: 5, MaxFitness: 1.0, AvgFitness: 0.5, ExpectedOffspring: 0, Age: 2, AgeOfLastImprovement: 1},
+				{Id: 2, Size: 15, MaxFitness: 3.5, AvgFitness: 2.5, ExpectedOffspring: 22, Age: 2, AgeOfLastImprovement: 2},
+				{Id: 3, Size: 10, MaxFitness: 2.0, AvgFitness: 1.5, ExpectedOffspring: 8, Age: 1, AgeOfLastImprovement: 1},
+			}},
+			{Id: 2, Species: []SpeciesStats{
+				{Id: 2, Size: 22, MaxFitness: 4.0, AvgFitness: 3.0, ExpectedOffspring: 20, Age: 3, AgeOfLastImprovement: 3},
+				{Id: 4, Size: 8, MaxFitness: 4.5, AvgFitness: 3.0, ExpectedOffspring: 10, Age: 1, AgeOfLastImprovement: 1},
+			}},
+		},
+	}
+}
@@ -64,6 +64,74 @@ func WriteGenomeCytoscapeJSON(genomeFile, outDir string, org *genetics.Organism,
 	return orgPath, nil
 }
 
+// WriteLineageJSON is to write the ancestry of the organism evaluated in the given epoch to the lineageFile in the
+// outDir directory using JSON encoding. The method return path to the file if successful or error if failed.
+func WriteLineageJSON(lineageFile, outDir string, lineage *genetics.Lineage, org *genetics.Organism, epoch *experiment.Generation) (string, error) {
+	lineagePath := fmt.Sprintf("%s/%s_%d.json", CreateOutDirForTrial(outDir, epoch.TrialId), lineageFile, epoch.Id)
+	file, err := os.Create(lineagePath)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	if err = lineage.Ancestry(epoch.Id, org).WriteJSON(file); err != nil {
+		return "", err
+	}
+	return lineagePath, nil
+}
+
+// WriteLineageDOT is to write the ancestry of the organism evaluated in the given epoch to the lineageFile in the
+// outDir directory using DOT encoding. The method return path to the file if successful or error if failed.
+func WriteLineageDOT(lineageFile, outDir string, lineage *genetics.Lineage, org *genetics.Organism, epoch *experiment.Generation) (string, error) {
+	lineagePath := fmt.Sprintf("%s/%s_%d.dot", CreateOutDirForTrial(outDir, epoch.TrialId), lineageFile, epoch.Id)
+	file, err := os.Create(lineagePath)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	if err = lineage.Ancestry(epoch.Id, org).WriteDOT(file); err != nil {
+		return "", err
+	}
+	return lineagePath, nil
+}
+
+// WriteHallOfFame is to write genomes of the hall of fame members to the hall_of_fame directory in the outDir directory
+// using YAML encoding. The files are named by the rank of members starting from the most fit one. The method return
+// paths to the files if successful or error if failed.
+func WriteHallOfFame(outDir string, hallOfFame *genetics.HallOfFame) ([]string, error) {
+	hofDir := fmt.Sprintf("%s/hall_of_fame", outDir)
+	if err := os.MkdirAll(hofDir, os.ModePerm); err != nil {
+		return nil, err
+	}
+	members := hallOfFame.Members()
+	paths := make([]string, len(members))
+	for i, member := range members {
+		paths[i] = fmt.Sprintf("%s/genome_%d.yml", hofDir, i)
+		if err := writeGenomeYAML(paths[i], member.Organism.Genotype); err != nil {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+func writeGenomeYAML(path string, genome *genetics.Genome) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	writer, err := genetics.NewGenomeWriter(file, genetics.YAMLGenomeEncoding)
+	if err != nil {
+		return err
+	}
+	return writer.WriteGenome(genome)
+}
+
 // WritePopulationPlain is to write genomes of the entire population using plain encoding in the outDir directory.
 // The methods return path to the file if successful or error if failed.
 func WritePopulationPlain(outDir string, pop *genetics.Population, epoch *experiment.Generation) (string, error) {
@@ -4,6 +4,7 @@ go 1.23.2
 
 require (
 	github.com/pkg/errors v0.9.1
+	github.com/sbinet/npyio v0.9.0
 	github.com/spf13/cast v1.7.1
 	github.com/stretchr/testify v1.10.0
 	gonum.org/v1/gonum v0.15.1
@@ -14,7 +15,6 @@ require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/nlpodyssey/gopickle v0.3.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/sbinet/npyio v0.9.0 // indirect
 	github.com/stretchr/objx v0.5.2 // indirect
 	golang.org/x/exp v0.0.0-20231110203233-9a3e6036ecaa // indirect
 	golang.org/x/text v0.14.0 // indirect
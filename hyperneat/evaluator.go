@@ -0,0 +1,73 @@
+package hyperneat
+
+import (
+	"context"
+	"deepneat/experiment"
+	"deepneat/neat"
+	"deepneat/neat/genetics"
+	"deepneat/neat/network"
+	"fmt"
+)
+
+// SubstrateNetworkEvaluator is to evaluate the substrate network built by CPPN of the given organism. It should set
+// the fitness of the organism and return true if the organism solved the task.
+type SubstrateNetworkEvaluator func(organism *genetics.Organism, net *network.FastModularNetworkSolver) (bool, error)
+
+type substrateGenerationEvaluator struct {
+	// The substrate to be painted by CPPNs
+	Substrate *Substrate
+	// The evaluator of the substrate networks
+	NetworkEvaluator SubstrateNetworkEvaluator
+}
+
+// NewGenerationEvaluator is to create generations evaluator for HyperNEAT experiment. The genomes of the population
+// encode CPPNs, which are used to build networks on provided substrate. The built networks are evaluated by the
+// given network evaluator.
+func NewGenerationEvaluator(substrate *Substrate, evaluator SubstrateNetworkEvaluator) experiment.GenerationEvaluator {
+	return &substrateGenerationEvaluator{
+		Substrate:        substrate,
+		NetworkEvaluator: evaluator,
+	}
+}
+
+// GenerationEvaluate evaluates one epoch for given population of CPPNs
+func (e *substrateGenerationEvaluator) GenerationEvaluate(ctx context.Context, pop *genetics.Population, epoch *experiment.Generation) error {
+	options, ok := neat.FromContext(ctx)
+	if !ok {
+		return neat.ErrNEATOptionsNotFound
+	}
+	evaluate := func(_ context.Context, organism *genetics.Organism) (bool, error) {
+		cppn, err := organism.Phenotype()
+		if err != nil {
+			return false, err
+		}
+		net, err := e.Substrate.CreateNetworkSolver(cppn)
+		if err != nil {
+			return false, err
+		}
+		return e.NetworkEvaluator(organism, net)
+	}
+	for _, org := range pop.Organisms {
+		res, err := experiment.EvaluateOrganism(ctx, org, options, evaluate)
+		if err != nil {
+			return err
+		}
+		org.IsWinner = res
+
+		if res && (epoch.Champion == nil || org.Fitness > epoch.Champion.Fitness) {
+			epoch.Solved = true
+			epoch.WinnerNodes = len(org.Genotype.Nodes)
+			epoch.WinnerGenes = org.Genotype.Extrons()
+			epoch.WinnerEvals = options.PopSize*epoch.Id + org.Genotype.Id
+			epoch.Champion = org
+		}
+	}
+
+	// Fill statistics about current epoch
+	epoch.FillPopulationStatistics(pop)
+
+	if epoch.Solved {
+		neat.InfoLog(fmt.Sprintf("Generation #%d winner CPPN: %s\n", epoch.Id, epoch.Champion))
+	}
+	return nil
+}
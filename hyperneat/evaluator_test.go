@@ -0,0 +1,67 @@
+package hyperneat
+
+import (
+	"context"
+	"deepneat/experiment"
+	"deepneat/neat"
+	"deepneat/neat/genetics"
+	neatmath "deepneat/neat/math"
+	"deepneat/neat/network"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubstrateGenerationEvaluator_GenerationEvaluate(t *testing.T) {
+	conf := &neat.Options{
+		CompatThreshold:    0.5,
+		PopSize:            10,
+		NodeActivators:     []neatmath.NodeActivationType{neatmath.SigmoidSteepenedActivation},
+		NodeActivatorsProb: []float64{1.0},
+	}
+	opts := conf.WithRandSource(rand.New(rand.NewSource(42)))
+	// the CPPNs receive coordinates of the source and target nodes of 2D substrate and the bias
+	pop, err := genetics.NewPopulationRandom(5, 1, 3, false, 0.8, opts)
+	require.NoError(t, err, "failed to create population of CPPNs")
+
+	substrate, err := NewSubstrate(2, SubstrateLayer{{X: -1, Y: -1}, {X: 1, Y: -1}}, SubstrateLayer{{X: 0, Y: 1}})
+	require.NoError(t, err)
+
+	// the organisms with genome ID not less than the selected one are the winners
+	winnerId := pop.Organisms[len(pop.Organisms)/2].Genotype.Id
+	evaluated := 0
+	evaluator := NewGenerationEvaluator(substrate, func(org *genetics.Organism, net *network.FastModularNetworkSolver) (bool, error) {
+		evaluated++
+		assert.Equal(t, substrate.NodeCount(), net.NodeCount())
+		org.Fitness = float64(org.Genotype.Id)
+		return org.Genotype.Id >= winnerId, nil
+	})
+
+	epoch := &experiment.Generation{Id: 1}
+	err = evaluator.GenerationEvaluate(opts.NeatContext(), pop, epoch)
+	require.NoError(t, err, "failed to evaluate generation")
+	assert.Equal(t, len(pop.Organisms), evaluated)
+	assert.True(t, epoch.Solved)
+	require.NotNil(t, epoch.Champion)
+	// the fittest winner is the champion
+	maxId := 0
+	for _, org := range pop.Organisms {
+		assert.Equal(t, org.Genotype.Id >= winnerId, org.IsWinner, "organism: %d", org.Genotype.Id)
+		if org.Genotype.Id > maxId {
+			maxId = org.Genotype.Id
+		}
+	}
+	assert.Equal(t, maxId, epoch.Champion.Genotype.Id)
+	assert.Equal(t, len(epoch.Champion.Genotype.Nodes), epoch.WinnerNodes)
+	assert.Len(t, epoch.Fitness, len(pop.Species))
+}
+
+func TestSubstrateGenerationEvaluator_GenerationEvaluate_noOptions(t *testing.T) {
+	substrate, err := NewSubstrate(2, SubstrateLayer{{}}, SubstrateLayer{{}})
+	require.NoError(t, err)
+	evaluator := NewGenerationEvaluator(substrate, nil)
+	err = evaluator.GenerationEvaluate(context.Background(), &genetics.Population{}, &experiment.Generation{})
+	assert.ErrorIs(t, err, neat.ErrNEATOptionsNotFound)
+}
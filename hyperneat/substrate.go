@@ -0,0 +1,243 @@
+// Package hyperneat implements the Hypercube-based NEAT (HyperNEAT) method, which evolves Compositional Pattern
+// Producing Networks (CPPNs) with NEAT and uses them to paint connection weights onto the substrate - the geometric
+// layout of the nodes of the target network.
+package hyperneat
+
+import (
+	neatmath "deepneat/neat/math"
+	"deepneat/neat/network"
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	ErrEmptySubstrateLayer     = errors.New("substrate must have at least one input and one output node")
+	ErrCPPNInputsSizeMismatch  = errors.New("number of CPPN inputs doesn't match substrate dimensions")
+	ErrCPPNNoOutputs           = errors.New("CPPN has no outputs")
+	ErrUnsupportedSubstrateDim = errors.New("substrate dimensions must be either 2 or 3")
+)
+
+// Point is the coordinates of the node in the substrate space. The Z coordinate is ignored by 2D substrates.
+type Point struct {
+	X, Y, Z float64
+}
+
+// SubstrateLayer holds the coordinates of the nodes within one layer of the substrate
+type SubstrateLayer []Point
+
+// Substrate defines the geometric layout of the nodes of the network to be built by CPPN. The nodes are arranged in
+// layers and each node of the layer can be connected only with nodes of the next layer, i.e., the inputs are
+// connected to the first hidden layer, the last hidden layer is connected to the outputs. If there are no hidden
+// layers, the inputs are connected directly to the outputs.
+type Substrate struct {
+	// The input nodes of the substrate
+	Inputs SubstrateLayer
+	// The hidden layers of the substrate
+	Hidden []SubstrateLayer
+	// The output nodes of the substrate
+	Outputs SubstrateLayer
+
+	// The number of dimensions of the nodes coordinates (2 or 3). The CPPN must have twice as many inputs to receive
+	// coordinates of the source and target nodes of each link.
+	Dimensions int
+
+	// The activation function of the hidden nodes
+	HiddenActivation neatmath.NodeActivationType
+	// The activation function of the output nodes
+	OutputActivation neatmath.NodeActivationType
+
+	// The minimal magnitude of the CPPN output to express the link. Links with smaller magnitude are not created.
+	WeightThreshold float64
+	// The maximal magnitude of the link weight, expressed CPPN outputs are scaled into [-MaxWeight;MaxWeight] range
+	MaxWeight float64
+}
+
+// NewSubstrate creates new substrate with given layers of nodes in the 2D or 3D space. The nodes of the created
+// substrate use sigmoid activation, and the CPPN outputs are expressed as links if their magnitude above 0.2 and
+// scaled to the maximal weight of 5.0.
+func NewSubstrate(dimensions int, inputs, outputs SubstrateLayer, hidden ...SubstrateLayer) (*Substrate, error) {
+	if dimensions != 2 && dimensions != 3 {
+		return nil, ErrUnsupportedSubstrateDim
+	}
+	if len(inputs) == 0 || len(outputs) == 0 {
+		return nil, ErrEmptySubstrateLayer
+	}
+	return &Substrate{
+		Inputs:           inputs,
+		Hidden:           hidden,
+		Outputs:          outputs,
+		Dimensions:       dimensions,
+		HiddenActivation: neatmath.SigmoidSteepenedActivation,
+		OutputActivation: neatmath.SigmoidSteepenedActivation,
+		WeightThreshold:  0.2,
+		MaxWeight:        5.0,
+	}, nil
+}
+
+// NodeCount Returns the total number of nodes in the substrate
+func (s *Substrate) NodeCount() int {
+	count := len(s.Inputs) + len(s.Outputs)
+	for _, layer := range s.Hidden {
+		count += len(layer)
+	}
+	return count
+}
+
+// CreateNetworkSolver is to build the network solver by querying provided CPPN for the weights of the links between
+// nodes of the substrate. The CPPN receives coordinates of the source node followed by coordinates of the target node
+// and its first output defines weight of the link. If CPPN has more than one output, the second output queried with
+// coordinates of the target node only (the source at origin) defines the bias of the target node.
+func (s *Substrate) CreateNetworkSolver(cppn *network.Network) (*network.FastModularNetworkSolver, error) {
+	if len(s.Inputs) == 0 || len(s.Outputs) == 0 {
+		return nil, ErrEmptySubstrateLayer
+	}
+	if s.Dimensions != 2 && s.Dimensions != 3 {
+		return nil, ErrUnsupportedSubstrateDim
+	}
+	q, err := newCPPNQuery(cppn, s.Dimensions)
+	if err != nil {
+		return nil, err
+	}
+
+	// The neurons are ordered as expected by the solver: bias, inputs, outputs, hidden
+	biasNeuronCount := 0
+	if q.outputs > 1 {
+		biasNeuronCount = 1
+	}
+	totalNeuronCount := biasNeuronCount + s.NodeCount()
+	layers := make([]SubstrateLayer, 0, len(s.Hidden)+2)
+	layers = append(layers, s.Inputs)
+	layers = append(layers, s.Hidden...)
+	layers = append(layers, s.Outputs)
+
+	// find index of the first neuron for each layer
+	indexes := make([]int, len(layers))
+	indexes[0] = biasNeuronCount
+	indexes[len(layers)-1] = biasNeuronCount + len(s.Inputs)
+	nextIndex := indexes[len(layers)-1] + len(s.Outputs)
+	for i := 1; i < len(layers)-1; i++ {
+		indexes[i] = nextIndex
+		nextIndex += len(layers[i])
+	}
+
+	activations := make([]neatmath.NodeActivationType, totalNeuronCount)
+	for i := 0; i < biasNeuronCount+len(s.Inputs); i++ {
+		activations[i] = neatmath.NullActivation
+	}
+	biases := make([]float64, totalNeuronCount)
+	connections := make([]*network.FastNetworkLink, 0)
+	for l := 1; l < len(layers); l++ {
+		activation := s.HiddenActivation
+		if l == len(layers)-1 {
+			activation = s.OutputActivation
+		}
+		for t, target := range layers[l] {
+			targetIndex := indexes[l] + t
+			activations[targetIndex] = activation
+
+			// query links from the previous layer
+			for src, source := range layers[l-1] {
+				outs, err := q.query(source, target)
+				if err != nil {
+					return nil, err
+				}
+				if weight, ok := s.expressWeight(outs[0]); ok {
+					connections = append(connections, &network.FastNetworkLink{
+						SourceIndex: indexes[l-1] + src,
+						TargetIndex: targetIndex,
+						Weight:      weight,
+					})
+				}
+			}
+
+			// query bias
+			if biasNeuronCount > 0 {
+				outs, err := q.query(Point{}, target)
+				if err != nil {
+					return nil, err
+				}
+				if bias, ok := s.expressWeight(outs[1]); ok {
+					biases[targetIndex] = bias
+				}
+			}
+		}
+	}
+
+	solver := network.NewFastModularNetworkSolver(biasNeuronCount, len(s.Inputs), len(s.Outputs), totalNeuronCount,
+		activations, connections, biases, nil)
+	solver.Id = cppn.Id
+	solver.Name = fmt.Sprintf("substrate of CPPN %d", cppn.Id)
+	return solver, nil
+}
+
+// expressWeight is to convert CPPN output into the link weight. Returns false if link should not be expressed.
+func (s *Substrate) expressWeight(out float64) (float64, bool) {
+	magnitude := math.Abs(out)
+	if magnitude <= s.WeightThreshold {
+		return 0, false
+	}
+	if magnitude > 1.0 {
+		magnitude = 1.0
+	}
+	weight := (magnitude - s.WeightThreshold) / (1.0 - s.WeightThreshold) * s.MaxWeight
+	if out < 0 {
+		weight = -weight
+	}
+	return weight, true
+}
+
+// cppnQuery holds the CPPN solver prepared to be queried for coordinates of the substrate nodes
+type cppnQuery struct {
+	solver     network.Solver
+	depth      int
+	dimensions int
+	outputs    int
+	inputs     []float64
+}
+
+func newCPPNQuery(cppn *network.Network, dimensions int) (*cppnQuery, error) {
+	solver, err := cppn.FastNetworkSolver()
+	if err != nil {
+		return nil, err
+	}
+	outputs := len(cppn.Outputs)
+	if outputs == 0 {
+		return nil, ErrCPPNNoOutputs
+	}
+	depth, err := cppn.MaxActivationDepthWithCap(0)
+	if err != nil {
+		return nil, err
+	}
+	q := &cppnQuery{
+		solver:     solver,
+		depth:      depth,
+		dimensions: dimensions,
+		outputs:    outputs,
+		inputs:     make([]float64, dimensions*2),
+	}
+	// check inputs size using solver validation
+	if err = solver.LoadSensors(q.inputs); err != nil {
+		return nil, ErrCPPNInputsSizeMismatch
+	}
+	return q, nil
+}
+
+// query is to activate CPPN with coordinates of the source and target nodes and return its outputs
+func (q *cppnQuery) query(source, target Point) ([]float64, error) {
+	q.inputs[0], q.inputs[1] = source.X, source.Y
+	q.inputs[q.dimensions], q.inputs[q.dimensions+1] = target.X, target.Y
+	if q.dimensions == 3 {
+		q.inputs[2], q.inputs[5] = source.Z, target.Z
+	}
+	if _, err := q.solver.Flush(); err != nil {
+		return nil, err
+	}
+	if err := q.solver.LoadSensors(q.inputs); err != nil {
+		return nil, err
+	}
+	if _, err := q.solver.ForwardSteps(q.depth); err != nil {
+		return nil, err
+	}
+	return q.solver.ReadOutputs(), nil
+}
@@ -0,0 +1,134 @@
+package hyperneat
+
+import (
+	neatmath "deepneat/neat/math"
+	"deepneat/neat/network"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildCPPN builds CPPN for 2D substrate which outputs X coordinate of the target node as weight and
+// negated Y coordinate of the target node as bias
+func buildCPPN(withBias bool) *network.Network {
+	allNodes := []*network.NNode{
+		network.NewNNode(1, network.InputNeuron),
+		network.NewNNode(2, network.InputNeuron),
+		network.NewNNode(3, network.InputNeuron),
+		network.NewNNode(4, network.InputNeuron),
+		network.NewNNode(5, network.OutputNeuron),
+	}
+	allNodes[4].ActivationType = neatmath.LinearActivation
+	allNodes[4].ConnectFrom(allNodes[2], 1.0)
+	if withBias {
+		biasNode := network.NewNNode(6, network.OutputNeuron)
+		biasNode.ActivationType = neatmath.LinearActivation
+		biasNode.ConnectFrom(allNodes[3], -1.0)
+		allNodes = append(allNodes, biasNode)
+	}
+	return network.NewNetwork(allNodes[0:4], allNodes[4:], allNodes, 1)
+}
+
+func TestNewSubstrate(t *testing.T) {
+	_, err := NewSubstrate(4, SubstrateLayer{{}}, SubstrateLayer{{}})
+	assert.ErrorIs(t, err, ErrUnsupportedSubstrateDim)
+
+	_, err = NewSubstrate(2, SubstrateLayer{}, SubstrateLayer{{}})
+	assert.ErrorIs(t, err, ErrEmptySubstrateLayer)
+
+	substrate, err := NewSubstrate(3, SubstrateLayer{{}, {}}, SubstrateLayer{{}}, SubstrateLayer{{}, {}, {}})
+	require.NoError(t, err)
+	assert.Equal(t, 6, substrate.NodeCount())
+}
+
+func TestSubstrate_CreateNetworkSolver(t *testing.T) {
+	inputs := SubstrateLayer{{X: -1, Y: -1}, {X: 1, Y: -1}}
+	outputs := SubstrateLayer{{X: 0.5, Y: 1}, {X: -0.1, Y: 1}, {X: -1, Y: 1}}
+	substrate, err := NewSubstrate(2, inputs, outputs)
+	require.NoError(t, err)
+	substrate.OutputActivation = neatmath.LinearActivation
+
+	solver, err := substrate.CreateNetworkSolver(buildCPPN(false))
+	require.NoError(t, err, "failed to create solver")
+	assert.Equal(t, 5, solver.NodeCount())
+	// the links to the second output are below threshold
+	assert.Equal(t, 4, solver.LinkCount())
+
+	err = solver.LoadSensors([]float64{1, 1})
+	require.NoError(t, err)
+	_, err = solver.ForwardSteps(1)
+	require.NoError(t, err)
+	outs := solver.ReadOutputs()
+	require.Len(t, outs, 3)
+	assert.InDelta(t, 3.75, outs[0], 1e-9)
+	assert.Equal(t, 0.0, outs[1])
+	assert.InDelta(t, -10.0, outs[2], 1e-9)
+}
+
+func TestSubstrate_CreateNetworkSolver_hiddenWithBias(t *testing.T) {
+	inputs := SubstrateLayer{{X: 1, Y: -1}}
+	hidden := SubstrateLayer{{X: 1, Y: -0.6}}
+	outputs := SubstrateLayer{{X: 1, Y: 1}}
+	substrate, err := NewSubstrate(2, inputs, outputs, hidden)
+	require.NoError(t, err)
+	substrate.HiddenActivation = neatmath.LinearActivation
+	substrate.OutputActivation = neatmath.LinearActivation
+
+	solver, err := substrate.CreateNetworkSolver(buildCPPN(true))
+	require.NoError(t, err, "failed to create solver")
+	// bias, input, hidden, and output
+	assert.Equal(t, 4, solver.NodeCount())
+	// input -> hidden, hidden -> output, and hidden and output biases
+	assert.Equal(t, 4, solver.LinkCount())
+
+	err = solver.LoadSensors([]float64{1})
+	require.NoError(t, err)
+	_, err = solver.ForwardSteps(2)
+	require.NoError(t, err)
+	outs := solver.ReadOutputs()
+	require.Len(t, outs, 1)
+	// hidden: 1 * 5 + 2.5 = 7.5, output: 7.5 * 5 - 5 = 32.5
+	assert.InDelta(t, 32.5, outs[0], 1e-9)
+}
+
+func TestSubstrate_CreateNetworkSolver_inputsMismatch(t *testing.T) {
+	substrate, err := NewSubstrate(3, SubstrateLayer{{}}, SubstrateLayer{{}})
+	require.NoError(t, err)
+	_, err = substrate.CreateNetworkSolver(buildCPPN(false))
+	assert.ErrorIs(t, err, ErrCPPNInputsSizeMismatch)
+}
+
+func TestSubstrate_CreateNetworkSolver_3D(t *testing.T) {
+	// CPPN outputs difference between Z coordinates of the source and target nodes as weight
+	allNodes := make([]*network.NNode, 7)
+	for i := 0; i < 6; i++ {
+		allNodes[i] = network.NewNNode(i+1, network.InputNeuron)
+	}
+	allNodes[6] = network.NewNNode(7, network.OutputNeuron)
+	allNodes[6].ActivationType = neatmath.LinearActivation
+	allNodes[6].ConnectFrom(allNodes[2], 1.0)
+	allNodes[6].ConnectFrom(allNodes[5], -1.0)
+	cppn := network.NewNetwork(allNodes[0:6], allNodes[6:], allNodes, 1)
+
+	inputs := SubstrateLayer{{X: 1, Y: 1, Z: 1}, {X: 1, Y: 1, Z: 0}, {X: 1, Y: 1, Z: -0.5}}
+	outputs := SubstrateLayer{{X: -1, Y: -1, Z: -0.5}}
+	substrate, err := NewSubstrate(3, inputs, outputs)
+	require.NoError(t, err)
+	substrate.OutputActivation = neatmath.LinearActivation
+
+	solver, err := substrate.CreateNetworkSolver(cppn)
+	require.NoError(t, err, "failed to create solver")
+	assert.Equal(t, 4, solver.NodeCount())
+	// the link from the input at the same depth as output is not expressed
+	assert.Equal(t, 2, solver.LinkCount())
+
+	err = solver.LoadSensors([]float64{1, 1, 1})
+	require.NoError(t, err)
+	_, err = solver.ForwardSteps(1)
+	require.NoError(t, err)
+	outs := solver.ReadOutputs()
+	require.Len(t, outs, 1)
+	// weights: 1.5 is capped to 1 -> 5, 0.5 -> (0.5 - 0.2) / 0.8 * 5 = 1.875
+	assert.InDelta(t, 6.875, outs[0], 1e-9)
+}
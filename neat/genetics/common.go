@@ -17,6 +17,8 @@ const (
 	newNodeInnType innovationType = iota + 1
 	// The novelty will be introduced by new NN link
 	newLinkInnType
+	// The novelty will be introduced by new MIMO module
+	newModuleInnType
 )
 
 // The mutator type that specifies a kind of mutation of connection weights between NN nodes
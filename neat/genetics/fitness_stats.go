@@ -0,0 +1,60 @@
+package genetics
+
+import (
+	"deepneat/neat"
+	"sort"
+)
+
+// aggregateFitness is to aggregate the fitness samples with given function. If function is not set, the mean is used.
+func aggregateFitness(samples []float64, aggregation neat.FitnessAggregation) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	switch aggregation {
+	case neat.FitnessAggregationMedian:
+		return median(samples)
+	case neat.FitnessAggregationWorst:
+		worst := samples[0]
+		for _, v := range samples {
+			if v < worst {
+				worst = v
+			}
+		}
+		return worst
+	default:
+		return mean(samples)
+	}
+}
+
+// mean is to get the average of the values
+func mean(values []float64) float64 {
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total / float64(len(values))
+}
+
+// median is to get the median of the values without reordering them
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+// variance is to get the unbiased variance of the values or zero if there are less than two values
+func variance(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	m := mean(values)
+	sum := 0.0
+	for _, v := range values {
+		sum += (v - m) * (v - m)
+	}
+	return sum / float64(len(values)-1)
+}
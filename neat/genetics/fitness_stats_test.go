@@ -0,0 +1,66 @@
+package genetics
+
+import (
+	"deepneat/neat"
+	"deepneat/neat/math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_aggregateFitness(t *testing.T) {
+	samples := []float64{3, 1, 8, 4}
+	assert.Equal(t, 4.0, aggregateFitness(samples, ""))
+	assert.Equal(t, 4.0, aggregateFitness(samples, neat.FitnessAggregationMean))
+	assert.Equal(t, 3.5, aggregateFitness(samples, neat.FitnessAggregationMedian))
+	assert.Equal(t, 1.0, aggregateFitness(samples, neat.FitnessAggregationWorst))
+	assert.Equal(t, []float64{3, 1, 8, 4}, samples, "samples must not be reordered")
+	assert.Zero(t, aggregateFitness(nil, neat.FitnessAggregationMean))
+}
+
+func Test_variance(t *testing.T) {
+	assert.Zero(t, variance(nil))
+	assert.Zero(t, variance([]float64{5}))
+	assert.InDelta(t, 26.0/3.0, variance([]float64{3, 1, 8, 4}), 1e-12)
+}
+
+func TestPopulationEpochExecutor_NextEpoch_reevaluateElites(t *testing.T) {
+	for _, ex := range []PopulationEpochExecutor{&SequentialPopulationEpochExecutor{}, &ParallelPopulationEpochExecutor{}} {
+		conf := &neat.Options{
+			CompatThreshold:       3.0,
+			DropOffAge:            20,
+			SurvivalThresh:        0.5,
+			PopSize:               30,
+			MutateOnlyProb:        0.5,
+			MutateAddLinkProb:     0.2,
+			MutateLinkWeightsProb: 0.8,
+			MateMultipointProb:    1.0,
+			PopulationElitism:     1,
+			ReevaluateElites:      true,
+			NodeActivators:        []math.NodeActivationType{math.GaussianBipolarActivation},
+			NodeActivatorsProb:    []float64{1.0},
+		}
+		var best *Organism
+		evaluate := func(pop *Population, _ *neat.Options) {
+			best = nil
+			for j, org := range pop.Organisms {
+				org.AddFitnessSamples([]float64{float64(j + 1)}, conf.FitnessAggregation)
+				if best == nil || org.Fitness > best.Fitness {
+					best = org
+				}
+			}
+		}
+		pop := runSeededEpochs(t, conf, 42, 3, ex, evaluate)
+
+		// the clone of the population champion inherits its samples
+		found := false
+		for _, org := range pop.Organisms {
+			if len(org.ParentIds) == 1 && org.ParentIds[0] == best.Genotype.Id && len(org.FitnessSamples) > 0 {
+				assert.Equal(t, best.FitnessSamples, org.FitnessSamples)
+				assert.Equal(t, best.Reevaluations, org.Reevaluations)
+				found = true
+			}
+		}
+		assert.True(t, found, "elite clone with inherited samples expected")
+	}
+}
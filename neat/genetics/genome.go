@@ -7,8 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"math/rand"
 	"reflect"
+	"sort"
 )
 
 // A Genome is the primary source of genotype information used to create  a phenotype.
@@ -80,6 +80,8 @@ func newGenomeWithNodeIdMap(id int, traits []*neat.Trait, nodes []*network.NNode
 // connectivity.  If rec is true then recurrent connections will be included. The last input is a bias
 // link_prob is the probability of a link. The created genome is not modular.
 func newGenomeRand(newId, in, out, n, maxHidden int, recurrent bool, linkProb float64, opts *neat.Options) (*Genome, error) {
+	rng := opts.Rand()
+
 	totalNodes := in + out + maxHidden
 	matrixDim := totalNodes * totalNodes
 	// The connection matrix which will be randomized
@@ -105,7 +107,7 @@ func newGenomeRand(newId, in, out, n, maxHidden int, recurrent bool, linkProb fl
 
 	// Step through the connection matrix, randomly assigning bits
 	for count := 0; count < matrixDim; count++ {
-		cm[count] = rand.Float64() < linkProb
+		cm[count] = rng.Float64() < linkProb
 	}
 
 	// Build the input nodes
@@ -184,7 +186,7 @@ func newGenomeRand(newId, in, out, n, maxHidden int, recurrent bool, linkProb fl
 					}
 
 					// Create the gene
-					weight := float64(math.RandSign()) * rand.Float64()
+					weight := float64(math.RandSignWith(rng)) * rng.Float64()
 					gene := NewGeneWithTrait(newTrait, weight, inNode, outNode, flagRecurrent, int64(count), weight)
 
 					//Add the gene to the genome
@@ -361,6 +363,77 @@ func (g *Genome) addNodes(nodes []*network.NNode) {
 	}
 }
 
+// Removes provided node from this Genome. The genes linking the node should be removed by the caller.
+func (g *Genome) removeNode(node *network.NNode) {
+	for i, n := range g.Nodes {
+		if n.Id == node.Id {
+			g.Nodes = append(g.Nodes[:i:i], g.Nodes[i+1:]...)
+			break
+		}
+	}
+	delete(g.nodeByIdMap, node.Id)
+}
+
+// Returns true if provided node is one of the IO nodes of any control gene of this Genome
+func (g *Genome) isControlGeneIONode(node *network.NNode) bool {
+	for _, cg := range g.ControlGenes {
+		for _, n := range cg.ioNodes {
+			if n.Id == node.Id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Returns true if provided node is one of the output nodes of any control gene of this Genome. The activation of such
+// node is set by the module rather than by its incoming links.
+func (g *Genome) isControlGeneOutputNode(node *network.NNode) bool {
+	for _, cg := range g.ControlGenes {
+		for _, l := range cg.ControlNode.Outgoing {
+			if l.OutNode.Id == node.Id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Returns true if the signal flows forward from the node with fromId to the node with toId through the not recurrent
+// genes and through the modules of this Genome, i.e., the not recurrent link from toId to fromId would create a loop.
+// The gene provided as except is not considered.
+func (g *Genome) isForwardPath(fromId, toId int, except *Gene) bool {
+	next := make(map[int][]int)
+	for _, gene := range g.Genes {
+		if gene != except && !gene.Link.IsRecurrent {
+			next[gene.Link.InNode.Id] = append(next[gene.Link.InNode.Id], gene.Link.OutNode.Id)
+		}
+	}
+	for _, cg := range g.ControlGenes {
+		for _, in := range cg.ControlNode.Incoming {
+			for _, out := range cg.ControlNode.Outgoing {
+				next[in.InNode.Id] = append(next[in.InNode.Id], out.OutNode.Id)
+			}
+		}
+	}
+
+	visited := make(map[int]bool)
+	stack := []int{fromId}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if id == toId {
+			return true
+		}
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		stack = append(stack, next[id]...)
+	}
+	return false
+}
+
 func (g *Genome) mapNodeId(node *network.NNode) {
 	g.nodeByIdMap[node.Id] = node
 }
@@ -496,6 +569,28 @@ func (g *Genome) Genesis(netId int) (*network.Network, error) {
 	return newNet, nil
 }
 
+// writeBackWeights is to set the weights of links of the enabled genes from the matching links of given phenotype
+// built by Genesis, e.g., after its weights were tuned
+func (g *Genome) writeBackWeights(phenotype *network.Network) {
+	type linkKey struct {
+		inId, outId int
+		recurrent   bool
+	}
+	genes := make(map[linkKey]*Gene, len(g.Genes))
+	for _, gn := range g.Genes {
+		if gn.IsEnabled {
+			genes[linkKey{gn.Link.InNode.Id, gn.Link.OutNode.Id, gn.Link.IsRecurrent}] = gn
+		}
+	}
+	for _, node := range phenotype.AllNodes() {
+		for _, link := range node.Incoming {
+			if gn, ok := genes[linkKey{link.InNode.Id, link.OutNode.Id, link.IsRecurrent}]; ok {
+				gn.Link.ConnectionWeight = link.ConnectionWeight
+			}
+		}
+	}
+}
+
 // Duplicate this Genome to create a new one with the specified id
 func (g *Genome) duplicate(newId int) (*Genome, error) {
 
@@ -532,40 +627,49 @@ func (g *Genome) duplicate(newId int) (*Genome, error) {
 func (g *Genome) duplicateControlGenes(traits []*neat.Trait, nodeIdMap map[int]*network.NNode) ([]*MIMOControlGene, error) {
 	controlGenesDup := make([]*MIMOControlGene, len(g.ControlGenes))
 	for i, cg := range g.ControlGenes {
-		// duplicate control node
-		controlNode := cg.ControlNode
-		// find duplicate of trait associated with control node
-		assocTrait := controlNode.Trait
-		if assocTrait != nil {
-			assocTrait = TraitWithId(assocTrait.Id, traits)
-		}
-		nodeCopy := network.NewNNodeCopy(controlNode, assocTrait)
-		// add incoming links
-		for _, l := range controlNode.Incoming {
-			inNode, ok := nodeIdMap[l.InNode.Id]
-			if !ok {
-				return nil, fmt.Errorf("incoming node: %d not found for control node: %d",
-					l.InNode.Id, controlNode.Id)
-			}
-			newInLink := network.NewLinkCopy(l, inNode, nodeCopy)
-			nodeCopy.Incoming = append(nodeCopy.Incoming, newInLink)
+		controlGene, err := copyControlGene(cg, traits, nodeIdMap)
+		if err != nil {
+			return nil, err
 		}
+		controlGenesDup[i] = controlGene
+	}
+	return controlGenesDup, nil
+}
 
-		// add outgoing links
-		for _, l := range controlNode.Outgoing {
-			outNode, ok := nodeIdMap[l.OutNode.Id]
-			if !ok {
-				return nil, fmt.Errorf("outgoing node: %d not found for control node: %d",
-					l.InNode.Id, controlNode.Id)
-			}
-			newOutLink := network.NewLinkCopy(l, nodeCopy, outNode)
-			nodeCopy.Outgoing = append(nodeCopy.Outgoing, newOutLink)
+// Creates copy of the given MIMO control gene with control node linked to the nodes with the same IDs from nodeIdMap
+func copyControlGene(cg *MIMOControlGene, traits []*neat.Trait, nodeIdMap map[int]*network.NNode) (*MIMOControlGene, error) {
+	// duplicate control node
+	controlNode := cg.ControlNode
+	// find duplicate of trait associated with control node
+	assocTrait := controlNode.Trait
+	if assocTrait != nil {
+		assocTrait = TraitWithId(assocTrait.Id, traits)
+	}
+	nodeCopy := network.NewNNodeCopy(controlNode, assocTrait)
+	// add incoming links
+	for _, l := range controlNode.Incoming {
+		inNode, ok := nodeIdMap[l.InNode.Id]
+		if !ok {
+			return nil, fmt.Errorf("incoming node: %d not found for control node: %d",
+				l.InNode.Id, controlNode.Id)
 		}
+		newInLink := network.NewLinkCopy(l, inNode, nodeCopy)
+		nodeCopy.Incoming = append(nodeCopy.Incoming, newInLink)
+	}
 
-		// add MIMO control gene
-		controlGenesDup[i] = NewMIMOGeneCopy(cg, nodeCopy)
+	// add outgoing links
+	for _, l := range controlNode.Outgoing {
+		outNode, ok := nodeIdMap[l.OutNode.Id]
+		if !ok {
+			return nil, fmt.Errorf("outgoing node: %d not found for control node: %d",
+				l.OutNode.Id, controlNode.Id)
+		}
+		newOutLink := network.NewLinkCopy(l, nodeCopy, outNode)
+		nodeCopy.Outgoing = append(nodeCopy.Outgoing, newOutLink)
 	}
-	return controlGenesDup, nil
+
+	// create MIMO control gene
+	return NewMIMOGeneCopy(cg, nodeCopy), nil
 }
 
 func (g *Genome) duplicateGenes(traits []*neat.Trait, nodeIdMap map[int]*network.NNode) ([]*Gene, error) {
@@ -646,6 +750,23 @@ func (g *Genome) verify() (bool, error) {
 		}
 	}
 
+	// Check each control gene's IO nodes and make sure that control node IDs are unique
+	for _, cg := range g.ControlGenes {
+		for _, node := range cg.ioNodes {
+			if NodeWithId(node.Id, g.Nodes) == nil {
+				return false, fmt.Errorf("missing IO node: %d of control gene in the genome nodes list: %s", node.Id, cg)
+			}
+		}
+		if NodeWithId(cg.ControlNode.Id, g.Nodes) != nil {
+			return false, fmt.Errorf("control node ID: %d is used by the genome node: %s", cg.ControlNode.Id, cg)
+		}
+		for _, cg2 := range g.ControlGenes {
+			if cg != cg2 && (cg.ControlNode.Id == cg2.ControlNode.Id || cg.InnovationNum == cg2.InnovationNum) {
+				return false, fmt.Errorf("duplicate control genes found: %s == %s", cg, cg2)
+			}
+		}
+	}
+
 	// Check for NNodes being out of order
 	lastId := 0
 	for _, n := range g.Nodes {
@@ -686,6 +807,18 @@ func (g *Genome) geneInsert(gene *Gene) {
 	g.Genes = geneInsert(g.Genes, gene)
 }
 
+func (g *Genome) controlGeneInsert(gene *MIMOControlGene) {
+	g.ControlGenes = append(g.ControlGenes, gene)
+	sortControlGenes(g.ControlGenes)
+}
+
+// Sorts given MIMO control genes in ascending order by innovation number
+func sortControlGenes(genes []*MIMOControlGene) {
+	sort.SliceStable(genes, func(i, j int) bool {
+		return genes[i].InnovationNum < genes[j].InnovationNum
+	})
+}
+
 // Inserts a NNode into a given ordered list of NNodes in ascending order by NNode ID
 func nodeInsert(nodes []*network.NNode, n *network.NNode) []*network.NNode {
 	if n == nil {
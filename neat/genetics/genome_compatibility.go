@@ -4,6 +4,7 @@ import (
 	"math"
 
 	"deepneat/neat"
+	neatmath "deepneat/neat/math"
 )
 
 /* ******** COMPATIBILITY CHECKING METHODS * ********/
@@ -15,13 +16,73 @@ import (
 // The three coefficients are global system parameters.
 // The bigger returned value the less compatible the genomes.
 //
+// The MIMO control genes of modular genomes are compared by innovation numbers as well: each control gene found only in
+// one of the genomes is accounted as disjoint and the average mutational difference of matching control genes is added
+// with mutdiff_coeff.
+//
+// If activation difference coefficient is set, the fraction of matching neuron nodes with different activation
+// functions is also accounted: activation_diff_coeff * pdan, where pdan - PERCENT DIFFERENT ACTIVATION NODES.
+//
 // Fully compatible genomes has 0.0 returned.
 func (g *Genome) compatibility(og *Genome, opts *neat.Options) float64 {
+	var comp float64
 	if opts.GenCompatMethod == neat.GenomeCompatibilityMethodLinear {
-		return g.compatLinear(og, opts)
+		comp = g.compatLinear(og, opts)
 	} else {
-		return g.compatFast(og, opts)
+		comp = g.compatFast(og, opts)
+	}
+	if len(g.ControlGenes) > 0 || len(og.ControlGenes) > 0 {
+		comp += g.modulesDifference(og, opts)
+	}
+	if opts.ActivationDiffCoeff > 0 {
+		comp += opts.ActivationDiffCoeff * g.activationDifference(og)
+	}
+	return comp
+}
+
+// Returns the compatibility distance between MIMO control genes of both genomes: disjoint_coeff * ndm + mutdiff_coeff * mdmm
+// where: ndm - NUMBER OF DISJOINT MODULES, and mdmm - MUTATIONAL DIFFERENCE WITHIN MATCHING MODULES
+func (g *Genome) modulesDifference(og *Genome, opts *neat.Options) float64 {
+	mutationNums := make(map[int64]float64, len(og.ControlGenes))
+	for _, cg := range og.ControlGenes {
+		mutationNums[cg.InnovationNum] = cg.MutationNum
+	}
+	numMatching, mutDiffTotal := 0.0, 0.0
+	for _, cg := range g.ControlGenes {
+		if mutationNum, ok := mutationNums[cg.InnovationNum]; ok {
+			numMatching += 1.0
+			mutDiffTotal += math.Abs(cg.MutationNum - mutationNum)
+		}
+	}
+	numDisjoint := float64(len(g.ControlGenes)+len(og.ControlGenes)) - 2*numMatching
+	comp := opts.DisjointCoeff * numDisjoint
+	if numMatching > 0 {
+		comp += opts.MutdiffCoeff * (mutDiffTotal / numMatching)
+	}
+	return comp
+}
+
+// Returns the fraction of neuron nodes found in both genomes which have different activation functions
+func (g *Genome) activationDifference(og *Genome) float64 {
+	activations := make(map[int]neatmath.NodeActivationType, len(og.Nodes))
+	for _, node := range og.Nodes {
+		if node.IsNeuron() {
+			activations[node.Id] = node.ActivationType
+		}
+	}
+	numMatching, numDifferent := 0.0, 0.0
+	for _, node := range g.Nodes {
+		if activation, ok := activations[node.Id]; ok && node.IsNeuron() {
+			numMatching += 1.0
+			if activation != node.ActivationType {
+				numDifferent += 1.0
+			}
+		}
+	}
+	if numMatching == 0 {
+		return 0.0
 	}
+	return numDifferent / numMatching
 }
 
 // The compatibility checking method with linear performance depending on the size of the lognest genome in comparison.
@@ -2,6 +2,7 @@ package genetics
 
 import (
 	"deepneat/neat"
+	"deepneat/neat/math"
 	"deepneat/neat/network"
 	"testing"
 
@@ -75,6 +76,52 @@ func TestGenome_Compatibility_Fast(t *testing.T) {
 	assert.Equal(t, 2.0, comp)
 }
 
+func TestGenome_Compatibility_activationDifference(t *testing.T) {
+	gnome1 := buildTestModularGenome(1)
+	gnome2 := buildTestModularGenome(2)
+
+	conf := neat.Options{
+		DisjointCoeff:   0.5,
+		ExcessCoeff:     0.5,
+		MutdiffCoeff:    0.5,
+		GenCompatMethod: neat.GenomeCompatibilityMethodFast,
+	}
+	gnome2.Nodes[3].ActivationType = math.TanhActivation
+	assert.Equal(t, 0.0, gnome1.compatibility(gnome2, &conf), "activation difference must be ignored")
+
+	// one of four matching neurons has different activation
+	conf.ActivationDiffCoeff = 2.0
+	assert.Equal(t, 0.5, gnome1.compatibility(gnome2, &conf))
+
+	// the sensors are not counted
+	gnome2.Nodes[0].ActivationType = math.TanhActivation
+	assert.Equal(t, 0.5, gnome1.compatibility(gnome2, &conf))
+}
+
+func TestGenome_Compatibility_modules(t *testing.T) {
+	conf := neat.Options{
+		DisjointCoeff:   0.5,
+		ExcessCoeff:     0.5,
+		MutdiffCoeff:    0.5,
+		GenCompatMethod: neat.GenomeCompatibilityMethodLinear,
+	}
+	for _, method := range []neat.GenomeCompatibilityMethod{neat.GenomeCompatibilityMethodLinear, neat.GenomeCompatibilityMethodFast} {
+		conf.GenCompatMethod = method
+		gnome1 := buildTestModularGenome(1)
+		gnome2 := buildTestModularGenome(2)
+		assert.Equal(t, 0.0, gnome1.compatibility(gnome2, &conf), method)
+
+		// matching control genes with different mutation numbers
+		gnome2.ControlGenes[0].MutationNum = 7.5
+		assert.Equal(t, 1.0, gnome1.compatibility(gnome2, &conf), method)
+
+		// disjoint control genes
+		gnome2.ControlGenes[0].InnovationNum = 8
+		assert.Equal(t, 1.0, gnome1.compatibility(gnome2, &conf), method)
+		assert.Equal(t, 1.0, gnome2.compatibility(gnome1, &conf), method)
+	}
+}
+
 func TestGenome_Compatibility_Duplicate(t *testing.T) {
 	//rand.Seed(42)
 	gnome1 := buildTestGenome(1)
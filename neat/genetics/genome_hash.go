@@ -0,0 +1,162 @@
+package genetics
+
+import (
+	"deepneat/neat"
+	"deepneat/neat/network"
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math"
+	"sort"
+)
+
+// Hash is to calculate the canonical structural hash of this genome. The genomes with the same hash encode identical
+// phenotypes, thus they can share results of the deterministic evaluation. The hash covers the nodes with their
+// activation and aggregation functions, the enabled genes with their weights, the enabled control genes, and the
+// traits. It doesn't depend on the genome ID, the order of nodes and genes, the historical markers of genes and the
+// disabled genes, which don't contribute to the phenotype.
+func (g *Genome) Hash() uint64 {
+	h := genomeHasher{hash: fnv.New64a()}
+
+	traits := make([]*neat.Trait, len(g.Traits))
+	copy(traits, g.Traits)
+	sort.Slice(traits, func(i, j int) bool {
+		return traits[i].Id < traits[j].Id
+	})
+	h.writeInt(len(traits))
+	for _, t := range traits {
+		h.writeInt(t.Id)
+		h.writeFloats(t.Params)
+	}
+
+	nodes := make([]*network.NNode, len(g.Nodes))
+	copy(nodes, g.Nodes)
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Id < nodes[j].Id
+	})
+	h.writeInt(len(nodes))
+	for _, n := range nodes {
+		h.writeNode(n)
+	}
+
+	links := make([]*network.Link, 0, len(g.Genes))
+	for _, gene := range g.Genes {
+		if gene.IsEnabled {
+			links = append(links, gene.Link)
+		}
+	}
+	sortLinks(links)
+	h.writeInt(len(links))
+	for _, l := range links {
+		h.writeLink(l)
+	}
+
+	controlNodes := make([]*network.NNode, 0, len(g.ControlGenes))
+	for _, cg := range g.ControlGenes {
+		if cg.IsEnabled {
+			controlNodes = append(controlNodes, cg.ControlNode)
+		}
+	}
+	sort.Slice(controlNodes, func(i, j int) bool {
+		return controlNodes[i].Id < controlNodes[j].Id
+	})
+	h.writeInt(len(controlNodes))
+	for _, cn := range controlNodes {
+		h.writeNode(cn)
+		h.writeInt(len(cn.Incoming))
+		for _, l := range cn.Incoming {
+			h.writeLink(l)
+		}
+		h.writeInt(len(cn.Outgoing))
+		for _, l := range cn.Outgoing {
+			h.writeLink(l)
+		}
+	}
+
+	return h.hash.Sum64()
+}
+
+// sortLinks is to sort links by IDs of their source and target nodes, and by other properties of parallel links
+func sortLinks(links []*network.Link) {
+	sort.SliceStable(links, func(i, j int) bool {
+		li, lj := links[i], links[j]
+		if li.InNode.Id != lj.InNode.Id {
+			return li.InNode.Id < lj.InNode.Id
+		}
+		if li.OutNode.Id != lj.OutNode.Id {
+			return li.OutNode.Id < lj.OutNode.Id
+		}
+		if li.IsRecurrent != lj.IsRecurrent {
+			return !li.IsRecurrent
+		}
+		if li.IsTimeDelayed != lj.IsTimeDelayed {
+			return !li.IsTimeDelayed
+		}
+		return li.ConnectionWeight < lj.ConnectionWeight
+	})
+}
+
+// genomeHasher writes genome constituents into the hash in the fixed binary format
+type genomeHasher struct {
+	hash hash.Hash64
+	buf  [8]byte
+}
+
+func (h *genomeHasher) writeUint(v uint64) {
+	binary.LittleEndian.PutUint64(h.buf[:], v)
+	_, _ = h.hash.Write(h.buf[:])
+}
+
+func (h *genomeHasher) writeInt(v int) {
+	h.writeUint(uint64(v))
+}
+
+func (h *genomeHasher) writeBool(v bool) {
+	if v {
+		h.writeUint(1)
+	} else {
+		h.writeUint(0)
+	}
+}
+
+func (h *genomeHasher) writeFloat(v float64) {
+	if v == 0 {
+		// the positive and negative zeros are the same value
+		v = 0
+	}
+	h.writeUint(math.Float64bits(v))
+}
+
+func (h *genomeHasher) writeFloats(v []float64) {
+	h.writeInt(len(v))
+	for _, f := range v {
+		h.writeFloat(f)
+	}
+}
+
+func (h *genomeHasher) writeTraitId(t *neat.Trait) {
+	if t == nil {
+		h.writeInt(0)
+	} else {
+		h.writeInt(t.Id)
+	}
+}
+
+func (h *genomeHasher) writeNode(n *network.NNode) {
+	h.writeInt(n.Id)
+	h.writeInt(int(n.NeuronType))
+	h.writeInt(int(n.ActivationType))
+	h.writeInt(int(n.AggregationType))
+	h.writeFloat(n.Bias)
+	h.writeFloat(n.Response)
+	h.writeTraitId(n.Trait)
+}
+
+func (h *genomeHasher) writeLink(l *network.Link) {
+	h.writeInt(l.InNode.Id)
+	h.writeInt(l.OutNode.Id)
+	h.writeFloat(l.ConnectionWeight)
+	h.writeBool(l.IsRecurrent)
+	h.writeBool(l.IsTimeDelayed)
+	h.writeTraitId(l.Trait)
+}
@@ -0,0 +1,97 @@
+package genetics
+
+import (
+	"deepneat/neat/math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenome_Hash(t *testing.T) {
+	gnome := buildTestGenome(1)
+	hash := gnome.Hash()
+	assert.Equal(t, hash, gnome.Hash(), "hash must be stable")
+
+	// the copy with another ID has the same hash
+	dup, err := gnome.duplicate(2)
+	require.NoError(t, err)
+	assert.Equal(t, hash, dup.Hash())
+
+	// the order of nodes and genes doesn't matter
+	dup.Nodes[0], dup.Nodes[3] = dup.Nodes[3], dup.Nodes[0]
+	dup.Genes[0], dup.Genes[2] = dup.Genes[2], dup.Genes[0]
+	dup.Traits[0], dup.Traits[1] = dup.Traits[1], dup.Traits[0]
+	assert.Equal(t, hash, dup.Hash())
+
+	// the historical markers don't matter
+	dup.Genes[1].InnovationNum = 100
+	dup.Genes[1].MutationNum = 1.5
+	assert.Equal(t, hash, dup.Hash())
+}
+
+func TestGenome_Hash_changes(t *testing.T) {
+	hash := buildTestGenome(1).Hash()
+
+	testCases := map[string]func(g *Genome){
+		"weight": func(g *Genome) {
+			g.Genes[0].Link.ConnectionWeight += 0.1
+		},
+		"disabled gene": func(g *Genome) {
+			g.Genes[1].IsEnabled = false
+		},
+		"recurrent": func(g *Genome) {
+			g.Genes[1].Link.IsRecurrent = true
+		},
+		"activation": func(g *Genome) {
+			g.Nodes[3].ActivationType = math.TanhActivation
+		},
+		"aggregation": func(g *Genome) {
+			g.Nodes[3].AggregationType = math.MaxAggregation
+		},
+		"bias": func(g *Genome) {
+			g.Nodes[3].Bias = 0.5
+		},
+		"trait": func(g *Genome) {
+			g.Traits[0].Params[0] = 0.5
+		},
+		"link trait": func(g *Genome) {
+			g.Genes[0].Link.Trait = g.Traits[1]
+		},
+	}
+	for name, change := range testCases {
+		t.Run(name, func(t *testing.T) {
+			gnome := buildTestGenome(1)
+			change(gnome)
+			assert.NotEqual(t, hash, gnome.Hash())
+		})
+	}
+}
+
+func TestGenome_Hash_disabledGenes(t *testing.T) {
+	gnome := buildTestGenome(1)
+	gnome.Genes[1].IsEnabled = false
+	hash := gnome.Hash()
+
+	// the weight of disabled gene doesn't contribute to the phenotype
+	gnome.Genes[1].Link.ConnectionWeight = -10
+	assert.Equal(t, hash, gnome.Hash())
+}
+
+func TestGenome_Hash_modular(t *testing.T) {
+	gnome := buildTestModularGenome(1)
+	hash := gnome.Hash()
+	assert.NotEqual(t, buildTestGenome(1).Hash(), hash)
+
+	dup, err := gnome.duplicate(2)
+	require.NoError(t, err)
+	assert.Equal(t, hash, dup.Hash())
+
+	dup.ControlGenes[0].ControlNode.ActivationType = math.MaxModuleActivation
+	assert.NotEqual(t, hash, dup.Hash())
+
+	dup, err = gnome.duplicate(3)
+	require.NoError(t, err)
+	dup.ControlGenes[0].IsEnabled = false
+	assert.NotEqual(t, hash, dup.Hash())
+}
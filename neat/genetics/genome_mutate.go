@@ -6,6 +6,7 @@ import (
 	"deepneat/neat/network"
 	"fmt"
 	"math/rand"
+	"sort"
 
 	"github.com/pkg/errors"
 )
@@ -21,7 +22,8 @@ import (
 //	(2) you don't need to know a priori what the important features of the domain are.
 //
 // If all sensors already connected than do nothing.
-func (g *Genome) mutateConnectSensors(innovations InnovationsObserver, _ *neat.Options) (bool, error) {
+func (g *Genome) mutateConnectSensors(innovations InnovationsObserver, opts *neat.Options) (bool, error) {
+	rng := opts.Rand()
 
 	if len(g.Genes) == 0 {
 		return false, errors.New("genome has no genes")
@@ -63,7 +65,7 @@ func (g *Genome) mutateConnectSensors(innovations InnovationsObserver, _ *neat.O
 	}
 
 	// pick randomly from disconnected sensors
-	sensor := disconnectedSensors[rand.Intn(len(disconnectedSensors))]
+	sensor := disconnectedSensors[rng.Intn(len(disconnectedSensors))]
 	// add new links to chosen sensor, avoiding redundancy
 	linkAdded := false
 	for _, output := range outputs {
@@ -96,9 +98,9 @@ func (g *Genome) mutateConnectSensors(innovations InnovationsObserver, _ *neat.O
 			// The innovation is totally novel
 			if !innovationFound {
 				// Choose a random trait
-				traitNum := rand.Intn(len(g.Traits))
+				traitNum := rng.Intn(len(g.Traits))
 				// Choose the new weight
-				newWeight := float64(math.RandSign()) * rand.Float64() * 10.0
+				newWeight := float64(math.RandSignWith(rng)) * rng.Float64() * 10.0
 				// read next innovation id
 				nextInnovId := innovations.NextInnovationNumber()
 
@@ -133,6 +135,8 @@ func (g *Genome) mutateConnectSensors(innovations InnovationsObserver, _ *neat.O
 // Mutate the genome by adding a new link between two random NNodes,
 // if NNodes are already connected, keep trying conf.NewLinkTries times
 func (g *Genome) mutateAddLink(innovations InnovationsObserver, generation int, opts *neat.Options) (bool, error) {
+	rng := opts.Rand()
+
 	// If the phenotype does not exist, exit on false, print error
 	// Note: This should never happen - if it does there is a bug
 	if g.Phenotype == nil {
@@ -147,7 +151,7 @@ func (g *Genome) mutateAddLink(innovations InnovationsObserver, generation int,
 
 	// Decide whether to make link recurrent
 	doRecur := false
-	if rand.Float64() < opts.RecurOnlyProb {
+	if rng.Float64() < opts.RecurOnlyProb {
 		doRecur = true
 	}
 
@@ -173,22 +177,22 @@ func (g *Genome) mutateAddLink(innovations InnovationsObserver, generation int,
 			// 50% of prob to decide create a recurrent link (node X to node X)
 			// 50% of a normal link (node X to node Y)
 			loopRecur := false
-			if rand.Float64() > 0.5 {
+			if rng.Float64() > 0.5 {
 				loopRecur = true
 			}
 			if loopRecur {
-				nodeNum1 = firstNonSensor + rand.Intn(nodesLen-firstNonSensor) // only NON SENSOR
+				nodeNum1 = firstNonSensor + rng.Intn(nodesLen-firstNonSensor) // only NON SENSOR
 				nodeNum2 = nodeNum1
 			} else {
 				for nodeNum1 == nodeNum2 {
-					nodeNum1 = rand.Intn(nodesLen)
-					nodeNum2 = firstNonSensor + rand.Intn(nodesLen-firstNonSensor) // only NON SENSOR
+					nodeNum1 = rng.Intn(nodesLen)
+					nodeNum2 = firstNonSensor + rng.Intn(nodesLen-firstNonSensor) // only NON SENSOR
 				}
 			}
 		} else {
 			for nodeNum1 == nodeNum2 {
-				nodeNum1 = rand.Intn(nodesLen)
-				nodeNum2 = firstNonSensor + rand.Intn(nodesLen-firstNonSensor) // only NON SENSOR
+				nodeNum1 = rng.Intn(nodesLen)
+				nodeNum2 = firstNonSensor + rng.Intn(nodesLen-firstNonSensor) // only NON SENSOR
 			}
 		}
 
@@ -266,9 +270,9 @@ func (g *Genome) mutateAddLink(innovations InnovationsObserver, generation int,
 		// The innovation is totally novel
 		if !innovationFound {
 			// Choose a random trait
-			traitNum := rand.Intn(len(g.Traits))
+			traitNum := rng.Intn(len(g.Traits))
 			// Choose the new weight
-			newWeight := float64(math.RandSign()) * rand.Float64() * 10.0
+			newWeight := float64(math.RandSignWith(rng)) * rng.Float64() * 10.0
 			// read next innovation id
 			nextInnovId := innovations.NextInnovationNumber()
 
@@ -299,6 +303,8 @@ func (g *Genome) mutateAddLink(innovations InnovationsObserver, generation int,
 		// Now add the new Gene to the Genome
 		if gene != nil {
 			g.geneInsert(gene)
+			// the phenotype built to check recurrence doesn't have the new link, thus it must be rebuilt when needed
+			g.Phenotype = nil
 		}
 	}
 
@@ -311,6 +317,8 @@ func (g *Genome) mutateAddLink(innovations InnovationsObserver, generation int,
 // whether they match. If they do, the same innovation numbers will be assigned to the new genes. If a disabled link
 // is chosen, then the method just exits with false.
 func (g *Genome) mutateAddNode(innovations InnovationsObserver, nodeIdGenerator network.NodeIdGenerator, opts *neat.Options) (bool, error) {
+	rng := opts.Rand()
+
 	if len(g.Genes) == 0 {
 		return false, nil // it's possible to have such a network without any link
 	}
@@ -324,7 +332,7 @@ func (g *Genome) mutateAddNode(innovations InnovationsObserver, nodeIdGenerator
 	if len(g.Genes) < 15 {
 		for _, gn := range g.Genes {
 			// Now randomize which gene is chosen.
-			if gn.IsEnabled && gn.Link.InNode.NeuronType != network.BiasNeuron && rand.Float32() >= 0.3 {
+			if gn.IsEnabled && gn.Link.InNode.NeuronType != network.BiasNeuron && rng.Float32() >= 0.3 {
 				gene = gn
 				found = true
 				break
@@ -334,7 +342,7 @@ func (g *Genome) mutateAddNode(innovations InnovationsObserver, nodeIdGenerator
 		tryCount := 0
 		// Alternative uniform random choice of genes. When the genome is not tiny, it is safe to choose randomly.
 		for tryCount < 20 && !found {
-			geneNum := rand.Intn(len(g.Genes))
+			geneNum := rng.Intn(len(g.Genes))
 			gene = g.Genes[geneNum]
 			if gene.IsEnabled && gene.Link.InNode.NeuronType != network.BiasNeuron {
 				found = true
@@ -447,16 +455,323 @@ func (g *Genome) mutateAddNode(innovations InnovationsObserver, nodeIdGenerator
 	return false, nil
 }
 
+// This mutator removes a random link (gene) from the Genome. The hidden nodes left without any connecting link are
+// removed as well. The last remaining gene of the genome is never removed.
+func (g *Genome) mutateDeleteLink(rng *rand.Rand) (bool, error) {
+	if len(g.Genes) <= 1 {
+		return false, nil // keep at least one gene to have valid genome
+	}
+	geneNum := rng.Intn(len(g.Genes))
+	g.Genes = append(g.Genes[:geneNum:geneNum], g.Genes[geneNum+1:]...)
+
+	g.removeOrphanHiddenNodes()
+	// the phenotype is out of date now
+	g.Phenotype = nil
+	return true, nil
+}
+
+// This mutator removes a random hidden node from the Genome together with all genes linking it. The nodes connected
+// to the control genes of modular genome are never removed. If removal of the node would leave genome without genes,
+// then the method just exits with false.
+func (g *Genome) mutateDeleteNode(rng *rand.Rand) (bool, error) {
+	candidates := make([]*network.NNode, 0)
+	for _, node := range g.Nodes {
+		if node.NeuronType == network.HiddenNeuron && !g.isControlGeneIONode(node) {
+			candidates = append(candidates, node)
+		}
+	}
+	if len(candidates) == 0 {
+		return false, nil
+	}
+	node := candidates[rng.Intn(len(candidates))]
+
+	// collect genes not linked with the node
+	genes := make([]*Gene, 0, len(g.Genes))
+	for _, gene := range g.Genes {
+		if gene.Link.InNode.Id != node.Id && gene.Link.OutNode.Id != node.Id {
+			genes = append(genes, gene)
+		}
+	}
+	if len(genes) == 0 {
+		return false, nil // keep at least one gene to have valid genome
+	}
+	g.Genes = genes
+	g.removeNode(node)
+
+	g.removeOrphanHiddenNodes()
+	// the phenotype is out of date now
+	g.Phenotype = nil
+	return true, nil
+}
+
+// Removes hidden nodes which are not connected by any gene and not included into any control gene
+func (g *Genome) removeOrphanHiddenNodes() {
+	linked := make(map[int]bool, len(g.Nodes))
+	for _, gene := range g.Genes {
+		linked[gene.Link.InNode.Id] = true
+		linked[gene.Link.OutNode.Id] = true
+	}
+	for _, node := range append([]*network.NNode(nil), g.Nodes...) {
+		if node.NeuronType == network.HiddenNeuron && !linked[node.Id] && !g.isControlGeneIONode(node) {
+			g.removeNode(node)
+		}
+	}
+}
+
+// The number of inputs of the MIMO module created by the add module mutator
+const moduleInputsCount = 2
+
+// This mutator adds a new MIMO module to the Genome. The module receives signals from two random nodes of the genome
+// through its new input nodes and sends the output of randomly selected module activation function to a random neuron
+// node through its new output node. The target node is selected among the nodes which don't feed the source nodes, thus
+// the module never closes the loop of not recurrent links. The innovations list from population is used to find whether
+// the same module was already added elsewhere in the population. If so, the same node IDs and innovation numbers will
+// be assigned to the new module. If this module is already present in the genome, then the method just exits with false.
+func (g *Genome) mutateAddModule(innovations InnovationsObserver, nodeIdGenerator network.NodeIdGenerator, opts *neat.Options) (bool, error) {
+	activationTypes := math.NodeActivators.ModuleActivationTypes()
+	targets := make([]*network.NNode, 0)
+	for _, node := range g.Nodes {
+		if node.IsNeuron() && !g.isControlGeneOutputNode(node) {
+			targets = append(targets, node)
+		}
+	}
+	if len(g.Nodes) < moduleInputsCount || len(targets) == 0 || len(activationTypes) == 0 || len(g.Traits) == 0 {
+		return false, nil
+	}
+	rng := opts.Rand()
+
+	// select distinct source nodes in order of their IDs, the target node and the module activation function
+	indexes := rng.Perm(len(g.Nodes))[:moduleInputsCount]
+	sort.Ints(indexes)
+	sources := make([]*network.NNode, moduleInputsCount)
+	sourceIds := make([]int, moduleInputsCount)
+	for i, index := range indexes {
+		sources[i] = g.Nodes[index]
+		sourceIds[i] = sources[i].Id
+	}
+	// skip targets which would be linked into the loop through the module
+	acyclic := make([]*network.NNode, 0, len(targets))
+	for _, node := range targets {
+		loop := false
+		for _, source := range sources {
+			loop = loop || g.isForwardPath(node.Id, source.Id, nil)
+		}
+		if !loop {
+			acyclic = append(acyclic, node)
+		}
+	}
+	if len(acyclic) == 0 {
+		return false, nil
+	}
+	target := acyclic[rng.Intn(len(acyclic))]
+	activation := activationTypes[rng.Intn(len(activationTypes))]
+
+	// Check to see if this innovation already occurred in the population
+	var nodeIds []int
+	var innovNums []int64
+	for _, inn := range innovations.Innovations() {
+		if inn.isSameModule(sourceIds, target.Id, activation) {
+			nodeIds, innovNums = inn.ModuleNodeIds, inn.ModuleInnovNums
+			break
+		}
+	}
+	if nodeIds == nil {
+		// The innovation is totally novel: the control node, the input nodes and the output node
+		nodeIds = make([]int, moduleInputsCount+2)
+		for i := range nodeIds {
+			nodeIds[i] = nodeIdGenerator.NextNodeId()
+		}
+		// The control gene, the genes linking input nodes and the gene linking output node
+		innovNums = make([]int64, moduleInputsCount+2)
+		for i := range innovNums {
+			innovNums[i] = innovations.NextInnovationNumber()
+		}
+		innovation := NewInnovationForModule(sourceIds, target.Id, activation, nodeIds, innovNums)
+		innovations.StoreInnovation(*innovation)
+	} else if g.haveNode(nodeIds[1]) {
+		// The same module was already added to this genome or to its parent in current epoch - just skip.
+		neat.InfoLog(fmt.Sprintf("GENOME: Add module innovation found in the same genome [%d] for control node [%d]",
+			g.Id, nodeIds[0]))
+		return false, nil
+	}
+
+	trait := g.Traits[rng.Intn(len(g.Traits))]
+	newNode := func(nodeId int, activationType math.NodeActivationType) *network.NNode {
+		node := network.NewNNode(nodeId, network.HiddenNeuron)
+		node.ActivationType = activationType
+		// By convention, it will point to the first trait
+		node.Trait = g.Traits[0]
+		return node
+	}
+	controlNode := newNode(nodeIds[0], activation)
+	for i, source := range sources {
+		inNode := newNode(nodeIds[i+1], math.LinearActivation)
+		g.nodeInsert(inNode)
+		g.geneInsert(NewGeneWithTrait(trait, 1.0, source, inNode, false, innovNums[i+1], 0))
+		controlNode.Incoming = append(controlNode.Incoming, network.NewLink(1.0, inNode, controlNode, false))
+	}
+	outNode := newNode(nodeIds[moduleInputsCount+1], math.NullActivation)
+	g.nodeInsert(outNode)
+	weight := float64(math.RandSignWith(rng)) * rng.Float64()
+	g.geneInsert(NewGeneWithTrait(trait, weight, outNode, target, false, innovNums[moduleInputsCount+1], weight))
+	controlNode.Outgoing = append(controlNode.Outgoing, network.NewLink(1.0, controlNode, outNode, false))
+
+	g.controlGeneInsert(NewMIMOGene(controlNode, innovNums[0], 0, true))
+	// the phenotype is out of date now
+	g.Phenotype = nil
+	return true, nil
+}
+
+// This mutator removes a random MIMO module from the Genome together with its IO nodes and all genes linking them.
+// If removal of the module would leave genome without genes, then the method just exits with false.
+func (g *Genome) mutateRemoveModule(rng *rand.Rand) (bool, error) {
+	if len(g.ControlGenes) == 0 {
+		return false, nil
+	}
+	index := rng.Intn(len(g.ControlGenes))
+	module := g.ControlGenes[index]
+	controlGenes := append(g.ControlGenes[:index:index], g.ControlGenes[index+1:]...)
+
+	// collect IO nodes not shared with other modules and the genes not linked with them
+	removed := make(map[int]bool, len(module.ioNodes))
+	for _, node := range module.ioNodes {
+		removed[node.Id] = true
+	}
+	for _, cg := range controlGenes {
+		for _, node := range cg.ioNodes {
+			delete(removed, node.Id)
+		}
+	}
+	genes := make([]*Gene, 0, len(g.Genes))
+	for _, gene := range g.Genes {
+		if !removed[gene.Link.InNode.Id] && !removed[gene.Link.OutNode.Id] {
+			genes = append(genes, gene)
+		}
+	}
+	if len(genes) == 0 {
+		return false, nil // keep at least one gene to have valid genome
+	}
+	g.Genes = genes
+	if len(controlGenes) == 0 {
+		controlGenes = nil
+	}
+	g.ControlGenes = controlGenes
+	for _, node := range module.ioNodes {
+		if removed[node.Id] {
+			g.removeNode(node)
+		}
+	}
+
+	g.removeOrphanHiddenNodes()
+	// the phenotype is out of date now
+	g.Phenotype = nil
+	return true, nil
+}
+
+// This mutator rewires one of the input nodes or the output node of a random MIMO module of the Genome. The gene which
+// links the selected IO node with the rest of the genome is replaced by the new gene linking it with another random
+// node, keeping the weight and the trait of the old gene. The nodes which would close the loop of not recurrent links
+// through the module are not considered. The innovations list from population is used to assign the new gene the same
+// innovation number as the identical link added elsewhere in the population. If no node appropriate for the new link
+// found, then the method just exits with false.
+func (g *Genome) mutateRewireModule(innovations InnovationsObserver, opts *neat.Options) (bool, error) {
+	if len(g.ControlGenes) == 0 || len(g.Traits) == 0 {
+		return false, nil
+	}
+	rng := opts.Rand()
+	module := g.ControlGenes[rng.Intn(len(g.ControlGenes))]
+	ioIndex := rng.Intn(len(module.ioNodes))
+	ioNode := module.ioNodes[ioIndex]
+	isInput := ioIndex < len(module.ControlNode.Incoming)
+
+	// find the gene linking the IO node with the rest of the genome and the nodes already linked with it
+	var oldGene *Gene
+	linked := map[int]bool{ioNode.Id: true}
+	for _, gene := range g.Genes {
+		if isInput && gene.Link.OutNode.Id == ioNode.Id {
+			linked[gene.Link.InNode.Id] = true
+		} else if !isInput && gene.Link.InNode.Id == ioNode.Id {
+			linked[gene.Link.OutNode.Id] = true
+		} else {
+			continue
+		}
+		if oldGene == nil {
+			oldGene = gene
+		}
+	}
+	candidates := make([]*network.NNode, 0)
+	for _, node := range g.Nodes {
+		if linked[node.Id] || (!isInput && (!node.IsNeuron() || g.isControlGeneOutputNode(node))) {
+			continue
+		}
+		// skip nodes which would be linked into the loop through the module
+		if (isInput && g.isForwardPath(ioNode.Id, node.Id, oldGene)) ||
+			(!isInput && g.isForwardPath(node.Id, ioNode.Id, oldGene)) {
+			continue
+		}
+		candidates = append(candidates, node)
+	}
+	if len(candidates) == 0 {
+		return false, nil
+	}
+	node := candidates[rng.Intn(len(candidates))]
+	inNode, outNode := node, ioNode
+	if !isInput {
+		inNode, outNode = ioNode, node
+	}
+
+	trait, weight := g.Traits[rng.Intn(len(g.Traits))], float64(math.RandSignWith(rng))*rng.Float64()
+	if oldGene != nil {
+		trait, weight = oldGene.Link.Trait, oldGene.Link.ConnectionWeight
+	}
+	var gene *Gene
+	// Check to see if this innovation already occurred in the population
+	for _, inn := range innovations.Innovations() {
+		if inn.innovationType == newLinkInnType && inn.InNodeId == inNode.Id && inn.OutNodeId == outNode.Id && !inn.IsRecurrent {
+			gene = NewGeneWithTrait(trait, weight, inNode, outNode, false, inn.InnovationNum, 0)
+			break
+		}
+	}
+	if gene == nil {
+		traitNum := 0
+		for i, t := range g.Traits {
+			if t == trait {
+				traitNum = i
+			}
+		}
+		nextInnovId := innovations.NextInnovationNumber()
+		gene = NewGeneWithTrait(trait, weight, inNode, outNode, false, nextInnovId, weight)
+		innovation := NewInnovationForLink(inNode.Id, outNode.Id, nextInnovId, weight, traitNum)
+		innovations.StoreInnovation(*innovation)
+	}
+
+	if oldGene != nil {
+		genes := make([]*Gene, 0, len(g.Genes))
+		for _, gn := range g.Genes {
+			if gn != oldGene {
+				genes = append(genes, gn)
+			}
+		}
+		g.Genes = genes
+	}
+	g.geneInsert(gene)
+
+	g.removeOrphanHiddenNodes()
+	// the phenotype is out of date now
+	g.Phenotype = nil
+	return true, nil
+}
+
 // Adds Gaussian noise to link weights either GAUSSIAN or COLD_GAUSSIAN (from zero).
 // The COLD_GAUSSIAN means ALL connection weights will be given completely new values
-func (g *Genome) mutateLinkWeights(power, rate float64, mutationType mutatorType) (bool, error) {
+func (g *Genome) mutateLinkWeights(power, rate float64, mutationType mutatorType, rng *rand.Rand) (bool, error) {
 	if len(g.Genes) == 0 {
 		return false, errors.New("genome has no genes")
 	}
 
 	// Once in a while really shake things up
 	severe := false
-	if rand.Float64() > 0.5 {
+	if rng.Float64() > 0.5 {
 		severe = true
 	}
 
@@ -480,7 +795,7 @@ func (g *Genome) mutateLinkWeights(power, rate float64, mutationType mutatorType
 			coldGaussPoint = 0.3 // Mutate the rest by replacement % of the time
 		} else {
 			// Half the time don't do any cold mutations
-			if rand.Float64() > 0.5 {
+			if rng.Float64() > 0.5 {
 				gaussPoint = 1.0 - rate
 				coldGaussPoint = gaussPoint - 0.1
 			} else {
@@ -489,9 +804,9 @@ func (g *Genome) mutateLinkWeights(power, rate float64, mutationType mutatorType
 			}
 		}
 
-		random := float64(math.RandSign()) * rand.Float64() * power
+		random := float64(math.RandSignWith(rng)) * rng.Float64() * power
 		if mutationType == gaussianMutator {
-			randChoice := rand.Float64()
+			randChoice := rng.Float64()
 			if randChoice > gaussPoint {
 				gene.Link.ConnectionWeight += random
 			} else if randChoice > coldGaussPoint {
@@ -512,29 +827,31 @@ func (g *Genome) mutateLinkWeights(power, rate float64, mutationType mutatorType
 
 // Perturb params in one trait
 func (g *Genome) mutateRandomTrait(context *neat.Options) (bool, error) {
+	rng := context.Rand()
+
 	if len(g.Traits) == 0 {
 		return false, errors.New("genome has no traits")
 	}
 	// Choose a random trait number
-	traitNum := rand.Intn(len(g.Traits))
+	traitNum := rng.Intn(len(g.Traits))
 
 	// Retrieve the trait and mutate it
-	g.Traits[traitNum].Mutate(context.TraitMutationPower, context.TraitParamMutProb)
+	g.Traits[traitNum].Mutate(context.TraitMutationPower, context.TraitParamMutProb, rng)
 
 	return true, nil
 }
 
 // This chooses a random gene, extracts the link from it and re-points the link to a random trait
-func (g *Genome) mutateLinkTrait(times int) (bool, error) {
+func (g *Genome) mutateLinkTrait(times int, rng *rand.Rand) (bool, error) {
 	if len(g.Traits) == 0 || len(g.Genes) == 0 {
 		return false, errors.New("genome has either no traits od genes")
 	}
 	for loop := 0; loop < times; loop++ {
 		// Choose a random trait number
-		traitNum := rand.Intn(len(g.Traits))
+		traitNum := rng.Intn(len(g.Traits))
 
 		// Choose a random link number
-		geneNum := rand.Intn(len(g.Genes))
+		geneNum := rng.Intn(len(g.Genes))
 
 		// set the link to point to the new trait
 		g.Genes[geneNum].Link.Trait = g.Traits[traitNum]
@@ -544,16 +861,16 @@ func (g *Genome) mutateLinkTrait(times int) (bool, error) {
 }
 
 // This chooses a random node and re-points the node to a random trait specified number of times
-func (g *Genome) mutateNodeTrait(times int) (bool, error) {
+func (g *Genome) mutateNodeTrait(times int, rng *rand.Rand) (bool, error) {
 	if len(g.Traits) == 0 || len(g.Nodes) == 0 {
 		return false, errors.New("genome has either no traits or nodes")
 	}
 	for loop := 0; loop < times; loop++ {
 		// Choose a random trait number
-		traitNum := rand.Intn(len(g.Traits))
+		traitNum := rng.Intn(len(g.Traits))
 
 		// Choose a random node number
-		nodeNum := rand.Intn(len(g.Nodes))
+		nodeNum := rng.Intn(len(g.Nodes))
 
 		// set the node to point to the new trait
 		g.Nodes[nodeNum].Trait = g.Traits[traitNum]
@@ -561,14 +878,90 @@ func (g *Genome) mutateNodeTrait(times int) (bool, error) {
 	return true, nil
 }
 
+// Perturbs the bias of each neuron node of this genome by random value within the given power
+func (g *Genome) mutateNodeBiases(power float64, rng *rand.Rand) (bool, error) {
+	mutated := false
+	for _, node := range g.Nodes {
+		if g.isMutableNeuron(node) {
+			node.Bias += float64(math.RandSignWith(rng)) * rng.Float64() * power
+			mutated = true
+		}
+	}
+	return mutated, nil
+}
+
+// Perturbs the response of each neuron node of this genome by random value within the given power
+func (g *Genome) mutateNodeResponses(power float64, rng *rand.Rand) (bool, error) {
+	mutated := false
+	for _, node := range g.Nodes {
+		if g.isMutableNeuron(node) {
+			node.Response += float64(math.RandSignWith(rng)) * rng.Float64() * power
+			mutated = true
+		}
+	}
+	return mutated, nil
+}
+
+// Assigns random aggregation function among registered with options to the randomly chosen neuron node of this genome
+func (g *Genome) mutateNodeAggregation(opts *neat.Options) (bool, error) {
+	node := g.randomNeuron(opts.Rand())
+	if node == nil {
+		return false, nil
+	}
+	aggregation, err := opts.RandomNodeAggregationType()
+	if err != nil {
+		return false, err
+	}
+	mutated := node.AggregationType != aggregation
+	node.AggregationType = aggregation
+	return mutated, nil
+}
+
+// Assigns random activation function among registered with options to the randomly chosen hidden or output node of
+// this genome
+func (g *Genome) mutateNodeActivation(opts *neat.Options) (bool, error) {
+	node := g.randomNeuron(opts.Rand())
+	if node == nil {
+		return false, nil
+	}
+	activation, err := opts.RandomNodeActivationType()
+	if err != nil {
+		return false, err
+	}
+	mutated := node.ActivationType != activation
+	node.ActivationType = activation
+	return mutated, nil
+}
+
+// Returns randomly chosen neuron (hidden or output) node of this genome or nil if genome has no neurons. The IO nodes
+// of control genes are never chosen.
+func (g *Genome) randomNeuron(rng *rand.Rand) *network.NNode {
+	neurons := make([]*network.NNode, 0, len(g.Nodes))
+	for _, node := range g.Nodes {
+		if g.isMutableNeuron(node) {
+			neurons = append(neurons, node)
+		}
+	}
+	if len(neurons) == 0 {
+		return nil
+	}
+	return neurons[rng.Intn(len(neurons))]
+}
+
+// Returns true if parameters of the given node can be mutated, i.e., it is the neuron node which is not the IO node
+// of any control gene. The IO nodes of modules keep their parameters, because the module relies on them.
+func (g *Genome) isMutableNeuron(node *network.NNode) bool {
+	return node.IsNeuron() && !g.isControlGeneIONode(node)
+}
+
 // Toggle genes from enable ON to enable OFF or vice versa. Do it specified number of times.
-func (g *Genome) mutateToggleEnable(times int) (bool, error) {
+func (g *Genome) mutateToggleEnable(times int, rng *rand.Rand) (bool, error) {
 	if len(g.Genes) == 0 {
 		return false, errors.New("genome has no genes to toggle")
 	}
 	for loop := 0; loop < times; loop++ {
 		// Choose a random gene number
-		geneNum := rand.Intn(len(g.Genes))
+		geneNum := rng.Intn(len(g.Genes))
 
 		gene := g.Genes[geneNum]
 		if gene.IsEnabled {
@@ -600,38 +993,76 @@ func (g *Genome) mutateGeneReEnable() (bool, error) {
 	return true, nil
 }
 
-// Applies all non-structural mutations to this genome
-func (g *Genome) mutateAllNonstructural(context *neat.Options) (bool, error) {
+// Applies all non-structural mutations to this genome. Returns the types of mutations which changed the genome.
+func (g *Genome) mutateAllNonstructural(context *neat.Options) ([]MutationType, error) {
+	rng := context.Rand()
+
+	mutations := make([]MutationType, 0)
 	res := false
 	var err error
-	if rand.Float64() < context.MutateRandomTraitProb {
+	apply := func(mutation MutationType) {
+		if err == nil && res {
+			mutations = append(mutations, mutation)
+		}
+	}
+	if rng.Float64() < context.MutateRandomTraitProb {
 		// mutate random trait
 		res, err = g.mutateRandomTrait(context)
+		apply(MutationRandomTrait)
 	}
 
-	if err == nil && rand.Float64() < context.MutateLinkTraitProb {
+	if err == nil && rng.Float64() < context.MutateLinkTraitProb {
 		// mutate link trait
-		res, err = g.mutateLinkTrait(1)
+		res, err = g.mutateLinkTrait(1, rng)
+		apply(MutationLinkTrait)
 	}
 
-	if err == nil && rand.Float64() < context.MutateNodeTraitProb {
+	if err == nil && rng.Float64() < context.MutateNodeTraitProb {
 		// mutate node trait
-		res, err = g.mutateNodeTrait(1)
+		res, err = g.mutateNodeTrait(1, rng)
+		apply(MutationNodeTrait)
 	}
 
-	if err == nil && rand.Float64() < context.MutateLinkWeightsProb {
+	if err == nil && rng.Float64() < context.MutateLinkWeightsProb {
 		// mutate link weight
-		res, err = g.mutateLinkWeights(context.WeightMutPower, 1.0, gaussianMutator)
+		res, err = g.mutateLinkWeights(context.WeightMutPower, 1.0, gaussianMutator, rng)
+		apply(MutationLinkWeights)
 	}
 
-	if err == nil && rand.Float64() < context.MutateToggleEnableProb {
+	if err == nil && rng.Float64() < context.MutateToggleEnableProb {
 		// mutate toggle enable
-		res, err = g.mutateToggleEnable(1)
+		res, err = g.mutateToggleEnable(1, rng)
+		apply(MutationToggleEnable)
 	}
 
-	if err == nil && rand.Float64() < context.MutateGeneReenableProb {
+	if err == nil && rng.Float64() < context.MutateGeneReenableProb {
 		// mutate gene reenable
 		res, err = g.mutateGeneReEnable()
+		apply(MutationGeneReenable)
+	}
+
+	if err == nil && context.MutateNodeBiasProb > 0 && rng.Float64() < context.MutateNodeBiasProb {
+		// mutate node biases
+		res, err = g.mutateNodeBiases(context.BiasMutPower, rng)
+		apply(MutationNodeBias)
+	}
+
+	if err == nil && context.MutateNodeResponseProb > 0 && rng.Float64() < context.MutateNodeResponseProb {
+		// mutate node responses
+		res, err = g.mutateNodeResponses(context.ResponseMutPower, rng)
+		apply(MutationNodeResponse)
+	}
+
+	if err == nil && context.MutateNodeAggregationProb > 0 && rng.Float64() < context.MutateNodeAggregationProb {
+		// mutate node aggregation
+		res, err = g.mutateNodeAggregation(context)
+		apply(MutationNodeAggregation)
+	}
+
+	if err == nil && context.MutateNodeActivationProb > 0 && rng.Float64() < context.MutateNodeActivationProb {
+		// mutate node activation
+		res, err = g.mutateNodeActivation(context)
+		apply(MutationNodeActivation)
 	}
-	return res, err
+	return mutations, err
 }
@@ -138,10 +138,317 @@ func TestGenome_mutateAddNode(t *testing.T) {
 	assert.Equal(t, math.SigmoidSteepenedActivation, addedNode.ActivationType, "wrong activation type")
 }
 
+func TestGenome_mutateDeleteNode(t *testing.T) {
+	gnome1 := buildTestGenome(1)
+	_, err := gnome1.Genesis(1)
+	require.NoError(t, err, "genesis failed")
+	opts := &neat.Options{
+		NodeActivators:     []math.NodeActivationType{math.SigmoidSteepenedActivation},
+		NodeActivatorsProb: []float64{1.0},
+		PopSize:            1,
+	}
+	pop := newPopulation()
+	err = pop.spawn(gnome1, opts)
+	require.NoError(t, err, "failed to spawn population")
+
+	// no hidden nodes to delete
+	res, err := gnome1.mutateDeleteNode(opts.Rand())
+	require.NoError(t, err, "failed to mutate")
+	assert.False(t, res, "no hidden nodes expected")
+
+	res, err = gnome1.mutateAddNode(pop, pop, opts)
+	require.NoError(t, err, "failed to add node")
+	require.True(t, res, "node was not added")
+	require.Len(t, gnome1.Nodes, 5)
+	require.Len(t, gnome1.Genes, 5)
+
+	res, err = gnome1.mutateDeleteNode(opts.Rand())
+	require.NoError(t, err, "failed to mutate")
+	require.True(t, res, "mutation failed")
+	// the added node and both genes linking it are removed
+	assert.Len(t, gnome1.Nodes, 4, "wrong number of nodes")
+	assert.Len(t, gnome1.Genes, 3, "wrong number of genes")
+	assert.Nil(t, gnome1.NodeWithId(6), "deleted node still mapped")
+	assert.Nil(t, gnome1.Phenotype, "outdated phenotype")
+
+	valid, err := gnome1.verify()
+	require.NoError(t, err, "failed to verify genome")
+	assert.True(t, valid)
+}
+
+func TestGenome_mutateDeleteNode_controlGeneIONodes(t *testing.T) {
+	gnome1 := buildTestModularGenome(1)
+	nodesCount, genesCount := len(gnome1.Nodes), len(gnome1.Genes)
+
+	// all hidden nodes are IO nodes of the control gene and must be kept
+	res, err := gnome1.mutateDeleteNode(rand.New(rand.NewSource(42)))
+	require.NoError(t, err, "failed to mutate")
+	assert.False(t, res, "IO nodes of control gene must not be deleted")
+	assert.Len(t, gnome1.Nodes, nodesCount)
+	assert.Len(t, gnome1.Genes, genesCount)
+
+	// the IO nodes of control gene are not orphans even if not linked by any gene
+	gnome1.Genes = gnome1.Genes[:3]
+	gnome1.removeOrphanHiddenNodes()
+	assert.Len(t, gnome1.Nodes, nodesCount)
+}
+
+func TestGenome_mutateDeleteLink(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	for i := 0; i < 10; i++ {
+		// the genome with hidden node linked by single gene
+		gnome1 := buildTestGenome(1)
+		hidden := network.NewNNode(5, network.HiddenNeuron)
+		gnome1.addNode(hidden)
+		gnome1.Genes = append(gnome1.Genes[:1], NewGene(1.0, gnome1.Nodes[0], hidden, false, 4, 0))
+
+		res, err := gnome1.mutateDeleteLink(rng)
+		require.NoError(t, err, "failed to mutate")
+		require.True(t, res, "mutation failed")
+		require.Len(t, gnome1.Genes, 1)
+
+		// the hidden node is removed only when left without links
+		hiddenLinked := gnome1.Genes[0].Link.OutNode.Id == hidden.Id
+		assert.Equal(t, hiddenLinked, gnome1.NodeWithId(hidden.Id) != nil, "at: %d", i)
+		assert.Equal(t, hiddenLinked, len(gnome1.Nodes) == 5, "at: %d", i)
+
+		valid, err := gnome1.verify()
+		require.NoError(t, err, "failed to verify genome at: %d", i)
+		assert.True(t, valid)
+
+		// the last gene is never removed
+		res, err = gnome1.mutateDeleteLink(rng)
+		require.NoError(t, err, "failed to mutate")
+		assert.False(t, res, "the last gene must be kept")
+	}
+}
+
+func TestGenome_mutateAddModule(t *testing.T) {
+	gnome1 := buildTestGenome(1)
+	opts := &neat.Options{
+		PopSize:    1,
+		RandSource: rand.New(rand.NewSource(42)),
+	}
+	// The population with one organism
+	pop := newPopulation()
+	err := pop.spawn(gnome1, opts)
+	require.NoError(t, err, "failed to spawn population")
+
+	opts.RandSource = rand.New(rand.NewSource(42))
+	res, err := gnome1.mutateAddModule(pop, pop, opts)
+	require.NoError(t, err, "failed to mutate")
+	require.True(t, res, "mutation failed")
+
+	// the control gene, two input and one output genes, expecting innovation + 4 (3+4)
+	assert.Len(t, pop.Innovations(), 1, "wrong number of innovations")
+	assert.EqualValues(t, 7, pop.nextInnovNum, "wrong next innovation number set for population")
+	require.Len(t, gnome1.ControlGenes, 1, "control gene expected")
+	assert.Len(t, gnome1.Genes, 3+moduleInputsCount+1, "wrong number of genes")
+	assert.Len(t, gnome1.Nodes, 4+moduleInputsCount+1, "wrong number of nodes")
+	module := gnome1.ControlGenes[0]
+	assert.Contains(t, math.NodeActivators.ModuleActivationTypes(), module.ControlNode.ActivationType)
+	assert.Len(t, module.ControlNode.Incoming, moduleInputsCount)
+	assert.Len(t, module.ControlNode.Outgoing, 1)
+	for _, node := range module.ioNodes {
+		assert.True(t, gnome1.isControlGeneIONode(node))
+		assert.Equal(t, node, gnome1.NodeWithId(node.Id), "IO node not mapped")
+	}
+
+	valid, err := gnome1.verify()
+	require.NoError(t, err, "failed to verify genome")
+	assert.True(t, valid)
+	net, err := gnome1.Genesis(1)
+	require.NoError(t, err, "genesis failed")
+	assert.Len(t, net.ControlNodes(), 1)
+
+	// the same mutation in other genome gets the same node IDs and innovation numbers
+	gnome2 := buildTestGenome(2)
+	opts.RandSource = rand.New(rand.NewSource(42))
+	res, err = gnome2.mutateAddModule(pop, pop, opts)
+	require.NoError(t, err, "failed to mutate")
+	require.True(t, res, "mutation failed")
+	assert.Len(t, pop.Innovations(), 1, "wrong number of innovations")
+	require.Len(t, gnome2.ControlGenes, 1, "control gene expected")
+	assert.Equal(t, module.InnovationNum, gnome2.ControlGenes[0].InnovationNum)
+	assert.Equal(t, module.ControlNode.Id, gnome2.ControlGenes[0].ControlNode.Id)
+	assert.Equal(t, 0.0, gnome1.compatibility(gnome2, &neat.Options{DisjointCoeff: 1, ExcessCoeff: 1}))
+}
+
+func TestGenome_mutateAddModule_parallel(t *testing.T) {
+	opts := &neat.Options{PopSize: 1}
+	pop := newPopulation()
+	err := pop.spawn(buildTestGenome(1), opts)
+	require.NoError(t, err, "failed to spawn population")
+
+	// the same module added concurrently by two species gets the same global numbers on commit
+	trackers := []*localInnovations{newLocalInnovations(pop), newLocalInnovations(pop)}
+	orgs := make([]*Organism, len(trackers))
+	for i, tracker := range trackers {
+		gnome := buildTestGenome(i + 2)
+		opts.RandSource = rand.New(rand.NewSource(42))
+		res, err := gnome.mutateAddModule(tracker, tracker, opts)
+		require.NoError(t, err, "failed to mutate")
+		require.True(t, res, "mutation failed")
+		orgs[i], err = NewOrganism(0, gnome, 1)
+		require.NoError(t, err, "failed to create organism")
+	}
+	for i, tracker := range trackers {
+		err = tracker.commit(pop, orgs[i:i+1])
+		require.NoError(t, err, "failed to commit innovations")
+	}
+	assert.Len(t, pop.Innovations(), 1, "wrong number of innovations")
+
+	module1, module2 := orgs[0].Genotype.ControlGenes[0], orgs[1].Genotype.ControlGenes[0]
+	assert.Equal(t, module1.InnovationNum, module2.InnovationNum)
+	assert.Equal(t, module1.ControlNode.Id, module2.ControlNode.Id)
+	assert.EqualValues(t, pop.nextInnovNum, module1.InnovationNum+moduleInputsCount+1)
+	for _, org := range orgs {
+		valid, err := org.Genotype.verify()
+		require.NoError(t, err, "failed to verify genome")
+		assert.True(t, valid)
+	}
+}
+
+func TestGenome_mutateRemoveModule(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	gnome1 := buildTestGenome(1)
+	res, err := gnome1.mutateRemoveModule(rng)
+	require.NoError(t, err, "failed to mutate")
+	assert.False(t, res, "no modules to remove")
+
+	gnome1 = buildTestModularGenome(1)
+	res, err = gnome1.mutateRemoveModule(rng)
+	require.NoError(t, err, "failed to mutate")
+	require.True(t, res, "mutation failed")
+	assert.Len(t, gnome1.ControlGenes, 0)
+	assert.Len(t, gnome1.Genes, 3, "genes linking IO nodes must be removed")
+	assert.Len(t, gnome1.Nodes, 4, "IO nodes must be removed")
+	assert.Nil(t, gnome1.NodeWithId(5), "removed node still mapped")
+
+	valid, err := gnome1.verify()
+	require.NoError(t, err, "failed to verify genome")
+	assert.True(t, valid)
+
+	// the module linked by all genes of the genome is kept
+	gnome1 = buildTestModularGenome(1)
+	gnome1.Genes = gnome1.Genes[3:]
+	res, err = gnome1.mutateRemoveModule(rng)
+	require.NoError(t, err, "failed to mutate")
+	assert.False(t, res, "the last genes removed")
+}
+
+func TestGenome_mutateRewireModule(t *testing.T) {
+	opts := &neat.Options{RandSource: rand.New(rand.NewSource(42))}
+	rewired := 0
+	for i := 0; i < 10; i++ {
+		gnome1 := buildTestModularGenome(1)
+		pop := newPopulation()
+		opts.PopSize = 1
+		err := pop.spawn(gnome1, opts)
+		require.NoError(t, err, "failed to spawn population")
+
+		res, err := gnome1.mutateRewireModule(pop, opts)
+		require.NoError(t, err, "failed to mutate")
+		if !res {
+			// the output node of the module can be linked only with the hidden nodes feeding the module
+			assert.Len(t, pop.Innovations(), 0, "no innovations expected at: %d", i)
+			assert.Len(t, gnome1.Genes, 6, "genes must not change at: %d", i)
+			continue
+		}
+		rewired++
+		assert.Len(t, pop.Innovations(), 1, "wrong number of innovations")
+		assert.Len(t, gnome1.Genes, 6, "the rewired gene must replace the old one")
+		assert.Len(t, gnome1.Nodes, 7)
+
+		// every IO node of the module is still linked with the genome
+		for _, node := range gnome1.ControlGenes[0].ioNodes {
+			linked := false
+			for _, gene := range gnome1.Genes {
+				linked = linked || gene.Link.InNode == node || gene.Link.OutNode == node
+			}
+			assert.True(t, linked, "IO node: %d is not linked at: %d", node.Id, i)
+		}
+
+		valid, err := gnome1.verify()
+		require.NoError(t, err, "failed to verify genome")
+		assert.True(t, valid)
+		_, err = gnome1.Genesis(1)
+		require.NoError(t, err, "genesis failed")
+	}
+	assert.True(t, rewired > 0, "no module rewired")
+
+	// no modules to rewire
+	gnome1 := buildTestGenome(1)
+	res, err := gnome1.mutateRewireModule(newPopulation(), opts)
+	require.NoError(t, err, "failed to mutate")
+	assert.False(t, res)
+}
+
+// hasFeedForwardLoop is to check whether the not recurrent genes and the modules of the genome form a loop by
+// topological sorting of the genome graph
+func hasFeedForwardLoop(g *Genome) bool {
+	next, inDegree := make(map[int][]int), make(map[int]int)
+	addEdge := func(from, to int) {
+		next[from] = append(next[from], to)
+		inDegree[to]++
+	}
+	for _, gene := range g.Genes {
+		if !gene.Link.IsRecurrent {
+			addEdge(gene.Link.InNode.Id, gene.Link.OutNode.Id)
+		}
+	}
+	for _, cg := range g.ControlGenes {
+		for _, in := range cg.ControlNode.Incoming {
+			for _, out := range cg.ControlNode.Outgoing {
+				addEdge(in.InNode.Id, out.OutNode.Id)
+			}
+		}
+	}
+	ready := make([]int, 0)
+	for _, node := range g.Nodes {
+		if inDegree[node.Id] == 0 {
+			ready = append(ready, node.Id)
+		}
+	}
+	sorted := 0
+	for len(ready) > 0 {
+		id := ready[0]
+		ready = ready[1:]
+		sorted++
+		for _, to := range next[id] {
+			if inDegree[to]--; inDegree[to] == 0 {
+				ready = append(ready, to)
+			}
+		}
+	}
+	return sorted < len(g.Nodes)
+}
+
+func TestGenome_mutateModule_noLoops(t *testing.T) {
+	for seed := int64(0); seed < 200; seed++ {
+		opts := &neat.Options{PopSize: 1, RandSource: rand.New(rand.NewSource(seed))}
+		gnome1 := buildTestModularGenome(1)
+		pop := newPopulation()
+		require.NoError(t, pop.spawn(gnome1, opts), "failed to spawn population")
+
+		_, err := gnome1.mutateRewireModule(pop, opts)
+		require.NoError(t, err, "failed to rewire module")
+		assert.False(t, hasFeedForwardLoop(gnome1), "rewire created loop with seed: %d", seed)
+
+		_, err = gnome1.mutateAddModule(pop, pop, opts)
+		require.NoError(t, err, "failed to add module")
+		assert.False(t, hasFeedForwardLoop(gnome1), "add module created loop with seed: %d", seed)
+
+		_, err = gnome1.mutateRewireModule(pop, opts)
+		require.NoError(t, err, "failed to rewire module")
+		assert.False(t, hasFeedForwardLoop(gnome1), "rewire created loop with seed: %d", seed)
+	}
+}
+
 func TestGenome_mutateLinkWeights(t *testing.T) {
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	gnome1 := buildTestGenome(1)
-	res, err := gnome1.mutateLinkWeights(0.5, 1.0, gaussianMutator)
+	res, err := gnome1.mutateLinkWeights(0.5, 1.0, gaussianMutator, rng)
 	require.NoError(t, err, "failed to mutate")
 	require.True(t, res, "mutation failed")
 
@@ -181,9 +488,10 @@ func TestGenome_mutateRandomTrait(t *testing.T) {
 }
 
 func TestGenome_mutateLinkTrait(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
 	gnome1 := buildTestGenome(1)
 
-	res, err := gnome1.mutateLinkTrait(10)
+	res, err := gnome1.mutateLinkTrait(10, rng)
 	require.NoError(t, err, "failed to mutate")
 	require.True(t, res, "mutation failed")
 
@@ -198,6 +506,7 @@ func TestGenome_mutateLinkTrait(t *testing.T) {
 }
 
 func TestGenome_mutateNodeTrait(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
 	gnome1 := buildTestGenome(1)
 
 	// Add traits to nodes
@@ -208,7 +517,7 @@ func TestGenome_mutateNodeTrait(t *testing.T) {
 	}
 	gnome1.Nodes[3].Trait = &neat.Trait{Id: 4, Params: []float64{0.4, 0, 0, 0, 0, 0, 0, 0}}
 
-	res, err := gnome1.mutateNodeTrait(2)
+	res, err := gnome1.mutateNodeTrait(2, rng)
 	require.NoError(t, err, "failed to mutate")
 	require.True(t, res, "mutation failed")
 
@@ -222,13 +531,129 @@ func TestGenome_mutateNodeTrait(t *testing.T) {
 	assert.True(t, mutationFound, "No mutation found in nodes traits")
 }
 
+func TestGenome_mutateNodeBiases(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	gnome1 := buildTestGenome(1)
+
+	res, err := gnome1.mutateNodeBiases(0.5, rng)
+	require.NoError(t, err, "failed to mutate")
+	require.True(t, res, "mutation failed")
+
+	for _, nd := range gnome1.Nodes {
+		if nd.IsSensor() {
+			assert.Zero(t, nd.Bias, "sensor bias mutated: %s", nd)
+		} else {
+			assert.NotZero(t, nd.Bias, "neuron bias not mutated: %s", nd)
+			assert.LessOrEqual(t, nd.Bias, 0.5)
+			assert.GreaterOrEqual(t, nd.Bias, -0.5)
+		}
+	}
+}
+
+func TestGenome_mutateNodeBiases_modular(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	gnome1 := buildTestModularGenome(1)
+
+	res, err := gnome1.mutateNodeBiases(0.5, rng)
+	require.NoError(t, err, "failed to mutate")
+	require.True(t, res, "mutation failed")
+	res, err = gnome1.mutateNodeResponses(0.5, rng)
+	require.NoError(t, err, "failed to mutate")
+	require.True(t, res, "mutation failed")
+
+	for _, nd := range gnome1.Nodes {
+		if gnome1.isControlGeneIONode(nd) {
+			assert.Zero(t, nd.Bias, "module IO node bias mutated: %s", nd)
+			assert.Equal(t, 1.0, nd.Response, "module IO node response mutated: %s", nd)
+		} else if nd.IsNeuron() {
+			assert.NotZero(t, nd.Bias, "neuron bias not mutated: %s", nd)
+			assert.NotEqual(t, 1.0, nd.Response, "neuron response not mutated: %s", nd)
+		}
+	}
+}
+
+func TestGenome_mutateNodeResponses(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	gnome1 := buildTestGenome(1)
+
+	res, err := gnome1.mutateNodeResponses(0.5, rng)
+	require.NoError(t, err, "failed to mutate")
+	require.True(t, res, "mutation failed")
+
+	for _, nd := range gnome1.Nodes {
+		if nd.IsSensor() {
+			assert.Equal(t, 1.0, nd.Response, "sensor response mutated: %s", nd)
+		} else {
+			assert.NotEqual(t, 1.0, nd.Response, "neuron response not mutated: %s", nd)
+		}
+	}
+}
+
+func TestGenome_mutateNodeAggregation(t *testing.T) {
+	gnome1 := buildTestGenome(1)
+	opts := &neat.Options{
+		NodeAggregators:     []math.NodeAggregationType{math.MedianAggregation},
+		NodeAggregatorsProb: []float64{1.0},
+		RandSource:          rand.New(rand.NewSource(42)),
+	}
+
+	res, err := gnome1.mutateNodeAggregation(opts)
+	require.NoError(t, err, "failed to mutate")
+	require.True(t, res, "mutation failed")
+
+	// the only neuron is the output node
+	for _, nd := range gnome1.Nodes {
+		if nd.IsSensor() {
+			assert.Equal(t, math.SumAggregation, nd.AggregationType, "sensor aggregation mutated: %s", nd)
+		} else {
+			assert.Equal(t, math.MedianAggregation, nd.AggregationType, "neuron aggregation not mutated: %s", nd)
+		}
+	}
+
+	// the same aggregation assigned again
+	res, err = gnome1.mutateNodeAggregation(opts)
+	require.NoError(t, err, "failed to mutate")
+	assert.False(t, res, "no changes expected")
+}
+
+func TestGenome_mutateNodeActivation(t *testing.T) {
+	gnome1 := buildTestModularGenome(1)
+	opts := &neat.Options{
+		NodeActivators:     []math.NodeActivationType{math.TanhActivation},
+		NodeActivatorsProb: []float64{1.0},
+		RandSource:         rand.New(rand.NewSource(42)),
+	}
+
+	for i := 0; i < 20; i++ {
+		_, err := gnome1.mutateNodeActivation(opts)
+		require.NoError(t, err, "failed to mutate")
+	}
+	mutated := 0
+	for _, nd := range gnome1.Nodes {
+		if nd.IsSensor() {
+			assert.NotEqual(t, math.TanhActivation, nd.ActivationType, "sensor activation mutated: %s", nd)
+		} else if gnome1.isControlGeneIONode(nd) {
+			assert.NotEqual(t, math.TanhActivation, nd.ActivationType, "module IO node activation mutated: %s", nd)
+		} else if nd.ActivationType == math.TanhActivation {
+			mutated++
+		}
+	}
+	assert.True(t, mutated > 0, "no neuron activation mutated")
+
+	// no activators registered
+	opts.NodeActivators = nil
+	_, err := gnome1.mutateNodeActivation(opts)
+	assert.ErrorIs(t, err, neat.ErrNoActivatorsRegistered)
+}
+
 func TestGenome_mutateToggleEnable(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
 	gnome1 := buildTestGenome(1)
 	// add extra connection gene from BIAS to OUT
 	gene := NewConnectionGene(network.NewLinkWithTrait(gnome1.Traits[2], 5.5, gnome1.Nodes[2], gnome1.Nodes[3], false), 4, 0, true)
 	gnome1.Genes = append(gnome1.Genes, gene)
 
-	res, err := gnome1.mutateToggleEnable(50)
+	res, err := gnome1.mutateToggleEnable(50, rng)
 	require.NoError(t, err, "failed to mutate")
 	require.True(t, res, "mutation failed")
 
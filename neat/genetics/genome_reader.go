@@ -103,6 +103,18 @@ func (r *plainGenomeReader) Read() (*Genome, error) {
 			}
 			gnome.Genes = append(gnome.Genes, gene)
 
+		case "module":
+			// Read a MIMO control gene
+			mGene, err := readPlainControlGene(lr, gnome.Traits, gnome.Nodes)
+			if err != nil {
+				return nil, err
+			}
+			// check that control node ID is unique
+			if gnome.haveNode(mGene.ControlNode.Id) {
+				return nil, fmt.Errorf("control node ID: %d is not unique", mGene.ControlNode.Id)
+			}
+			gnome.ControlGenes = append(gnome.ControlGenes, mGene)
+
 		case "genomeend":
 			// Read Genome ID
 			_, err := fmt.Fscanf(lr, "%d", &gId)
@@ -168,11 +180,25 @@ func readPlainNetworkNode(r io.Reader, traits []*neat.Trait) (*network.NNode, er
 		node.NeuronType = network.NodeNeuronType(neuronType)
 	}
 
-	if len(parts) == 5 {
-		node.ActivationType, err = math.NodeActivators.ActivationTypeFromName(parts[4])
+	if len(parts) >= 5 {
+		if node.ActivationType, err = math.NodeActivators.ActivationTypeFromName(parts[4]); err != nil {
+			return nil, err
+		}
+	}
+	// the bias, response and aggregation are optional and have default values if omitted
+	if len(parts) >= 8 {
+		if node.Bias, err = strconv.ParseFloat(parts[5], 64); err != nil {
+			return nil, err
+		}
+		if node.Response, err = strconv.ParseFloat(parts[6], 64); err != nil {
+			return nil, err
+		}
+		if node.AggregationType, err = math.NodeAggregators.AggregationTypeFromName(parts[7]); err != nil {
+			return nil, err
+		}
 	}
 
-	return node, err
+	return node, nil
 }
 
 // Reads Gene from reader in plain text format
@@ -204,6 +230,55 @@ func readPlainConnectionGene(r io.Reader, traits []*neat.Trait, nodes []*network
 	}
 }
 
+// Reads MIMO control gene from reader in plain text format
+func readPlainControlGene(r io.Reader, traits []*neat.Trait, nodes []*network.NNode) (*MIMOControlGene, error) {
+	var nodeId, traitId int
+	var innovationNum int64
+	var mutNum float64
+	var enabled bool
+	var activation, inputs, outputs string
+	_, err := fmt.Fscanf(r, "%d %d %d %g %t %s %s %s",
+		&nodeId, &traitId, &innovationNum, &mutNum, &enabled, &activation, &inputs, &outputs)
+	if err != nil {
+		return nil, err
+	}
+
+	controlNode := network.NewNNode(nodeId, network.HiddenNeuron)
+	controlNode.Trait = TraitWithId(traitId, traits)
+	if controlNode.ActivationType, err = math.NodeActivators.ActivationTypeFromName(activation); err != nil {
+		return nil, err
+	}
+	readModuleNodes := func(ids string) ([]*network.NNode, error) {
+		fields := strings.Split(ids, ",")
+		moduleNodes := make([]*network.NNode, len(fields))
+		for i, field := range fields {
+			id, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, err
+			}
+			if moduleNodes[i] = NodeWithId(id, nodes); moduleNodes[i] == nil {
+				return nil, fmt.Errorf("no MIMO IO node with id: %d can be found for module: %d", id, nodeId)
+			}
+		}
+		return moduleNodes, nil
+	}
+	inNodes, err := readModuleNodes(inputs)
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range inNodes {
+		controlNode.Incoming = append(controlNode.Incoming, network.NewLink(1.0, node, controlNode, false))
+	}
+	outNodes, err := readModuleNodes(outputs)
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range outNodes {
+		controlNode.Outgoing = append(controlNode.Outgoing, network.NewLink(1.0, controlNode, node, false))
+	}
+	return NewMIMOGene(controlNode, innovationNum, mutNum, enabled), nil
+}
+
 // A YAMLGenomeReader reads genome data from YAML encoded text file
 type yamlGenomeReader struct {
 	r *bufio.Reader
@@ -425,8 +500,26 @@ func readNNode(conf map[string]interface{}, traits []*neat.Trait) (*network.NNod
 		return nil, err
 	}
 	activation := conf["activation"].(string)
-	node.ActivationType, err = math.NodeActivators.ActivationTypeFromName(activation)
-	return node, err
+	if node.ActivationType, err = math.NodeActivators.ActivationTypeFromName(activation); err != nil {
+		return nil, err
+	}
+	// the bias, response and aggregation are optional and have default values if omitted
+	if bias, ok := conf["bias"]; ok {
+		if node.Bias, err = cast.ToFloat64E(bias); err != nil {
+			return nil, err
+		}
+	}
+	if response, ok := conf["response"]; ok {
+		if node.Response, err = cast.ToFloat64E(response); err != nil {
+			return nil, err
+		}
+	}
+	if aggregation, ok := conf["aggregation"]; ok {
+		if node.AggregationType, err = math.NodeAggregators.AggregationTypeFromName(cast.ToString(aggregation)); err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
 }
 
 // Reads Trait configuration
@@ -129,6 +129,22 @@ func TestReadGene_ReadPlainNNode(t *testing.T) {
 	assert.Equal(t, genNodeLabel, node.NeuronType, "wrong node placement label (neuron type) found")
 }
 
+func TestReadGene_ReadPlainNNode_nodeGenes(t *testing.T) {
+	nodeStr := "4 0 0 2 LinearActivation -0.5 1.5 MeanAggregation"
+
+	node, err := readPlainNetworkNode(strings.NewReader(nodeStr), nil)
+	require.NoError(t, err, "failed to read network node")
+
+	assert.Equal(t, 4, node.Id, "wrong node ID")
+	assert.Equal(t, math.LinearActivation, node.ActivationType, "wrong activation")
+	assert.Equal(t, -0.5, node.Bias, "wrong bias")
+	assert.Equal(t, 1.5, node.Response, "wrong response")
+	assert.Equal(t, math.MeanAggregation, node.AggregationType, "wrong aggregation")
+
+	_, err = readPlainNetworkNode(strings.NewReader("4 0 0 2 LinearActivation -0.5 1.5 UnknownAggregation"), nil)
+	assert.Error(t, err)
+}
+
 func TestReadGene_ReadPlainNNode_readError(t *testing.T) {
 	trait := neat.NewTrait()
 	trait.Id = 10
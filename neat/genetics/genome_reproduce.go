@@ -13,7 +13,7 @@ import (
 // the innovation number, the Gene is chosen randomly from either parent.  If one parent has an innovation absent in
 // the other, the baby may inherit the innovation if it is from the more fit parent.
 // The new Genome is given the id in the genomeId argument.
-func (g *Genome) mateMultipoint(og *Genome, genomeId int, fitness1, fitness2 float64) (*Genome, error) {
+func (g *Genome) mateMultipoint(og *Genome, genomeId int, fitness1, fitness2 float64, rng *rand.Rand) (*Genome, error) {
 	// Check if genomes has equal number of traits
 	if len(g.Traits) != len(og.Traits) {
 		return nil, fmt.Errorf("genomes has different traits count, %d != %d", len(g.Traits), len(og.Traits))
@@ -85,14 +85,14 @@ func (g *Genome) mateMultipoint(og *Genome, genomeId int, fitness1, fitness2 flo
 			p2innov := p2gene.InnovationNum
 
 			if p1innov == p2innov {
-				if rand.Float64() < 0.5 {
+				if rng.Float64() < 0.5 {
 					chosenGene = p1gene
 				} else {
 					chosenGene = p2gene
 				}
 
 				// If one is disabled, the corresponding gene in the offspring will likely be disabled
-				if !p1gene.IsEnabled || !p2gene.IsEnabled && rand.Float64() < 0.75 {
+				if !p1gene.IsEnabled || !p2gene.IsEnabled && rng.Float64() < 0.75 {
 					disable = true
 				}
 				i1++
@@ -185,15 +185,16 @@ func (g *Genome) mateMultipoint(og *Genome, genomeId int, fitness1, fitness2 flo
 		} // end SKIP
 	} // end FOR
 
+	// The nodes found in both parents inherit activation function from any of them
+	g.mateNodeActivations(og, newNodes, rng)
+
 	// check if parent's MIMO control genes should be inherited
 	if len(g.ControlGenes) != 0 || len(og.ControlGenes) != 0 {
 		// MIMO control genes found at least in one parent - append it to child if appropriate
-		if extraNodes, modules := g.mateModules(childNodesMap, og); modules != nil {
-			if len(extraNodes) > 0 {
-				// append extra IO nodes of MIMO genes not found in child
-				newNodes = append(newNodes, extraNodes...)
-			}
-
+		var modules []*MIMOControlGene
+		if newNodes, modules, err = g.mateModules(newNodes, childNodesMap, og, newTraits); err != nil {
+			return nil, err
+		} else if modules != nil {
 			// Return modular baby genome
 			return NewModularGenome(genomeId, newTraits, newNodes, newGenes, modules), nil
 		}
@@ -204,7 +205,7 @@ func (g *Genome) mateMultipoint(og *Genome, genomeId int, fitness1, fitness2 flo
 
 // This method mates like multipoint but instead of selecting one or the other when the innovation numbers match,
 // it averages their weights.
-func (g *Genome) mateMultipointAvg(og *Genome, genomeId int, fitness1, fitness2 float64) (*Genome, error) {
+func (g *Genome) mateMultipointAvg(og *Genome, genomeId int, fitness1, fitness2 float64, rng *rand.Rand) (*Genome, error) {
 	// Check if genomes has equal number of traits
 	if len(g.Traits) != len(og.Traits) {
 		return nil, fmt.Errorf("genomes has different traits count, %d != %d", len(g.Traits), len(og.Traits))
@@ -281,24 +282,24 @@ func (g *Genome) mateMultipointAvg(og *Genome, genomeId int, fitness1, fitness2
 
 			if p1innov == p2innov {
 				// Average them into the avg_gene
-				if rand.Float64() > 0.5 {
+				if rng.Float64() > 0.5 {
 					avgGene.Link.Trait = p1gene.Link.Trait
 				} else {
 					avgGene.Link.Trait = p2gene.Link.Trait
 				}
 				avgGene.Link.ConnectionWeight = (p1gene.Link.ConnectionWeight + p2gene.Link.ConnectionWeight) / 2.0 // WEIGHTS AVERAGED HERE
 
-				if rand.Float64() > 0.5 {
+				if rng.Float64() > 0.5 {
 					avgGene.Link.InNode = p1gene.Link.InNode
 				} else {
 					avgGene.Link.InNode = p2gene.Link.InNode
 				}
-				if rand.Float64() > 0.5 {
+				if rng.Float64() > 0.5 {
 					avgGene.Link.OutNode = p1gene.Link.OutNode
 				} else {
 					avgGene.Link.OutNode = p2gene.Link.OutNode
 				}
-				if rand.Float64() > 0.5 {
+				if rng.Float64() > 0.5 {
 					avgGene.Link.IsRecurrent = p1gene.Link.IsRecurrent
 				} else {
 					avgGene.Link.IsRecurrent = p2gene.Link.IsRecurrent
@@ -306,7 +307,7 @@ func (g *Genome) mateMultipointAvg(og *Genome, genomeId int, fitness1, fitness2
 
 				avgGene.InnovationNum = p1innov
 				avgGene.MutationNum = (p1gene.MutationNum + p2gene.MutationNum) / 2.0
-				if !p1gene.IsEnabled || !p2gene.IsEnabled && rand.Float64() < 0.75 {
+				if !p1gene.IsEnabled || !p2gene.IsEnabled && rng.Float64() < 0.75 {
 					avgGene.IsEnabled = false
 				}
 
@@ -398,15 +399,16 @@ func (g *Genome) mateMultipointAvg(og *Genome, genomeId int, fitness1, fitness2
 			newGenes = append(newGenes, gene)
 		} // end SKIP
 	} // end FOR
+	// The nodes found in both parents inherit activation function from any of them
+	g.mateNodeActivations(og, newNodes, rng)
+
 	// check if parent's MIMO control genes should be inherited
 	if len(g.ControlGenes) != 0 || len(og.ControlGenes) != 0 {
 		// MIMO control genes found at least in one parent - append it to child if appropriate
-		if extraNodes, modules := g.mateModules(childNodesMap, og); modules != nil {
-			if len(extraNodes) > 0 {
-				// append extra IO nodes of MIMO genes not found in child
-				newNodes = append(newNodes, extraNodes...)
-			}
-
+		var modules []*MIMOControlGene
+		if newNodes, modules, err = g.mateModules(newNodes, childNodesMap, og, newTraits); err != nil {
+			return nil, err
+		} else if modules != nil {
 			// Return modular baby genome
 			return NewModularGenome(genomeId, newTraits, newNodes, newGenes, modules), nil
 		}
@@ -418,7 +420,7 @@ func (g *Genome) mateMultipointAvg(og *Genome, genomeId int, fitness1, fitness2
 // This method is similar to a standard single point CROSSOVER operator. Traits are averaged as in the previous two
 // mating methods. A Gene is chosen in the smaller Genome for splitting. When the Gene is reached, it is averaged with
 // the matching Gene from the larger Genome, if one exists. Then every other Gene is taken from the larger Genome.
-func (g *Genome) mateSinglePoint(og *Genome, genomeId int) (*Genome, error) {
+func (g *Genome) mateSinglePoint(og *Genome, genomeId int, rng *rand.Rand) (*Genome, error) {
 	// Check if genomes has equal number of traits
 	if len(g.Traits) != len(og.Traits) {
 		return nil, fmt.Errorf("genomes has different traits count, %d != %d", len(g.Traits), len(og.Traits))
@@ -461,14 +463,14 @@ func (g *Genome) mateSinglePoint(og *Genome, genomeId int) (*Genome, error) {
 	var p1genes, p2genes []*Gene
 	size1, size2 := len(g.Genes), len(og.Genes)
 	if size1 < size2 {
-		crossPoint = rand.Intn(size1)
+		crossPoint = rng.Intn(size1)
 		p1stop = size1
 		p2stop = size2
 		stopper = size2
 		p1genes = g.Genes
 		p2genes = og.Genes
 	} else {
-		crossPoint = rand.Intn(size2)
+		crossPoint = rng.Intn(size2)
 		p1stop = size2
 		p2stop = size1
 		stopper = size1
@@ -504,24 +506,24 @@ func (g *Genome) mateSinglePoint(og *Genome, genomeId int) (*Genome, error) {
 					chosenGene = p2gene
 				} else {
 					// We are at the crossPoint here - average genes into the avgene
-					if rand.Float64() > 0.5 {
+					if rng.Float64() > 0.5 {
 						avgGene.Link.Trait = p1gene.Link.Trait
 					} else {
 						avgGene.Link.Trait = p2gene.Link.Trait
 					}
 					avgGene.Link.ConnectionWeight = (p1gene.Link.ConnectionWeight + p2gene.Link.ConnectionWeight) / 2.0 // WEIGHTS AVERAGED HERE
 
-					if rand.Float64() > 0.5 {
+					if rng.Float64() > 0.5 {
 						avgGene.Link.InNode = p1gene.Link.InNode
 					} else {
 						avgGene.Link.InNode = p2gene.Link.InNode
 					}
-					if rand.Float64() > 0.5 {
+					if rng.Float64() > 0.5 {
 						avgGene.Link.OutNode = p1gene.Link.OutNode
 					} else {
 						avgGene.Link.OutNode = p2gene.Link.OutNode
 					}
-					if rand.Float64() > 0.5 {
+					if rng.Float64() > 0.5 {
 						avgGene.Link.IsRecurrent = p1gene.Link.IsRecurrent
 					} else {
 						avgGene.Link.IsRecurrent = p2gene.Link.IsRecurrent
@@ -529,7 +531,7 @@ func (g *Genome) mateSinglePoint(og *Genome, genomeId int) (*Genome, error) {
 
 					avgGene.InnovationNum = p1innov
 					avgGene.MutationNum = (p1gene.MutationNum + p2gene.MutationNum) / 2.0
-					if !p1gene.IsEnabled || !p2gene.IsEnabled && rand.Float64() < 0.75 {
+					if !p1gene.IsEnabled || !p2gene.IsEnabled && rng.Float64() < 0.75 {
 						avgGene.IsEnabled = false
 					}
 
@@ -626,15 +628,16 @@ func (g *Genome) mateSinglePoint(og *Genome, genomeId int) (*Genome, error) {
 			newGenes = append(newGenes, gene)
 		} // end SKIP
 	} // end FOR
+	// The nodes found in both parents inherit activation function from any of them
+	g.mateNodeActivations(og, newNodes, rng)
+
 	// check if parent's MIMO control genes should be inherited
 	if len(g.ControlGenes) != 0 || len(og.ControlGenes) != 0 {
 		// MIMO control genes found at least in one parent - append it to child if appropriate
-		if extraNodes, modules := g.mateModules(childNodesMap, og); modules != nil {
-			if len(extraNodes) > 0 {
-				// append extra IO nodes of MIMO genes not found in child
-				newNodes = append(newNodes, extraNodes...)
-			}
-
+		var modules []*MIMOControlGene
+		if newNodes, modules, err = g.mateModules(newNodes, childNodesMap, og, newTraits); err != nil {
+			return nil, err
+		} else if modules != nil {
 			// Return modular baby genome
 			return NewModularGenome(genomeId, newTraits, newNodes, newGenes, modules), nil
 		}
@@ -643,35 +646,72 @@ func (g *Genome) mateSinglePoint(og *Genome, genomeId int) (*Genome, error) {
 	return NewGenome(genomeId, newTraits, newNodes, newGenes), nil
 }
 
+// Assigns activation function of each child neuron node found in both parents from the randomly chosen parent. Thus,
+// the activation is inherited per matching node rather than with the connection gene which brought the node to the child.
+func (g *Genome) mateNodeActivations(og *Genome, childNodes []*network.NNode, rng *rand.Rand) {
+	for _, node := range childNodes {
+		if node.IsSensor() {
+			continue
+		}
+		node1, node2 := NodeWithId(node.Id, g.Nodes), NodeWithId(node.Id, og.Nodes)
+		if node1 == nil || node2 == nil || node1.ActivationType == node2.ActivationType {
+			continue
+		}
+		if rng.Float64() < 0.5 {
+			node.ActivationType = node1.ActivationType
+		} else {
+			node.ActivationType = node2.ActivationType
+		}
+	}
+}
+
 // Builds an array of modules to be added to the child during crossover.
 // If any or both parents has module and at least one modular endpoint node already inherited by child genome than make
-// sure that child get all associated module nodes
-func (g *Genome) mateModules(childNodes map[int]*network.NNode, og *Genome) ([]*network.NNode, []*MIMOControlGene) {
-	parentModules := make([]*MIMOControlGene, 0)
-	currGenomeModules := findModulesIntersection(childNodes, g.ControlGenes)
-	if len(currGenomeModules) > 0 {
-		parentModules = append(parentModules, currGenomeModules...)
+// sure that child get all associated module nodes. The module found in both parents is inherited only once. The IO
+// nodes not inherited by child yet are copied into the list of child nodes and the control genes are copied to link
+// the child nodes, thus the child doesn't share anything with parents. Returns the updated list of child nodes and
+// the list of child control genes or nil if no module was inherited.
+func (g *Genome) mateModules(childNodes []*network.NNode, childNodesMap map[int]*network.NNode, og *Genome,
+	childTraits []*neat.Trait) ([]*network.NNode, []*MIMOControlGene, error) {
+	parentModules := findModulesIntersection(childNodesMap, g.ControlGenes)
+	inherited := make(map[int64]bool, len(parentModules))
+	for _, cg := range parentModules {
+		inherited[cg.InnovationNum] = true
 	}
-	outGenomeModules := findModulesIntersection(childNodes, og.ControlGenes)
-	if len(outGenomeModules) > 0 {
-		parentModules = append(parentModules, outGenomeModules...)
+	for _, cg := range findModulesIntersection(childNodesMap, og.ControlGenes) {
+		if !inherited[cg.InnovationNum] {
+			parentModules = append(parentModules, cg)
+		}
 	}
 	if len(parentModules) == 0 {
-		return nil, nil
+		return childNodes, nil, nil
 	}
 
-	// collect IO nodes from all included modules and add return it as extra ones
-	extraNodes := make([]*network.NNode, 0)
+	// copy IO nodes from all included modules not found in known child nodes
 	for _, cg := range parentModules {
 		for _, n := range cg.ioNodes {
-			if _, ok := childNodes[n.Id]; !ok {
-				// not found in known child nodes - collect it
-				extraNodes = append(extraNodes, n)
+			if _, ok := childNodesMap[n.Id]; !ok {
+				var trait *neat.Trait
+				if n.Trait != nil {
+					trait = TraitWithId(n.Trait.Id, childTraits)
+				}
+				nodeCopy := network.NewNNodeCopy(n, trait)
+				childNodes = nodeInsert(childNodes, nodeCopy)
+				childNodesMap[nodeCopy.Id] = nodeCopy
 			}
 		}
 	}
 
-	return extraNodes, parentModules
+	modules := make([]*MIMOControlGene, len(parentModules))
+	for i, cg := range parentModules {
+		module, err := copyControlGene(cg, childTraits, childNodesMap)
+		if err != nil {
+			return nil, nil, err
+		}
+		modules[i] = module
+	}
+	sortControlGenes(modules)
+	return childNodes, modules, nil
 }
 
 // Finds intersection of provided nodes with IO nodes from control genes and returns list of control genes found.
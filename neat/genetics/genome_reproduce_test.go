@@ -1,6 +1,7 @@
 package genetics
 
 import (
+	"deepneat/neat/math"
 	"deepneat/neat/network"
 	"math/rand"
 	"testing"
@@ -10,14 +11,14 @@ import (
 )
 
 func TestGenome_mateMultipoint(t *testing.T) {
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	// Check equal sized gene pools
 	//
 	gnome1 := buildTestGenome(1)
 	gnome2 := buildTestGenome(2)
 	genomeId := 3
 	fitness1, fitness2 := 1.0, 2.3
-	genomeChild, err := gnome1.mateMultipoint(gnome2, genomeId, fitness1, fitness2)
+	genomeChild, err := gnome1.mateMultipoint(gnome2, genomeId, fitness1, fitness2, rng)
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -31,7 +32,7 @@ func TestGenome_mateMultipoint(t *testing.T) {
 		gnome1.Nodes[3], false), 4, 0, true)
 	gnome1.Genes = append(gnome1.Genes, gene)
 	fitness1, fitness2 = 15.0, 2.3
-	genomeChild, err = gnome1.mateMultipoint(gnome2, genomeId, fitness1, fitness2)
+	genomeChild, err = gnome1.mateMultipoint(gnome2, genomeId, fitness1, fitness2, rng)
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -40,15 +41,37 @@ func TestGenome_mateMultipoint(t *testing.T) {
 	assert.Len(t, genomeChild.Traits, 3, "wrong number of traits")
 }
 
+func TestGenome_mateNodeActivations(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	gnome1 := buildTestGenome(1)
+	gnome2 := buildTestGenome(2)
+	gnome2.Nodes[3].ActivationType = math.TanhActivation
+
+	inherited := make(map[math.NodeActivationType]int)
+	for i := 0; i < 20; i++ {
+		// the output node is matching and inherits activation of any parent regardless of the chosen genes
+		genomeChild, err := gnome1.mateSinglePoint(gnome2, 3, rng)
+		require.NoError(t, err, "failed to mate")
+		output := NodeWithId(4, genomeChild.Nodes)
+		require.NotNil(t, output, "output node not found")
+		inherited[output.ActivationType]++
+
+		// the sensors keep their activations
+		assert.Equal(t, math.NullActivation, NodeWithId(1, genomeChild.Nodes).ActivationType)
+	}
+	assert.Len(t, inherited, 2, "activations of both parents expected to be inherited")
+	assert.Equal(t, math.SigmoidSteepenedActivation, gnome1.Nodes[3].ActivationType, "parent changed")
+}
+
 func TestGenome_mateMultipointModular(t *testing.T) {
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	// Check equal sized gene pools
 	//
 	gnome1 := buildTestGenome(1)
 	gnome2 := buildTestModularGenome(2)
 	genomeId := 3
 	fitness1, fitness2 := 1.0, 2.3
-	genomeChild, err := gnome1.mateMultipoint(gnome2, genomeId, fitness1, fitness2)
+	genomeChild, err := gnome1.mateMultipoint(gnome2, genomeId, fitness1, fitness2, rng)
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -58,15 +81,47 @@ func TestGenome_mateMultipointModular(t *testing.T) {
 	assert.Len(t, genomeChild.ControlGenes, 1, "wrong number of control genes")
 }
 
+func TestGenome_mateModules(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	gnome1 := buildTestModularGenome(1)
+	gnome2 := buildTestModularGenome(2)
+	genomeChild, err := gnome1.mateMultipoint(gnome2, 3, 1.0, 2.3, rng)
+	require.NoError(t, err, "failed to mate")
+
+	// the module found in both parents is inherited once and not shared with parents
+	require.Len(t, genomeChild.ControlGenes, 1, "wrong number of control genes")
+	module := genomeChild.ControlGenes[0]
+	assert.NotSame(t, gnome1.ControlGenes[0], module)
+	assert.NotSame(t, gnome2.ControlGenes[0], module)
+	for _, node := range module.ioNodes {
+		assert.Same(t, genomeChild.NodeWithId(node.Id), node, "IO node is not the child node")
+	}
+
+	valid, err := genomeChild.verify()
+	require.NoError(t, err, "failed to verify genome")
+	assert.True(t, valid)
+
+	// the IO node missing in child is copied in order of IDs
+	gnome2.Genes = gnome2.Genes[:5]
+	gnome1 = buildTestGenome(1)
+	genomeChild, err = gnome1.mateMultipoint(gnome2, 3, 1.0, 2.3, rng)
+	require.NoError(t, err, "failed to mate")
+	require.Len(t, genomeChild.ControlGenes, 1, "wrong number of control genes")
+	valid, err = genomeChild.verify()
+	require.NoError(t, err, "failed to verify genome")
+	assert.True(t, valid)
+	assert.NotSame(t, gnome2.NodeWithId(7), genomeChild.NodeWithId(7))
+}
+
 func TestGenome_mateMultipointAvg(t *testing.T) {
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	// Check equal sized gene pools
 	//
 	gnome1 := buildTestGenome(1)
 	gnome2 := buildTestGenome(2)
 	genomeId := 3
 	fitness1, fitness2 := 1.0, 2.3
-	genomeChild, err := gnome1.mateMultipointAvg(gnome2, genomeId, fitness1, fitness2)
+	genomeChild, err := gnome1.mateMultipointAvg(gnome2, genomeId, fitness1, fitness2, rng)
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -84,7 +139,7 @@ func TestGenome_mateMultipointAvg(t *testing.T) {
 	gnome2.Genes = append(gnome2.Genes, gene2)
 
 	fitness1, fitness2 = 15.0, 2.3
-	genomeChild, err = gnome1.mateMultipointAvg(gnome2, genomeId, fitness1, fitness2)
+	genomeChild, err = gnome1.mateMultipointAvg(gnome2, genomeId, fitness1, fitness2, rng)
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -94,14 +149,14 @@ func TestGenome_mateMultipointAvg(t *testing.T) {
 }
 
 func TestGenome_mateMultipointAvgModular(t *testing.T) {
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	// Check equal sized gene pools
 	//
 	gnome1 := buildTestGenome(1)
 	gnome2 := buildTestModularGenome(2)
 	genomeId := 3
 	fitness1, fitness2 := 1.0, 2.3
-	genomeChild, err := gnome1.mateMultipointAvg(gnome2, genomeId, fitness1, fitness2)
+	genomeChild, err := gnome1.mateMultipointAvg(gnome2, genomeId, fitness1, fitness2, rng)
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -112,13 +167,13 @@ func TestGenome_mateMultipointAvgModular(t *testing.T) {
 }
 
 func TestGenome_mateSinglePoint(t *testing.T) {
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	// Check equal sized gene pools
 	//
 	gnome1 := buildTestGenome(1)
 	gnome2 := buildTestGenome(2)
 	genomeId := 3
-	genomeChild, err := gnome1.mateSinglePoint(gnome2, genomeId)
+	genomeChild, err := gnome1.mateSinglePoint(gnome2, genomeId, rng)
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -131,7 +186,7 @@ func TestGenome_mateSinglePoint(t *testing.T) {
 	gene := NewConnectionGene(network.NewLinkWithTrait(gnome1.Traits[2], 5.5, gnome1.Nodes[2],
 		gnome1.Nodes[3], false), 4, 0, false)
 	gnome1.Genes = append(gnome1.Genes, gene)
-	genomeChild, err = gnome1.mateSinglePoint(gnome2, genomeId)
+	genomeChild, err = gnome1.mateSinglePoint(gnome2, genomeId, rng)
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -145,7 +200,7 @@ func TestGenome_mateSinglePoint(t *testing.T) {
 	// append additional gene
 	gnome2.Genes = append(gnome2.Genes, NewConnectionGene(network.NewLinkWithTrait(gnome2.Traits[2], 5.5, gnome2.Nodes[1],
 		gnome2.Nodes[3], true), 4, 0, false))
-	genomeChild, err = gnome1.mateSinglePoint(gnome2, genomeId)
+	genomeChild, err = gnome1.mateSinglePoint(gnome2, genomeId, rng)
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
@@ -155,14 +210,14 @@ func TestGenome_mateSinglePoint(t *testing.T) {
 }
 
 func TestGenome_mateSinglePointModular(t *testing.T) {
-	rand.Seed(42)
+	rng := rand.New(rand.NewSource(42))
 	// Check equal sized gene pools
 	//
 	gnome1 := buildTestGenome(1)
 	gnome2 := buildTestModularGenome(2)
 	genomeId := 3
 
-	genomeChild, err := gnome1.mateSinglePoint(gnome2, genomeId)
+	genomeChild, err := gnome1.mateSinglePoint(gnome2, genomeId, rng)
 	require.NoError(t, err, "failed to mate")
 	require.NotNil(t, genomeChild, "Failed to create child genome")
 
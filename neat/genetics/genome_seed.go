@@ -0,0 +1,269 @@
+package genetics
+
+import (
+	"deepneat/neat"
+	"deepneat/neat/math"
+	"deepneat/neat/network"
+
+	"github.com/pkg/errors"
+)
+
+// NewGenomeFromINIConfig is to create the seed genome as neat-python would do from the parameters of the
+// [DefaultGenome] section of its configuration file. The input nodes get IDs starting from 1, followed by the hidden
+// and the output nodes. The neat-python genomes have no bias input, instead the bias of each neuron node is
+// initialized from the normal distribution. If the genome is not feed forward, each neuron node gets the recurrent
+// self-connection as well. If no connections are created, e.g., with unconnected scheme or with partial scheme and small
+// connection fraction, the single enabled connection from the random input to the random output is created as FS-NEAT
+// does, thus the genome is valid and gets more connections by mutations.
+func NewGenomeFromINIConfig(id int, conf *neat.INIGenomeConfig, opts *neat.Options) (*Genome, error) {
+	if conf.NumInputs <= 0 || conf.NumOutputs <= 0 {
+		return nil, errors.Errorf("number of inputs: %d and outputs: %d must be positive", conf.NumInputs, conf.NumOutputs)
+	}
+	if conf.NumHidden < 0 {
+		return nil, errors.Errorf("number of hidden nodes must not be negative: %d", conf.NumHidden)
+	}
+	if err := conf.InitialConnection.Validate(); err != nil {
+		return nil, err
+	}
+	rng := opts.Rand()
+
+	trait := neat.NewTrait()
+	trait.Id = 1
+	trait.Params = make([]float64, neat.NumTraitParams)
+
+	gnome := newGenome(id, []*neat.Trait{trait}, make([]*network.NNode, 0), make([]*Gene, 0), nil)
+
+	newNeuron := func(nodeId int, neuronType network.NodeNeuronType) *network.NNode {
+		node := network.NewNNode(nodeId, neuronType)
+		node.Trait = trait
+		node.ActivationType = conf.ActivationDefault
+		node.AggregationType = conf.AggregationDefault
+		node.Bias = rng.NormFloat64()*conf.BiasInitStdev + conf.BiasInitMean
+		node.Response = rng.NormFloat64()*conf.ResponseInitStdev + conf.ResponseInitMean
+		return node
+	}
+	inputs := make([]*network.NNode, conf.NumInputs)
+	for i := range inputs {
+		inputs[i] = network.NewSensorNode(i+1, false)
+		inputs[i].Trait = trait
+		gnome.addNode(inputs[i])
+	}
+	hidden := make([]*network.NNode, conf.NumHidden)
+	for i := range hidden {
+		hidden[i] = newNeuron(conf.NumInputs+i+1, network.HiddenNeuron)
+		gnome.addNode(hidden[i])
+	}
+	outputs := make([]*network.NNode, conf.NumOutputs)
+	for i := range outputs {
+		outputs[i] = newNeuron(conf.NumInputs+conf.NumHidden+i+1, network.OutputNeuron)
+		gnome.addNode(outputs[i])
+	}
+
+	innovation := int64(1)
+	for _, c := range collectINIConnections(conf, gnome.Nodes, inputs, hidden, outputs) {
+		if conf.InitialConnection.IsPartial() && rng.Float64() >= conf.ConnectionFraction {
+			continue
+		}
+		weight := rng.NormFloat64()*conf.WeightInitStdev + conf.WeightInitMean
+		gene := NewGeneWithTrait(trait, weight, c.in, c.out, c.in == c.out, innovation, 0)
+		gene.IsEnabled = conf.EnabledDefault
+		gnome.Genes = append(gnome.Genes, gene)
+		innovation++
+	}
+	if len(gnome.Genes) == 0 {
+		in, out := inputs[rng.Intn(len(inputs))], outputs[rng.Intn(len(outputs))]
+		weight := rng.NormFloat64()*conf.WeightInitStdev + conf.WeightInitMean
+		gnome.Genes = append(gnome.Genes, NewGeneWithTrait(trait, weight, in, out, false, innovation, 0))
+	}
+	return gnome, nil
+}
+
+// iniConnection The connection of the seed genome created from the neat-python configuration
+type iniConnection struct {
+	in, out *network.NNode
+}
+
+// collectINIConnections is to collect the connections of the seed genome with given nodes as neat-python does
+func collectINIConnections(conf *neat.INIGenomeConfig, nodes, inputs, hidden, outputs []*network.NNode) []iniConnection {
+	connections := make([]iniConnection, 0)
+	if conf.InitialConnection == neat.INIConnectionUnconnected {
+		return connections
+	}
+	if len(hidden) > 0 {
+		for _, h := range hidden {
+			for _, in := range inputs {
+				connections = append(connections, iniConnection{in: in, out: h})
+			}
+		}
+		for _, h := range hidden {
+			for _, out := range outputs {
+				connections = append(connections, iniConnection{in: h, out: out})
+			}
+		}
+	}
+	if len(hidden) == 0 || conf.InitialConnection.IsDirect() {
+		for _, out := range outputs {
+			for _, in := range inputs {
+				connections = append(connections, iniConnection{in: in, out: out})
+			}
+		}
+	}
+	if !conf.FeedForward {
+		for _, n := range nodes {
+			if !n.IsSensor() {
+				connections = append(connections, iniConnection{in: n, out: n})
+			}
+		}
+	}
+	return connections
+}
+
+// SeedConnectionScheme defines how the layers of the seed genome are connected
+type SeedConnectionScheme string
+
+const (
+	// SeedConnectionFull every node of each layer is connected to every node of the next layer
+	SeedConnectionFull SeedConnectionScheme = "full"
+	// SeedConnectionSparse every possible connection between adjacent layers is created with given probability
+	SeedConnectionSparse SeedConnectionScheme = "sparse"
+	// SeedConnectionFSNEAT only one randomly selected input is connected as in Feature Selective NEAT (FS-NEAT), the
+	// hidden layers if any are fully connected
+	SeedConnectionFSNEAT SeedConnectionScheme = "fs-neat"
+)
+
+// Validate is to check if this seed connection scheme is supported
+func (s SeedConnectionScheme) Validate() error {
+	if s != SeedConnectionFull && s != SeedConnectionSparse && s != SeedConnectionFSNEAT {
+		return errors.Errorf("unsupported seed connection scheme: [%s]", s)
+	}
+	return nil
+}
+
+// SeedGenomeSpec The specification of the seed genome to be created
+type SeedGenomeSpec struct {
+	// The number of input and output nodes
+	NumInputs  int
+	NumOutputs int
+	// If set, the bias node is added after the inputs and connected to every hidden and output node. The bias is left
+	// unconnected by the FS-NEAT scheme.
+	Bias bool
+	// The sizes of the hidden layers between inputs and outputs, can be empty
+	HiddenLayers []int
+	// The scheme to connect adjacent layers
+	Scheme SeedConnectionScheme
+	// The probability of connection for the sparse scheme
+	ConnectionProb float64
+}
+
+// Validate is to check that this specification defines valid genome
+func (s SeedGenomeSpec) Validate() error {
+	if s.NumInputs <= 0 || s.NumOutputs <= 0 {
+		return errors.Errorf("number of inputs: %d and outputs: %d must be positive", s.NumInputs, s.NumOutputs)
+	}
+	for i, size := range s.HiddenLayers {
+		if size <= 0 {
+			return errors.Errorf("size of hidden layer: %d must be positive: %d", i, size)
+		}
+	}
+	if err := s.Scheme.Validate(); err != nil {
+		return err
+	}
+	if s.Scheme == SeedConnectionSparse && (s.ConnectionProb <= 0 || s.ConnectionProb > 1) {
+		return errors.Errorf("connection probability out of range (0;1]: %f", s.ConnectionProb)
+	}
+	return nil
+}
+
+// NewSeedGenome is to create the seed genome according to the given specification. The input nodes get IDs starting
+// from 1, followed by the bias, the hidden and the output nodes. The connections are created only between adjacent
+// layers and get unique innovation numbers starting from 1. The activation functions of the hidden nodes are selected
+// randomly from the ones provided by options. The sparse scheme always keeps at least one connection to each hidden
+// and output node, so that every node is reachable from inputs.
+func NewSeedGenome(id int, spec SeedGenomeSpec, opts *neat.Options) (*Genome, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	rng := opts.Rand()
+
+	trait := neat.NewTrait()
+	trait.Id = 1
+	trait.Params = make([]float64, neat.NumTraitParams)
+
+	gnome := newGenome(id, []*neat.Trait{trait}, make([]*network.NNode, 0), make([]*Gene, 0), nil)
+
+	nodeId := 0
+	addNode := func(node *network.NNode) *network.NNode {
+		node.Trait = trait
+		gnome.addNode(node)
+		return node
+	}
+
+	// create layers of nodes
+	inputs := make([]*network.NNode, spec.NumInputs)
+	for i := range inputs {
+		nodeId++
+		inputs[i] = addNode(network.NewSensorNode(nodeId, false))
+	}
+	var bias *network.NNode
+	if spec.Bias {
+		nodeId++
+		bias = addNode(network.NewSensorNode(nodeId, true))
+	}
+	layers := [][]*network.NNode{inputs}
+	for _, size := range spec.HiddenLayers {
+		layer := make([]*network.NNode, size)
+		for i := range layer {
+			nodeId++
+			layer[i] = addNode(network.NewNNode(nodeId, network.HiddenNeuron))
+			activationType, err := opts.RandomNodeActivationType()
+			if err != nil {
+				return nil, err
+			}
+			layer[i].ActivationType = activationType
+		}
+		layers = append(layers, layer)
+	}
+	outputs := make([]*network.NNode, spec.NumOutputs)
+	for i := range outputs {
+		nodeId++
+		outputs[i] = addNode(network.NewNNode(nodeId, network.OutputNeuron))
+	}
+	layers = append(layers, outputs)
+
+	innovation := int64(1)
+	connect := func(in, out *network.NNode) {
+		weight := float64(math.RandSignWith(rng)) * rng.Float64()
+		gnome.Genes = append(gnome.Genes, NewGeneWithTrait(trait, weight, in, out, false, innovation, weight))
+		innovation++
+	}
+
+	// the FS-NEAT connects only one random input to the first layer after inputs
+	if spec.Scheme == SeedConnectionFSNEAT {
+		layers[0] = []*network.NNode{inputs[rng.Intn(len(inputs))]}
+	}
+	for l := 1; l < len(layers); l++ {
+		sources := layers[l-1]
+		if bias != nil && spec.Scheme != SeedConnectionFSNEAT {
+			sources = append(sources[:len(sources):len(sources)], bias)
+		}
+		for _, out := range layers[l] {
+			if spec.Scheme != SeedConnectionSparse {
+				for _, in := range sources {
+					connect(in, out)
+				}
+				continue
+			}
+			connected := false
+			for _, in := range sources {
+				if rng.Float64() < spec.ConnectionProb {
+					connect(in, out)
+					connected = true
+				}
+			}
+			if !connected {
+				connect(sources[rng.Intn(len(sources))], out)
+			}
+		}
+	}
+	return gnome, nil
+}
@@ -0,0 +1,223 @@
+package genetics
+
+import (
+	"deepneat/neat"
+	"deepneat/neat/math"
+	"deepneat/neat/network"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGenomeFromINIConfig(t *testing.T) {
+	configFile, err := os.Open("../../config-neat.ini")
+	require.NoError(t, err)
+	conf, err := neat.LoadINIConfig(configFile)
+	require.NoError(t, err, "failed to load INI config")
+	opts := conf.Options.WithRandSource(rand.New(rand.NewSource(42)))
+
+	gnome, err := NewGenomeFromINIConfig(1, conf.Genome, opts)
+	require.NoError(t, err, "failed to create genome")
+	require.Len(t, gnome.Nodes, 23)
+	// inputs to hidden and hidden to outputs
+	require.Len(t, gnome.Genes, 10*10+10*3)
+	checkSeedGenome(t, gnome)
+
+	for _, node := range gnome.Nodes[10:] {
+		assert.Equal(t, math.SigmoidSteepenedActivation, node.ActivationType)
+		assert.Equal(t, 1.0, node.Response)
+	}
+	for _, gene := range gnome.Genes {
+		assert.False(t, gene.Link.InNode.IsSensor() && gene.Link.OutNode.NeuronType == network.OutputNeuron, "no direct connections expected")
+	}
+}
+
+func TestNewGenomeFromINIConfig_connectionSchemes(t *testing.T) {
+	opts := (&neat.Options{}).WithRandSource(rand.New(rand.NewSource(42)))
+	conf := &neat.INIGenomeConfig{
+		NumInputs:         3,
+		NumOutputs:        2,
+		NumHidden:         2,
+		InitialConnection: neat.INIConnectionFullDirect,
+		FeedForward:       true,
+		ActivationDefault: math.TanhActivation,
+		WeightInitStdev:   1.0,
+		EnabledDefault:    true,
+	}
+	gnome, err := NewGenomeFromINIConfig(1, conf, opts)
+	require.NoError(t, err)
+	assert.Len(t, gnome.Genes, 3*2+2*2+3*2)
+	checkSeedGenome(t, gnome)
+
+	// no hidden nodes - direct connections
+	conf.NumHidden = 0
+	conf.InitialConnection = neat.INIConnectionFull
+	gnome, err = NewGenomeFromINIConfig(1, conf, opts)
+	require.NoError(t, err)
+	assert.Len(t, gnome.Genes, 3*2)
+	checkSeedGenome(t, gnome)
+
+	// recurrent self-connections
+	conf.FeedForward = false
+	gnome, err = NewGenomeFromINIConfig(1, conf, opts)
+	require.NoError(t, err)
+	assert.Len(t, gnome.Genes, 3*2+2)
+	assert.True(t, gnome.Genes[6].Link.IsRecurrent)
+	checkSeedGenome(t, gnome)
+
+	// partial connections
+	conf.FeedForward = true
+	conf.InitialConnection = neat.INIConnectionPartialNoDirect
+	conf.ConnectionFraction = 0.5
+	gnome, err = NewGenomeFromINIConfig(1, conf, opts)
+	require.NoError(t, err)
+	assert.True(t, len(gnome.Genes) > 0 && len(gnome.Genes) < 3*2)
+
+	// unconnected
+	conf.InitialConnection = neat.INIConnectionUnconnected
+	gnome, err = NewGenomeFromINIConfig(1, conf, opts)
+	require.NoError(t, err)
+	assert.Len(t, gnome.Nodes, 5)
+	// the single connection from input to output
+	require.Len(t, gnome.Genes, 1)
+	assert.True(t, gnome.Genes[0].IsEnabled)
+	assert.True(t, gnome.Genes[0].Link.InNode.IsSensor())
+	assert.Equal(t, network.OutputNeuron, gnome.Genes[0].Link.OutNode.NeuronType)
+	checkSeedGenome(t, gnome)
+
+	conf.NumInputs = 0
+	_, err = NewGenomeFromINIConfig(1, conf, opts)
+	assert.Error(t, err)
+}
+
+func TestNewGenomeFromINIConfig_unconnectedPopulation(t *testing.T) {
+	opts := &neat.Options{
+		PopSize:            20,
+		CompatThreshold:    3.0,
+		NodeActivators:     []math.NodeActivationType{math.SigmoidSteepenedActivation},
+		NodeActivatorsProb: []float64{1.0},
+	}
+	opts = opts.WithRandSource(rand.New(rand.NewSource(42)))
+	conf := &neat.INIGenomeConfig{
+		NumInputs:         3,
+		NumOutputs:        2,
+		InitialConnection: neat.INIConnectionUnconnected,
+		FeedForward:       true,
+		ActivationDefault: math.SigmoidSteepenedActivation,
+		WeightInitStdev:   1.0,
+	}
+	gnome, err := NewGenomeFromINIConfig(1, conf, opts)
+	require.NoError(t, err)
+
+	pop, err := NewPopulation(gnome, opts)
+	require.NoError(t, err, "failed to create population from unconnected seed")
+	assert.Len(t, pop.Organisms, opts.PopSize)
+	for _, org := range pop.Organisms {
+		_, err = org.Phenotype()
+		assert.NoError(t, err)
+	}
+}
+
+// checkSeedGenome is to check that seed genome is valid and has unique innovation numbers
+func checkSeedGenome(t *testing.T, gnome *Genome) {
+	res, err := gnome.verify()
+	require.NoError(t, err, "failed to verify genome")
+	assert.True(t, res)
+	innovations := make(map[int64]bool)
+	for _, gene := range gnome.Genes {
+		assert.False(t, innovations[gene.InnovationNum], "duplicate innovation number: %d", gene.InnovationNum)
+		innovations[gene.InnovationNum] = true
+	}
+	_, err = gnome.Genesis(gnome.Id)
+	assert.NoError(t, err, "failed to create phenotype")
+}
+
+func TestNewSeedGenome(t *testing.T) {
+	opts := &neat.Options{
+		NodeActivators:     []math.NodeActivationType{math.TanhActivation},
+		NodeActivatorsProb: []float64{1.0},
+	}
+	opts = opts.WithRandSource(rand.New(rand.NewSource(42)))
+	spec := SeedGenomeSpec{
+		NumInputs:    2,
+		NumOutputs:   1,
+		Bias:         true,
+		HiddenLayers: []int{3, 2},
+		Scheme:       SeedConnectionFull,
+	}
+	gnome, err := NewSeedGenome(1, spec, opts)
+	require.NoError(t, err, "failed to create seed genome")
+	require.Len(t, gnome.Nodes, 2+1+3+2+1)
+	assert.Equal(t, network.BiasNeuron, gnome.Nodes[2].NeuronType)
+	assert.Equal(t, math.TanhActivation, gnome.Nodes[3].ActivationType)
+	assert.Equal(t, network.OutputNeuron, gnome.Nodes[8].NeuronType)
+	// (inputs + bias) * hidden1 + (hidden1 + bias) * hidden2 + (hidden2 + bias) * outputs
+	assert.Len(t, gnome.Genes, 3*3+4*2+3*1)
+	checkSeedGenome(t, gnome)
+
+	// no hidden layers
+	spec.HiddenLayers = nil
+	gnome, err = NewSeedGenome(1, spec, opts)
+	require.NoError(t, err, "failed to create seed genome")
+	assert.Len(t, gnome.Genes, 3)
+	checkSeedGenome(t, gnome)
+}
+
+func TestNewSeedGenome_sparse(t *testing.T) {
+	opts := (&neat.Options{}).WithRandSource(rand.New(rand.NewSource(42)))
+	spec := SeedGenomeSpec{
+		NumInputs:      10,
+		NumOutputs:     3,
+		HiddenLayers:   []int{5},
+		Scheme:         SeedConnectionSparse,
+		ConnectionProb: 0.1,
+	}
+	opts.NodeActivators, opts.NodeActivatorsProb = []math.NodeActivationType{math.TanhActivation}, []float64{1.0}
+	gnome, err := NewSeedGenome(1, spec, opts)
+	require.NoError(t, err, "failed to create seed genome")
+	assert.True(t, len(gnome.Genes) < 10*5+5*3)
+	checkSeedGenome(t, gnome)
+
+	// every hidden and output node has incoming connection
+	for _, node := range gnome.Nodes[10:] {
+		incoming := 0
+		for _, gene := range gnome.Genes {
+			if gene.Link.OutNode == node {
+				incoming++
+			}
+		}
+		assert.True(t, incoming > 0, "no incoming connections to node: %d", node.Id)
+	}
+}
+
+func TestNewSeedGenome_FSNEAT(t *testing.T) {
+	opts := (&neat.Options{}).WithRandSource(rand.New(rand.NewSource(42)))
+	spec := SeedGenomeSpec{
+		NumInputs:  5,
+		NumOutputs: 2,
+		Bias:       true,
+		Scheme:     SeedConnectionFSNEAT,
+	}
+	gnome, err := NewSeedGenome(1, spec, opts)
+	require.NoError(t, err, "failed to create seed genome")
+	require.Len(t, gnome.Genes, 2)
+	assert.Equal(t, gnome.Genes[0].Link.InNode, gnome.Genes[1].Link.InNode)
+	assert.Equal(t, network.InputNeuron, gnome.Genes[0].Link.InNode.NeuronType)
+	checkSeedGenome(t, gnome)
+}
+
+func TestSeedGenomeSpec_Validate(t *testing.T) {
+	testCases := map[string]SeedGenomeSpec{
+		"no inputs":        {NumOutputs: 1, Scheme: SeedConnectionFull},
+		"no outputs":       {NumInputs: 1, Scheme: SeedConnectionFull},
+		"empty layer":      {NumInputs: 1, NumOutputs: 1, HiddenLayers: []int{0}, Scheme: SeedConnectionFull},
+		"unknown scheme":   {NumInputs: 1, NumOutputs: 1, Scheme: "random"},
+		"zero probability": {NumInputs: 1, NumOutputs: 1, Scheme: SeedConnectionSparse},
+	}
+	for name, spec := range testCases {
+		assert.Error(t, spec.Validate(), name)
+	}
+}
@@ -15,10 +15,10 @@ const gnomeStr = "genomestart 1\n" +
 	"trait 1 0.1 0 0 0 0 0 0 0\n" +
 	"trait 3 0.3 0 0 0 0 0 0 0\n" +
 	"trait 2 0.2 0 0 0 0 0 0 0\n" +
-	"node 1 0 1 1 NullActivation\n" + // SENSOR
-	"node 2 0 1 1 NullActivation\n" + // SENSOR
-	"node 3 0 1 3 SigmoidSteepenedActivation\n" + // BIAS
-	"node 4 0 0 2 SigmoidSteepenedActivation\n" + // OUTPUT
+	"node 1 0 1 1 NullActivation 0 1 SumAggregation\n" + // SENSOR
+	"node 2 0 1 1 NullActivation 0 1 SumAggregation\n" + // SENSOR
+	"node 3 0 1 3 SigmoidSteepenedActivation 0 1 SumAggregation\n" + // BIAS
+	"node 4 0 0 2 SigmoidSteepenedActivation 0 1 SumAggregation\n" + // OUTPUT
 	"gene 1 1 4 1.5 false 1 0 true\n" +
 	"gene 2 2 4 2.5 false 2 0 true\n" +
 	"gene 3 3 4 3.5 false 3 0 true\n" +
@@ -32,10 +32,10 @@ func buildTestGenome(id int) *Genome {
 	}
 
 	nodes := []*network.NNode{
-		{Id: 1, NeuronType: network.InputNeuron, ActivationType: math.NullActivation, Incoming: make([]*network.Link, 0), Outgoing: make([]*network.Link, 0)},
-		{Id: 2, NeuronType: network.InputNeuron, ActivationType: math.NullActivation, Incoming: make([]*network.Link, 0), Outgoing: make([]*network.Link, 0)},
-		{Id: 3, NeuronType: network.BiasNeuron, ActivationType: math.SigmoidSteepenedActivation, Incoming: make([]*network.Link, 0), Outgoing: make([]*network.Link, 0)},
-		{Id: 4, NeuronType: network.OutputNeuron, ActivationType: math.SigmoidSteepenedActivation, Incoming: make([]*network.Link, 0), Outgoing: make([]*network.Link, 0)},
+		{Id: 1, NeuronType: network.InputNeuron, ActivationType: math.NullActivation, Response: 1.0, Incoming: make([]*network.Link, 0), Outgoing: make([]*network.Link, 0)},
+		{Id: 2, NeuronType: network.InputNeuron, ActivationType: math.NullActivation, Response: 1.0, Incoming: make([]*network.Link, 0), Outgoing: make([]*network.Link, 0)},
+		{Id: 3, NeuronType: network.BiasNeuron, ActivationType: math.SigmoidSteepenedActivation, Response: 1.0, Incoming: make([]*network.Link, 0), Outgoing: make([]*network.Link, 0)},
+		{Id: 4, NeuronType: network.OutputNeuron, ActivationType: math.SigmoidSteepenedActivation, Response: 1.0, Incoming: make([]*network.Link, 0), Outgoing: make([]*network.Link, 0)},
 	}
 
 	genes := []*Gene{
@@ -52,9 +52,9 @@ func buildTestModularGenome(id int) *Genome {
 
 	// append module with it's IO nodes
 	ioNodes := []*network.NNode{
-		{Id: 5, NeuronType: network.HiddenNeuron, ActivationType: math.LinearActivation, Incoming: make([]*network.Link, 0), Outgoing: make([]*network.Link, 0)},
-		{Id: 6, NeuronType: network.HiddenNeuron, ActivationType: math.LinearActivation, Incoming: make([]*network.Link, 0), Outgoing: make([]*network.Link, 0)},
-		{Id: 7, NeuronType: network.HiddenNeuron, ActivationType: math.NullActivation, Incoming: make([]*network.Link, 0), Outgoing: make([]*network.Link, 0)},
+		{Id: 5, NeuronType: network.HiddenNeuron, ActivationType: math.LinearActivation, Response: 1.0, Incoming: make([]*network.Link, 0), Outgoing: make([]*network.Link, 0)},
+		{Id: 6, NeuronType: network.HiddenNeuron, ActivationType: math.LinearActivation, Response: 1.0, Incoming: make([]*network.Link, 0), Outgoing: make([]*network.Link, 0)},
+		{Id: 7, NeuronType: network.HiddenNeuron, ActivationType: math.NullActivation, Response: 1.0, Incoming: make([]*network.Link, 0), Outgoing: make([]*network.Link, 0)},
 	}
 	gnome.addNodes(ioNodes)
 
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"gopkg.in/yaml.v3"
 	"io"
+	"strconv"
+	"strings"
 )
 
 // GenomeWriter is the interface to define genome writer
@@ -73,6 +75,18 @@ func (wr *plainGenomeWriter) WriteGenome(g *Genome) error {
 			return err
 		}
 	}
+
+	for _, cg := range g.ControlGenes {
+		if _, err := fmt.Fprint(wr.w, "module "); err != nil {
+			return err
+		}
+		if err := wr.writeControlGene(cg); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(wr.w, ""); err != nil {
+			return err
+		}
+	}
 	if _, err := fmt.Fprintf(wr.w, "genomeend %d\n", g.Id); err != nil {
 		return err
 	}
@@ -107,9 +121,13 @@ func (wr *plainGenomeWriter) writeNetworkNode(n *network.NNode) error {
 		traitId = n.Trait.Id
 	}
 	actStr, err := math.NodeActivators.ActivationNameFromType(n.ActivationType)
+	if err != nil {
+		return err
+	}
+	aggrStr, err := math.NodeAggregators.AggregationNameFromType(n.AggregationType)
 	if err == nil {
-		_, err = fmt.Fprintf(wr.w, "%d %d %d %d %s", n.Id, traitId, n.NodeType(),
-			n.NeuronType, actStr)
+		_, err = fmt.Fprintf(wr.w, "%d %d %d %d %s %g %g %s", n.Id, traitId, n.NodeType(),
+			n.NeuronType, actStr, n.Bias, n.Response, aggrStr)
 	}
 	return err
 }
@@ -134,6 +152,29 @@ func (wr *plainGenomeWriter) writeConnectionGene(g *Gene) error {
 	return err
 }
 
+// Dump MIMO control gene in plain text format. The IDs of input and output nodes of the module are comma separated.
+func (wr *plainGenomeWriter) writeControlGene(g *MIMOControlGene) error {
+	traitId := 0
+	if g.ControlNode.Trait != nil {
+		traitId = g.ControlNode.Trait.Id
+	}
+	actStr, err := math.NodeActivators.ActivationNameFromType(g.ControlNode.ActivationType)
+	if err != nil {
+		return err
+	}
+	inputs := make([]string, len(g.ControlNode.Incoming))
+	for i, l := range g.ControlNode.Incoming {
+		inputs[i] = strconv.Itoa(l.InNode.Id)
+	}
+	outputs := make([]string, len(g.ControlNode.Outgoing))
+	for i, l := range g.ControlNode.Outgoing {
+		outputs[i] = strconv.Itoa(l.OutNode.Id)
+	}
+	_, err = fmt.Fprintf(wr.w, "%d %d %d %g %t %s %s %s", g.ControlNode.Id, traitId, g.InnovationNum,
+		g.MutationNum, g.IsEnabled, actStr, strings.Join(inputs, ","), strings.Join(outputs, ","))
+	return err
+}
+
 // The YAML encoded genome writer
 type yamlGenomeWriter struct {
 	w *bufio.Writer
@@ -259,7 +300,12 @@ func (wr *yamlGenomeWriter) encodeNetworkNode(node *network.NNode) (nMap map[str
 		nMap["trait_id"] = 0
 	}
 	nMap["type"] = network.NeuronTypeName(node.NeuronType)
-	nMap["activation"], err = math.NodeActivators.ActivationNameFromType(node.ActivationType)
+	if nMap["activation"], err = math.NodeActivators.ActivationNameFromType(node.ActivationType); err != nil {
+		return nil, err
+	}
+	nMap["aggregation"], err = math.NodeAggregators.AggregationNameFromType(node.AggregationType)
+	nMap["bias"] = node.Bias
+	nMap["response"] = node.Response
 	return nMap, err
 }
 
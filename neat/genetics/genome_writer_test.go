@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	"deepneat/neat"
+	"deepneat/neat/math"
 	"deepneat/neat/network"
 	"fmt"
 	"strings"
@@ -46,12 +47,15 @@ func TestPlainGenomeWriter_WriteTrait_writeError(t *testing.T) {
 // Tests NNode serialization
 func TestPlainGenomeWriter_WriteNetworkNode(t *testing.T) {
 	nodeId, traitId, nodeType, neuronType := 1, 10, network.SensorNode, network.InputNeuron
-	nodeStr := fmt.Sprintf("%d %d %d %d SigmoidSteepenedActivation", nodeId, traitId, nodeType, neuronType)
+	nodeStr := fmt.Sprintf("%d %d %d %d SigmoidSteepenedActivation 0.5 1.5 MedianAggregation", nodeId, traitId, nodeType, neuronType)
 	trait := neat.NewTrait()
 	trait.Id = 10
 
 	node := network.NewNNode(nodeId, neuronType)
 	node.Trait = trait
+	node.Bias = 0.5
+	node.Response = 1.5
+	node.AggregationType = math.MedianAggregation
 	outBuffer := bytes.NewBufferString("")
 
 	wr := plainGenomeWriter{w: bufio.NewWriter(outBuffer)}
@@ -129,6 +133,33 @@ func TestPlainGenomeWriter_WriteGenome(t *testing.T) {
 	}
 }
 
+func TestPlainGenomeWriter_WriteGenome_modular(t *testing.T) {
+	gnome := buildTestModularGenome(1)
+	outBuf := bytes.NewBufferString("")
+	wr, err := NewGenomeWriter(outBuf, PlainGenomeEncoding)
+	require.NoError(t, err, "failed to create genome writer")
+	err = wr.WriteGenome(gnome)
+	require.NoError(t, err, "failed to write genome")
+	assert.Contains(t, outBuf.String(), "module 8 0 7 5.5 true MultiplyModuleActivation 5,6 7\n")
+
+	// read it back
+	rd, err := NewGenomeReader(outBuf, PlainGenomeEncoding)
+	require.NoError(t, err, "failed to create genome reader")
+	readGnome, err := rd.Read()
+	require.NoError(t, err, "failed to read genome")
+	require.Len(t, readGnome.ControlGenes, 1)
+	module := readGnome.ControlGenes[0]
+	assert.Equal(t, gnome.ControlGenes[0].String(), module.String())
+	require.Len(t, module.ioNodes, 3)
+	for i, node := range module.ioNodes {
+		assert.Equal(t, gnome.ControlGenes[0].ioNodes[i].Id, node.Id)
+		assert.Same(t, readGnome.NodeWithId(node.Id), node)
+	}
+	valid, err := readGnome.verify()
+	require.NoError(t, err, "failed to verify genome")
+	assert.True(t, valid)
+}
+
 func TestPlainGenomeWriter_WriteGenome_writeError(t *testing.T) {
 	errorWriter := ErrorWriter(1)
 	wr, err := NewGenomeWriter(bufio.NewWriter(&errorWriter), PlainGenomeEncoding)
@@ -142,6 +173,9 @@ func TestPlainGenomeWriter_WriteGenome_writeError(t *testing.T) {
 
 func TestYamlGenomeWriter_WriteGenome(t *testing.T) {
 	gnome := buildTestModularGenome(1)
+	gnome.Nodes[3].Bias = -0.5
+	gnome.Nodes[3].Response = 2.0
+	gnome.Nodes[3].AggregationType = math.ProductAggregation
 
 	// encode genome
 	outBuf := bytes.NewBufferString("")
@@ -177,6 +211,9 @@ func TestYamlGenomeWriter_WriteGenome(t *testing.T) {
 		assert.Equal(t, n.Id, nd.Id, "wrong node ID at: %d", i)
 		assert.Equal(t, n.ActivationType, nd.ActivationType, "wrong node activation at: %d", i)
 		assert.Equal(t, n.NeuronType, nd.NeuronType, "wrong node neuron type at: %d", i)
+		assert.Equal(t, n.Bias, nd.Bias, "wrong node bias at: %d", i)
+		assert.Equal(t, n.Response, nd.Response, "wrong node response at: %d", i)
+		assert.Equal(t, n.AggregationType, nd.AggregationType, "wrong node aggregation at: %d", i)
 	}
 
 	// check encoded traits
@@ -0,0 +1,171 @@
+package genetics
+
+import (
+	"encoding/gob"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// HallOfFameMember is the organism recorded in the hall of fame
+type HallOfFameMember struct {
+	// The copy of the organism with its own genome and the fitness it got at evaluation
+	Organism *Organism
+	// The generation in which organism was evaluated
+	Generation int
+	// The canonical hash of the organism's genome
+	Hash uint64
+}
+
+// HallOfFame keeps the most fit distinct genomes found during evolution. The genomes are deduplicated by their
+// canonical structural hash, thus the clones of the same champion surviving for many generations take only one place.
+// The number of members is bounded by the capacity. It is safe for concurrent use.
+type HallOfFame struct {
+	// The maximal number of members
+	Capacity int
+
+	// The members sorted to have the most fit first
+	members []*HallOfFameMember
+	// The index of members by the hash of their genomes
+	index map[uint64]*HallOfFameMember
+
+	mutex sync.RWMutex
+}
+
+// NewHallOfFame is to create new empty hall of fame with given capacity
+func NewHallOfFame(capacity int) *HallOfFame {
+	return &HallOfFame{
+		Capacity: capacity,
+		members:  make([]*HallOfFameMember, 0, capacity),
+		index:    make(map[uint64]*HallOfFameMember),
+	}
+}
+
+// Update is to add the organisms evaluated in the given generation which are fitter than the current members. If
+// the genome of organism is already in the hall of fame, only its fitness is updated if it's better. Returns the
+// number of new members added.
+func (h *HallOfFame) Update(generation int, organisms []*Organism) (int, error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if h.Capacity <= 0 {
+		return 0, nil
+	}
+
+	added := 0
+	for _, org := range organisms {
+		if len(h.members) >= h.Capacity && org.Fitness <= h.members[len(h.members)-1].Organism.Fitness {
+			// not fit enough
+			continue
+		}
+		hash := org.Genotype.Hash()
+		if member, ok := h.index[hash]; ok {
+			if org.Fitness > member.Organism.Fitness {
+				member.Organism.Fitness = org.Fitness
+				member.Organism.Error = org.Error
+				member.Generation = generation
+				h.sortAndTrim()
+			}
+			continue
+		}
+		genome, err := org.Genotype.duplicate(org.Genotype.Id)
+		if err != nil {
+			return added, err
+		}
+		clone, err := NewOrganism(org.Fitness, genome, org.Generation)
+		if err != nil {
+			return added, err
+		}
+		clone.Error = org.Error
+		member := &HallOfFameMember{Organism: clone, Generation: generation, Hash: hash}
+		h.members = append(h.members, member)
+		h.index[hash] = member
+		added++
+		// keep members sorted and trimmed, thus the last one is always the least fit retained member
+		h.sortAndTrim()
+	}
+	return added, nil
+}
+
+// sortAndTrim is to sort members to have the most fit first and to remove the least fit ones exceeding the capacity
+func (h *HallOfFame) sortAndTrim() {
+	sort.SliceStable(h.members, func(i, j int) bool {
+		return h.members[i].Organism.Fitness > h.members[j].Organism.Fitness
+	})
+	for len(h.members) > h.Capacity {
+		last := h.members[len(h.members)-1]
+		delete(h.index, last.Hash)
+		h.members = h.members[:len(h.members)-1]
+	}
+}
+
+// Members is to get the members of this hall of fame with the most fit first
+func (h *HallOfFame) Members() []*HallOfFameMember {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	members := make([]*HallOfFameMember, len(h.members))
+	copy(members, h.members)
+	return members
+}
+
+// Len is to get the number of members
+func (h *HallOfFame) Len() int {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return len(h.members)
+}
+
+// Encode is to encode the members of this hall of fame with provided GOB encoder
+func (h *HallOfFame) Encode(enc *gob.Encoder) error {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	if err := enc.Encode(h.Capacity); err != nil {
+		return err
+	}
+	if err := enc.Encode(len(h.members)); err != nil {
+		return err
+	}
+	for _, member := range h.members {
+		if err := enc.Encode(member.Organism); err != nil {
+			return err
+		}
+		if err := enc.Encode(member.Generation); err != nil {
+			return err
+		}
+		// the hash of the original genome is kept, because the genome encoding is not exact for all parameters
+		if err := enc.Encode(member.Hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Decode is to decode the members of this hall of fame with provided GOB decoder
+func (h *HallOfFame) Decode(dec *gob.Decoder) error {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	if err := dec.Decode(&h.Capacity); err != nil {
+		return errors.Wrap(err, "failed to decode hall of fame capacity")
+	}
+	var membersNum int
+	if err := dec.Decode(&membersNum); err != nil {
+		return errors.Wrap(err, "failed to decode number of hall of fame members")
+	}
+	h.members = make([]*HallOfFameMember, membersNum)
+	h.index = make(map[uint64]*HallOfFameMember, membersNum)
+	for i := 0; i < membersNum; i++ {
+		member := &HallOfFameMember{Organism: &Organism{}}
+		if err := dec.Decode(member.Organism); err != nil {
+			return errors.Wrap(err, "failed to decode hall of fame member")
+		}
+		if err := dec.Decode(&member.Generation); err != nil {
+			return errors.Wrap(err, "failed to decode hall of fame member generation")
+		}
+		if err := dec.Decode(&member.Hash); err != nil {
+			return errors.Wrap(err, "failed to decode hall of fame member hash")
+		}
+		h.members[i] = member
+		h.index[member.Hash] = member
+	}
+	return nil
+}
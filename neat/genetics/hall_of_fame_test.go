@@ -0,0 +1,111 @@
+package genetics
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHallOfFame_Update(t *testing.T) {
+	hof := NewHallOfFame(3)
+	organisms := make([]*Organism, 5)
+	for i := range organisms {
+		organisms[i] = buildTestOrganism(i+1, float64(i+1))
+	}
+	added, err := hof.Update(1, organisms)
+	require.NoError(t, err)
+	assert.Equal(t, 5, added, "all distinct genomes added before trimmed to capacity")
+	require.Equal(t, 3, hof.Len())
+
+	members := hof.Members()
+	for i, fitness := range []float64{5, 4, 3} {
+		assert.Equal(t, fitness, members[i].Organism.Fitness)
+		assert.Equal(t, 1, members[i].Generation)
+		assert.Equal(t, organisms[int(fitness)-1].Genotype.Hash(), members[i].Hash)
+	}
+	// members hold their own copies of genomes
+	assert.NotSame(t, organisms[4].Genotype, members[0].Organism.Genotype)
+
+	// not fit enough
+	added, err = hof.Update(2, []*Organism{buildTestOrganism(10, 2)})
+	require.NoError(t, err)
+	assert.Zero(t, added)
+	assert.Equal(t, 3.0, hof.Members()[2].Organism.Fitness)
+}
+
+func TestHallOfFame_Update_severalFitter(t *testing.T) {
+	hof := NewHallOfFame(3)
+	_, err := hof.Update(1, []*Organism{buildTestOrganism(1, 10), buildTestOrganism(2, 9), buildTestOrganism(3, 8)})
+	require.NoError(t, err)
+
+	// both organisms are fitter than the least fit member
+	added, err := hof.Update(2, []*Organism{buildTestOrganism(4, 12), buildTestOrganism(5, 11)})
+	require.NoError(t, err)
+	assert.Equal(t, 2, added)
+	require.Equal(t, 3, hof.Len())
+	for i, fitness := range []float64{12, 11, 10} {
+		assert.Equal(t, fitness, hof.Members()[i].Organism.Fitness)
+	}
+}
+
+func TestHallOfFame_Update_duplicates(t *testing.T) {
+	hof := NewHallOfFame(3)
+	org := buildTestOrganism(1, 1.0)
+	added, err := hof.Update(1, []*Organism{org})
+	require.NoError(t, err)
+	assert.Equal(t, 1, added)
+
+	// the clone with different ID and better fitness only updates the member
+	clone, err := org.Genotype.duplicate(2)
+	require.NoError(t, err)
+	cloneOrg, err := NewOrganism(2.0, clone, 2)
+	require.NoError(t, err)
+	added, err = hof.Update(2, []*Organism{cloneOrg, org})
+	require.NoError(t, err)
+	assert.Zero(t, added)
+	require.Equal(t, 1, hof.Len())
+	member := hof.Members()[0]
+	assert.Equal(t, 2.0, member.Organism.Fitness)
+	assert.Equal(t, 2, member.Generation)
+}
+
+func TestHallOfFame_Update_zeroCapacity(t *testing.T) {
+	hof := NewHallOfFame(0)
+	added, err := hof.Update(1, []*Organism{buildTestOrganism(1, 1.0)})
+	require.NoError(t, err)
+	assert.Zero(t, added)
+	assert.Zero(t, hof.Len())
+}
+
+func TestHallOfFame_Encode_Decode(t *testing.T) {
+	hof := NewHallOfFame(3)
+	organisms := []*Organism{buildTestOrganism(1, 1.0), buildTestOrganism(2, 2.0)}
+	_, err := hof.Update(7, organisms)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	err = hof.Encode(gob.NewEncoder(&buf))
+	require.NoError(t, err)
+
+	decoded := NewHallOfFame(0)
+	err = decoded.Decode(gob.NewDecoder(&buf))
+	require.NoError(t, err)
+	assert.Equal(t, hof.Capacity, decoded.Capacity)
+	require.Equal(t, hof.Len(), decoded.Len())
+	for i, member := range hof.Members() {
+		decodedMember := decoded.Members()[i]
+		assert.Equal(t, member.Hash, decodedMember.Hash)
+		assert.Equal(t, member.Generation, decodedMember.Generation)
+		assert.Equal(t, member.Organism.Fitness, decodedMember.Organism.Fitness)
+	}
+}
+
+// buildTestOrganism is to create organism with the test genome which weights are distinct for different IDs
+func buildTestOrganism(id int, fitness float64) *Organism {
+	genome := buildTestGenome(id)
+	genome.Genes[0].Link.ConnectionWeight = float64(id)
+	return &Organism{Fitness: fitness, Genotype: genome, Generation: 1}
+}
@@ -1,5 +1,12 @@
 package genetics
 
+import (
+	"deepneat/neat/math"
+	"deepneat/neat/network"
+	"fmt"
+	"sort"
+)
+
 // InnovationsObserver the definition of component able to manage records of innovations
 type InnovationsObserver interface {
 	// StoreInnovation is to store specific innovation
@@ -39,7 +46,18 @@ type Innovation struct {
 	// Flag to indicate whether its innovation for recurrent link
 	IsRecurrent bool
 
-	// Either NEWNODE or NEWLINK
+	// If a new module was created, these are IDs of the nodes connected to its inputs. The node connected to its output
+	// is OutNodeId.
+	ModuleInNodeIds []int
+	// If a new module was created, this is the activation type of its control node
+	ModuleActivation math.NodeActivationType
+	// If a new module was created, these are IDs of its control node followed by IDs of its input and output nodes
+	ModuleNodeIds []int
+	// If a new module was created, these are innovation numbers of its control gene followed by innovation numbers of
+	// genes connecting its input and output nodes
+	ModuleInnovNums []int64
+
+	// Either NEWNODE, NEWLINK or NEWMODULE
 	innovationType innovationType
 }
 
@@ -80,3 +98,233 @@ func NewInnovationForRecurrentLink(nodeInId, nodeOutId int, innovationNum int64,
 		IsRecurrent:    recur,
 	}
 }
+
+// NewInnovationForModule is a constructor for new module case
+func NewInnovationForModule(inNodeIds []int, outNodeId int, activation math.NodeActivationType, nodeIds []int, innovationNums []int64) *Innovation {
+	return &Innovation{
+		innovationType:   newModuleInnType,
+		OutNodeId:        outNodeId,
+		ModuleInNodeIds:  inNodeIds,
+		ModuleActivation: activation,
+		ModuleNodeIds:    nodeIds,
+		ModuleInnovNums:  innovationNums,
+	}
+}
+
+// isSameModule is to check whether this innovation is a new module connecting the given nodes with given activation
+func (i *Innovation) isSameModule(inNodeIds []int, outNodeId int, activation math.NodeActivationType) bool {
+	if i.innovationType != newModuleInnType || i.OutNodeId != outNodeId || i.ModuleActivation != activation ||
+		len(i.ModuleInNodeIds) != len(inNodeIds) {
+		return false
+	}
+	for j, id := range inNodeIds {
+		if i.ModuleInNodeIds[j] != id {
+			return false
+		}
+	}
+	return true
+}
+
+// innovationsTracker is the component able to manage records of innovations and to generate IDs of new nodes, i.e.,
+// everything needed by the structural mutations of the genome
+type innovationsTracker interface {
+	InnovationsObserver
+	network.NodeIdGenerator
+}
+
+// localInnovations is the innovations tracker used by species during the parallel reproduction cycle. It assigns
+// provisional innovation numbers and node IDs, which are replaced by the global ones after reproduction of all species
+// is complete. The global numbers are assigned in the order of species, thus numbering doesn't depend on the order in
+// which goroutines were scheduled.
+type localInnovations struct {
+	// The innovations known before reproduction cycle started
+	known []Innovation
+	// The innovations created by this tracker in order of creation
+	created []Innovation
+	// The last assigned provisional innovation number and node ID
+	lastInnovNum int64
+	lastNodeId   int
+	// All innovation numbers and node IDs greater than these are provisional
+	baseInnovNum int64
+	baseNodeId   int
+}
+
+// newLocalInnovations creates local innovations tracker which assigns provisional numbers starting after the last
+// innovation number and node ID assigned by the population
+func newLocalInnovations(pop *Population) *localInnovations {
+	innovNum, nodeId := pop.nextInnovNum, int(pop.nextNodeId)
+	return &localInnovations{
+		known:        append([]Innovation(nil), pop.innovations...),
+		lastInnovNum: innovNum,
+		lastNodeId:   nodeId,
+		baseInnovNum: innovNum,
+		baseNodeId:   nodeId,
+	}
+}
+
+func (l *localInnovations) StoreInnovation(innovation Innovation) {
+	l.created = append(l.created, innovation)
+}
+
+func (l *localInnovations) Innovations() []Innovation {
+	return append(l.known[:len(l.known):len(l.known)], l.created...)
+}
+
+func (l *localInnovations) NextInnovationNumber() int64 {
+	l.lastInnovNum++
+	return l.lastInnovNum
+}
+
+func (l *localInnovations) NextNodeId() int {
+	l.lastNodeId++
+	return l.lastNodeId
+}
+
+// commit is to register innovations created by this tracker in the population and to replace provisional innovation
+// numbers and node IDs in the genomes of given organisms by the global ones. The innovation which was already
+// registered in population, e.g. by other species, gets the same numbers as the registered one.
+func (l *localInnovations) commit(pop *Population, organisms []*Organism) error {
+	innovMap := make(map[int64]int64)
+	nodeMap := make(map[int]int)
+	mapNode := func(id int) int {
+		if id > l.baseNodeId {
+			return nodeMap[id]
+		}
+		return id
+	}
+	mapInnov := func(num int64) int64 {
+		if num > l.baseInnovNum {
+			return innovMap[num]
+		}
+		return num
+	}
+	for _, inn := range l.created {
+		inNodeId, outNodeId := mapNode(inn.InNodeId), mapNode(inn.OutNodeId)
+		found := false
+		switch inn.innovationType {
+		case newNodeInnType:
+			oldInnovNum := mapInnov(inn.OldInnovNum)
+			for _, pInn := range pop.innovations {
+				if pInn.innovationType == newNodeInnType && pInn.InNodeId == inNodeId &&
+					pInn.OutNodeId == outNodeId && pInn.OldInnovNum == oldInnovNum {
+					nodeMap[inn.NewNodeId] = pInn.NewNodeId
+					innovMap[inn.InnovationNum] = pInn.InnovationNum
+					innovMap[inn.InnovationNum2] = pInn.InnovationNum2
+					found = true
+					break
+				}
+			}
+			if !found {
+				nodeId := pop.NextNodeId()
+				innovNum1, innovNum2 := pop.NextInnovationNumber(), pop.NextInnovationNumber()
+				nodeMap[inn.NewNodeId] = nodeId
+				innovMap[inn.InnovationNum] = innovNum1
+				innovMap[inn.InnovationNum2] = innovNum2
+				pop.StoreInnovation(*NewInnovationForNode(inNodeId, outNodeId, innovNum1, innovNum2, nodeId, oldInnovNum))
+			}
+		case newLinkInnType:
+			for _, pInn := range pop.innovations {
+				if pInn.innovationType == newLinkInnType && pInn.InNodeId == inNodeId &&
+					pInn.OutNodeId == outNodeId && pInn.IsRecurrent == inn.IsRecurrent {
+					innovMap[inn.InnovationNum] = pInn.InnovationNum
+					found = true
+					break
+				}
+			}
+			if !found {
+				innovNum := pop.NextInnovationNumber()
+				innovMap[inn.InnovationNum] = innovNum
+				pop.StoreInnovation(*NewInnovationForRecurrentLink(inNodeId, outNodeId, innovNum, inn.NewWeight,
+					inn.NewTraitNum, inn.IsRecurrent))
+			}
+		case newModuleInnType:
+			inNodeIds := make([]int, len(inn.ModuleInNodeIds))
+			for i, id := range inn.ModuleInNodeIds {
+				inNodeIds[i] = mapNode(id)
+			}
+			for _, pInn := range pop.innovations {
+				if pInn.isSameModule(inNodeIds, outNodeId, inn.ModuleActivation) {
+					for i, id := range inn.ModuleNodeIds {
+						nodeMap[id] = pInn.ModuleNodeIds[i]
+					}
+					for i, num := range inn.ModuleInnovNums {
+						innovMap[num] = pInn.ModuleInnovNums[i]
+					}
+					found = true
+					break
+				}
+			}
+			if !found {
+				nodeIds := make([]int, len(inn.ModuleNodeIds))
+				for i, id := range inn.ModuleNodeIds {
+					nodeIds[i] = pop.NextNodeId()
+					nodeMap[id] = nodeIds[i]
+				}
+				innovNums := make([]int64, len(inn.ModuleInnovNums))
+				for i, num := range inn.ModuleInnovNums {
+					innovNums[i] = pop.NextInnovationNumber()
+					innovMap[num] = innovNums[i]
+				}
+				pop.StoreInnovation(*NewInnovationForModule(inNodeIds, outNodeId, inn.ModuleActivation, nodeIds, innovNums))
+			}
+		}
+	}
+
+	// update genomes of organisms
+	for _, org := range organisms {
+		g := org.Genotype
+		for _, node := range g.Nodes {
+			if node.Id > l.baseNodeId {
+				id, ok := nodeMap[node.Id]
+				if !ok {
+					return fmt.Errorf("no global ID found for provisional node ID: %d in genome: %d", node.Id, g.Id)
+				}
+				node.Id = id
+			}
+		}
+		for _, gene := range g.Genes {
+			if gene.InnovationNum > l.baseInnovNum {
+				num, ok := innovMap[gene.InnovationNum]
+				if !ok {
+					return fmt.Errorf("no global innovation number found for provisional one: %d in genome: %d",
+						gene.InnovationNum, g.Id)
+				}
+				gene.InnovationNum = num
+			}
+		}
+		for _, cg := range g.ControlGenes {
+			if cg.ControlNode.Id > l.baseNodeId {
+				id, ok := nodeMap[cg.ControlNode.Id]
+				if !ok {
+					return fmt.Errorf("no global ID found for provisional control node ID: %d in genome: %d",
+						cg.ControlNode.Id, g.Id)
+				}
+				cg.ControlNode.Id = id
+			}
+			if cg.InnovationNum > l.baseInnovNum {
+				num, ok := innovMap[cg.InnovationNum]
+				if !ok {
+					return fmt.Errorf("no global innovation number found for provisional one: %d of control gene in genome: %d",
+						cg.InnovationNum, g.Id)
+				}
+				cg.InnovationNum = num
+			}
+		}
+		// restore order of nodes and genes
+		sort.SliceStable(g.Nodes, func(i, j int) bool {
+			return g.Nodes[i].Id < g.Nodes[j].Id
+		})
+		sort.SliceStable(g.Genes, func(i, j int) bool {
+			return g.Genes[i].InnovationNum < g.Genes[j].InnovationNum
+		})
+		sortControlGenes(g.ControlGenes)
+		g.nodeByIdMap = make(map[int]*network.NNode, len(g.Nodes))
+		for _, node := range g.Nodes {
+			g.mapNodeId(node)
+		}
+		if err := org.UpdatePhenotype(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
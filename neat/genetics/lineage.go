@@ -0,0 +1,275 @@
+package genetics
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// MatingMethod defines the method of mating which produced the offspring from two parents
+type MatingMethod string
+
+const (
+	// MatingMultipoint the genes are chosen randomly from either parent
+	MatingMultipoint MatingMethod = "multipoint"
+	// MatingMultipointAvg the weights of matching genes are averaged between parents
+	MatingMultipointAvg MatingMethod = "multipoint-avg"
+	// MatingSinglepoint the genes are taken from one parent up to the crossover point and from another after it
+	MatingSinglepoint MatingMethod = "singlepoint"
+)
+
+// MutationType defines the type of mutation applied to the genome of offspring
+type MutationType string
+
+// The types of mutations recorded in the lineage
+const (
+	MutationAddNode         MutationType = "add_node"
+	MutationAddLink         MutationType = "add_link"
+	MutationConnectSensors  MutationType = "connect_sensors"
+	MutationDeleteNode      MutationType = "delete_node"
+	MutationDeleteLink      MutationType = "delete_link"
+	MutationAddModule       MutationType = "add_module"
+	MutationRemoveModule    MutationType = "remove_module"
+	MutationRewireModule    MutationType = "rewire_module"
+	MutationRandomTrait     MutationType = "random_trait"
+	MutationLinkTrait       MutationType = "link_trait"
+	MutationNodeTrait       MutationType = "node_trait"
+	MutationLinkWeights     MutationType = "link_weights"
+	MutationToggleEnable    MutationType = "toggle_enable"
+	MutationGeneReenable    MutationType = "gene_reenable"
+	MutationNodeBias        MutationType = "node_bias"
+	MutationNodeResponse    MutationType = "node_response"
+	MutationNodeAggregation MutationType = "node_aggregation"
+	MutationNodeActivation  MutationType = "node_activation"
+)
+
+// LineageKey identifies the organism in the lineage. The genome IDs are only unique within the population evaluated
+// in particular generation, thus the key includes both.
+type LineageKey struct {
+	// The generation in which organism was evaluated
+	Generation int `json:"generation"`
+	// The ID of the organism's genome in that generation
+	GenomeId int `json:"genome_id"`
+}
+
+func (k LineageKey) String() string {
+	return fmt.Sprintf("g%d_%d", k.Generation, k.GenomeId)
+}
+
+// LineageNode is the record of one organism in the lineage
+type LineageNode struct {
+	LineageKey
+	// The generation in which organism was born
+	BirthGeneration int `json:"birth_generation"`
+	// The ID of the species organism belonged to
+	SpeciesId int `json:"species_id"`
+	// The fitness of the organism
+	Fitness float64 `json:"fitness"`
+	// The IDs of parent genomes in the previous generation: the mother first and the father second if mated
+	ParentIds []int `json:"parent_ids,omitempty"`
+	// The mating method which produced the organism if any
+	MatingMethod MatingMethod `json:"mating_method,omitempty"`
+	// The mutations applied to the genome of the organism
+	Mutations []MutationType `json:"mutations,omitempty"`
+}
+
+// newLineageNode is to create the lineage record of the organism evaluated in given generation
+func newLineageNode(generation int, org *Organism) *LineageNode {
+	node := &LineageNode{
+		LineageKey:      LineageKey{Generation: generation, GenomeId: org.Genotype.Id},
+		BirthGeneration: org.Generation,
+		Fitness:         org.Fitness,
+		ParentIds:       append([]int(nil), org.ParentIds...),
+		MatingMethod:    org.MatingMethod,
+		Mutations:       append([]MutationType(nil), org.Mutations...),
+	}
+	if org.Species != nil {
+		node.SpeciesId = org.Species.Id
+	}
+	return node
+}
+
+// LineageEdge is the link from the parent to the offspring in the lineage graph
+type LineageEdge struct {
+	Parent LineageKey `json:"parent"`
+	Child  LineageKey `json:"child"`
+}
+
+// LineageGraph is the ancestry of the organism as directed acyclic graph
+type LineageGraph struct {
+	// The key of the organism which ancestry is represented
+	Root LineageKey `json:"root"`
+	// The organism and its ancestors sorted by generation and genome ID
+	Nodes []*LineageNode `json:"nodes"`
+	// The links from parents to offspring
+	Edges []LineageEdge `json:"edges"`
+}
+
+// WriteJSON is to write this graph in JSON format
+func (g *LineageGraph) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
+
+// WriteDOT is to write this graph in DOT format of the GraphViz
+func (g *LineageGraph) WriteDOT(w io.Writer) error {
+	b := &strings.Builder{}
+	_, _ = fmt.Fprintf(b, "digraph lineage_%s {\n", g.Root)
+	_, _ = fmt.Fprintln(b, "\trankdir=TB;")
+	_, _ = fmt.Fprintln(b, "\tnode [shape=box];")
+	for _, node := range g.Nodes {
+		label := fmt.Sprintf("generation: %d, genome: %d\\nspecies: %d, fitness: %.4f",
+			node.Generation, node.GenomeId, node.SpeciesId, node.Fitness)
+		if len(node.MatingMethod) > 0 {
+			label += fmt.Sprintf("\\n%s", node.MatingMethod)
+		}
+		if len(node.Mutations) > 0 {
+			mutations := make([]string, len(node.Mutations))
+			for i, m := range node.Mutations {
+				mutations[i] = string(m)
+			}
+			label += fmt.Sprintf("\\n%s", strings.Join(mutations, ", "))
+		}
+		style := ""
+		if node.LineageKey == g.Root {
+			style = ", style=bold"
+		}
+		_, _ = fmt.Fprintf(b, "\t%s [label=\"%s\"%s];\n", node.LineageKey, label, style)
+	}
+	for _, edge := range g.Edges {
+		_, _ = fmt.Fprintf(b, "\t%s -> %s;\n", edge.Parent, edge.Child)
+	}
+	_, _ = fmt.Fprintln(b, "}")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Lineage is the store of organisms' origins across generations of the population. It is safe for concurrent use.
+type Lineage struct {
+	// The recorded organisms by their keys
+	nodes map[LineageKey]*LineageNode
+
+	mutex sync.RWMutex
+}
+
+// NewLineage is to create new empty lineage
+func NewLineage() *Lineage {
+	return &Lineage{nodes: make(map[LineageKey]*LineageNode)}
+}
+
+// Record is to record the origins and fitness of the organisms evaluated in given generation
+func (l *Lineage) Record(generation int, organisms []*Organism) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for _, org := range organisms {
+		node := newLineageNode(generation, org)
+		l.nodes[node.LineageKey] = node
+	}
+}
+
+// Node is to get the record of the organism with given genome ID evaluated in given generation
+func (l *Lineage) Node(generation, genomeId int) (*LineageNode, bool) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	node, ok := l.nodes[LineageKey{Generation: generation, GenomeId: genomeId}]
+	return node, ok
+}
+
+// Len is to get the number of recorded organisms
+func (l *Lineage) Len() int {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return len(l.nodes)
+}
+
+// Ancestry is to build the ancestry graph of the organism evaluated in given generation. The organism doesn't need
+// to be recorded yet, e.g., it can be the winner of the last generation. The ancestors are traced back until the
+// initial population or the earliest recorded generation.
+func (l *Lineage) Ancestry(generation int, organism *Organism) *LineageGraph {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	root := newLineageNode(generation, organism)
+	graph := &LineageGraph{Root: root.LineageKey}
+	visited := map[LineageKey]*LineageNode{root.LineageKey: root}
+	queue := []*LineageNode{root}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, parentId := range node.ParentIds {
+			parentKey := LineageKey{Generation: node.Generation - 1, GenomeId: parentId}
+			parent, ok := l.nodes[parentKey]
+			if !ok {
+				// not recorded
+				continue
+			}
+			if _, seen := visited[parentKey]; !seen {
+				visited[parentKey] = parent
+				queue = append(queue, parent)
+			}
+			edge := LineageEdge{Parent: parentKey, Child: node.LineageKey}
+			if len(graph.Edges) == 0 || graph.Edges[len(graph.Edges)-1] != edge {
+				// the same parent twice when organism mated with itself
+				graph.Edges = append(graph.Edges, edge)
+			}
+		}
+	}
+
+	graph.Nodes = make([]*LineageNode, 0, len(visited))
+	for _, node := range visited {
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		return lessLineageKey(graph.Nodes[i].LineageKey, graph.Nodes[j].LineageKey)
+	})
+	sort.SliceStable(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].Child != graph.Edges[j].Child {
+			return lessLineageKey(graph.Edges[i].Child, graph.Edges[j].Child)
+		}
+		return lessLineageKey(graph.Edges[i].Parent, graph.Edges[j].Parent)
+	})
+	return graph
+}
+
+func lessLineageKey(a, b LineageKey) bool {
+	if a.Generation != b.Generation {
+		return a.Generation < b.Generation
+	}
+	return a.GenomeId < b.GenomeId
+}
+
+// Encode is to encode the records of this lineage with provided GOB encoder
+func (l *Lineage) Encode(enc *gob.Encoder) error {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	nodes := make([]*LineageNode, 0, len(l.nodes))
+	for _, node := range l.nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return lessLineageKey(nodes[i].LineageKey, nodes[j].LineageKey)
+	})
+	return enc.Encode(nodes)
+}
+
+// Decode is to decode the records of this lineage with provided GOB decoder
+func (l *Lineage) Decode(dec *gob.Decoder) error {
+	var nodes []*LineageNode
+	if err := dec.Decode(&nodes); err != nil {
+		return errors.Wrap(err, "failed to decode lineage records")
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.nodes = make(map[LineageKey]*LineageNode, len(nodes))
+	for _, node := range nodes {
+		l.nodes[node.LineageKey] = node
+	}
+	return nil
+}
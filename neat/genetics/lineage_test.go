@@ -0,0 +1,127 @@
+package genetics
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildTestLineage is to build lineage of three generations and the organism of the fourth generation. The organism
+// is the child of mated organisms 0 and 1 of the third generation. Both of them are the children of the organism 1 of
+// the second generation: the organism 0 is its mutated clone and the organism 1 is its child mated with itself.
+func buildTestLineage(t *testing.T) (*Lineage, *Organism) {
+	newOrg := func(id int, fitness float64, birth int, parents []int, mating MatingMethod, mutations ...MutationType) *Organism {
+		org, err := NewOrganism(fitness, buildTestGenome(id), birth)
+		require.NoError(t, err)
+		org.ParentIds = parents
+		org.MatingMethod = mating
+		org.Mutations = mutations
+		return org
+	}
+	lineage := NewLineage()
+	lineage.Record(1, []*Organism{
+		newOrg(0, 1.0, 1, nil, ""),
+		newOrg(1, 2.0, 1, nil, ""),
+	})
+	lineage.Record(2, []*Organism{
+		newOrg(0, 1.5, 1, []int{0}, ""),
+		newOrg(1, 2.5, 1, []int{1, 0}, MatingMultipoint, MutationAddNode),
+	})
+	lineage.Record(3, []*Organism{
+		newOrg(0, 3.0, 2, []int{1}, "", MutationLinkWeights, MutationNodeBias),
+		newOrg(1, 3.5, 2, []int{1, 1}, MatingSinglepoint),
+		newOrg(2, 0.5, 2, []int{0}, ""),
+	})
+	champion := newOrg(7, 4.0, 3, []int{0, 1}, MatingMultipointAvg, MutationAddLink)
+	return lineage, champion
+}
+
+func TestLineage_Ancestry(t *testing.T) {
+	lineage, champion := buildTestLineage(t)
+	assert.Equal(t, 7, lineage.Len())
+
+	graph := lineage.Ancestry(4, champion)
+	assert.Equal(t, LineageKey{Generation: 4, GenomeId: 7}, graph.Root)
+
+	keys := make([]LineageKey, len(graph.Nodes))
+	for i, node := range graph.Nodes {
+		keys[i] = node.LineageKey
+	}
+	expected := []LineageKey{{1, 0}, {1, 1}, {2, 1}, {3, 0}, {3, 1}, {4, 7}}
+	assert.Equal(t, expected, keys, "only ancestors expected")
+
+	expectedEdges := []LineageEdge{
+		{Parent: LineageKey{1, 0}, Child: LineageKey{2, 1}},
+		{Parent: LineageKey{1, 1}, Child: LineageKey{2, 1}},
+		{Parent: LineageKey{2, 1}, Child: LineageKey{3, 0}},
+		{Parent: LineageKey{2, 1}, Child: LineageKey{3, 1}},
+		{Parent: LineageKey{3, 0}, Child: LineageKey{4, 7}},
+		{Parent: LineageKey{3, 1}, Child: LineageKey{4, 7}},
+	}
+	assert.Equal(t, expectedEdges, graph.Edges)
+
+	root := graph.Nodes[len(graph.Nodes)-1]
+	assert.Equal(t, 3, root.BirthGeneration)
+	assert.Equal(t, 4.0, root.Fitness)
+	assert.Equal(t, MatingMultipointAvg, root.MatingMethod)
+	assert.Equal(t, []MutationType{MutationAddLink}, root.Mutations)
+}
+
+func TestLineage_Ancestry_notRecorded(t *testing.T) {
+	org, err := NewOrganism(1.0, buildTestGenome(1), 1)
+	require.NoError(t, err)
+	org.ParentIds = []int{3}
+
+	graph := NewLineage().Ancestry(5, org)
+	require.Len(t, graph.Nodes, 1)
+	assert.Empty(t, graph.Edges)
+}
+
+func TestLineageGraph_WriteJSON(t *testing.T) {
+	lineage, champion := buildTestLineage(t)
+	graph := lineage.Ancestry(4, champion)
+
+	var buf bytes.Buffer
+	err := graph.WriteJSON(&buf)
+	require.NoError(t, err)
+
+	var decoded LineageGraph
+	err = json.Unmarshal(buf.Bytes(), &decoded)
+	require.NoError(t, err)
+	assert.Equal(t, graph, &decoded)
+	assert.Contains(t, buf.String(), `"mating_method": "multipoint-avg"`)
+}
+
+func TestLineageGraph_WriteDOT(t *testing.T) {
+	lineage, champion := buildTestLineage(t)
+	graph := lineage.Ancestry(4, champion)
+
+	var buf bytes.Buffer
+	err := graph.WriteDOT(&buf)
+	require.NoError(t, err)
+	dot := buf.String()
+	assert.Contains(t, dot, "digraph lineage_g4_7 {")
+	assert.Contains(t, dot, `g4_7 [label="generation: 4, genome: 7\nspecies: 0, fitness: 4.0000\nmultipoint-avg\nadd_link", style=bold];`)
+	assert.Contains(t, dot, `g3_0 [label="generation: 3, genome: 0\nspecies: 0, fitness: 3.0000\nlink_weights, node_bias"];`)
+	assert.Contains(t, dot, "g3_1 -> g4_7;")
+	assert.Contains(t, dot, "g1_1 -> g2_1;")
+	assert.NotContains(t, dot, "g3_2")
+}
+
+func TestLineage_Encode_Decode(t *testing.T) {
+	lineage, champion := buildTestLineage(t)
+
+	var buf bytes.Buffer
+	err := lineage.Encode(gob.NewEncoder(&buf))
+	require.NoError(t, err)
+
+	decoded := NewLineage()
+	err = decoded.Decode(gob.NewDecoder(&buf))
+	require.NoError(t, err)
+	assert.Equal(t, lineage.Len(), decoded.Len())
+	assert.Equal(t, lineage.Ancestry(4, champion), decoded.Ancestry(4, champion))
+}
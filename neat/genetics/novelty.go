@@ -0,0 +1,166 @@
+package genetics
+
+import (
+	"deepneat/neat"
+	"encoding/gob"
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// BehaviorDistanceFunc is to calculate the distance between two behavior characterization vectors
+type BehaviorDistanceFunc func(a, b []float64) float64
+
+// EuclideanBehaviorDistance is the default behavior distance metric which returns the Euclidean distance between
+// provided behavior vectors. If vectors have different length, the missing values are considered to be zero.
+func EuclideanBehaviorDistance(a, b []float64) float64 {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+	sum := 0.0
+	for i := range a {
+		diff := a[i]
+		if i < len(b) {
+			diff -= b[i]
+		}
+		sum += diff * diff
+	}
+	return math.Sqrt(sum)
+}
+
+// NoveltyArchive keeps the behaviors of the most novel organisms found so far during the novelty search. The novelty
+// of the organism is estimated as sparseness of its behavior, i.e., the average distance to the k-nearest neighbors
+// among behaviors of the current population and behaviors stored in the archive.
+type NoveltyArchive struct {
+	// The behaviors stored in the archive, the oldest first
+	Behaviors [][]float64
+	// The distance metric between behaviors
+	Distance BehaviorDistanceFunc
+}
+
+// NewNoveltyArchive creates new empty novelty archive with Euclidean behavior distance metric
+func NewNoveltyArchive() *NoveltyArchive {
+	return &NoveltyArchive{
+		Behaviors: make([][]float64, 0),
+		Distance:  EuclideanBehaviorDistance,
+	}
+}
+
+// Sparseness is to calculate the sparseness of the given behavior as the average distance to its k-nearest neighbors
+// among the provided behaviors of the population and behaviors stored in the archive. The behaviors of the
+// population should not include the one being evaluated.
+func (a *NoveltyArchive) Sparseness(behavior []float64, population [][]float64, k int) float64 {
+	distances := make([]float64, 0, len(population)+len(a.Behaviors))
+	for _, other := range population {
+		distances = append(distances, a.Distance(behavior, other))
+	}
+	for _, other := range a.Behaviors {
+		distances = append(distances, a.Distance(behavior, other))
+	}
+	if len(distances) == 0 {
+		return 0
+	}
+	sort.Float64s(distances)
+	if k > len(distances) {
+		k = len(distances)
+	}
+	sum := 0.0
+	for _, d := range distances[:k] {
+		sum += d
+	}
+	return sum / float64(k)
+}
+
+// EvaluateNovelty is to estimate novelty of the behaviors recorded by organisms during evaluation, to update archive
+// with behaviors which are novel enough, and to replace the fitness of organisms according to the novelty search mode
+// set in the options. The objective fitness is replaced by novelty score in pure novelty mode, or by a weighted sum of
+// both normalized to the population maximum in blend mode.
+func (a *NoveltyArchive) EvaluateNovelty(organisms Organisms, opts *neat.Options) error {
+	if opts.NoveltyKNearest <= 0 {
+		return errors.Errorf("wrong number of nearest neighbors for novelty search: %d", opts.NoveltyKNearest)
+	}
+	behaviors := make([][]float64, len(organisms))
+	for i, org := range organisms {
+		if org.Behavior == nil {
+			return errors.Errorf("no behavior recorded for organism with genome ID: %d", org.Genotype.Id)
+		}
+		behaviors[i] = org.Behavior
+	}
+
+	// estimate novelty against other organisms of the population and the archive
+	others := make([][]float64, 0, len(behaviors))
+	for i, org := range organisms {
+		others = append(others[:0], behaviors[:i]...)
+		others = append(others, behaviors[i+1:]...)
+		org.Novelty = a.Sparseness(org.Behavior, others, opts.NoveltyKNearest)
+	}
+
+	// store novel behaviors in the archive, dropping the oldest ones if archive is full
+	for _, org := range organisms {
+		if org.Novelty > opts.NoveltyArchiveThreshold {
+			a.Behaviors = append(a.Behaviors, append([]float64(nil), org.Behavior...))
+		}
+	}
+	if opts.NoveltyArchiveSize > 0 && len(a.Behaviors) > opts.NoveltyArchiveSize {
+		a.Behaviors = a.Behaviors[len(a.Behaviors)-opts.NoveltyArchiveSize:]
+	}
+
+	// replace fitness
+	switch opts.NoveltySearchMode {
+	case neat.NoveltySearchModeNovelty:
+		for _, org := range organisms {
+			org.Fitness = org.Novelty
+		}
+	case neat.NoveltySearchModeBlend:
+		maxFitness, maxNovelty := 0.0, 0.0
+		for _, org := range organisms {
+			maxFitness = math.Max(maxFitness, org.Fitness)
+			maxNovelty = math.Max(maxNovelty, org.Novelty)
+		}
+		for _, org := range organisms {
+			fitness, novelty := 0.0, 0.0
+			if maxFitness > 0 {
+				fitness = org.Fitness / maxFitness
+			}
+			if maxNovelty > 0 {
+				novelty = org.Novelty / maxNovelty
+			}
+			org.Fitness = (1.0-opts.NoveltyFitnessBlend)*fitness + opts.NoveltyFitnessBlend*novelty
+		}
+	default:
+		return errors.Errorf("unsupported novelty search mode: [%s]", opts.NoveltySearchMode)
+	}
+	return nil
+}
+
+// Encode is to encode the behaviors stored in this archive with provided GOB encoder
+func (a *NoveltyArchive) Encode(enc *gob.Encoder) error {
+	if err := enc.Encode(len(a.Behaviors)); err != nil {
+		return err
+	}
+	for _, behavior := range a.Behaviors {
+		if err := enc.Encode(behavior); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Decode is to decode the behaviors of this archive with provided GOB decoder
+func (a *NoveltyArchive) Decode(dec *gob.Decoder) error {
+	var num int
+	if err := dec.Decode(&num); err != nil {
+		return errors.Wrap(err, "failed to decode number of archived behaviors")
+	}
+	a.Behaviors = make([][]float64, num)
+	for i := 0; i < num; i++ {
+		if err := dec.Decode(&a.Behaviors[i]); err != nil {
+			return errors.Wrap(err, "failed to decode archived behavior")
+		}
+	}
+	if a.Distance == nil {
+		a.Distance = EuclideanBehaviorDistance
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package genetics
+
+import (
+	"deepneat/neat"
+	"deepneat/neat/math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEuclideanBehaviorDistance(t *testing.T) {
+	assert.Equal(t, 5.0, EuclideanBehaviorDistance([]float64{0, 0}, []float64{3, 4}))
+	assert.Equal(t, 5.0, EuclideanBehaviorDistance([]float64{3}, []float64{0, 4}))
+	assert.Equal(t, 0.0, EuclideanBehaviorDistance(nil, nil))
+}
+
+func TestNoveltyArchive_Sparseness(t *testing.T) {
+	archive := NewNoveltyArchive()
+	archive.Behaviors = [][]float64{{10}}
+	population := [][]float64{{1}, {3}, {7}}
+
+	assert.Equal(t, 2.0, archive.Sparseness([]float64{0}, population, 2))
+	// the archived behavior is among nearest neighbors
+	assert.Equal(t, 1.5, archive.Sparseness([]float64{9}, population, 2))
+	// k greater than number of neighbors
+	assert.Equal(t, 5.25, archive.Sparseness([]float64{0}, population, 10))
+	assert.Equal(t, 0.0, NewNoveltyArchive().Sparseness([]float64{0}, nil, 2))
+}
+
+func TestNoveltyArchive_EvaluateNovelty(t *testing.T) {
+	newOrganisms := func() Organisms {
+		return Organisms{
+			{Fitness: 1, Behavior: []float64{0}, Genotype: &Genome{Id: 1}},
+			{Fitness: 2, Behavior: []float64{1}, Genotype: &Genome{Id: 2}},
+			{Fitness: 4, Behavior: []float64{5}, Genotype: &Genome{Id: 3}},
+		}
+	}
+	opts := &neat.Options{
+		NoveltySearchMode:       neat.NoveltySearchModeNovelty,
+		NoveltyKNearest:         1,
+		NoveltyArchiveThreshold: 2,
+		NoveltyArchiveSize:      1,
+	}
+
+	archive := NewNoveltyArchive()
+	organisms := newOrganisms()
+	err := archive.EvaluateNovelty(organisms, opts)
+	require.NoError(t, err, "failed to evaluate novelty")
+	expected := []float64{1, 1, 4}
+	for i, org := range organisms {
+		assert.Equal(t, expected[i], org.Novelty, "wrong novelty at: %d", i)
+		assert.Equal(t, expected[i], org.Fitness, "wrong fitness at: %d", i)
+	}
+	require.Len(t, archive.Behaviors, 1)
+	assert.Equal(t, []float64{5}, archive.Behaviors[0])
+
+	// test blend mode
+	opts.NoveltySearchMode = neat.NoveltySearchModeBlend
+	opts.NoveltyFitnessBlend = 0.5
+	archive = NewNoveltyArchive()
+	organisms = newOrganisms()
+	err = archive.EvaluateNovelty(organisms, opts)
+	require.NoError(t, err, "failed to evaluate novelty")
+	expected = []float64{0.25, 0.375, 1}
+	for i, org := range organisms {
+		assert.Equal(t, expected[i], org.Fitness, "wrong fitness at: %d", i)
+	}
+}
+
+func TestNoveltyArchive_EvaluateNovelty_noBehavior(t *testing.T) {
+	opts := &neat.Options{
+		NoveltySearchMode: neat.NoveltySearchModeNovelty,
+		NoveltyKNearest:   1,
+	}
+	organisms := Organisms{
+		{Fitness: 1, Behavior: []float64{0}, Genotype: &Genome{Id: 1}},
+		{Fitness: 2, Genotype: &Genome{Id: 2}},
+	}
+	err := NewNoveltyArchive().EvaluateNovelty(organisms, opts)
+	assert.Error(t, err)
+}
+
+func TestSequentialPopulationEpochExecutor_NextEpoch_novelty(t *testing.T) {
+	conf := &neat.Options{
+		CompatThreshold:         0.5,
+		DropOffAge:              5,
+		PopSize:                 20,
+		BabiesStolen:            5,
+		NodeActivators:          []math.NodeActivationType{math.GaussianBipolarActivation},
+		NodeActivatorsProb:      []float64{1.0},
+		NoveltySearchMode:       neat.NoveltySearchModeNovelty,
+		NoveltyKNearest:         3,
+		NoveltyArchiveThreshold: 0.5,
+	}
+	pop := runSeededEpochs(t, conf, 42, 5, &SequentialPopulationEpochExecutor{}, func(pop *Population, opts *neat.Options) {
+		for _, org := range pop.Organisms {
+			org.Behavior = []float64{opts.Rand().Float64() * 10}
+		}
+	})
+	require.NotNil(t, pop.NoveltyArchive, "novelty archive expected")
+	assert.True(t, len(pop.NoveltyArchive.Behaviors) > 0, "novel behaviors expected in archive")
+}
@@ -2,8 +2,12 @@ package genetics
 
 import (
 	"bytes"
+	"deepneat/neat"
 	"deepneat/neat/network"
+	"encoding/json"
 	"fmt"
+
+	"github.com/pkg/errors"
 )
 
 // Organisms represents sortable list of organisms by fitness
@@ -25,6 +29,27 @@ type Organism struct {
 	// Win marker (if needed for a particular task)
 	IsWinner bool
 
+	// The fitness samples of repeated evaluations of the organism when the fitness is noisy. The samples of the elite
+	// ancestors are included if the elites are re-evaluated. The Fitness is aggregated from these samples.
+	FitnessSamples []float64
+	// The variance of the fitness samples
+	FitnessVariance float64
+	// The number of generations after the first one in which the fitness samples of this organism or of its elite
+	// ancestors were accumulated. The organism with re-evaluations has validated fitness estimate.
+	Reevaluations int
+
+	// The behavior characterization vector recorded by evaluator, e.g., the final position of the agent. It is used
+	// to estimate novelty of the organism when novelty search is enabled.
+	Behavior []float64
+	// The novelty score of the organism's behavior estimated during the last epoch
+	Novelty float64
+
+	// The objectives values recorded by evaluator for multi-objective selection, e.g., task fitness and negated
+	// network complexity. All objectives are maximized.
+	Objectives []float64
+	// The index of the Pareto front this organism belongs to (zero is the best) estimated during the last epoch
+	ParetoRank int
+
 	// The Organism's genotype
 	Genotype *Genome
 	// The Species of the Organism
@@ -35,6 +60,14 @@ type Organism struct {
 	// Tells which generation this Organism is from
 	Generation int
 
+	// The IDs of the parent genomes in the generation which produced this organism: the mother first and the father
+	// second if organism was produced by mating. It is empty for the organisms of the initial population.
+	ParentIds []int
+	// The method of mating which produced this organism or empty if it was produced from the single parent
+	MatingMethod MatingMethod
+	// The mutations applied to the genome of this organism during reproduction
+	Mutations []MutationType
+
 	// The utility data transfer object to be used by different GA implementations to hold additional data.
 	// Implemented as ANY to allow implementation specific objects.
 	Data *OrganismData
@@ -68,6 +101,15 @@ type Organism struct {
 	Flag int
 }
 
+// organismOrigin is the origin and the fitness samples of organism encoded along with it
+type organismOrigin struct {
+	ParentIds      []int          `json:"parent_ids,omitempty"`
+	MatingMethod   MatingMethod   `json:"mating_method,omitempty"`
+	Mutations      []MutationType `json:"mutations,omitempty"`
+	FitnessSamples []float64      `json:"fitness_samples,omitempty"`
+	Reevaluations  int            `json:"reevaluations,omitempty"`
+}
+
 // NewOrganism Creates new organism with specified genome, fitness and given generation number
 func NewOrganism(fit float64, g *Genome, generation int) (org *Organism, err error) {
 	org = &Organism{
@@ -92,6 +134,20 @@ func (o *Organism) Phenotype() (*network.Network, error) {
 	return o.orgPhenotype, nil
 }
 
+// PhenotypeSolver is to get the network solver to activate the phenotype of this organism. If the plasticity rule is
+// set in provided options, the plastic network solver adapting the weights of phenotype links at runtime is returned,
+// otherwise the phenotype itself.
+func (o *Organism) PhenotypeSolver(opts *neat.Options) (network.Solver, error) {
+	phenotype, err := o.Phenotype()
+	if err != nil {
+		return nil, err
+	}
+	if opts == nil || opts.PlasticityRule == "" {
+		return phenotype, nil
+	}
+	return network.NewPlasticNetworkSolver(phenotype, opts.PlasticityRule, opts.PlasticWeightBound)
+}
+
 // UpdatePhenotype Regenerate the underlying network graph based on a change in the genotype
 func (o *Organism) UpdatePhenotype() (err error) {
 	// First, delete the old phenotype (net)
@@ -102,6 +158,33 @@ func (o *Organism) UpdatePhenotype() (err error) {
 	return err
 }
 
+// FineTune is to tune the link weights of the feed-forward phenotype of this organism by gradient descent over the
+// given supervised samples as configured by the NEAT options. In Lamarckian mode the tuned weights are written back
+// to the genes of genome, thus inherited by offspring. In Baldwinian mode the genome is kept unchanged and only the
+// fitness evaluated with tuned phenotype benefits from tuning. Returns the mean squared error of tuned phenotype.
+// Returns network.ErrNetNotDifferentiable if phenotype can not be tuned by gradient descent.
+func (o *Organism) FineTune(inputs, targets [][]float64, opts *neat.Options) (float64, error) {
+	if !opts.GradientTuningMode.IsEnabled() {
+		return 0, errors.New("gradient tuning is disabled")
+	}
+	phenotype, err := o.Phenotype()
+	if err != nil {
+		return 0, err
+	}
+	optimizer, err := network.NewOptimizer(opts.GradientOptimizer, opts.GradientLearningRate)
+	if err != nil {
+		return 0, err
+	}
+	loss, err := phenotype.FitGradient(inputs, targets, opts.GradientTuningEpochs, optimizer)
+	if err != nil {
+		return 0, err
+	}
+	if opts.GradientTuningMode == neat.GradientTuningLamarckian {
+		o.Genotype.writeBackWeights(phenotype)
+	}
+	return loss, nil
+}
+
 // CheckChampionChildDamaged Method to check if this organism is a child of the champion
 // but has the fitness score less than of the parent. This can be used to check if champion's offsprings degraded.
 func (o *Organism) CheckChampionChildDamaged() bool {
@@ -111,12 +194,49 @@ func (o *Organism) CheckChampionChildDamaged() bool {
 	return false
 }
 
+// AddFitnessSamples is to add the fitness samples of evaluations of this organism in the current generation and to
+// set its fitness aggregated over all accumulated samples with given function. If there are samples accumulated
+// before, i.e., inherited from the elite ancestor, the organism is counted as re-evaluated. It should be invoked once
+// per generation.
+func (o *Organism) AddFitnessSamples(samples []float64, aggregation neat.FitnessAggregation) {
+	if len(samples) == 0 {
+		return
+	}
+	if len(o.FitnessSamples) > 0 {
+		o.Reevaluations++
+	}
+	o.FitnessSamples = append(o.FitnessSamples, samples...)
+	o.Fitness = aggregateFitness(o.FitnessSamples, aggregation)
+	o.FitnessVariance = variance(o.FitnessSamples)
+}
+
+// inheritFitnessSamples is to copy the accumulated fitness samples of the parent, which this organism is the
+// unchanged clone of
+func (o *Organism) inheritFitnessSamples(parent *Organism) {
+	o.FitnessSamples = append([]float64(nil), parent.FitnessSamples...)
+	o.Reevaluations = parent.Reevaluations
+}
+
 // MarshalBinary Encodes this organism for wired transmission during parallel reproduction cycle or parallel simulation
 func (o *Organism) MarshalBinary() ([]byte, error) {
 	var buf bytes.Buffer
 	if _, err := fmt.Fprintln(&buf, o.Fitness, o.Generation, o.highestFitness, o.isPopulationChampionChild, o.Genotype.Id); err != nil {
 		return nil, err
 	}
+	// encode origin as single line
+	origin, err := json.Marshal(organismOrigin{
+		ParentIds:      o.ParentIds,
+		MatingMethod:   o.MatingMethod,
+		Mutations:      o.Mutations,
+		FitnessSamples: o.FitnessSamples,
+		Reevaluations:  o.Reevaluations,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err = fmt.Fprintln(&buf, string(origin)); err != nil {
+		return nil, err
+	}
 	// encode genotype next
 	if err := o.Genotype.Write(&buf); err != nil {
 		return nil, err
@@ -131,6 +251,17 @@ func (o *Organism) UnmarshalBinary(data []byte) (err error) {
 	if _, err = fmt.Fscanln(b, &o.Fitness, &o.Generation, &o.highestFitness, &o.isPopulationChampionChild, &genotypeId); err != nil {
 		return err
 	}
+	// decode origin
+	line, err := b.ReadBytes('\n')
+	if err != nil {
+		return err
+	}
+	var origin organismOrigin
+	if err = json.Unmarshal(line, &origin); err != nil {
+		return err
+	}
+	o.ParentIds, o.MatingMethod, o.Mutations = origin.ParentIds, origin.MatingMethod, origin.Mutations
+	o.FitnessSamples, o.Reevaluations = origin.FitnessSamples, origin.Reevaluations
 	// decode genotype next
 	if o.Genotype, err = ReadGenome(b, genotypeId); err != nil {
 		return err
@@ -162,6 +293,9 @@ func (o *Organism) Dump() string {
 	_, _ = fmt.Fprintln(b, "Genotype: ", o.Genotype)
 	_, _ = fmt.Fprintln(b, "Species: ", o.Species)
 	_, _ = fmt.Fprintln(b, "ExpectedOffspring: ", o.ExpectedOffspring)
+	_, _ = fmt.Fprintln(b, "ParentIds: ", o.ParentIds)
+	_, _ = fmt.Fprintln(b, "MatingMethod: ", o.MatingMethod)
+	_, _ = fmt.Fprintln(b, "Mutations: ", o.Mutations)
 	_, _ = fmt.Fprintln(b, "Data: ", o.Data)
 	_, _ = fmt.Fprintln(b, "Phenotype: ", o.orgPhenotype)
 	_, _ = fmt.Fprintln(b, "originalFitness: ", o.originalFitness)
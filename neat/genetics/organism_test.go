@@ -2,6 +2,8 @@ package genetics
 
 import (
 	"bytes"
+	"deepneat/neat"
+	"deepneat/neat/network"
 	"encoding/gob"
 	"math"
 	"math/rand"
@@ -64,10 +66,34 @@ func TestOrganism_Phenotype(t *testing.T) {
 	assert.True(t, phenotype == other, "must be the same pointer")
 }
 
+func TestOrganism_PhenotypeSolver(t *testing.T) {
+	gnome := buildTestGenome(1)
+	organism, err := NewOrganism(rand.Float64(), gnome, 1)
+	require.NoError(t, err)
+	phenotype, err := organism.Phenotype()
+	require.NoError(t, err)
+
+	// the phenotype itself without plasticity
+	solver, err := organism.PhenotypeSolver(&neat.Options{})
+	require.NoError(t, err)
+	assert.Same(t, phenotype, solver)
+
+	// the plastic solver over phenotype with plasticity rule set
+	solver, err = organism.PhenotypeSolver(&neat.Options{PlasticityRule: neat.PlasticityRuleOja, PlasticWeightBound: 2})
+	require.NoError(t, err)
+	require.IsType(t, &network.PlasticNetworkSolver{}, solver)
+	assert.Equal(t, phenotype.NodeCount(), solver.NodeCount())
+}
+
 func TestOrganism_MarshalBinary(t *testing.T) {
 	gnome := buildTestGenome(1)
 	org, err := NewOrganism(rand.Float64(), gnome, 1)
 	require.NoError(t, err, "failed to create organism")
+	org.ParentIds = []int{3, 5}
+	org.MatingMethod = MatingMultipointAvg
+	org.Mutations = []MutationType{MutationAddNode, MutationLinkWeights}
+	org.FitnessSamples = []float64{0.5, 1.5}
+	org.Reevaluations = 1
 
 	// Marshal to binary
 	var buf bytes.Buffer
@@ -83,6 +109,11 @@ func TestOrganism_MarshalBinary(t *testing.T) {
 
 	// check results
 	assert.Equal(t, org.Fitness, decOrg.Fitness)
+	assert.Equal(t, org.ParentIds, decOrg.ParentIds)
+	assert.Equal(t, org.MatingMethod, decOrg.MatingMethod)
+	assert.Equal(t, org.Mutations, decOrg.Mutations)
+	assert.Equal(t, org.FitnessSamples, decOrg.FitnessSamples)
+	assert.Equal(t, org.Reevaluations, decOrg.Reevaluations)
 
 	decGnome := decOrg.Genotype
 	assert.Equal(t, gnome.Id, decGnome.Id)
@@ -121,3 +152,63 @@ func TestOrganism_UpdatePhenotype(t *testing.T) {
 	require.NoError(t, err, "failed to recreate phenotype")
 	assert.NotNil(t, org.orgPhenotype)
 }
+
+func TestOrganism_AddFitnessSamples(t *testing.T) {
+	org, err := NewOrganism(0, buildTestGenome(1), 1)
+	require.NoError(t, err, "failed to create organism")
+
+	org.AddFitnessSamples(nil, neat.FitnessAggregationMean)
+	assert.Zero(t, org.Fitness)
+	assert.Empty(t, org.FitnessSamples)
+
+	org.AddFitnessSamples([]float64{2, 4, 9}, neat.FitnessAggregationMedian)
+	assert.Equal(t, 4.0, org.Fitness)
+	assert.Equal(t, 13.0, org.FitnessVariance)
+	assert.Zero(t, org.Reevaluations)
+
+	// the clone accumulates samples in the next generation
+	clone, err := NewOrganism(0, buildTestGenome(1), 2)
+	require.NoError(t, err, "failed to create organism")
+	clone.inheritFitnessSamples(org)
+	clone.AddFitnessSamples([]float64{1}, neat.FitnessAggregationWorst)
+	assert.Equal(t, 1.0, clone.Fitness)
+	assert.Equal(t, []float64{2, 4, 9, 1}, clone.FitnessSamples)
+	assert.Equal(t, 1, clone.Reevaluations)
+	assert.Equal(t, []float64{2, 4, 9}, org.FitnessSamples, "parent samples must not change")
+}
+
+func TestOrganism_FineTune(t *testing.T) {
+	inputs := [][]float64{{0, 0}, {0, 1}, {1, 0}, {1, 1}}
+	targets := [][]float64{{0}, {1}, {1}, {1}}
+	for _, mode := range []neat.GradientTuningMode{neat.GradientTuningLamarckian, neat.GradientTuningBaldwinian} {
+		opts := &neat.Options{
+			GradientTuningMode:   mode,
+			GradientTuningEpochs: 100,
+			GradientOptimizer:    neat.GradientOptimizerAdam,
+			GradientLearningRate: 0.1,
+		}
+		org, err := NewOrganism(0, buildTestGenome(1), 1)
+		require.NoError(t, err, mode)
+		loss, err := org.FineTune(inputs, targets, opts)
+		require.NoError(t, err, mode)
+		assert.True(t, loss < 0.05, "%s: loss: %f", mode, loss)
+
+		phenotype, err := org.Phenotype()
+		require.NoError(t, err, mode)
+		tuned := phenotype.Outputs[0].Incoming
+		for i, gene := range org.Genotype.Genes {
+			initial := buildTestGenome(1).Genes[i].Link.ConnectionWeight
+			assert.NotEqual(t, initial, tuned[i].ConnectionWeight, mode)
+			if mode == neat.GradientTuningLamarckian {
+				assert.Equal(t, tuned[i].ConnectionWeight, gene.Link.ConnectionWeight, mode)
+			} else {
+				assert.Equal(t, initial, gene.Link.ConnectionWeight, mode)
+			}
+		}
+	}
+
+	org, err := NewOrganism(0, buildTestGenome(1), 1)
+	require.NoError(t, err)
+	_, err = org.FineTune(inputs, targets, &neat.Options{})
+	assert.Error(t, err, "gradient tuning is disabled")
+}
@@ -0,0 +1,99 @@
+package genetics
+
+import (
+	"deepneat/neat"
+	"deepneat/neat/math"
+	"math/rand"
+
+	"github.com/pkg/errors"
+)
+
+// ParentSelector is to select the parents for reproduction among the organisms of species
+type ParentSelector interface {
+	// Select is to select the parent among given organisms, which are sorted to have the most fit first
+	Select(organisms Organisms, rng *rand.Rand) *Organism
+}
+
+// NewParentSelector is to create the parent selector for the scheme defined by the NEAT options
+func NewParentSelector(opts *neat.Options) (ParentSelector, error) {
+	switch opts.ParentSelection {
+	case "", neat.ParentSelectionTruncation:
+		return truncationSelector{}, nil
+	case neat.ParentSelectionTournament:
+		if opts.TournamentSize <= 0 {
+			return nil, errors.Errorf("tournament size must be positive: %d", opts.TournamentSize)
+		}
+		return tournamentSelector{size: opts.TournamentSize}, nil
+	case neat.ParentSelectionRoulette:
+		return rouletteSelector{}, nil
+	case neat.ParentSelectionRank:
+		return rankSelector{}, nil
+	default:
+		return nil, errors.Errorf("unsupported parent selection scheme: [%s]", opts.ParentSelection)
+	}
+}
+
+// truncationSelector selects uniformly among organisms survived the truncation by fitness
+type truncationSelector struct{}
+
+func (truncationSelector) Select(organisms Organisms, rng *rand.Rand) *Organism {
+	return organisms[rng.Int31n(int32(len(organisms)))]
+}
+
+// tournamentSelector selects the most fit among the given number of randomly chosen organisms
+type tournamentSelector struct {
+	size int
+}
+
+func (t tournamentSelector) Select(organisms Organisms, rng *rand.Rand) *Organism {
+	// the organisms are sorted, thus the one with the lowest index is the most fit
+	best := len(organisms)
+	for i := 0; i < t.size; i++ {
+		if index := rng.Intn(len(organisms)); index < best {
+			best = index
+		}
+	}
+	return organisms[best]
+}
+
+// rouletteSelector selects organisms with probability proportional to their fitness. If any fitness is negative, the
+// fitness values are shifted by the minimal one to get non-negative weights, thus the least fit organism is never
+// selected in this case.
+type rouletteSelector struct{}
+
+func (rouletteSelector) Select(organisms Organisms, rng *rand.Rand) *Organism {
+	fitness := make([]float64, len(organisms))
+	minFitness := 0.0
+	for i, org := range organisms {
+		fitness[i] = org.Fitness
+		minFitness = min(minFitness, org.Fitness)
+	}
+	if minFitness < 0 {
+		for i := range fitness {
+			fitness[i] -= minFitness
+		}
+	}
+	return selectByRoulette(organisms, fitness, rng)
+}
+
+// rankSelector selects organisms with probability proportional to their linear rank, i.e., the most fit of N
+// organisms has weight N and the least fit has weight 1
+type rankSelector struct{}
+
+func (rankSelector) Select(organisms Organisms, rng *rand.Rand) *Organism {
+	ranks := make([]float64, len(organisms))
+	for i := range organisms {
+		ranks[i] = float64(len(organisms) - i)
+	}
+	return selectByRoulette(organisms, ranks, rng)
+}
+
+// selectByRoulette is to select the organism by single throw onto roulette wheel with given weights. If throw fails,
+// e.g., due to all weights are zero, the most fit organism is selected.
+func selectByRoulette(organisms Organisms, weights []float64, rng *rand.Rand) *Organism {
+	index := math.SingleRouletteThrowWith(rng, weights)
+	if index < 0 {
+		return organisms[0]
+	}
+	return organisms[index]
+}
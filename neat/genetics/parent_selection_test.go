@@ -0,0 +1,155 @@
+package genetics
+
+import (
+	"deepneat/neat"
+	"deepneat/neat/math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewParentSelector(t *testing.T) {
+	testCases := []struct {
+		opts     neat.Options
+		expected ParentSelector
+	}{
+		{opts: neat.Options{}, expected: truncationSelector{}},
+		{opts: neat.Options{ParentSelection: neat.ParentSelectionTruncation}, expected: truncationSelector{}},
+		{opts: neat.Options{ParentSelection: neat.ParentSelectionTournament, TournamentSize: 3}, expected: tournamentSelector{size: 3}},
+		{opts: neat.Options{ParentSelection: neat.ParentSelectionRoulette}, expected: rouletteSelector{}},
+		{opts: neat.Options{ParentSelection: neat.ParentSelectionRank}, expected: rankSelector{}},
+	}
+	for _, tc := range testCases {
+		selector, err := NewParentSelector(&tc.opts)
+		require.NoError(t, err, tc.opts.ParentSelection)
+		assert.Equal(t, tc.expected, selector, tc.opts.ParentSelection)
+	}
+
+	_, err := NewParentSelector(&neat.Options{ParentSelection: neat.ParentSelectionTournament})
+	assert.Error(t, err, "tournament size must be set")
+	_, err = NewParentSelector(&neat.Options{ParentSelection: "lottery"})
+	assert.Error(t, err)
+}
+
+func TestParentSelector_Select(t *testing.T) {
+	organisms := buildSortedTestOrganisms(5)
+	testCases := map[string]struct {
+		selector ParentSelector
+		// the expected order of selection counts from the most fit organism
+		decreasing bool
+	}{
+		"truncation": {selector: truncationSelector{}},
+		"tournament": {selector: tournamentSelector{size: 3}, decreasing: true},
+		"roulette":   {selector: rouletteSelector{}, decreasing: true},
+		"rank":       {selector: rankSelector{}, decreasing: true},
+	}
+	trials := 5000
+	for name, tc := range testCases {
+		rng := rand.New(rand.NewSource(42))
+		counts := make(map[*Organism]int)
+		for i := 0; i < trials; i++ {
+			counts[tc.selector.Select(organisms, rng)]++
+		}
+		for i, org := range organisms {
+			assert.True(t, counts[org] > 0, "%s: organism %d never selected", name, i)
+			if i > 0 && tc.decreasing {
+				assert.True(t, counts[organisms[i-1]] > counts[org], "%s: organism %d", name, i)
+			}
+		}
+		if !tc.decreasing {
+			// uniform selection
+			for _, org := range organisms {
+				assert.InDelta(t, trials/len(organisms), counts[org], float64(trials)*0.05, name)
+			}
+		}
+	}
+}
+
+func TestParentSelector_Select_reproducible(t *testing.T) {
+	organisms := buildSortedTestOrganisms(10)
+	for _, selector := range []ParentSelector{truncationSelector{}, tournamentSelector{size: 2}, rouletteSelector{}, rankSelector{}} {
+		rng1, rng2 := rand.New(rand.NewSource(42)), rand.New(rand.NewSource(42))
+		for i := 0; i < 100; i++ {
+			assert.Same(t, selector.Select(organisms, rng1), selector.Select(organisms, rng2))
+		}
+	}
+}
+
+func TestRouletteSelector_Select_zeroFitness(t *testing.T) {
+	organisms := Organisms{{Fitness: 0}, {Fitness: 0}}
+	rng := rand.New(rand.NewSource(42))
+	assert.Same(t, organisms[0], rouletteSelector{}.Select(organisms, rng))
+}
+
+func TestRouletteSelector_Select_negativeFitness(t *testing.T) {
+	organisms := Organisms{{Fitness: 1}, {Fitness: -1}, {Fitness: -3}}
+	rng := rand.New(rand.NewSource(42))
+	counts := make(map[*Organism]int)
+	for i := 0; i < 3000; i++ {
+		counts[rouletteSelector{}.Select(organisms, rng)]++
+	}
+	// the weights are shifted by the minimal fitness to: 4, 2, 0
+	assert.InDelta(t, 2000, counts[organisms[0]], 150)
+	assert.InDelta(t, 1000, counts[organisms[1]], 150)
+	assert.Zero(t, counts[organisms[2]])
+}
+
+func TestSpecies_adjustFitness_tournamentSelection(t *testing.T) {
+	sp, err := buildSpeciesWithOrganisms(1)
+	require.NoError(t, err, "failed to build species")
+	conf := neat.Options{
+		DropOffAge:      5,
+		SurvivalThresh:  0.2,
+		AgeSignificance: 1.0,
+		ParentSelection: neat.ParentSelectionTournament,
+		TournamentSize:  2,
+	}
+	err = sp.adjustFitness(false, &conf)
+	require.NoError(t, err, "failed to adjust fitness")
+	assert.True(t, sp.Organisms[0].isChampion)
+	for _, org := range sp.Organisms {
+		assert.False(t, org.toEliminate, "all organisms must be able to become parents")
+	}
+}
+
+func TestPopulationEpochExecutor_NextEpoch_parentSelection(t *testing.T) {
+	schemes := []neat.ParentSelection{
+		neat.ParentSelectionTruncation, neat.ParentSelectionTournament, neat.ParentSelectionRoulette, neat.ParentSelectionRank,
+	}
+	for _, scheme := range schemes {
+		conf := &neat.Options{
+			CompatThreshold:       3.0,
+			DropOffAge:            20,
+			SurvivalThresh:        0.5,
+			PopSize:               30,
+			MutateOnlyProb:        0.5,
+			MutateAddLinkProb:     0.2,
+			MutateLinkWeightsProb: 0.8,
+			MateMultipointProb:    1.0,
+			ParentSelection:       scheme,
+			TournamentSize:        3,
+			NodeActivators:        []math.NodeActivationType{math.GaussianBipolarActivation},
+			NodeActivatorsProb:    []float64{1.0},
+		}
+		evaluate := func(pop *Population, _ *neat.Options) {
+			for j, org := range pop.Organisms {
+				org.Fitness = float64(j + 1)
+			}
+		}
+		for _, ex := range []PopulationEpochExecutor{&SequentialPopulationEpochExecutor{}, &ParallelPopulationEpochExecutor{}} {
+			pop := runSeededEpochs(t, conf, 42, 5, ex, evaluate)
+			assert.Len(t, pop.Organisms, conf.PopSize, scheme)
+		}
+	}
+}
+
+// buildSortedTestOrganisms is to build organisms with decreasing fitness
+func buildSortedTestOrganisms(count int) Organisms {
+	organisms := make(Organisms, count)
+	for i := range organisms {
+		organisms[i] = &Organism{Fitness: float64(count - i), Genotype: &Genome{Id: i}}
+	}
+	return organisms
+}
@@ -0,0 +1,142 @@
+package genetics
+
+import (
+	"math"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Dominates is to check whether objectives vector a Pareto dominates objectives vector b, i.e., it is not worse
+// in all objectives and strictly better at least in one. All objectives are maximized.
+func Dominates(a, b []float64) bool {
+	better := false
+	for i := range a {
+		if i >= len(b) || a[i] < b[i] {
+			return false
+		}
+		if a[i] > b[i] {
+			better = true
+		}
+	}
+	return better && len(a) == len(b)
+}
+
+// NonDominatedSort is to sort provided organisms into the Pareto fronts by their objectives using the fast
+// non-dominated sorting of NSGA-II. The first front holds organisms which are not dominated by any other. All organisms
+// must have the same number of objectives.
+func NonDominatedSort(organisms Organisms) ([]Organisms, error) {
+	for _, org := range organisms {
+		if len(org.Objectives) == 0 {
+			return nil, errors.Errorf("no objectives recorded for organism: %s", org)
+		}
+		if len(org.Objectives) != len(organisms[0].Objectives) {
+			return nil, errors.Errorf("number of objectives: %d of organism: %s doesn't match expected: %d",
+				len(org.Objectives), org, len(organisms[0].Objectives))
+		}
+	}
+	size := len(organisms)
+	dominated := make([][]int, size)
+	dominationCount := make([]int, size)
+	current := make([]int, 0)
+	for i := 0; i < size; i++ {
+		for j := 0; j < size; j++ {
+			if i == j {
+				continue
+			}
+			if Dominates(organisms[i].Objectives, organisms[j].Objectives) {
+				dominated[i] = append(dominated[i], j)
+			} else if Dominates(organisms[j].Objectives, organisms[i].Objectives) {
+				dominationCount[i]++
+			}
+		}
+		if dominationCount[i] == 0 {
+			current = append(current, i)
+		}
+	}
+
+	fronts := make([]Organisms, 0)
+	for len(current) > 0 {
+		front := make(Organisms, len(current))
+		next := make([]int, 0)
+		for k, i := range current {
+			front[k] = organisms[i]
+			for _, j := range dominated[i] {
+				dominationCount[j]--
+				if dominationCount[j] == 0 {
+					next = append(next, j)
+				}
+			}
+		}
+		fronts = append(fronts, front)
+		current = next
+	}
+	return fronts, nil
+}
+
+// CrowdingDistance is to calculate NSGA-II crowding distance of each organism within the given Pareto front. The
+// organisms at the boundaries of the front get infinite distance.
+func CrowdingDistance(front Organisms) []float64 {
+	size := len(front)
+	distances := make([]float64, size)
+	if size == 0 {
+		return distances
+	}
+	indexes := make([]int, size)
+	for m := range front[0].Objectives {
+		for i := range indexes {
+			indexes[i] = i
+		}
+		sort.SliceStable(indexes, func(i, j int) bool {
+			return front[indexes[i]].Objectives[m] < front[indexes[j]].Objectives[m]
+		})
+		distances[indexes[0]] = math.Inf(1)
+		distances[indexes[size-1]] = math.Inf(1)
+		objRange := front[indexes[size-1]].Objectives[m] - front[indexes[0]].Objectives[m]
+		if objRange == 0 {
+			continue
+		}
+		for i := 1; i < size-1; i++ {
+			distances[indexes[i]] += (front[indexes[i+1]].Objectives[m] - front[indexes[i-1]].Objectives[m]) / objRange
+		}
+	}
+	return distances
+}
+
+// ParetoFront is to find the organisms which are not dominated by any other organism of the given list
+func ParetoFront(organisms Organisms) (Organisms, error) {
+	fronts, err := NonDominatedSort(organisms)
+	if err != nil || len(fronts) == 0 {
+		return nil, err
+	}
+	return fronts[0], nil
+}
+
+// sortByParetoRank is to sort provided organisms as NSGA-II selection would do, i.e., the organisms of the better
+// Pareto front go first, and within the same front the less crowded organisms go first. The ties are broken by the
+// fitness. The fitness of organisms is not changed, and the Pareto front rank of each organism is stored.
+func sortByParetoRank(organisms Organisms) error {
+	fronts, err := NonDominatedSort(organisms)
+	if err != nil {
+		return err
+	}
+	crowding := make(map[*Organism]float64, len(organisms))
+	for rank, front := range fronts {
+		distances := CrowdingDistance(front)
+		for i, org := range front {
+			org.ParetoRank = rank
+			crowding[org] = distances[i]
+		}
+	}
+	sort.SliceStable(organisms, func(i, j int) bool {
+		left, right := organisms[i], organisms[j]
+		if left.ParetoRank != right.ParetoRank {
+			return left.ParetoRank < right.ParetoRank
+		}
+		if crowding[left] != crowding[right] {
+			return crowding[left] > crowding[right]
+		}
+		return left.Fitness > right.Fitness
+	})
+	return nil
+}
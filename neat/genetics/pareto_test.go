@@ -0,0 +1,111 @@
+package genetics
+
+import (
+	"deepneat/neat"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDominates(t *testing.T) {
+	assert.True(t, Dominates([]float64{2, 2}, []float64{1, 2}))
+	assert.False(t, Dominates([]float64{1, 2}, []float64{1, 2}))
+	assert.False(t, Dominates([]float64{3, 1}, []float64{1, 2}))
+	assert.False(t, Dominates([]float64{1, 2}, []float64{2, 2}))
+	assert.False(t, Dominates([]float64{2, 2}, []float64{1}))
+}
+
+func TestNonDominatedSort(t *testing.T) {
+	organisms := buildOrganismsWithObjectives([][]float64{{1, 1}, {3, 1}, {2, 2}, {1, 3}, {0, 0}, {2, 1}})
+	fronts, err := NonDominatedSort(organisms)
+	require.NoError(t, err, "failed to sort")
+	require.Len(t, fronts, 4)
+	assert.ElementsMatch(t, Organisms{organisms[1], organisms[2], organisms[3]}, fronts[0])
+	assert.ElementsMatch(t, Organisms{organisms[5]}, fronts[1])
+	assert.ElementsMatch(t, Organisms{organisms[0]}, fronts[2])
+	assert.ElementsMatch(t, Organisms{organisms[4]}, fronts[3])
+
+	front, err := ParetoFront(organisms)
+	require.NoError(t, err, "failed to find Pareto front")
+	assert.Equal(t, fronts[0], front)
+}
+
+func TestNonDominatedSort_noObjectives(t *testing.T) {
+	organisms := buildOrganismsWithObjectives([][]float64{{1, 1}, nil})
+	_, err := NonDominatedSort(organisms)
+	assert.Error(t, err)
+}
+
+func TestCrowdingDistance(t *testing.T) {
+	front := buildOrganismsWithObjectives([][]float64{{0, 4}, {1, 3}, {3, 1}, {4, 0}})
+	distances := CrowdingDistance(front)
+	require.Len(t, distances, 4)
+	assert.True(t, math.IsInf(distances[0], 1))
+	assert.True(t, math.IsInf(distances[3], 1))
+	assert.InDelta(t, 1.5, distances[1], 1e-9)
+	assert.InDelta(t, 1.5, distances[2], 1e-9)
+}
+
+func TestNonDominatedSort_objectivesMismatch(t *testing.T) {
+	organisms := buildOrganismsWithObjectives([][]float64{{1, 1}, {2}})
+	_, err := NonDominatedSort(organisms)
+	assert.Error(t, err)
+}
+
+func Test_sortByParetoRank(t *testing.T) {
+	organisms := buildOrganismsWithObjectives([][]float64{{0, 0}, {1, 1}, {0, 2}, {2, 0}, {1.5, 1.5}})
+	for i, org := range organisms {
+		org.Fitness = float64(i)
+	}
+	sorted := make(Organisms, len(organisms))
+	copy(sorted, organisms)
+	err := sortByParetoRank(sorted)
+	require.NoError(t, err, "failed to sort by Pareto rank")
+
+	expectedRanks := []int{2, 1, 0, 0, 0}
+	for i, org := range organisms {
+		assert.Equal(t, expectedRanks[i], org.ParetoRank, "wrong rank at: %d", i)
+		assert.Equal(t, float64(i), org.Fitness, "fitness must not change at: %d", i)
+	}
+	// the boundary organisms of the first front are less crowded and ties are broken by fitness
+	expected := Organisms{organisms[3], organisms[2], organisms[4], organisms[1], organisms[0]}
+	assert.Equal(t, expected, sorted)
+}
+
+func TestSpecies_adjustFitness_paretoSelection(t *testing.T) {
+	sp := NewSpecies(1)
+	organisms := buildOrganismsWithObjectives([][]float64{{0, 0}, {1, 1}, {0, 3}, {3, 0}, {2, 2}})
+	for i, org := range organisms {
+		// the fitness is opposite to the Pareto rank
+		org.Fitness = float64(len(organisms) - i)
+		sp.addOrganism(org)
+	}
+	conf := neat.Options{
+		DropOffAge:      5,
+		SurvivalThresh:  0.5,
+		AgeSignificance: 1.0,
+		ParetoSelection: true,
+	}
+	stagnated := NewDefaultStagnation().StagnatedSpecies([]*Species{sp}, &conf)
+	err := sp.adjustFitness(stagnated[sp.Id], &conf)
+	require.NoError(t, err, "failed to adjust fitness")
+
+	// the organisms are ordered by Pareto rank, but the species record keeps the highest task fitness
+	expected := Organisms{organisms[2], organisms[3], organisms[4], organisms[1], organisms[0]}
+	assert.Equal(t, expected, sp.Organisms)
+	assert.True(t, organisms[2].isChampion)
+	assert.Equal(t, 5.0, sp.MaxFitnessEver)
+	assert.Equal(t, 3.0, organisms[2].originalFitness)
+	assert.True(t, organisms[1].toEliminate)
+	assert.True(t, organisms[0].toEliminate)
+}
+
+func buildOrganismsWithObjectives(objectives [][]float64) Organisms {
+	organisms := make(Organisms, len(objectives))
+	for i, obj := range objectives {
+		organisms[i] = &Organism{Objectives: obj, Genotype: &Genome{Id: i}}
+	}
+	return organisms
+}
@@ -5,13 +5,24 @@ import (
 	"deepneat/neat"
 	"fmt"
 	"math"
-	"math/rand"
+	"sort"
 	"sync"
 	"sync/atomic"
 
 	"github.com/pkg/errors"
 )
 
+// SearchPhase defines the phase of the phased search, in which either complexifying or simplifying structural
+// mutations are applied to the genomes
+type SearchPhase int
+
+const (
+	// ComplexifyPhase is the phase in which genomes grow by adding new nodes and links
+	ComplexifyPhase SearchPhase = iota
+	// SimplifyPhase is the phase in which genomes are pruned by deleting nodes and links
+	SimplifyPhase
+)
+
 // A Population is a group of Organisms including their species
 type Population struct {
 	// Species in the Population. Note that the species should comprise all the genomes
@@ -25,6 +36,13 @@ type Population struct {
 	// The last generation played
 	FinalGen int
 
+	// The compatibility threshold used to speciate organisms. It's adjusted after each speciation if the target number of
+	// species is set in options, otherwise it's equal to the one in options.
+	CompatThreshold float64
+	// The compatibility threshold used in the last speciation, i.e., the one the current organisms were speciated with
+	// before CompatThreshold was adjusted for the next speciation.
+	SpeciationCompatThreshold float64
+
 	// Stagnation detector
 	HighestFitness float64
 	// The number of epochs when highest fitness was recorded for this population. If it was too long before
@@ -36,12 +54,34 @@ type Population struct {
 	Variance    float64
 	StandardDev float64
 
+	// The archive of novel behaviors, it is created on demand when novelty search is enabled
+	NoveltyArchive *NoveltyArchive
+	// The lineage of organisms across generations, it is created on demand when lineage tracking is enabled
+	Lineage *Lineage
+	// The most fit distinct genomes found by the population, it is created on demand when hall of fame size is set
+	HallOfFame *HallOfFame
+
+	// The current phase of the phased search
+	SearchPhase SearchPhase
+	// The mean genome complexity at the start of the current complexification phase
+	phaseBaseComplexity float64
+	// The flag to indicate whether the complexity at the start of the current complexification phase was recorded
+	phaseBaseRecorded bool
+	// The lowest mean genome complexity during the current simplification phase
+	lowestMeanComplexity float64
+	// The number of generations since the lowest mean genome complexity was recorded
+	lowestComplexityAge int
+
 	// For holding the genetic innovations of the newest generation
 	innovations []Innovation
 	// The next innovation number for population
 	nextInnovNum int64
 	// The next ID for new node in population
 	nextNodeId int32
+	// The number of offspring to be replaced by the clones of the hall of fame members in the current reproduction cycle
+	hallOfFameOffspring int
+	// The most fit organisms of the population to be cloned unchanged in the current reproduction cycle
+	populationElites []*Organism
 
 	// The mutex to guard against concurrent modifications
 	mutex *sync.Mutex
@@ -53,6 +93,8 @@ type reproductionResult struct {
 	babies       []byte // the encoded offsprings
 	err          error  // error occurred during reproduction if any
 	speciesId    int    // the ID of species used for reproduction
+
+	innovations *localInnovations // the structural innovations produced during reproduction
 }
 
 // NewPopulation constructs off of a single spawning Genome
@@ -79,7 +121,7 @@ func NewPopulationRandom(in, out, maxHidden int, recurrent bool, linkProb float6
 
 	pop := newPopulation()
 	for count := 0; count < opts.PopSize; count++ {
-		gen, err := newGenomeRand(count, in, out, rand.Intn(maxHidden), maxHidden, recurrent, linkProb, opts)
+		gen, err := newGenomeRand(count, in, out, opts.Rand().Intn(maxHidden), maxHidden, recurrent, linkProb, opts)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to create random population")
 		}
@@ -100,6 +142,56 @@ func NewPopulationRandom(in, out, maxHidden int, recurrent bool, linkProb float6
 	return pop, nil
 }
 
+// MeanComplexity Returns the mean complexity (the number of nodes and genes) of the genomes in this Population
+func (p *Population) MeanComplexity() float64 {
+	if len(p.Organisms) == 0 {
+		return 0
+	}
+	total := 0
+	for _, org := range p.Organisms {
+		total += len(org.Genotype.Nodes) + len(org.Genotype.Genes)
+	}
+	return float64(total) / float64(len(p.Organisms))
+}
+
+// updateSearchPhase is to switch the phase of the phased search if appropriate. The simplification phase starts when
+// the mean complexity of genomes grows by more than complexity ceiling since the start of the complexification phase.
+// The complexification phase resumes when the mean complexity stops falling for the specified number of generations.
+func (p *Population) updateSearchPhase(opts *neat.Options) {
+	if opts.PhasedSearchComplexityCeiling <= 0 {
+		p.SearchPhase = ComplexifyPhase
+		return
+	}
+	meanComplexity := p.MeanComplexity()
+	switch p.SearchPhase {
+	case ComplexifyPhase:
+		if !p.phaseBaseRecorded {
+			p.phaseBaseComplexity = meanComplexity
+			p.phaseBaseRecorded = true
+		}
+		if meanComplexity-p.phaseBaseComplexity > opts.PhasedSearchComplexityCeiling {
+			neat.InfoLog(fmt.Sprintf("POPULATION: Mean complexity %.2f exceeds ceiling, start simplification phase",
+				meanComplexity))
+			p.SearchPhase = SimplifyPhase
+			p.lowestMeanComplexity = meanComplexity
+			p.lowestComplexityAge = 0
+		}
+	case SimplifyPhase:
+		if meanComplexity < p.lowestMeanComplexity {
+			p.lowestMeanComplexity = meanComplexity
+			p.lowestComplexityAge = 0
+		} else {
+			p.lowestComplexityAge++
+		}
+		if p.lowestComplexityAge >= opts.PhasedSearchSimplifyGenerations {
+			neat.InfoLog(fmt.Sprintf("POPULATION: Mean complexity %.2f stopped falling, start complexification phase",
+				meanComplexity))
+			p.SearchPhase = ComplexifyPhase
+			p.phaseBaseComplexity = meanComplexity
+		}
+	}
+}
+
 // Verify is to run verification on all Genomes in this Population (Debugging)
 func (p *Population) Verify() (bool, error) {
 	res := true
@@ -153,7 +245,7 @@ func (p *Population) spawn(g *Genome, opts *neat.Options) (err error) {
 			return err
 		}
 		// introduce initial mutations
-		if _, err = newGenome.mutateLinkWeights(1.0, 1.0, gaussianMutator); err != nil {
+		if _, err = newGenome.mutateLinkWeights(1.0, 1.0, gaussianMutator, opts.Rand()); err != nil {
 			return err
 		}
 		// create organism for new genome
@@ -220,6 +312,9 @@ func (p *Population) speciate(ctx context.Context, organisms []*Organism) error
 	if !found {
 		return neat.ErrNEATOptionsNotFound
 	}
+	if opts.SpeciesCountTarget == 0 || p.CompatThreshold == 0 {
+		p.CompatThreshold = opts.CompatThreshold
+	}
 
 	// Step through all given organisms and speciate them within the population
 	for _, currOrg := range organisms {
@@ -234,7 +329,7 @@ func (p *Population) speciate(ctx context.Context, organisms []*Organism) error
 			// Create the first species
 			createFirstSpecies(p, currOrg)
 		} else {
-			if opts.CompatThreshold == 0 {
+			if p.CompatThreshold == 0 {
 				return errors.New("compatibility threshold is set to ZERO - will not find any compatible species")
 			}
 			// For each organism, search for a species it is compatible to
@@ -246,7 +341,7 @@ func (p *Population) speciate(ctx context.Context, organisms []*Organism) error
 				// compare current organism with first organism in current specie
 				if compOrg != nil {
 					currCompat := currOrg.Genotype.compatibility(compOrg.Genotype, opts)
-					if currCompat < opts.CompatThreshold && currCompat < bestCompatValue {
+					if currCompat < p.CompatThreshold && currCompat < bestCompatValue {
 						bestCompatible = currSpecies
 						bestCompatValue = currCompat
 						done = true
@@ -269,9 +364,33 @@ func (p *Population) speciate(ctx context.Context, organisms []*Organism) error
 		}
 	}
 
+	p.SpeciationCompatThreshold = p.CompatThreshold
+	if opts.SpeciesCountTarget > 0 {
+		p.adjustCompatThreshold(organisms, opts)
+	}
 	return nil
 }
 
+// adjustCompatThreshold is to adjust the compatibility threshold to bring the number of species closer to the target.
+// Only the species of the given speciated organisms are counted, because the species of the previous generation
+// without offspring are going to be removed.
+func (p *Population) adjustCompatThreshold(organisms []*Organism, opts *neat.Options) {
+	species := make(map[*Species]bool)
+	for _, org := range organisms {
+		species[org.Species] = true
+	}
+	if len(species) < opts.SpeciesCountTarget {
+		p.CompatThreshold -= opts.CompatThresholdStep
+	} else if len(species) > opts.SpeciesCountTarget {
+		p.CompatThreshold += opts.CompatThresholdStep
+	}
+	p.CompatThreshold = math.Max(opts.CompatThresholdMin, math.Min(opts.CompatThresholdMax, p.CompatThreshold))
+	if neat.LogLevel == neat.LogLevelDebug {
+		neat.DebugLog(fmt.Sprintf("POPULATION: Species count: %d, target: %d, compatibility threshold adjusted to: %f",
+			len(species), opts.SpeciesCountTarget, p.CompatThreshold))
+	}
+}
+
 // Removes zero offspring species from this population, i.e. species which will not have any offspring organism belonging to it
 // after reproduction cycle due to its fitness stagnation
 func (p *Population) purgeZeroOffspringSpecies(generation int) {
@@ -360,6 +479,117 @@ func (p *Population) purgeZeroOffspringSpecies(generation int) {
 	p.Species = speciesToKeep
 }
 
+// UpdateHallOfFame is to record the organisms evaluated in the given generation into the hall of fame of this
+// population. The hall of fame is created on demand when hall of fame size is set in options, otherwise nothing is done.
+func (p *Population) UpdateHallOfFame(generation int, opts *neat.Options) error {
+	if opts.HallOfFameSize <= 0 {
+		return nil
+	}
+	if p.HallOfFame == nil {
+		p.HallOfFame = NewHallOfFame(opts.HallOfFameSize)
+	}
+	_, err := p.HallOfFame.Update(generation, p.Organisms)
+	return err
+}
+
+// reserveHallOfFameOffspring is to take the offspring away from the worst species to be replaced by the clones of the
+// hall of fame members when population stagnation detected. The best species keeps at least one offspring.
+func (p *Population) reserveHallOfFameOffspring(sortedSpecies []*Species, opts *neat.Options) {
+	neat.DebugLog("POPULATION: RE-INJECTING HALL OF FAME MEMBERS TO FIX STAGNATION")
+	p.EpochsHighestLastChanged = 0
+	count := p.HallOfFame.Len()
+	if opts.HallOfFameReinjectCount > 0 && opts.HallOfFameReinjectCount < count {
+		count = opts.HallOfFameReinjectCount
+	}
+	reserved := reserveOffspring(sortedSpecies, count)
+	p.hallOfFameOffspring = reserved
+	if neat.LogLevel == neat.LogLevelDebug {
+		neat.DebugLog(fmt.Sprintf("POPULATION: Offspring reserved for hall of fame members: %d\n", reserved))
+	}
+}
+
+// reservePopulationElites is to take the offspring away from the worst species to be replaced by the clones of the
+// given number of the most fit organisms of the population. The best species keeps at least one offspring.
+func (p *Population) reservePopulationElites(sortedSpecies []*Species, count int) {
+	organisms := make(Organisms, len(p.Organisms))
+	copy(organisms, p.Organisms)
+	sort.SliceStable(organisms, func(i, j int) bool {
+		return organisms[i].originalFitness > organisms[j].originalFitness
+	})
+	reserved := reserveOffspring(sortedSpecies, min(count, len(organisms)))
+	p.populationElites = organisms[:reserved]
+	if neat.LogLevel == neat.LogLevelDebug {
+		neat.DebugLog(fmt.Sprintf("POPULATION: Offspring reserved for population elites: %d\n", reserved))
+	}
+}
+
+// populationEliteBabies is to create the clones of the population elites for the offspring reserved by
+// reservePopulationElites. The genome IDs of the babies start from the given one.
+func (p *Population) populationEliteBabies(generation, firstGenomeId int, opts *neat.Options) ([]*Organism, error) {
+	if len(p.populationElites) == 0 {
+		return nil, nil
+	}
+	babies := make([]*Organism, 0, len(p.populationElites))
+	for i, elite := range p.populationElites {
+		genome, err := elite.Genotype.duplicate(firstGenomeId + i)
+		if err != nil {
+			return nil, err
+		}
+		baby, err := NewOrganism(0.0, genome, generation)
+		if err != nil {
+			return nil, err
+		}
+		baby.ParentIds = []int{elite.Genotype.Id}
+		if opts.ReevaluateElites {
+			baby.inheritFitnessSamples(elite)
+		}
+		babies = append(babies, baby)
+	}
+	p.populationElites = nil
+	return babies, nil
+}
+
+// reserveOffspring is to take up to the given number of offspring away from the worst species. The best species keeps
+// at least one offspring. Returns the number of offspring taken.
+func reserveOffspring(sortedSpecies []*Species, count int) int {
+	reserved := 0
+	for i := len(sortedSpecies) - 1; i >= 0 && reserved < count; i-- {
+		currSpecies := sortedSpecies[i]
+		keep := 0
+		if i == 0 {
+			keep = 1
+		}
+		if taken := min(currSpecies.ExpectedOffspring-keep, count-reserved); taken > 0 {
+			currSpecies.ExpectedOffspring -= taken
+			reserved += taken
+		}
+	}
+	return reserved
+}
+
+// hallOfFameBabies is to create the clones of the hall of fame members for the offspring reserved by
+// reserveHallOfFameOffspring. The genome IDs of the babies start from the given one.
+func (p *Population) hallOfFameBabies(generation, firstGenomeId int) ([]*Organism, error) {
+	if p.hallOfFameOffspring == 0 {
+		return nil, nil
+	}
+	members := p.HallOfFame.Members()
+	babies := make([]*Organism, 0, p.hallOfFameOffspring)
+	for i := 0; i < p.hallOfFameOffspring && i < len(members); i++ {
+		genome, err := members[i].Organism.Genotype.duplicate(firstGenomeId + i)
+		if err != nil {
+			return nil, err
+		}
+		baby, err := NewOrganism(0.0, genome, generation)
+		if err != nil {
+			return nil, err
+		}
+		babies = append(babies, baby)
+	}
+	p.hallOfFameOffspring = 0
+	return babies, nil
+}
+
 // When population stagnation detected the delta coding will be performed in attempt to fix this
 func (p *Population) deltaCoding(sortedSpecies []*Species, opts *neat.Options) {
 	neat.DebugLog("POPULATION: PERFORMING DELTA CODING TO FIX STAGNATION")
@@ -441,7 +671,7 @@ func (p *Population) giveBabiesToTheBest(sortedSpecies []*Species, opts *neat.Op
 			stolenBabies -= stolenBlocks[blockIndex]
 		} else if blockIndex >= 3 {
 			// Give stolen to the rest in random ratios
-			if rand.Float64() > 0.1 {
+			if opts.Rand().Float64() > 0.1 {
 				// Randomize a little which species get boosted by a super champ
 				if stolenBabies > 3 {
 					currSpecies.Organisms[0].superChampOffspring = 3
@@ -6,6 +6,7 @@ import (
 	"deepneat/neat"
 	"encoding/gob"
 	"fmt"
+	"math/rand"
 	"sort"
 	"sync"
 )
@@ -18,6 +19,10 @@ type PopulationEpochExecutor interface {
 
 // SequentialPopulationEpochExecutor The epoch executor that runs execution sequentially in single thread for all species and organisms
 type SequentialPopulationEpochExecutor struct {
+	// The policy of handling the stagnation and of preserving the most fit organisms. If not set, the
+	// DefaultStagnation is used.
+	Stagnation Stagnation
+
 	// sortedSpecies sorted to have species with the best fitness score first
 	sortedSpecies         []*Species
 	bestSpeciesReproduced bool
@@ -49,13 +54,43 @@ func (s *SequentialPopulationEpochExecutor) prepareForReproduction(ctx context.C
 
 	// clear executor state from previous run
 	s.sortedSpecies = nil
+	stagnation := s.stagnation()
+
+	// Record the evaluated organisms into the lineage before their fitness gets adjusted
+	if opts.LineageTracking {
+		if p.Lineage == nil {
+			p.Lineage = NewLineage()
+		}
+		p.Lineage.Record(generation, p.Organisms)
+	}
+
+	// Keep the most fit distinct genomes before fitness of organisms gets adjusted
+	if err := p.UpdateHallOfFame(generation, opts); err != nil {
+		return err
+	}
+
+	// Replace the objective fitness of organisms with the novelty of their behaviors if novelty search enabled
+	if opts.NoveltySearchMode.IsEnabled() {
+		if p.NoveltyArchive == nil {
+			p.NoveltyArchive = NewNoveltyArchive()
+		}
+		if err := p.NoveltyArchive.EvaluateNovelty(p.Organisms, opts); err != nil {
+			return err
+		}
+	}
+
+	// Switch between complexification and simplification phases if phased search enabled
+	p.updateSearchPhase(opts)
 
-	// Use Species' ages to modify the objective fitness of organisms in other words, make it more fair for younger
-	// species, so they have a chance to take hold and also penalize stagnant species. Then adjust the fitness using
-	// the species size to "share" fitness within a species. Then, within each Species, mark for death those below
-	// survival_thresh * average
+	// Find the stagnated species according to the stagnation policy. Then use Species' ages to modify the objective
+	// fitness of organisms in other words, make it more fair for younger species, so they have a chance to take hold
+	// and also penalize stagnant species. Then adjust the fitness using the species size to "share" fitness within
+	// a species. Then, within each Species, mark for death those below survival_thresh * average
+	stagnated := stagnation.StagnatedSpecies(p.Species, opts)
 	for _, sp := range p.Species {
-		sp.adjustFitness(opts)
+		if err := sp.adjustFitness(stagnated[sp.Id], opts); err != nil {
+			return err
+		}
 	}
 
 	// find and remove species unable to produce offspring due to fitness stagnation
@@ -99,21 +134,40 @@ func (s *SequentialPopulationEpochExecutor) prepareForReproduction(ctx context.C
 		}
 	}
 
-	// Check for stagnation - if there is stagnation, perform delta-coding
-	if p.EpochsHighestLastChanged >= opts.DropOffAge+5 {
-		// Population stagnated - trying to fix it by delta coding
-		p.deltaCoding(s.sortedSpecies, opts)
+	// Check for stagnation - if there is stagnation, perform delta-coding and/or re-inject hall of fame members
+	if stagnation.PopulationStagnated(p, opts) {
+		reinject := opts.HallOfFameReinjection.IsEnabled() && p.HallOfFame != nil && p.HallOfFame.Len() > 0
+		if !reinject || opts.HallOfFameReinjection == neat.HallOfFameReinjectionCombine {
+			// Population stagnated - trying to fix it by delta coding
+			p.deltaCoding(s.sortedSpecies, opts)
+		}
+		if reinject {
+			p.reserveHallOfFameOffspring(s.sortedSpecies, opts)
+		}
 	} else if opts.BabiesStolen > 0 {
 		// STOLEN BABIES: The system can take expected offspring away from worse species and give them
 		// to superior species depending on the system parameter BabiesStolen (when BabiesStolen > 0)
 		p.giveBabiesToTheBest(s.sortedSpecies, opts)
 	}
 
+	// Preserve the most fit organisms of the population by cloning them unchanged into the next generation
+	if elitism := stagnation.PopulationElitism(p, opts); elitism > 0 {
+		p.reservePopulationElites(s.sortedSpecies, elitism)
+	}
+
 	// Kill off all Organisms marked for death. The remainder will be allowed to reproduce.
 	err := p.purgeOrganisms()
 	return err
 }
 
+// stagnation is to get the stagnation policy of this executor
+func (s *SequentialPopulationEpochExecutor) stagnation() Stagnation {
+	if s.Stagnation == nil {
+		return NewDefaultStagnation()
+	}
+	return s.Stagnation
+}
+
 // reproduce is to run the reproduction cycle
 func (s *SequentialPopulationEpochExecutor) reproduce(ctx context.Context, generation int, p *Population) error {
 	neat.DebugLog("POPULATION: Start Sequential Reproduction Cycle >>>>>")
@@ -125,8 +179,10 @@ func (s *SequentialPopulationEpochExecutor) reproduce(ctx context.Context, gener
 	// Perform reproduction. Reproduction is done on a per-Species basis
 	babies := make([]*Organism, 0)
 
+	stagnation := s.stagnation()
 	for _, sp := range p.Species {
-		repBabies, err := sp.reproduce(ctx, generation, p, s.sortedSpecies)
+		elitism := stagnation.SpeciesElitism(sp, opts)
+		repBabies, err := sp.reproduce(ctx, generation, p.SearchPhase, p, s.sortedSpecies, elitism)
 		if err != nil {
 			return err
 		}
@@ -140,6 +196,18 @@ func (s *SequentialPopulationEpochExecutor) reproduce(ctx context.Context, gener
 		babies = append(babies, repBabies...)
 	}
 
+	// add clones of the population elites and of the hall of fame members if re-injected
+	eliteBabies, err := p.populationEliteBabies(generation, len(babies), opts)
+	if err != nil {
+		return err
+	}
+	babies = append(babies, eliteBabies...)
+	hofBabies, err := p.hallOfFameBabies(generation, len(babies))
+	if err != nil {
+		return err
+	}
+	babies = append(babies, hofBabies...)
+
 	// sanity check - make sure that population size keep the same
 	if len(babies) != opts.PopSize {
 		return fmt.Errorf("progeny size after reproduction cycle dimished, expected: [%d], but got: [%d]",
@@ -147,7 +215,7 @@ func (s *SequentialPopulationEpochExecutor) reproduce(ctx context.Context, gener
 	}
 
 	// speciate fresh progeny
-	err := p.speciate(ctx, babies)
+	err = p.speciate(ctx, babies)
 
 	neat.DebugLog("POPULATION: >>>>> Reproduction Complete")
 
@@ -185,13 +253,21 @@ func (s *SequentialPopulationEpochExecutor) finalizeReproduction(_ context.Conte
 	return err
 }
 
-// ParallelPopulationEpochExecutor The population epoch executor with parallel reproduction cycle
+// ParallelPopulationEpochExecutor The population epoch executor with parallel reproduction cycle. Each species
+// reproduces with its own random stream derived from the random source of the NEAT options, thus stochastic decisions
+// made during reproduction are independent of goroutines scheduling. The structural innovations produced by each species
+// get provisional innovation numbers and node IDs, which are replaced by the global ones in the order of species after
+// reproduction cycle completes. Thus, evolution with the parallel executor is reproducible given the same random source.
 type ParallelPopulationEpochExecutor struct {
+	// The policy of handling the stagnation and of preserving the most fit organisms. If not set, the
+	// DefaultStagnation is used.
+	Stagnation Stagnation
+
 	sequential *SequentialPopulationEpochExecutor
 }
 
 func (p *ParallelPopulationEpochExecutor) NextEpoch(ctx context.Context, generation int, population *Population) error {
-	p.sequential = &SequentialPopulationEpochExecutor{}
+	p.sequential = &SequentialPopulationEpochExecutor{Stagnation: p.Stagnation}
 	err := p.sequential.prepareForReproduction(ctx, generation, population)
 	if err != nil {
 		return err
@@ -224,16 +300,21 @@ func (p *ParallelPopulationEpochExecutor) reproduce(ctx context.Context, generat
 	// The wait group to wait for all GO routines
 	var wg sync.WaitGroup
 
+	rng := opts.Rand()
+	stagnation := p.sequential.stagnation()
 	for _, species := range pop.Species {
+		// each species reproduce with its own random stream derived deterministically from the population's one
+		spCtx := neat.NewContext(ctx, opts.WithRandSource(rand.New(rand.NewSource(rng.Int63()))))
 		wg.Add(1)
 		// run in separate GO thread
-		go func(ctx context.Context, sp *Species, generation int, p *Population, sortedSpecies []*Species, resChan chan<- reproductionResult, wg *sync.WaitGroup) {
+		go func(ctx context.Context, sp *Species, generation int, phase SearchPhase, innovations *localInnovations, sortedSpecies []*Species, elitism int, resChan chan<- reproductionResult, wg *sync.WaitGroup) {
 			defer wg.Done()
-			babies, err := sp.reproduce(ctx, generation, p, sortedSpecies)
+			babies, err := sp.reproduce(ctx, generation, phase, innovations, sortedSpecies, elitism)
 
 			res := reproductionResult{}
 			if err == nil {
 				res.speciesId = sp.Id
+				res.innovations = innovations
 
 				// fill babies into result
 				var buf bytes.Buffer
@@ -253,29 +334,45 @@ func (p *ParallelPopulationEpochExecutor) reproduce(ctx context.Context, generat
 			// write result to channel and signal to wait group
 			resChan <- res
 
-		}(ctx, species, generation, pop, p.sequential.sortedSpecies, resChan, &wg)
+		}(spCtx, species, generation, pop.SearchPhase, newLocalInnovations(pop), p.sequential.sortedSpecies,
+			stagnation.SpeciesElitism(species, opts), resChan, &wg)
 	}
 
 	// wait for reproduction results
 	wg.Wait()
 	close(resChan)
 
+	// collect reproduction results in the species order to keep progeny order independent of goroutines scheduling
+	results := make([]reproductionResult, 0, spNum)
+	for result := range resChan {
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].speciesId < results[j].speciesId
+	})
+
 	// read reproduction results, instantiate progeny and speciate over population
 	babies := make([]*Organism, 0)
-	for result := range resChan {
+	for _, result := range results {
 		if result.err != nil {
 			return result.err
 		}
 		// read baby genome
 		dec := gob.NewDecoder(bytes.NewBuffer(result.babies))
+		spBabies := make([]*Organism, 0, result.babiesStored)
 		for i := 0; i < result.babiesStored; i++ {
 			org := Organism{}
 			err := dec.Decode(&org)
 			if err != nil {
 				return fmt.Errorf("failed to decode baby organism, reason: %v", err)
 			}
-			babies = append(babies, &org)
+			spBabies = append(spBabies, &org)
+		}
+		// assign global innovation numbers and node IDs to the structural innovations of species
+		if err := result.innovations.commit(pop, spBabies); err != nil {
+			return err
 		}
+		babies = append(babies, spBabies...)
 		if result.speciesId == p.sequential.bestSpeciesId {
 			// store flag if best species reproduced - it will be used to determine if best species
 			// produced offspring before died
@@ -283,6 +380,18 @@ func (p *ParallelPopulationEpochExecutor) reproduce(ctx context.Context, generat
 		}
 	}
 
+	// add clones of the population elites and of the hall of fame members if re-injected
+	eliteBabies, err := pop.populationEliteBabies(generation, len(babies), opts)
+	if err != nil {
+		return err
+	}
+	babies = append(babies, eliteBabies...)
+	hofBabies, err := pop.hallOfFameBabies(generation, len(babies))
+	if err != nil {
+		return err
+	}
+	babies = append(babies, hofBabies...)
+
 	// sanity check - make sure that population size keep the same
 	if len(babies) != opts.PopSize {
 		return fmt.Errorf("progeny size after reproduction cycle dimished, expected: [%d], but got: [%d]",
@@ -290,7 +399,7 @@ func (p *ParallelPopulationEpochExecutor) reproduce(ctx context.Context, generat
 	}
 
 	// speciate fresh progeny
-	err := pop.speciate(ctx, babies)
+	err = pop.speciate(ctx, babies)
 
 	neat.DebugLog("POPULATION: >>>>> Reproduction Complete")
 
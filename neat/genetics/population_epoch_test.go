@@ -36,6 +36,29 @@ func parallelExecutorNextEpoch(pop *Population, opts *neat.Options) error {
 	return nil
 }
 
+// runSeededEpochs is to spawn population from the random genome using options with random source seeded by given
+// value and to run the given number of epochs with provided executor. The beforeEpoch callback, if provided, is invoked
+// before each epoch to imitate evaluation of organisms.
+func runSeededEpochs(t *testing.T, conf *neat.Options, seed int64, epochs int, ex PopulationEpochExecutor,
+	beforeEpoch func(pop *Population, opts *neat.Options)) *Population {
+	in, out, maxHidden, n := 3, 2, 5, 3
+	linkProb := 0.5
+	opts := conf.WithRandSource(rand.New(rand.NewSource(seed)))
+	gen, err := newGenomeRand(1, in, out, n, maxHidden, false, linkProb, opts)
+	require.NoError(t, err, "failed to create random genome")
+	pop, err := NewPopulation(gen, opts)
+	require.NoError(t, err, "failed to create population")
+
+	for i := 0; i < epochs; i++ {
+		if beforeEpoch != nil {
+			beforeEpoch(pop, opts)
+		}
+		err = ex.NextEpoch(opts.NeatContext(), i+1, pop)
+		require.NoError(t, err, "failed at: %d epoch", i)
+	}
+	return pop
+}
+
 func TestPopulationEpochExecutor_NextEpoch(t *testing.T) {
 	rand.Seed(42)
 	in, out, maxHidden, n := 3, 2, 15, 3
@@ -65,3 +88,217 @@ func TestPopulationEpochExecutor_NextEpoch(t *testing.T) {
 	err = parallelExecutorNextEpoch(pop, conf)
 	assert.NoError(t, err, "failed to run parallel epoch executor")
 }
+
+func TestSequentialPopulationEpochExecutor_NextEpoch_reproducible(t *testing.T) {
+	testPopulationEpochExecutorReproducible(t, func() PopulationEpochExecutor {
+		return &SequentialPopulationEpochExecutor{}
+	})
+}
+
+func TestParallelPopulationEpochExecutor_NextEpoch_reproducible(t *testing.T) {
+	testPopulationEpochExecutorReproducible(t, func() PopulationEpochExecutor {
+		return &ParallelPopulationEpochExecutor{}
+	})
+}
+
+func TestPopulationEpochExecutor_NextEpoch_modules(t *testing.T) {
+	conf := &neat.Options{
+		CompatThreshold:        0.5,
+		DropOffAge:             5,
+		PopSize:                30,
+		MutateOnlyProb:         0.5,
+		MutateAddNodeProb:      0.1,
+		MutateAddLinkProb:      0.1,
+		MutateAddModuleProb:    0.3,
+		MutateRemoveModuleProb: 0.1,
+		MutateRewireModuleProb: 0.3,
+		MateMultipointProb:     1.0,
+		NodeActivators:         []math.NodeActivationType{math.GaussianBipolarActivation},
+		NodeActivatorsProb:     []float64{1.0},
+	}
+	neat.LogLevel = neat.LogLevelInfo
+	evaluate := func(pop *Population, _ *neat.Options) {
+		for j, org := range pop.Organisms {
+			org.Fitness = float64(j)
+		}
+	}
+	executors := map[string]func() PopulationEpochExecutor{
+		"sequential": func() PopulationEpochExecutor { return &SequentialPopulationEpochExecutor{} },
+		"parallel":   func() PopulationEpochExecutor { return &ParallelPopulationEpochExecutor{} },
+	}
+	for name, newExecutor := range executors {
+		pop1 := runSeededEpochs(t, conf, 42, 20, newExecutor(), evaluate)
+		pop2 := runSeededEpochs(t, conf, 42, 20, newExecutor(), evaluate)
+		modular := 0
+		for i, org := range pop1.Organisms {
+			valid, err := org.Genotype.verify()
+			require.NoError(t, err, "%s: organism at: %d", name, i)
+			assert.True(t, valid, "%s: organism at: %d", name, i)
+			equal, err := org.Genotype.IsEqual(pop2.Organisms[i].Genotype)
+			require.NoError(t, err, "%s: organism at: %d", name, i)
+			assert.True(t, equal, "%s: organism at: %d", name, i)
+			if len(org.Genotype.ControlGenes) > 0 {
+				modular++
+			}
+		}
+		assert.True(t, modular > 0, "%s: modular genomes expected", name)
+	}
+}
+
+func testPopulationEpochExecutorReproducible(t *testing.T, newExecutor func() PopulationEpochExecutor) {
+	conf := &neat.Options{
+		CompatThreshold:    0.5,
+		DropOffAge:         1,
+		PopSize:            30,
+		BabiesStolen:       10,
+		RecurOnlyProb:      0.2,
+		MutateAddNodeProb:  0.1,
+		MutateAddLinkProb:  0.2,
+		NodeActivators:     []math.NodeActivationType{math.GaussianBipolarActivation},
+		NodeActivatorsProb: []float64{1.0},
+	}
+	neat.LogLevel = neat.LogLevelInfo
+
+	pop1 := runSeededEpochs(t, conf, 42, 10, newExecutor(), nil)
+	pop2 := runSeededEpochs(t, conf, 42, 10, newExecutor(), nil)
+	require.Len(t, pop2.Organisms, len(pop1.Organisms))
+	for i, org := range pop1.Organisms {
+		equal, err := org.Genotype.IsEqual(pop2.Organisms[i].Genotype)
+		require.NoError(t, err, "organism at: %d", i)
+		assert.True(t, equal, "organism at: %d", i)
+		_, err = org.Genotype.verify()
+		assert.NoError(t, err, "organism at: %d", i)
+	}
+	assert.Equal(t, pop1.nextInnovNum, pop2.nextInnovNum)
+	assert.Equal(t, pop1.nextNodeId, pop2.nextNodeId)
+	assert.Equal(t, len(pop1.Species), len(pop2.Species))
+}
+
+func TestPopulationEpochExecutor_NextEpoch_lineage(t *testing.T) {
+	conf := &neat.Options{
+		CompatThreshold:       3.0,
+		DropOffAge:            20,
+		SurvivalThresh:        0.5,
+		PopSize:               30,
+		MutateOnlyProb:        0.5,
+		MutateAddNodeProb:     0.1,
+		MutateAddLinkProb:     0.2,
+		MutateLinkWeightsProb: 0.8,
+		MateMultipointProb:    0.4,
+		MateMultipointAvgProb: 0.4,
+		MateSinglepointProb:   0.2,
+		LineageTracking:       true,
+		NodeActivators:        []math.NodeActivationType{math.GaussianBipolarActivation},
+		NodeActivatorsProb:    []float64{1.0},
+	}
+	neat.LogLevel = neat.LogLevelInfo
+	evaluate := func(pop *Population, _ *neat.Options) {
+		for j, org := range pop.Organisms {
+			org.Fitness = float64(j + 1)
+		}
+	}
+	executors := map[string]func() PopulationEpochExecutor{
+		"sequential": func() PopulationEpochExecutor { return &SequentialPopulationEpochExecutor{} },
+		"parallel":   func() PopulationEpochExecutor { return &ParallelPopulationEpochExecutor{} },
+	}
+	epochs := 10
+	for name, newExecutor := range executors {
+		pop := runSeededEpochs(t, conf, 42, epochs, newExecutor(), evaluate)
+		require.NotNil(t, pop.Lineage, name)
+		assert.Equal(t, epochs*conf.PopSize, pop.Lineage.Len(), name)
+
+		// the initial population has no parents
+		for id := 0; id < conf.PopSize; id++ {
+			if node, ok := pop.Lineage.Node(1, id); ok {
+				assert.Empty(t, node.ParentIds, name)
+			}
+		}
+
+		mated, mutated := 0, 0
+		for _, org := range pop.Organisms {
+			require.NotEmpty(t, org.ParentIds, name)
+			require.True(t, len(org.ParentIds) <= 2, name)
+			if len(org.ParentIds) == 2 {
+				mated++
+				assert.Contains(t, []MatingMethod{MatingMultipoint, MatingMultipointAvg, MatingSinglepoint}, org.MatingMethod, name)
+			} else {
+				assert.Empty(t, org.MatingMethod, name)
+			}
+			if len(org.Mutations) > 0 {
+				mutated++
+			}
+			assert.Equal(t, epochs, org.Generation, name)
+			for _, parentId := range org.ParentIds {
+				_, ok := pop.Lineage.Node(epochs, parentId)
+				assert.True(t, ok, "%s: parent %d not found", name, parentId)
+			}
+		}
+		assert.True(t, mated > 0, name)
+		assert.True(t, mutated > 0, name)
+
+		// the ancestry reaches the initial population
+		graph := pop.Lineage.Ancestry(epochs+1, pop.Organisms[0])
+		require.NotEmpty(t, graph.Nodes, name)
+		assert.Equal(t, 1, graph.Nodes[0].Generation, name)
+		assert.Equal(t, LineageKey{Generation: epochs + 1, GenomeId: pop.Organisms[0].Genotype.Id}, graph.Root, name)
+		assert.True(t, len(graph.Edges) >= epochs, name)
+	}
+}
+
+func TestPopulationEpochExecutor_NextEpoch_hallOfFameReinjection(t *testing.T) {
+	executors := map[string]func() PopulationEpochExecutor{
+		"sequential": func() PopulationEpochExecutor { return &SequentialPopulationEpochExecutor{} },
+		"parallel":   func() PopulationEpochExecutor { return &ParallelPopulationEpochExecutor{} },
+	}
+	modes := []neat.HallOfFameReinjection{neat.HallOfFameReinjectionReplace, neat.HallOfFameReinjectionCombine}
+	epochs, reinjectCount := 5, 3
+	for name, newExecutor := range executors {
+		for _, mode := range modes {
+			conf := &neat.Options{
+				CompatThreshold:         3.0,
+				DropOffAge:              20,
+				SurvivalThresh:          0.5,
+				PopSize:                 30,
+				MutateOnlyProb:          0.5,
+				MutateAddLinkProb:       0.2,
+				MutateLinkWeightsProb:   0.8,
+				MateMultipointProb:      1.0,
+				HallOfFameSize:          5,
+				HallOfFameReinjection:   mode,
+				HallOfFameReinjectCount: reinjectCount,
+				NodeActivators:          []math.NodeActivationType{math.GaussianBipolarActivation},
+				NodeActivatorsProb:      []float64{1.0},
+			}
+			epoch := 0
+			evaluate := func(pop *Population, _ *neat.Options) {
+				for j, org := range pop.Organisms {
+					org.Fitness = float64(j + 1)
+				}
+				if epoch++; epoch == epochs {
+					// imitate stagnation in the last epoch
+					pop.HighestFitness = float64(len(pop.Organisms) + 1)
+					pop.EpochsHighestLastChanged = conf.DropOffAge + 5
+				}
+			}
+			pop := runSeededEpochs(t, conf, 42, epochs, newExecutor(), evaluate)
+			require.NotNil(t, pop.HallOfFame, "%s: %s", name, mode)
+			assert.Equal(t, conf.HallOfFameSize, pop.HallOfFame.Len(), "%s: %s", name, mode)
+			assert.Zero(t, pop.EpochsHighestLastChanged, "%s: %s", name, mode)
+			assert.Len(t, pop.Organisms, conf.PopSize, "%s: %s", name, mode)
+
+			// only re-injected clones have no parents
+			hashes := make(map[uint64]bool)
+			for _, member := range pop.HallOfFame.Members() {
+				hashes[member.Hash] = true
+			}
+			reinjected := 0
+			for _, org := range pop.Organisms {
+				if len(org.ParentIds) == 0 {
+					reinjected++
+					assert.True(t, hashes[org.Genotype.Hash()], "%s: %s", name, mode)
+				}
+			}
+			assert.Equal(t, reinjectCount, reinjected, "%s: %s", name, mode)
+		}
+	}
+}
@@ -4,10 +4,14 @@ import (
 	"bufio"
 	"bytes"
 	"deepneat/neat"
+	"encoding/gob"
 	"fmt"
 	"io"
 	"strconv"
 	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
 )
 
 // ReadPopulation reads population from provided reader
@@ -108,3 +112,254 @@ func (p *Population) WriteBySpecies(w io.Writer) error {
 	}
 	return nil
 }
+
+// WriteCheckpoint writes the complete state of this population into provided writer. Unlike Write, which stores only
+// genomes of the organisms, the checkpoint also includes species with their age, innovations and all counters needed
+// to continue evolution from the exact point it was saved. Use ReadPopulationCheckpoint to restore population.
+func (p *Population) WriteCheckpoint(w io.Writer) error {
+	enc := gob.NewEncoder(w)
+	return p.Encode(enc)
+}
+
+// ReadPopulationCheckpoint reads population from the checkpoint data previously stored with WriteCheckpoint
+func ReadPopulationCheckpoint(r io.Reader) (*Population, error) {
+	dec := gob.NewDecoder(r)
+	pop := newPopulation()
+	if err := pop.Decode(dec); err != nil {
+		return nil, err
+	}
+	return pop, nil
+}
+
+// Encode is to encode the complete state of this population with provided GOB encoder
+func (p *Population) Encode(enc *gob.Encoder) error {
+	fields := []interface{}{p.LastSpecies, p.WinnerGen, p.FinalGen, p.HighestFitness, p.EpochsHighestLastChanged,
+		p.MeanFitness, p.Variance, p.StandardDev, p.nextInnovNum, p.nextNodeId, p.SearchPhase, p.phaseBaseComplexity,
+		p.phaseBaseRecorded, p.lowestMeanComplexity, p.lowestComplexityAge, p.CompatThreshold,
+		p.SpeciationCompatThreshold}
+	for _, f := range fields {
+		if err := enc.Encode(f); err != nil {
+			return err
+		}
+	}
+
+	// encode innovations
+	if err := enc.Encode(len(p.innovations)); err != nil {
+		return err
+	}
+	for _, innovation := range p.innovations {
+		if err := enc.Encode(innovation); err != nil {
+			return err
+		}
+		if err := enc.Encode(innovation.innovationType); err != nil {
+			return err
+		}
+	}
+
+	// encode organisms
+	orgIndex := make(map[*Organism]int, len(p.Organisms))
+	if err := enc.Encode(len(p.Organisms)); err != nil {
+		return err
+	}
+	for i, org := range p.Organisms {
+		if err := encodeOrganismState(enc, org); err != nil {
+			return err
+		}
+		orgIndex[org] = i
+	}
+
+	// encode species with indexes of their organisms in the population
+	if err := enc.Encode(len(p.Species)); err != nil {
+		return err
+	}
+	for _, sp := range p.Species {
+		fields = []interface{}{sp.Id, sp.Age, sp.AgeOfLastImprovement, sp.MaxFitnessEver, sp.ExpectedOffspring,
+			sp.IsNovel, sp.IsChecked}
+		for _, f := range fields {
+			if err := enc.Encode(f); err != nil {
+				return err
+			}
+		}
+		indexes := make([]int, len(sp.Organisms))
+		for i, org := range sp.Organisms {
+			if index, ok := orgIndex[org]; ok {
+				indexes[i] = index
+			} else {
+				return fmt.Errorf("organism of species [%d] not found in population: %s", sp.Id, org)
+			}
+		}
+		if err := enc.Encode(indexes); err != nil {
+			return err
+		}
+	}
+
+	// encode novelty archive if any
+	if err := enc.Encode(p.NoveltyArchive != nil); err != nil {
+		return err
+	}
+	if p.NoveltyArchive != nil {
+		if err := p.NoveltyArchive.Encode(enc); err != nil {
+			return err
+		}
+	}
+
+	// encode lineage if any
+	if err := enc.Encode(p.Lineage != nil); err != nil {
+		return err
+	}
+	if p.Lineage != nil {
+		if err := p.Lineage.Encode(enc); err != nil {
+			return err
+		}
+	}
+
+	// encode hall of fame if any
+	if err := enc.Encode(p.HallOfFame != nil); err != nil {
+		return err
+	}
+	if p.HallOfFame != nil {
+		return p.HallOfFame.Encode(enc)
+	}
+	return nil
+}
+
+// Decode is to decode the complete state of this population with provided GOB decoder
+func (p *Population) Decode(dec *gob.Decoder) error {
+	if p.mutex == nil {
+		p.mutex = &sync.Mutex{}
+	}
+	fields := []interface{}{&p.LastSpecies, &p.WinnerGen, &p.FinalGen, &p.HighestFitness, &p.EpochsHighestLastChanged,
+		&p.MeanFitness, &p.Variance, &p.StandardDev, &p.nextInnovNum, &p.nextNodeId, &p.SearchPhase, &p.phaseBaseComplexity,
+		&p.phaseBaseRecorded, &p.lowestMeanComplexity, &p.lowestComplexityAge, &p.CompatThreshold,
+		&p.SpeciationCompatThreshold}
+	for _, f := range fields {
+		if err := dec.Decode(f); err != nil {
+			return errors.Wrap(err, "failed to decode population statistics")
+		}
+	}
+
+	// decode innovations
+	var innovationsNum int
+	if err := dec.Decode(&innovationsNum); err != nil {
+		return errors.Wrap(err, "failed to decode number of innovations")
+	}
+	p.innovations = make([]Innovation, innovationsNum)
+	for i := 0; i < innovationsNum; i++ {
+		if err := dec.Decode(&p.innovations[i]); err != nil {
+			return errors.Wrap(err, "failed to decode innovation")
+		}
+		if err := dec.Decode(&p.innovations[i].innovationType); err != nil {
+			return errors.Wrap(err, "failed to decode innovation type")
+		}
+	}
+
+	// decode organisms
+	var organismsNum int
+	if err := dec.Decode(&organismsNum); err != nil {
+		return errors.Wrap(err, "failed to decode number of organisms")
+	}
+	p.Organisms = make(Organisms, organismsNum)
+	for i := 0; i < organismsNum; i++ {
+		org, err := decodeOrganismState(dec)
+		if err != nil {
+			return err
+		}
+		p.Organisms[i] = org
+	}
+
+	// decode species
+	var speciesNum int
+	if err := dec.Decode(&speciesNum); err != nil {
+		return errors.Wrap(err, "failed to decode number of species")
+	}
+	p.Species = make([]*Species, speciesNum)
+	for i := 0; i < speciesNum; i++ {
+		sp := newSpecies(0)
+		fields = []interface{}{&sp.Id, &sp.Age, &sp.AgeOfLastImprovement, &sp.MaxFitnessEver, &sp.ExpectedOffspring,
+			&sp.IsNovel, &sp.IsChecked}
+		for _, f := range fields {
+			if err := dec.Decode(f); err != nil {
+				return errors.Wrap(err, "failed to decode species")
+			}
+		}
+		var indexes []int
+		if err := dec.Decode(&indexes); err != nil {
+			return errors.Wrap(err, "failed to decode species organisms")
+		}
+		for _, index := range indexes {
+			if index < 0 || index >= organismsNum {
+				return fmt.Errorf("organism index: %d of species [%d] is out of bounds", index, sp.Id)
+			}
+			org := p.Organisms[index]
+			org.Species = sp
+			sp.addOrganism(org)
+		}
+		p.Species[i] = sp
+	}
+
+	// decode novelty archive if any
+	var hasArchive bool
+	if err := dec.Decode(&hasArchive); err != nil {
+		return errors.Wrap(err, "failed to decode novelty archive presence")
+	}
+	if hasArchive {
+		p.NoveltyArchive = NewNoveltyArchive()
+		if err := p.NoveltyArchive.Decode(dec); err != nil {
+			return err
+		}
+	}
+
+	// decode lineage if any
+	var hasLineage bool
+	if err := dec.Decode(&hasLineage); err != nil {
+		return errors.Wrap(err, "failed to decode lineage presence")
+	}
+	if hasLineage {
+		p.Lineage = NewLineage()
+		if err := p.Lineage.Decode(dec); err != nil {
+			return err
+		}
+	}
+
+	// decode hall of fame if any
+	var hasHallOfFame bool
+	if err := dec.Decode(&hasHallOfFame); err != nil {
+		return errors.Wrap(err, "failed to decode hall of fame presence")
+	}
+	if hasHallOfFame {
+		p.HallOfFame = NewHallOfFame(0)
+		return p.HallOfFame.Decode(dec)
+	}
+	return nil
+}
+
+// encodeOrganismState is to encode organism with its genome and the state of reproduction related fields
+func encodeOrganismState(enc *gob.Encoder, org *Organism) error {
+	if err := enc.Encode(org); err != nil {
+		return err
+	}
+	fields := []interface{}{org.Error, org.IsWinner, org.ExpectedOffspring, org.originalFitness, org.isChampion,
+		org.superChampOffspring, org.isPopulationChampion, org.mutationStructBaby, org.mateBaby, org.Flag}
+	for _, f := range fields {
+		if err := enc.Encode(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeOrganismState is to decode organism encoded with encodeOrganismState
+func decodeOrganismState(dec *gob.Decoder) (*Organism, error) {
+	org := &Organism{}
+	if err := dec.Decode(org); err != nil {
+		return nil, errors.Wrap(err, "failed to decode organism")
+	}
+	fields := []interface{}{&org.Error, &org.IsWinner, &org.ExpectedOffspring, &org.originalFitness, &org.isChampion,
+		&org.superChampOffspring, &org.isPopulationChampion, &org.mutationStructBaby, &org.mateBaby, &org.Flag}
+	for _, f := range fields {
+		if err := dec.Decode(f); err != nil {
+			return nil, errors.Wrap(err, "failed to decode organism state")
+		}
+	}
+	return org, nil
+}
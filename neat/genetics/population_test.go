@@ -1,6 +1,7 @@
 package genetics
 
 import (
+	"bytes"
 	"deepneat/neat"
 	"deepneat/neat/math"
 	"github.com/stretchr/testify/assert"
@@ -106,3 +107,210 @@ func TestPopulation_verify(t *testing.T) {
 	require.NoError(t, err, "failed to verify population")
 	assert.True(t, res, "Population verification failed, but must not")
 }
+
+func TestPopulation_WriteCheckpoint(t *testing.T) {
+	conf := &neat.Options{
+		CompatThreshold:    0.5,
+		DropOffAge:         5,
+		PopSize:            20,
+		BabiesStolen:       5,
+		MutateAddNodeProb:  0.1,
+		MutateAddLinkProb:  0.2,
+		LineageTracking:    true,
+		HallOfFameSize:     3,
+		NodeActivators:     []math.NodeActivationType{math.GaussianBipolarActivation},
+		NodeActivatorsProb: []float64{1.0},
+	}
+	pop := runSeededEpochs(t, conf, 42, 5, &SequentialPopulationEpochExecutor{}, func(pop *Population, _ *neat.Options) {
+		for j, org := range pop.Organisms {
+			org.Fitness = float64(j)
+		}
+	})
+
+	pop.NoveltyArchive = NewNoveltyArchive()
+	pop.NoveltyArchive.Behaviors = [][]float64{{1, 2}, {3, 4}}
+
+	// write and read checkpoint
+	buf := bytes.NewBufferString("")
+	err := pop.WriteCheckpoint(buf)
+	require.NoError(t, err, "failed to write checkpoint")
+	restored, err := ReadPopulationCheckpoint(buf)
+	require.NoError(t, err, "failed to read checkpoint")
+
+	assert.Equal(t, pop.LastSpecies, restored.LastSpecies)
+	assert.Equal(t, pop.HighestFitness, restored.HighestFitness)
+	assert.Equal(t, pop.EpochsHighestLastChanged, restored.EpochsHighestLastChanged)
+	assert.Equal(t, pop.CompatThreshold, restored.CompatThreshold)
+	assert.Equal(t, pop.SpeciationCompatThreshold, restored.SpeciationCompatThreshold)
+	assert.Equal(t, pop.nextInnovNum, restored.nextInnovNum)
+	assert.Equal(t, pop.nextNodeId, restored.nextNodeId)
+	assert.Equal(t, pop.innovations, restored.innovations)
+	require.NotNil(t, restored.NoveltyArchive)
+	assert.Equal(t, pop.NoveltyArchive.Behaviors, restored.NoveltyArchive.Behaviors)
+	require.NotNil(t, restored.Lineage)
+	assert.Equal(t, pop.Lineage.Len(), restored.Lineage.Len())
+	require.NotNil(t, restored.HallOfFame)
+	require.Equal(t, conf.HallOfFameSize, restored.HallOfFame.Len())
+	for i, member := range pop.HallOfFame.Members() {
+		assert.Equal(t, member.Hash, restored.HallOfFame.Members()[i].Hash, "member at: %d", i)
+	}
+
+	require.Len(t, restored.Organisms, len(pop.Organisms))
+	for i, org := range pop.Organisms {
+		expected, actual := bytes.NewBufferString(""), bytes.NewBufferString("")
+		require.NoError(t, org.Genotype.Write(expected), "organism at: %d", i)
+		require.NoError(t, restored.Organisms[i].Genotype.Write(actual), "organism at: %d", i)
+		assert.Equal(t, expected.String(), actual.String(), "organism at: %d", i)
+		assert.Equal(t, org.Species.Id, restored.Organisms[i].Species.Id, "organism at: %d", i)
+		assert.Equal(t, org.ParentIds, restored.Organisms[i].ParentIds, "organism at: %d", i)
+	}
+	require.Len(t, restored.Species, len(pop.Species))
+	for i, sp := range pop.Species {
+		rsp := restored.Species[i]
+		assert.Equal(t, sp.Id, rsp.Id)
+		assert.Equal(t, sp.Age, rsp.Age)
+		assert.Equal(t, sp.AgeOfLastImprovement, rsp.AgeOfLastImprovement)
+		assert.Equal(t, sp.MaxFitnessEver, rsp.MaxFitnessEver)
+		assert.Len(t, rsp.Organisms, len(sp.Organisms))
+	}
+	res, err := restored.Verify()
+	require.NoError(t, err, "failed to verify restored population")
+	assert.True(t, res)
+}
+
+func TestPopulation_updateSearchPhase(t *testing.T) {
+	opts := &neat.Options{
+		PhasedSearchComplexityCeiling:   2,
+		PhasedSearchSimplifyGenerations: 2,
+	}
+	setGenomes := func(pop *Population, build func(id int) *Genome) {
+		pop.Organisms = make(Organisms, 3)
+		for i := range pop.Organisms {
+			pop.Organisms[i] = &Organism{Genotype: build(i)}
+		}
+	}
+	pop := newPopulation()
+
+	// the empty population has zero complexity which must be recorded as the base complexity
+	pop.updateSearchPhase(opts)
+	assert.Equal(t, ComplexifyPhase, pop.SearchPhase)
+	assert.True(t, pop.phaseBaseRecorded)
+	assert.Equal(t, 0.0, pop.phaseBaseComplexity)
+
+	// complexity grows above the ceiling: 4 nodes and 3 genes
+	setGenomes(pop, buildTestGenome)
+	assert.Equal(t, 7.0, pop.MeanComplexity())
+	pop.updateSearchPhase(opts)
+	assert.Equal(t, SimplifyPhase, pop.SearchPhase)
+
+	// complexity stops falling for two generations
+	pop.updateSearchPhase(opts)
+	assert.Equal(t, SimplifyPhase, pop.SearchPhase)
+	pop.updateSearchPhase(opts)
+	assert.Equal(t, ComplexifyPhase, pop.SearchPhase)
+	assert.Equal(t, 7.0, pop.phaseBaseComplexity)
+
+	// complexity grows within the ceiling
+	pop.Organisms[0].Genotype = buildTestModularGenome(0)
+	assert.Equal(t, 9.0, pop.MeanComplexity())
+	pop.updateSearchPhase(opts)
+	assert.Equal(t, ComplexifyPhase, pop.SearchPhase)
+
+	// the phased search is disabled
+	setGenomes(pop, buildTestModularGenome)
+	pop.updateSearchPhase(&neat.Options{})
+	assert.Equal(t, ComplexifyPhase, pop.SearchPhase)
+}
+
+func TestSequentialPopulationEpochExecutor_NextEpoch_phasedSearch(t *testing.T) {
+	conf := &neat.Options{
+		CompatThreshold:                 0.5,
+		DropOffAge:                      5,
+		PopSize:                         20,
+		BabiesStolen:                    5,
+		MutateOnlyProb:                  0.5,
+		MutateAddNodeProb:               0.3,
+		MutateAddLinkProb:               0.3,
+		MutateDeleteNodeProb:            0.3,
+		MutateDeleteLinkProb:            0.3,
+		PhasedSearchComplexityCeiling:   1,
+		PhasedSearchSimplifyGenerations: 2,
+		NodeActivators:                  []math.NodeActivationType{math.GaussianBipolarActivation},
+		NodeActivatorsProb:              []float64{1.0},
+	}
+	simplified := false
+	pop := runSeededEpochs(t, conf, 42, 10, &SequentialPopulationEpochExecutor{}, func(pop *Population, _ *neat.Options) {
+		simplified = simplified || pop.SearchPhase == SimplifyPhase
+		for j, org := range pop.Organisms {
+			org.Fitness = float64(j)
+		}
+	})
+	assert.True(t, simplified, "simplification phase expected")
+	res, err := pop.Verify()
+	require.NoError(t, err, "failed to verify population")
+	assert.True(t, res)
+}
+
+func TestPopulation_adjustCompatThreshold(t *testing.T) {
+	opts := &neat.Options{
+		SpeciesCountTarget:  2,
+		CompatThresholdStep: 0.5,
+		CompatThresholdMin:  1.0,
+		CompatThresholdMax:  3.0,
+	}
+	sp1, sp2, sp3 := NewSpecies(1), NewSpecies(2), NewSpecies(3)
+	organisms := []*Organism{{Species: sp1}, {Species: sp1}, {Species: sp2}, {Species: sp3}}
+	pop := newPopulation()
+
+	// too many species
+	pop.CompatThreshold = 2.0
+	pop.adjustCompatThreshold(organisms, opts)
+	assert.Equal(t, 2.5, pop.CompatThreshold)
+	pop.adjustCompatThreshold(organisms, opts)
+	pop.adjustCompatThreshold(organisms, opts)
+	assert.Equal(t, opts.CompatThresholdMax, pop.CompatThreshold, "must be bounded")
+
+	// exactly the target number of species
+	pop.adjustCompatThreshold(organisms[:3], opts)
+	assert.Equal(t, opts.CompatThresholdMax, pop.CompatThreshold)
+
+	// too few species
+	pop.CompatThreshold = 1.2
+	pop.adjustCompatThreshold(organisms[:2], opts)
+	assert.Equal(t, opts.CompatThresholdMin, pop.CompatThreshold, "must be bounded")
+}
+
+func TestPopulationEpochExecutor_NextEpoch_adaptiveCompatThreshold(t *testing.T) {
+	conf := &neat.Options{
+		CompatThreshold:       50.0,
+		SpeciesCountTarget:    10,
+		CompatThresholdStep:   0.5,
+		CompatThresholdMin:    0.5,
+		CompatThresholdMax:    50.0,
+		DropOffAge:            20,
+		SurvivalThresh:        0.5,
+		PopSize:               30,
+		MutateOnlyProb:        0.5,
+		MutateLinkWeightsProb: 0.8,
+		MateMultipointProb:    1.0,
+		NodeActivators:        []math.NodeActivationType{math.GaussianBipolarActivation},
+		NodeActivatorsProb:    []float64{1.0},
+	}
+	epochs := 5
+	pop := runSeededEpochs(t, conf, 42, epochs, &SequentialPopulationEpochExecutor{}, func(pop *Population, _ *neat.Options) {
+		for j, org := range pop.Organisms {
+			org.Fitness = float64(j + 1)
+		}
+	})
+	// single species with so high threshold, thus it's decreased after initial speciation and each epoch
+	assert.Len(t, pop.Species, 1)
+	assert.Equal(t, conf.CompatThreshold-float64(epochs+1)*conf.CompatThresholdStep, pop.CompatThreshold)
+	// the current organisms were speciated with threshold before the last adjustment
+	assert.Equal(t, pop.CompatThreshold+conf.CompatThresholdStep, pop.SpeciationCompatThreshold)
+
+	// the fixed threshold is taken from options
+	conf.SpeciesCountTarget = 0
+	pop = runSeededEpochs(t, conf, 42, 1, &SequentialPopulationEpochExecutor{}, nil)
+	assert.Equal(t, conf.CompatThreshold, pop.CompatThreshold)
+	assert.Equal(t, conf.CompatThreshold, pop.SpeciationCompatThreshold)
+}
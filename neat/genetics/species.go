@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"math"
-	"math/rand"
 	"sort"
 )
 
@@ -112,22 +111,19 @@ func (s *Species) removeOrganism(org *Organism) (bool, error) {
 	}
 }
 
-// Can change the fitness of the organisms in the Species to be higher for very new species (to protect them).
+// Can change the fitness of the organisms in the Species to be higher for very new species (to protect them) and
+// penalizes the fitness of the organisms if species is stagnated as decided by the Stagnation policy.
 // Divides the fitness by the size of the Species, so that fitness is "shared" by the species.
 // NOTE: Invocation of this method will result of species organisms sorted by fitness in descending order, i.e. most fit will be first.
-func (s *Species) adjustFitness(opts *neat.Options) {
-	ageDebt := (s.Age - s.AgeOfLastImprovement + 1) - opts.DropOffAge
-	if ageDebt == 0 {
-		ageDebt = 1
-	}
-
+// If Pareto selection enabled, the organisms are sorted by their Pareto front rank and crowding distance instead.
+func (s *Species) adjustFitness(stagnated bool, opts *neat.Options) error {
 	for _, org := range s.Organisms {
 		// Remember the original fitness before it gets modified
 		org.originalFitness = org.Fitness
 
-		// Make fitness decrease after a stagnation point dropoff_age
+		// Make fitness decrease after a stagnation point
 		// Added as if to keep species pristine until the dropoff point
-		if ageDebt >= 1 {
+		if stagnated {
 			// Extreme penalty for a long period of stagnation (divide fitness by 100)
 			org.Fitness = org.Fitness * 0.01
 		}
@@ -148,12 +144,19 @@ func (s *Species) adjustFitness(opts *neat.Options) {
 	}
 
 	// Sort the population (most fit first) and mark for death those after : survival_thresh * pop_size
-	sort.Sort(sort.Reverse(s.Organisms))
+	if opts.ParetoSelection {
+		// with multi-objective selection the organisms are ranked by Pareto front and crowding distance
+		if err := sortByParetoRank(s.Organisms); err != nil {
+			return err
+		}
+	} else {
+		sort.Sort(sort.Reverse(s.Organisms))
+	}
 
-	// Update age_of_last_improvement here
-	if s.Organisms[0].originalFitness > s.MaxFitnessEver {
-		s.AgeOfLastImprovement = s.Age
-		s.MaxFitnessEver = s.Organisms[0].originalFitness
+	s.Organisms[0].isChampion = true // Mark the champ as such
+	if !opts.ParentSelection.IsTruncation() {
+		// all organisms can become parents, the selection pressure is applied by parent selector
+		return nil
 	}
 
 	// Decide how many get to reproduce based on survival_thresh * pop_size
@@ -161,10 +164,10 @@ func (s *Species) adjustFitness(opts *neat.Options) {
 	numParents := int(math.Floor(opts.SurvivalThresh*float64(len(s.Organisms)) + 1.0))
 
 	// Mark for death those who are ranked too low to be parents
-	s.Organisms[0].isChampion = true // Mark the champ as such
 	for c := numParents; c < len(s.Organisms); c++ {
 		s.Organisms[c].toEliminate = true
 	}
+	return nil
 }
 
 // ComputeMaxAndAvgFitness Computes maximal and average fitness of species
@@ -249,16 +252,23 @@ func (s *Species) findChampion() *Organism {
 }
 
 // Perform mating and mutation to form next generation. The sorted_species is ordered to have best species in the beginning.
-// Returns list of baby organisms as a result of reproduction of all organisms in this species.
-func (s *Species) reproduce(ctx context.Context, generation int, pop *Population, sortedSpecies []*Species) ([]*Organism, error) {
+// Returns list of baby organisms as a result of reproduction of all organisms in this species. The provided innovations
+// tracker is used to record structural innovations and to assign IDs of new nodes. The search phase of the population
+// defines which structural mutations are applied. The given number of the most fit organisms are cloned unchanged.
+func (s *Species) reproduce(ctx context.Context, generation int, phase SearchPhase, innovations innovationsTracker, sortedSpecies []*Species, elitism int) ([]*Organism, error) {
 	opts, found := neat.FromContext(ctx)
 	if !found {
 		return nil, neat.ErrNEATOptionsNotFound
 	}
+	rng := opts.Rand()
 	//Check for a mistake
 	if s.ExpectedOffspring > 0 && len(s.Organisms) == 0 {
 		return nil, errors.New("attempt to reproduce out of empty species")
 	}
+	selector, err := NewParentSelector(opts)
+	if err != nil {
+		return nil, err
+	}
 
 	// The number of Organisms in the old generation
 	poolSize := len(s.Organisms)
@@ -268,8 +278,8 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 	// The species babies
 	babies := make([]*Organism, 0)
 
-	// Flag the preservation of the champion
-	champCloneDone := false
+	// The number of the most fit organisms already preserved by cloning
+	elitesCloned := 0
 
 	// Create the designated number of offspring for the Species one at a time
 	for count := 0; count < s.ExpectedOffspring; count++ {
@@ -285,6 +295,10 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 				count, s.ExpectedOffspring, s.Id))
 		}
 		mutStructBaby, mateBaby := false, false
+		// The origin of the baby
+		var parentIds []int
+		var matingMethod MatingMethod
+		mutations := make([]MutationType, 0)
 
 		// Debug Trap
 		if s.ExpectedOffspring > opts.PopSize {
@@ -302,22 +316,25 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 			if err != nil {
 				return nil, err
 			}
+			parentIds = []int{mom.Genotype.Id}
 
 			// Most superchamp offspring will have their connection weights mutated only
 			// The last offspring will be an exact duplicate of this super_champ
 			// Note: Superchamp offspring only occur with stolen babies!
 			//      Settings used for published experiments did not use this
 			if theChamp.superChampOffspring > 1 {
-				if rand.Float64() < 0.8 || opts.MutateAddLinkProb == 0.0 {
+				if rng.Float64() < 0.8 || opts.MutateAddLinkProb == 0.0 {
 					// Make sure no links get added when the system has link adding disabled
-					if _, err = newGenome.mutateLinkWeights(opts.WeightMutPower, 1.0, gaussianMutator); err != nil {
+					if _, err = newGenome.mutateLinkWeights(opts.WeightMutPower, 1.0, gaussianMutator, rng); err != nil {
 						return nil, err
 					}
+					mutations = append(mutations, MutationLinkWeights)
 				} else {
 					// Sometimes we add a link to a superchamp
-					if _, err = newGenome.mutateAddLink(pop, generation, opts); err != nil {
+					if _, err = newGenome.mutateAddLink(innovations, generation, opts); err != nil {
 						return nil, err
 					}
+					mutations = append(mutations, MutationAddLink)
 					mutStructBaby = true
 				}
 			}
@@ -336,67 +353,43 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 			}
 
 			theChamp.superChampOffspring--
-		} else if !champCloneDone && s.ExpectedOffspring > 5 {
-			neat.DebugLog("SPECIES: Clone species champion")
+		} else if elitesCloned < elitism && elitesCloned < poolSize {
+			neat.DebugLog("SPECIES: Clone species elite")
 
-			// If we have a Species champion, just clone it
-			mom := theChamp // Mom is the champ
+			// Preserve the most fit organisms starting from the Species champion, just clone them
+			mom := s.Organisms[elitesCloned]
 			newGenome, err := mom.Genotype.duplicate(count)
 			if err != nil {
 				return nil, err
 			}
 			// Baby is just like mommy
-			champCloneDone = true
+			elitesCloned++
+			parentIds = []int{mom.Genotype.Id}
 
 			// Create the new baby organism
 			baby, err = NewOrganism(0.0, newGenome, generation)
 			if err != nil {
 				return nil, err
 			}
+			if opts.ReevaluateElites {
+				// the fitness estimate of elite accumulates
+				baby.inheritFitnessSamples(mom)
+			}
 
-		} else if rand.Float64() < opts.MutateOnlyProb || poolSize == 1 {
+		} else if rng.Float64() < opts.MutateOnlyProb || poolSize == 1 {
 			neat.DebugLog("SPECIES: Reproduce by applying random mutation:")
 
 			// Apply mutations
-			orgNum := rand.Int31n(int32(poolSize)) // select random mom
-			mom := s.Organisms[orgNum]
+			mom := selector.Select(s.Organisms, rng) // select mom
 			newGenome, err := mom.Genotype.duplicate(count)
 			if err != nil {
 				return nil, err
 			}
+			parentIds = []int{mom.Genotype.Id}
 
 			// Do the mutation depending on probabilities of various mutations
-			if rand.Float64() < opts.MutateAddNodeProb {
-				neat.DebugLog("SPECIES: ---> mutateAddNode")
-
-				// Mutate add node
-				if _, err = newGenome.mutateAddNode(pop, pop, opts); err != nil {
-					return nil, err
-				}
-				mutStructBaby = true
-			} else if rand.Float64() < opts.MutateAddLinkProb {
-				neat.DebugLog("SPECIES: ---> mutateAddLink")
-
-				if _, err = newGenome.mutateAddLink(pop, generation, opts); err != nil {
-					return nil, err
-				}
-				mutStructBaby = true
-			} else if rand.Float64() < opts.MutateConnectSensors {
-				neat.DebugLog("SPECIES: ---> mutateConnectSensors")
-				if linkAdded, err := newGenome.mutateConnectSensors(pop, opts); err != nil {
-					return nil, err
-				} else {
-					mutStructBaby = linkAdded
-				}
-			}
-
-			if !mutStructBaby {
-				neat.DebugLog("SPECIES: ---> mutateAllNonstructural")
-
-				// If we didn't do a structural mutation, we do the other kinds
-				if _, err = newGenome.mutateAllNonstructural(opts); err != nil {
-					return nil, err
-				}
+			if mutations, mutStructBaby, err = mutateGenome(newGenome, innovations, phase, generation, opts); err != nil {
+				return nil, err
 			}
 
 			// Create the new baby organism
@@ -408,17 +401,15 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 			neat.DebugLog("SPECIES: Reproduce by mating:")
 
 			// Otherwise we should mate
-			orgNum := rand.Int31n(int32(poolSize)) // select random mom
-			mom := s.Organisms[orgNum]
+			mom := selector.Select(s.Organisms, rng) // select mom
 
-			// Choose random dad
+			// Choose dad
 			var dad *Organism
-			if rand.Float64() > opts.InterspeciesMateRate {
+			if rng.Float64() > opts.InterspeciesMateRate {
 				neat.DebugLog("SPECIES: ---> mate within species")
 
 				// Mate within Species
-				orgNum = rand.Int31n(int32(poolSize))
-				dad = s.Organisms[orgNum]
+				dad = selector.Select(s.Organisms, rng)
 			} else {
 				neat.DebugLog("SPECIES: ---> mate outside species")
 
@@ -429,7 +420,7 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 				giveup := 0
 				for randSpecies.Id == s.Id && giveup < 5 {
 					// Choose a random species tending towards better species
-					randMult := rand.Float64() / 4.0
+					randMult := rng.Float64() / 4.0
 					// This tends to select better species
 					randSpeciesNum := int(math.Floor(randMult * float64(len(sortedSpecies))))
 					randSpecies = sortedSpecies[randSpeciesNum]
@@ -442,70 +433,47 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 			// Perform mating based on probabilities of different mating types
 			var newGenome *Genome
 			var err error
-			if rand.Float64() < opts.MateMultipointProb {
+			if rng.Float64() < opts.MateMultipointProb {
 				neat.DebugLog("SPECIES: ------> mateMultipoint")
 
 				// mate multipoint baby
-				newGenome, err = mom.Genotype.mateMultipoint(dad.Genotype, count, mom.originalFitness, dad.originalFitness)
+				newGenome, err = mom.Genotype.mateMultipoint(dad.Genotype, count, mom.originalFitness, dad.originalFitness, rng)
 				if err != nil {
 					return nil, err
 				}
-			} else if rand.Float64() < opts.MateMultipointAvgProb/(opts.MateMultipointAvgProb+opts.MateSinglepointProb) {
+				matingMethod = MatingMultipoint
+			} else if rng.Float64() < opts.MateMultipointAvgProb/(opts.MateMultipointAvgProb+opts.MateSinglepointProb) {
 				neat.DebugLog("SPECIES: ------> mateMultipointAvg")
 
 				// mate multipoint_avg baby
-				newGenome, err = mom.Genotype.mateMultipointAvg(dad.Genotype, count, mom.originalFitness, dad.originalFitness)
+				newGenome, err = mom.Genotype.mateMultipointAvg(dad.Genotype, count, mom.originalFitness, dad.originalFitness, rng)
 				if err != nil {
 					return nil, err
 				}
+				matingMethod = MatingMultipointAvg
 			} else {
 				neat.DebugLog("SPECIES: ------> mateSinglePoint")
 
-				newGenome, err = mom.Genotype.mateSinglePoint(dad.Genotype, count)
+				newGenome, err = mom.Genotype.mateSinglePoint(dad.Genotype, count, rng)
 				if err != nil {
 					return nil, err
 				}
+				matingMethod = MatingSinglepoint
 			}
 
 			mateBaby = true
+			parentIds = []int{mom.Genotype.Id, dad.Genotype.Id}
 
 			// Determine whether to mutate the baby's Genome
 			// This is done randomly or if the mom and dad are the same organism
-			if rand.Float64() > opts.MateOnlyProb ||
+			if rng.Float64() > opts.MateOnlyProb ||
 				dad.Genotype.Id == mom.Genotype.Id ||
 				dad.Genotype.compatibility(mom.Genotype, opts) == 0.0 {
 				neat.DebugLog("SPECIES: ------> Mutate baby genome:")
 
 				// Do the mutation depending on probabilities of  various mutations
-				if rand.Float64() < opts.MutateAddNodeProb {
-					neat.DebugLog("SPECIES: ---------> mutateAddNode")
-
-					// mutate_add_node
-					if _, err = newGenome.mutateAddNode(pop, pop, opts); err != nil {
-						return nil, err
-					}
-					mutStructBaby = true
-				} else if rand.Float64() < opts.MutateAddLinkProb {
-					neat.DebugLog("SPECIES: ---------> mutateAddLink")
-
-					if _, err = newGenome.mutateAddLink(pop, generation, opts); err != nil {
-						return nil, err
-					}
-					mutStructBaby = true
-				} else if rand.Float64() < opts.MutateConnectSensors {
-					neat.DebugLog("SPECIES: ---> mutateConnectSensors")
-					if mutStructBaby, err = newGenome.mutateConnectSensors(pop, opts); err != nil {
-						return nil, err
-					}
-				}
-
-				if !mutStructBaby {
-					neat.DebugLog("SPECIES: ---> mutateAllNonstructural")
-
-					// If we didn't do a structural mutation, we do the other kinds
-					if _, err = newGenome.mutateAllNonstructural(opts); err != nil {
-						return nil, err
-					}
+				if mutations, mutStructBaby, err = mutateGenome(newGenome, innovations, phase, generation, opts); err != nil {
+					return nil, err
 				}
 			}
 			// Create the new baby organism
@@ -517,6 +485,9 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 
 		baby.mutationStructBaby = mutStructBaby
 		baby.mateBaby = mateBaby
+		baby.ParentIds = parentIds
+		baby.MatingMethod = matingMethod
+		baby.Mutations = mutations
 
 		babies = append(babies, baby)
 
@@ -524,6 +495,87 @@ func (s *Species) reproduce(ctx context.Context, generation int, pop *Population
 	return babies, nil
 }
 
+// Applies either one of the structural mutations or, if the structure was not mutated, the non-structural mutations
+// to the genome. Returns the types of applied mutations and true if the structure of the genome was mutated.
+func mutateGenome(genome *Genome, innovations innovationsTracker, phase SearchPhase, generation int, opts *neat.Options) ([]MutationType, bool, error) {
+	mutation, mutStructBaby, err := mutateStructure(genome, innovations, phase, generation, opts)
+	if err != nil {
+		return nil, false, err
+	}
+	if mutStructBaby {
+		return []MutationType{mutation}, true, nil
+	}
+
+	neat.DebugLog("SPECIES: ---> mutateAllNonstructural")
+
+	// If we didn't do a structural mutation, we do the other kinds
+	mutations, err := genome.mutateAllNonstructural(opts)
+	return mutations, false, err
+}
+
+// Applies one of the structural mutations to the genome depending on the probabilities of mutations and the current
+// phase of the phased search of the population. During the simplification phase only the deletion mutations and
+// the module removal are applied. Returns the type of applied mutation and true if the structure of the genome was
+// mutated.
+func mutateStructure(genome *Genome, innovations innovationsTracker, phase SearchPhase, generation int, opts *neat.Options) (mutation MutationType, mutated bool, err error) {
+	rng := opts.Rand()
+	if phase == SimplifyPhase {
+		if rng.Float64() < opts.MutateDeleteNodeProb {
+			neat.DebugLog("SPECIES: ---> mutateDeleteNode")
+			mutated, err = genome.mutateDeleteNode(rng)
+			return MutationDeleteNode, mutated, err
+		} else if rng.Float64() < opts.MutateDeleteLinkProb {
+			neat.DebugLog("SPECIES: ---> mutateDeleteLink")
+			mutated, err = genome.mutateDeleteLink(rng)
+			return MutationDeleteLink, mutated, err
+		} else if opts.MutateRemoveModuleProb > 0 && rng.Float64() < opts.MutateRemoveModuleProb {
+			neat.DebugLog("SPECIES: ---> mutateRemoveModule")
+			mutated, err = genome.mutateRemoveModule(rng)
+			return MutationRemoveModule, mutated, err
+		}
+		return "", false, nil
+	}
+
+	if rng.Float64() < opts.MutateAddNodeProb {
+		neat.DebugLog("SPECIES: ---> mutateAddNode")
+		if _, err = genome.mutateAddNode(innovations, innovations, opts); err != nil {
+			return MutationAddNode, false, err
+		}
+		return MutationAddNode, true, nil
+	} else if rng.Float64() < opts.MutateAddLinkProb {
+		neat.DebugLog("SPECIES: ---> mutateAddLink")
+		if _, err = genome.mutateAddLink(innovations, generation, opts); err != nil {
+			return MutationAddLink, false, err
+		}
+		return MutationAddLink, true, nil
+	} else if rng.Float64() < opts.MutateConnectSensors {
+		neat.DebugLog("SPECIES: ---> mutateConnectSensors")
+		mutated, err = genome.mutateConnectSensors(innovations, opts)
+		return MutationConnectSensors, mutated, err
+	} else if opts.MutateDeleteNodeProb > 0 && rng.Float64() < opts.MutateDeleteNodeProb {
+		neat.DebugLog("SPECIES: ---> mutateDeleteNode")
+		mutated, err = genome.mutateDeleteNode(rng)
+		return MutationDeleteNode, mutated, err
+	} else if opts.MutateDeleteLinkProb > 0 && rng.Float64() < opts.MutateDeleteLinkProb {
+		neat.DebugLog("SPECIES: ---> mutateDeleteLink")
+		mutated, err = genome.mutateDeleteLink(rng)
+		return MutationDeleteLink, mutated, err
+	} else if opts.MutateAddModuleProb > 0 && rng.Float64() < opts.MutateAddModuleProb {
+		neat.DebugLog("SPECIES: ---> mutateAddModule")
+		mutated, err = genome.mutateAddModule(innovations, innovations, opts)
+		return MutationAddModule, mutated, err
+	} else if opts.MutateRemoveModuleProb > 0 && rng.Float64() < opts.MutateRemoveModuleProb {
+		neat.DebugLog("SPECIES: ---> mutateRemoveModule")
+		mutated, err = genome.mutateRemoveModule(rng)
+		return MutationRemoveModule, mutated, err
+	} else if opts.MutateRewireModuleProb > 0 && rng.Float64() < opts.MutateRewireModuleProb {
+		neat.DebugLog("SPECIES: ---> mutateRewireModule")
+		mutated, err = genome.mutateRewireModule(innovations, opts)
+		return MutationRewireModule, mutated, err
+	}
+	return "", false, nil
+}
+
 func createFirstSpecies(pop *Population, baby *Organism) {
 	if neat.LogLevel == neat.LogLevelDebug {
 		neat.DebugLog(fmt.Sprintf("SPECIES: Create first species for baby organism [%d]", baby.Genotype.Id))
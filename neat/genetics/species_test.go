@@ -108,7 +108,9 @@ func TestSpecies_adjustFitness(t *testing.T) {
 		SurvivalThresh:  0.5,
 		AgeSignificance: 0.5,
 	}
-	sp.adjustFitness(&conf)
+	stagnated := NewDefaultStagnation().StagnatedSpecies([]*Species{sp}, &conf)
+	err = sp.adjustFitness(stagnated[sp.Id], &conf)
+	require.NoError(t, err, "failed to adjust fitness")
 
 	// test results
 	assert.True(t, sp.Organisms[0].isChampion)
@@ -199,7 +201,7 @@ func TestSpecies_reproduce_fail(t *testing.T) {
 
 	opts := neat.Options{}
 
-	babies, err := sp.reproduce(opts.NeatContext(), 1, nil, nil)
+	babies, err := sp.reproduce(opts.NeatContext(), 1, ComplexifyPhase, nil, nil, 0)
 	assert.Empty(t, babies, "no offsprings expected")
 	assert.EqualError(t, err, "attempt to reproduce out of empty species")
 }
@@ -240,7 +242,7 @@ func TestSpecies_reproduce(t *testing.T) {
 
 	pop.Species[0].ExpectedOffspring = 11
 
-	babies, err := pop.Species[0].reproduce(opts.NeatContext(), 1, pop, sortedSpecies)
+	babies, err := pop.Species[0].reproduce(opts.NeatContext(), 1, pop.SearchPhase, pop, sortedSpecies, 1)
 	require.NoError(t, err, "failed to reproduce")
 	require.NotEmpty(t, babies, "offsprings expected")
 
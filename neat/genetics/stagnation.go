@@ -0,0 +1,126 @@
+package genetics
+
+import (
+	"deepneat/neat"
+	"sort"
+)
+
+// Stagnation is the policy of handling the fitness stagnation of species and population, and of preserving the most
+// fit organisms. It is called by the population epoch executors when population is prepared for reproduction, thus
+// each experiment can choose its own policy.
+type Stagnation interface {
+	// StagnatedSpecies is to update the fitness improvement records of the given species and to find the stagnated
+	// ones. The fitness of organisms of the stagnated species is penalized, which eventually leads to extinction of
+	// the species. It's called before the fitness of organisms gets adjusted. Returns the set of IDs of the stagnated
+	// species.
+	StagnatedSpecies(species []*Species, opts *neat.Options) map[int]bool
+	// PopulationStagnated is to check whether the population stagnated and delta coding or re-injection of the hall
+	// of fame members must be applied. It's called after the record fitness of population is updated.
+	PopulationStagnated(pop *Population, opts *neat.Options) bool
+	// SpeciesElitism is to get the number of the most fit organisms of the species which are cloned unchanged into
+	// the next generation. It's called after the offspring of species are counted.
+	SpeciesElitism(species *Species, opts *neat.Options) int
+	// PopulationElitism is to get the number of the most fit organisms of the population which are cloned unchanged
+	// into the next generation in addition to the species elitism.
+	PopulationElitism(pop *Population, opts *neat.Options) int
+}
+
+// DefaultStagnation is the stagnation policy configured by the NEAT options. The species is stagnated when its
+// fitness measured by neat.Options.SpeciesFitnessFunc doesn't improve for neat.Options.DropOffAge generations, except
+// neat.Options.ProtectedSpecies the most fit species. The population is stagnated when its record fitness doesn't
+// improve for neat.Options.DropOffAge + 5 generations. With default options, it follows the original NEAT.
+type DefaultStagnation struct{}
+
+// NewDefaultStagnation is to create new stagnation policy configured by the NEAT options
+func NewDefaultStagnation() *DefaultStagnation {
+	return &DefaultStagnation{}
+}
+
+// StagnatedSpecies is to update the fitness improvement records of the given species and to find the stagnated ones
+func (d *DefaultStagnation) StagnatedSpecies(species []*Species, opts *neat.Options) map[int]bool {
+	fitness := make(map[int]float64, len(species))
+	stagnated := make(map[int]bool)
+	for _, sp := range species {
+		if len(sp.Organisms) == 0 {
+			continue
+		}
+		// the stagnation is checked against the improvements recorded before this generation
+		if sp.lastImproved()+1 >= opts.DropOffAge {
+			stagnated[sp.Id] = true
+		}
+		fitness[sp.Id] = speciesFitness(sp, opts.SpeciesFitnessFunc)
+		if fitness[sp.Id] > sp.MaxFitnessEver {
+			sp.AgeOfLastImprovement = sp.Age
+			sp.MaxFitnessEver = fitness[sp.Id]
+		}
+	}
+
+	if opts.ProtectedSpecies > 0 && len(stagnated) > 0 {
+		ranked := make([]*Species, 0, len(fitness))
+		for _, sp := range species {
+			if _, ok := fitness[sp.Id]; ok {
+				ranked = append(ranked, sp)
+			}
+		}
+		sort.SliceStable(ranked, func(i, j int) bool {
+			return fitness[ranked[i].Id] > fitness[ranked[j].Id]
+		})
+		for i := 0; i < opts.ProtectedSpecies && i < len(ranked); i++ {
+			delete(stagnated, ranked[i].Id)
+		}
+	}
+	return stagnated
+}
+
+// PopulationStagnated is to check whether the record fitness of population wasn't improved for too long
+func (d *DefaultStagnation) PopulationStagnated(pop *Population, opts *neat.Options) bool {
+	return pop.EpochsHighestLastChanged >= opts.DropOffAge+5
+}
+
+// SpeciesElitism is to get the number of the most fit organisms of the species to be cloned
+func (d *DefaultStagnation) SpeciesElitism(species *Species, opts *neat.Options) int {
+	switch {
+	case opts.SpeciesElitism < 0:
+		return 0
+	case opts.SpeciesElitism == 0:
+		// the original NEAT clones the champion of big enough species
+		if species.ExpectedOffspring > 5 {
+			return 1
+		}
+		return 0
+	case species.ExpectedOffspring > opts.SpeciesElitismThreshold:
+		return opts.SpeciesElitism
+	default:
+		return 0
+	}
+}
+
+// PopulationElitism is to get the number of the most fit organisms of the population to be cloned
+func (d *DefaultStagnation) PopulationElitism(_ *Population, opts *neat.Options) int {
+	return opts.PopulationElitism
+}
+
+// speciesFitness is to measure the fitness of species from the fitness of its organisms with given function
+func speciesFitness(species *Species, fn neat.SpeciesFitnessFunc) float64 {
+	if len(species.Organisms) == 0 {
+		return 0
+	}
+	fitness := make([]float64, len(species.Organisms))
+	for i, org := range species.Organisms {
+		fitness[i] = org.Fitness
+	}
+	switch fn {
+	case neat.SpeciesFitnessMean:
+		return mean(fitness)
+	case neat.SpeciesFitnessMedian:
+		return median(fitness)
+	default:
+		maxFitness := fitness[0]
+		for _, v := range fitness {
+			if v > maxFitness {
+				maxFitness = v
+			}
+		}
+		return maxFitness
+	}
+}
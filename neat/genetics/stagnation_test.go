@@ -0,0 +1,201 @@
+package genetics
+
+import (
+	"deepneat/neat"
+	"deepneat/neat/math"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultStagnation_StagnatedSpecies(t *testing.T) {
+	testCases := []struct {
+		protected int
+		expected  map[int]bool
+	}{
+		{protected: 0, expected: map[int]bool{1: true, 3: true}},
+		{protected: 1, expected: map[int]bool{1: true}},
+		{protected: 3, expected: map[int]bool{}},
+	}
+	for _, tc := range testCases {
+		species := make([]*Species, 3)
+		for i := range species {
+			sp, err := buildSpeciesWithOrganisms(i + 1)
+			require.NoError(t, err, "failed to build species")
+			species[i] = sp
+		}
+		// not improved for too long
+		species[0].Age, species[0].AgeOfLastImprovement, species[0].MaxFitnessEver = 10, 1, 100
+		// young species
+		species[1].Age, species[1].AgeOfLastImprovement = 3, 1
+		// stagnated before improvement in this generation
+		species[2].Age, species[2].AgeOfLastImprovement, species[2].MaxFitnessEver = 10, 2, 1
+
+		conf := &neat.Options{DropOffAge: 5, ProtectedSpecies: tc.protected}
+		stagnated := NewDefaultStagnation().StagnatedSpecies(species, conf)
+		assert.Equal(t, tc.expected, stagnated, "protected: %d", tc.protected)
+
+		assert.Equal(t, 1, species[0].AgeOfLastImprovement)
+		assert.Equal(t, 100.0, species[0].MaxFitnessEver)
+		assert.Equal(t, 3, species[1].AgeOfLastImprovement)
+		assert.Equal(t, 30.0, species[1].MaxFitnessEver)
+		assert.Equal(t, 10, species[2].AgeOfLastImprovement)
+		assert.Equal(t, 45.0, species[2].MaxFitnessEver)
+	}
+}
+
+func TestDefaultStagnation_PopulationStagnated(t *testing.T) {
+	conf := &neat.Options{DropOffAge: 5}
+	pop := &Population{EpochsHighestLastChanged: 9}
+	stagnation := NewDefaultStagnation()
+	assert.False(t, stagnation.PopulationStagnated(pop, conf))
+	pop.EpochsHighestLastChanged = 10
+	assert.True(t, stagnation.PopulationStagnated(pop, conf))
+}
+
+func TestDefaultStagnation_SpeciesElitism(t *testing.T) {
+	testCases := []struct {
+		elitism, threshold, offspring int
+		expected                      int
+	}{
+		{elitism: 0, offspring: 5, expected: 0},
+		{elitism: 0, offspring: 6, expected: 1},
+		{elitism: -1, offspring: 10, expected: 0},
+		{elitism: 3, threshold: 0, offspring: 1, expected: 3},
+		{elitism: 3, threshold: 2, offspring: 2, expected: 0},
+		{elitism: 3, threshold: 2, offspring: 3, expected: 3},
+	}
+	for i, tc := range testCases {
+		conf := &neat.Options{SpeciesElitism: tc.elitism, SpeciesElitismThreshold: tc.threshold, PopulationElitism: 2}
+		sp := &Species{ExpectedOffspring: tc.offspring}
+		stagnation := NewDefaultStagnation()
+		assert.Equal(t, tc.expected, stagnation.SpeciesElitism(sp, conf), "case: %d", i)
+		assert.Equal(t, 2, stagnation.PopulationElitism(nil, conf), "case: %d", i)
+	}
+}
+
+func Test_speciesFitness(t *testing.T) {
+	sp, err := buildSpeciesWithOrganisms(1)
+	require.NoError(t, err, "failed to build species")
+	assert.Equal(t, 15.0, speciesFitness(sp, ""))
+	assert.Equal(t, 15.0, speciesFitness(sp, neat.SpeciesFitnessMax))
+	assert.Equal(t, 10.0, speciesFitness(sp, neat.SpeciesFitnessMean))
+	assert.Equal(t, 10.0, speciesFitness(sp, neat.SpeciesFitnessMedian))
+
+	sp.addOrganism(&Organism{Fitness: 1.0})
+	assert.Equal(t, 7.75, speciesFitness(sp, neat.SpeciesFitnessMean))
+	assert.Equal(t, 7.5, speciesFitness(sp, neat.SpeciesFitnessMedian))
+
+	assert.Zero(t, speciesFitness(NewSpecies(2), neat.SpeciesFitnessMax))
+}
+
+func TestPopulation_reservePopulationElites(t *testing.T) {
+	species := make([]*Species, 2)
+	pop := &Population{}
+	for i := range species {
+		sp, err := buildSpeciesWithOrganisms(i + 1)
+		require.NoError(t, err, "failed to build species")
+		for j, org := range sp.Organisms {
+			org.Genotype = &Genome{Id: i*10 + j}
+			org.originalFitness = org.Fitness
+			pop.Organisms = append(pop.Organisms, org)
+		}
+		species[i] = sp
+	}
+	// the best species first
+	sortedSpecies := []*Species{species[1], species[0]}
+	species[1].ExpectedOffspring, species[0].ExpectedOffspring = 4, 2
+
+	pop.reservePopulationElites(sortedSpecies, 3)
+	assert.Equal(t, 3, species[1].ExpectedOffspring)
+	assert.Equal(t, 0, species[0].ExpectedOffspring)
+	require.Len(t, pop.populationElites, 3)
+
+	babies, err := pop.populationEliteBabies(2, 3, &neat.Options{})
+	require.NoError(t, err)
+	require.Len(t, babies, 3)
+	for i, parentId := range []int{12, 11, 2} {
+		assert.Equal(t, 3+i, babies[i].Genotype.Id)
+		assert.Equal(t, 2, babies[i].Generation)
+		assert.Equal(t, []int{parentId}, babies[i].ParentIds)
+	}
+	assert.Empty(t, pop.populationElites)
+}
+
+// testStagnation is the stagnation policy counting its invocations
+type testStagnation struct {
+	DefaultStagnation
+	speciesCalls, populationCalls int
+}
+
+func (s *testStagnation) StagnatedSpecies(species []*Species, opts *neat.Options) map[int]bool {
+	s.speciesCalls++
+	return s.DefaultStagnation.StagnatedSpecies(species, opts)
+}
+
+func (s *testStagnation) PopulationStagnated(pop *Population, opts *neat.Options) bool {
+	s.populationCalls++
+	return s.DefaultStagnation.PopulationStagnated(pop, opts)
+}
+
+func (s *testStagnation) SpeciesElitism(_ *Species, _ *neat.Options) int {
+	return 0
+}
+
+func TestPopulationEpochExecutor_NextEpoch_stagnationPolicy(t *testing.T) {
+	executors := map[string]func(stagnation Stagnation) PopulationEpochExecutor{
+		"sequential": func(stagnation Stagnation) PopulationEpochExecutor {
+			return &SequentialPopulationEpochExecutor{Stagnation: stagnation}
+		},
+		"parallel": func(stagnation Stagnation) PopulationEpochExecutor {
+			return &ParallelPopulationEpochExecutor{Stagnation: stagnation}
+		},
+	}
+	epochs := 3
+	for name, newExecutor := range executors {
+		conf := &neat.Options{
+			CompatThreshold:       3.0,
+			DropOffAge:            20,
+			SurvivalThresh:        0.5,
+			PopSize:               30,
+			MutateOnlyProb:        0.5,
+			MutateAddLinkProb:     0.2,
+			MutateLinkWeightsProb: 0.8,
+			MateMultipointProb:    1.0,
+			PopulationElitism:     2,
+			NodeActivators:        []math.NodeActivationType{math.GaussianBipolarActivation},
+			NodeActivatorsProb:    []float64{1.0},
+		}
+		var elites []*Organism
+		evaluate := func(pop *Population, _ *neat.Options) {
+			for j, org := range pop.Organisms {
+				org.Fitness = float64(j + 1)
+			}
+			elites = make([]*Organism, len(pop.Organisms))
+			copy(elites, pop.Organisms)
+			sort.Slice(elites, func(i, j int) bool {
+				return elites[i].Fitness > elites[j].Fitness
+			})
+			elites = elites[:conf.PopulationElitism]
+		}
+		stagnation := &testStagnation{}
+		pop := runSeededEpochs(t, conf, 42, epochs, newExecutor(stagnation), evaluate)
+		assert.Equal(t, epochs, stagnation.speciesCalls, name)
+		assert.Equal(t, epochs, stagnation.populationCalls, name)
+		require.Len(t, pop.Organisms, conf.PopSize, name)
+
+		// the most fit organisms of the last evaluated population are cloned
+		for _, elite := range elites {
+			cloned := false
+			for _, org := range pop.Organisms {
+				if len(org.ParentIds) == 1 && org.ParentIds[0] == elite.Genotype.Id && len(org.Mutations) == 0 &&
+					len(org.MatingMethod) == 0 && org.Genotype.Hash() == elite.Genotype.Hash() {
+					cloned = true
+				}
+			}
+			assert.True(t, cloned, "%s: elite %d not cloned", name, elite.Genotype.Id)
+		}
+	}
+}
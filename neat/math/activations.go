@@ -3,6 +3,7 @@ package math
 import (
 	"fmt"
 	"math"
+	"sort"
 )
 
 // NodeActivationType defines the type of activation function to use for the neuron node
@@ -33,6 +34,7 @@ const (
 	SignActivation
 	SineActivation
 	StepActivation
+	ReLUActivation
 
 	// The modular activators (with multiple inputs/outputs)
 	MultiplyModuleActivation
@@ -43,6 +45,10 @@ const (
 // ActivationFunction The neuron node activation function type
 type ActivationFunction func(float64, []float64) float64
 
+// ActivationDerivative The derivative of the neuron node activation function with respect to its input. It gets the
+// input and the output of activation function, thus the derivatives expressed through output are cheap to compute.
+type ActivationDerivative func(input, output float64, auxParams []float64) float64
+
 // ModuleActivationFunction The neurons module activation function type
 type ModuleActivationFunction func([]float64, []float64) []float64
 
@@ -55,6 +61,8 @@ type NodeActivatorsFactory struct {
 	activators map[NodeActivationType]ActivationFunction
 	// The map of registered neuron module activators by type
 	moduleActivators map[NodeActivationType]ModuleActivationFunction
+	// The map of registered derivatives of the differentiable neuron node activators by type
+	derivatives map[NodeActivationType]ActivationDerivative
 
 	// The forward and inverse maps of activator type and function name
 	forward map[NodeActivationType]string
@@ -66,6 +74,7 @@ func NewNodeActivatorsFactory() *NodeActivatorsFactory {
 	af := &NodeActivatorsFactory{
 		activators:       make(map[NodeActivationType]ActivationFunction),
 		moduleActivators: make(map[NodeActivationType]ModuleActivationFunction),
+		derivatives:      make(map[NodeActivationType]ActivationDerivative),
 		forward:          make(map[NodeActivationType]string),
 		inverse:          make(map[string]NodeActivationType),
 	}
@@ -91,6 +100,29 @@ func NewNodeActivatorsFactory() *NodeActivatorsFactory {
 	af.Register(SignActivation, signFunction, "SignActivation")
 	af.Register(SineActivation, sineFunction, "SineActivation")
 	af.Register(StepActivation, stepFunction, "StepActivation")
+	af.Register(ReLUActivation, rectifiedLinear, "ReLUActivation")
+
+	// Register derivatives of the differentiable neuron node activators
+	af.RegisterDerivative(SigmoidPlainActivation, plainSigmoidDerivative)
+	af.RegisterDerivative(SigmoidReducedActivation, reducedSigmoidDerivative)
+	af.RegisterDerivative(SigmoidSteepenedActivation, steepenedSigmoidDerivative)
+	af.RegisterDerivative(SigmoidBipolarActivation, bipolarSigmoidDerivative)
+	af.RegisterDerivative(SigmoidApproximationActivation, approximationSigmoidDerivative)
+	af.RegisterDerivative(SigmoidSteepenedApproximationActivation, approximationSteepenedSigmoidDerivative)
+	af.RegisterDerivative(SigmoidInverseAbsoluteActivation, inverseAbsoluteSigmoidDerivative)
+	af.RegisterDerivative(SigmoidLeftShiftedActivation, plainSigmoidDerivative)
+	af.RegisterDerivative(SigmoidLeftShiftedSteepenedActivation, steepenedSigmoidDerivative)
+	af.RegisterDerivative(SigmoidRightShiftedSteepenedActivation, steepenedSigmoidDerivative)
+
+	af.RegisterDerivative(TanhActivation, hyperbolicTangentDerivative)
+	af.RegisterDerivative(GaussianBipolarActivation, bipolarGaussianDerivative)
+	af.RegisterDerivative(GaussianActivation, gaussianDerivative)
+	af.RegisterDerivative(LinearActivation, linearDerivative)
+	af.RegisterDerivative(LinearAbsActivation, absoluteLinearDerivative)
+	af.RegisterDerivative(LinearClippedActivation, clippedLinearDerivative)
+	af.RegisterDerivative(NullActivation, nullDerivative)
+	af.RegisterDerivative(SineActivation, sineDerivative)
+	af.RegisterDerivative(ReLUActivation, rectifiedLinearDerivative)
 
 	// register neuron modules activators
 	af.RegisterModule(MultiplyModuleActivation, multiplyModule, "MultiplyModuleActivation")
@@ -110,6 +142,22 @@ func (a *NodeActivatorsFactory) ActivateByType(input float64, auxParams []float6
 	}
 }
 
+// DerivativeByType is to calculate the derivative of activation function with specified type at given input, which
+// produced given output. Will return error if activation function of specified type is not differentiable.
+func (a *NodeActivatorsFactory) DerivativeByType(input, output float64, auxParams []float64, aType NodeActivationType) (float64, error) {
+	if fn, ok := a.derivatives[aType]; ok {
+		return fn(input, output, auxParams), nil
+	} else {
+		return math.NaN(), fmt.Errorf("no derivative registered for neuron activation type: %d", aType)
+	}
+}
+
+// IsDifferentiable is to check if the derivative of activation function with specified type is registered
+func (a *NodeActivatorsFactory) IsDifferentiable(aType NodeActivationType) bool {
+	_, ok := a.derivatives[aType]
+	return ok
+}
+
 // ActivateModuleByType will apply corresponding module activation function to the input values and returns appropriate output values.
 // Will panic if unsupported activation function requested
 func (a *NodeActivatorsFactory) ActivateModuleByType(inputs []float64, auxParams []float64, aType NodeActivationType) ([]float64, error) {
@@ -129,6 +177,11 @@ func (a *NodeActivatorsFactory) Register(aType NodeActivationType, aFunc Activat
 	a.inverse[fName] = aType
 }
 
+// RegisterDerivative Registers the derivative of the neuron activation function with provided type into the factory
+func (a *NodeActivatorsFactory) RegisterDerivative(aType NodeActivationType, aFunc ActivationDerivative) {
+	a.derivatives[aType] = aFunc
+}
+
 // RegisterModule Registers given neuron module activation function with provided type and name into the factory
 func (a *NodeActivatorsFactory) RegisterModule(aType NodeActivationType, aFunc ModuleActivationFunction, fName string) {
 	// store function
@@ -138,6 +191,18 @@ func (a *NodeActivatorsFactory) RegisterModule(aType NodeActivationType, aFunc M
 	a.inverse[fName] = aType
 }
 
+// ModuleActivationTypes Returns types of all registered module activation functions in ascending order
+func (a *NodeActivatorsFactory) ModuleActivationTypes() []NodeActivationType {
+	types := make([]NodeActivationType, 0, len(a.moduleActivators))
+	for aType := range a.moduleActivators {
+		types = append(types, aType)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i] < types[j]
+	})
+	return types
+}
+
 // ActivationTypeFromName Parse node activation type name and return corresponding activation type
 func (a *NodeActivatorsFactory) ActivationTypeFromName(name string) (NodeActivationType, error) {
 	if t, ok := a.inverse[name]; ok {
@@ -276,6 +341,97 @@ var (
 			return 1.0
 		}
 	}
+	// The rectified linear unit x<0 ? 0.0 : x
+	rectifiedLinear = func(input float64, auxParams []float64) float64 {
+		return math.Max(0.0, input)
+	}
+)
+
+// The derivatives of differentiable activation functions
+var (
+	// The derivative of the plain and left shifted sigmoids
+	plainSigmoidDerivative = func(input, output float64, auxParams []float64) float64 {
+		return output * (1.0 - output)
+	}
+	// The derivative of the reduced sigmoid
+	reducedSigmoidDerivative = func(input, output float64, auxParams []float64) float64 {
+		return 0.5 * output * (1.0 - output)
+	}
+	// The derivative of the steepened and steepened shifted sigmoids
+	steepenedSigmoidDerivative = func(input, output float64, auxParams []float64) float64 {
+		return 4.924273 * output * (1.0 - output)
+	}
+	// The derivative of the bipolar sigmoid
+	bipolarSigmoidDerivative = func(input, output float64, auxParams []float64) float64 {
+		return 4.924273 * (1.0 + output) * (1.0 - output) / 2.0
+	}
+	// The derivative of the approximation sigmoid
+	approximationSigmoidDerivative = func(input, output float64, auxParams []float64) float64 {
+		if input < -4.0 || input >= 4.0 {
+			return 0.0
+		} else if input < 0.0 {
+			return (input + 4.0) * 0.0625
+		} else {
+			return (4.0 - input) * 0.0625
+		}
+	}
+	// The derivative of the steepened approximation sigmoid
+	approximationSteepenedSigmoidDerivative = func(input, output float64, auxParams []float64) float64 {
+		if input < -1.0 || input >= 1.0 {
+			return 0.0
+		} else if input < 0.0 {
+			return input + 1.0
+		} else {
+			return 1.0 - input
+		}
+	}
+	// The derivative of the inverse absolute sigmoid
+	inverseAbsoluteSigmoidDerivative = func(input, output float64, auxParams []float64) float64 {
+		d := 1.0 + math.Abs(input)
+		return 0.5 / (d * d)
+	}
+	// The derivative of the hyperbolic tangent
+	hyperbolicTangentDerivative = func(input, output float64, auxParams []float64) float64 {
+		return 0.9 * (1.0 - output*output)
+	}
+	// The derivative of the bipolar Gaussian
+	bipolarGaussianDerivative = func(input, output float64, auxParams []float64) float64 {
+		return -12.5 * input * (output + 1.0)
+	}
+	// The derivative of the Gaussian
+	gaussianDerivative = func(input, output float64, auxParams []float64) float64 {
+		return -2.0 * input * output
+	}
+	// The derivative of the linear activation
+	linearDerivative = func(input, output float64, auxParams []float64) float64 {
+		return 1.0
+	}
+	// The derivative of the absolute linear, zero at zero input
+	absoluteLinearDerivative = func(input, output float64, auxParams []float64) float64 {
+		return signFunction(input, auxParams)
+	}
+	// The derivative of the clipped linear
+	clippedLinearDerivative = func(input, output float64, auxParams []float64) float64 {
+		if input < -1.0 || input > 1.0 {
+			return 0.0
+		}
+		return 1.0
+	}
+	// The derivative of the null activator
+	nullDerivative = func(input, output float64, auxParams []float64) float64 {
+		return 0.0
+	}
+	// The derivative of the sine activation
+	sineDerivative = func(input, output float64, auxParams []float64) float64 {
+		return 2.0 * math.Cos(2.0*input)
+	}
+	// The derivative of the rectified linear unit, zero at zero input
+	rectifiedLinearDerivative = func(input, output float64, auxParams []float64) float64 {
+		if input > 0.0 {
+			return 1.0
+		}
+		return 0.0
+	}
 )
 
 // The modular activators
@@ -0,0 +1,42 @@
+package math
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeActivatorsFactory_DerivativeByType(t *testing.T) {
+	// the points away from the kinks of piecewise functions
+	inputs := []float64{-2.3, -0.7, -0.3, 0.4, 0.9, 2.7}
+	h := 1e-6
+	for aType := range NodeActivators.derivatives {
+		name, err := NodeActivators.ActivationNameFromType(aType)
+		require.NoError(t, err)
+		for _, x := range inputs {
+			y, err := NodeActivators.ActivateByType(x, nil, aType)
+			require.NoError(t, err, name)
+			yPlus, _ := NodeActivators.ActivateByType(x+h, nil, aType)
+			yMinus, _ := NodeActivators.ActivateByType(x-h, nil, aType)
+			numerical := (yPlus - yMinus) / (2 * h)
+
+			derivative, err := NodeActivators.DerivativeByType(x, y, nil, aType)
+			require.NoError(t, err, name)
+			assert.InDelta(t, numerical, derivative, 1e-5, "%s at %f", name, x)
+		}
+	}
+}
+
+func TestNodeActivatorsFactory_IsDifferentiable(t *testing.T) {
+	assert.True(t, NodeActivators.IsDifferentiable(SigmoidSteepenedActivation))
+	assert.True(t, NodeActivators.IsDifferentiable(ReLUActivation))
+	assert.False(t, NodeActivators.IsDifferentiable(StepActivation))
+	assert.False(t, NodeActivators.IsDifferentiable(SignActivation))
+	assert.False(t, NodeActivators.IsDifferentiable(MultiplyModuleActivation))
+
+	derivative, err := NodeActivators.DerivativeByType(0.5, 1.0, nil, StepActivation)
+	assert.Error(t, err)
+	assert.True(t, math.IsNaN(derivative))
+}
@@ -0,0 +1,153 @@
+package math
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// NodeAggregationType defines the type of aggregation function to use for combining input signals of the neuron node
+type NodeAggregationType byte
+
+// The neuron aggregation function types. The zero value is the sum, which is the classic NEAT aggregation.
+const (
+	// SumAggregation The sum of the input signals
+	SumAggregation NodeAggregationType = iota
+	// ProductAggregation The product of the input signals
+	ProductAggregation
+	// MaxAggregation The maximal input signal
+	MaxAggregation
+	// MinAggregation The minimal input signal
+	MinAggregation
+	// MeanAggregation The arithmetic mean of the input signals
+	MeanAggregation
+	// MedianAggregation The median of the input signals
+	MedianAggregation
+)
+
+// AggregationFunction The neuron node aggregation function type
+type AggregationFunction func([]float64) float64
+
+// NodeAggregators The default node aggregators factory reference
+var NodeAggregators = NewNodeAggregatorsFactory()
+
+// NodeAggregatorsFactory The factory to provide appropriate neuron node aggregation function
+type NodeAggregatorsFactory struct {
+	// The map of registered neuron node aggregators by type
+	aggregators map[NodeAggregationType]AggregationFunction
+
+	// The forward and inverse maps of aggregator type and function name
+	forward map[NodeAggregationType]string
+	inverse map[string]NodeAggregationType
+}
+
+// NewNodeAggregatorsFactory Returns node aggregator factory initialized with default aggregation functions
+func NewNodeAggregatorsFactory() *NodeAggregatorsFactory {
+	af := &NodeAggregatorsFactory{
+		aggregators: make(map[NodeAggregationType]AggregationFunction),
+		forward:     make(map[NodeAggregationType]string),
+		inverse:     make(map[string]NodeAggregationType),
+	}
+	af.Register(SumAggregation, sumAggregation, "SumAggregation")
+	af.Register(ProductAggregation, productAggregation, "ProductAggregation")
+	af.Register(MaxAggregation, maxAggregation, "MaxAggregation")
+	af.Register(MinAggregation, minAggregation, "MinAggregation")
+	af.Register(MeanAggregation, meanAggregation, "MeanAggregation")
+	af.Register(MedianAggregation, medianAggregation, "MedianAggregation")
+
+	return af
+}
+
+// AggregateByType is to combine given input signals using aggregation function with specified type.
+// Will return error and NaN if unsupported aggregation type requested.
+func (a *NodeAggregatorsFactory) AggregateByType(inputs []float64, aType NodeAggregationType) (float64, error) {
+	if fn, ok := a.aggregators[aType]; ok {
+		return fn(inputs), nil
+	} else {
+		return math.NaN(), fmt.Errorf("unknown neuron aggregation type: %d", aType)
+	}
+}
+
+// Register Registers given neuron aggregation function with provided type and name into the factory
+func (a *NodeAggregatorsFactory) Register(aType NodeAggregationType, aFunc AggregationFunction, fName string) {
+	// store function
+	a.aggregators[aType] = aFunc
+	// store name<->type bi-directional mapping
+	a.forward[aType] = fName
+	a.inverse[fName] = aType
+}
+
+// AggregationTypeFromName Parse node aggregation type name and return corresponding aggregation type
+func (a *NodeAggregatorsFactory) AggregationTypeFromName(name string) (NodeAggregationType, error) {
+	if t, ok := a.inverse[name]; ok {
+		return t, nil
+	} else {
+		return math.MaxInt8, fmt.Errorf("unsupported aggregation type name: %s", name)
+	}
+}
+
+// AggregationNameFromType Returns aggregation function name from given type
+func (a *NodeAggregatorsFactory) AggregationNameFromType(aType NodeAggregationType) (string, error) {
+	if n, ok := a.forward[aType]; ok {
+		return n, nil
+	} else {
+		return "", fmt.Errorf("unsupported aggregation type: %d", aType)
+	}
+}
+
+// The aggregation functions. All of them return zero for the empty inputs, except the product which returns one.
+var (
+	sumAggregation = func(inputs []float64) float64 {
+		sum := 0.0
+		for _, v := range inputs {
+			sum += v
+		}
+		return sum
+	}
+	productAggregation = func(inputs []float64) float64 {
+		product := 1.0
+		for _, v := range inputs {
+			product *= v
+		}
+		return product
+	}
+	maxAggregation = func(inputs []float64) float64 {
+		if len(inputs) == 0 {
+			return 0.0
+		}
+		maxVal := inputs[0]
+		for _, v := range inputs[1:] {
+			maxVal = math.Max(maxVal, v)
+		}
+		return maxVal
+	}
+	minAggregation = func(inputs []float64) float64 {
+		if len(inputs) == 0 {
+			return 0.0
+		}
+		minVal := inputs[0]
+		for _, v := range inputs[1:] {
+			minVal = math.Min(minVal, v)
+		}
+		return minVal
+	}
+	meanAggregation = func(inputs []float64) float64 {
+		if len(inputs) == 0 {
+			return 0.0
+		}
+		return sumAggregation(inputs) / float64(len(inputs))
+	}
+	medianAggregation = func(inputs []float64) float64 {
+		size := len(inputs)
+		if size == 0 {
+			return 0.0
+		}
+		sorted := make([]float64, size)
+		copy(sorted, inputs)
+		sort.Float64s(sorted)
+		if size%2 == 1 {
+			return sorted[size/2]
+		}
+		return (sorted[size/2-1] + sorted[size/2]) / 2.0
+	}
+)
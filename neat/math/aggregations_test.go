@@ -0,0 +1,52 @@
+package math
+
+import (
+	"testing"
+)
+
+func TestNodeAggregatorsFactory_AggregateByType(t *testing.T) {
+	inputs := []float64{3, -1, 2, 4}
+	testCases := []struct {
+		aType    NodeAggregationType
+		expected float64
+		empty    float64
+	}{
+		{aType: SumAggregation, expected: 8, empty: 0},
+		{aType: ProductAggregation, expected: -24, empty: 1},
+		{aType: MaxAggregation, expected: 4, empty: 0},
+		{aType: MinAggregation, expected: -1, empty: 0},
+		{aType: MeanAggregation, expected: 2, empty: 0},
+		{aType: MedianAggregation, expected: 2.5, empty: 0},
+	}
+	for _, tc := range testCases {
+		name, err := NodeAggregators.AggregationNameFromType(tc.aType)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if res, err := NodeAggregators.AggregateByType(inputs, tc.aType); err != nil {
+			t.Error(err)
+		} else if res != tc.expected {
+			t.Errorf("wrong %s result: %f, expected: %f", name, res, tc.expected)
+		}
+		if res, err := NodeAggregators.AggregateByType(nil, tc.aType); err != nil {
+			t.Error(err)
+		} else if res != tc.empty {
+			t.Errorf("wrong %s result for empty inputs: %f, expected: %f", name, res, tc.empty)
+		}
+		if aType, err := NodeAggregators.AggregationTypeFromName(name); err != nil || aType != tc.aType {
+			t.Errorf("wrong aggregation type: %d for name: %s, expected: %d", aType, name, tc.aType)
+		}
+	}
+	// check that inputs are not modified by median
+	if inputs[0] != 3 || inputs[3] != 4 {
+		t.Errorf("inputs modified: %v", inputs)
+	}
+
+	if _, err := NodeAggregators.AggregateByType(inputs, MedianAggregation+1); err == nil {
+		t.Error("error expected for unknown aggregation type")
+	}
+	if _, err := NodeAggregators.AggregationTypeFromName("UnknownAggregation"); err == nil {
+		t.Error("error expected for unknown aggregation name")
+	}
+}
@@ -5,9 +5,34 @@ import (
 	"math/rand"
 )
 
+// GlobalRand The random numbers generator backed by the global source of the math/rand package. It is used by
+// default when no explicit source of randomness was provided and is safe for concurrent use by multiple goroutines.
+var GlobalRand = rand.New(globalSource{})
+
+// globalSource The rand.Source64 delegating to the top-level functions of the math/rand package
+type globalSource struct{}
+
+func (globalSource) Int63() int64 {
+	return rand.Int63()
+}
+
+func (globalSource) Uint64() uint64 {
+	return rand.Uint64()
+}
+
+func (globalSource) Seed(seed int64) {
+	rand.Seed(seed)
+}
+
 // RandSign Returns subsequent random positive or negative integer value (1 or -1) to randomize value sign
 func RandSign() int32 {
-	v := rand.Int()
+	return RandSignWith(GlobalRand)
+}
+
+// RandSignWith Returns subsequent random positive or negative integer value (1 or -1) to randomize value sign using
+// provided random numbers generator
+func RandSignWith(rng *rand.Rand) int32 {
+	v := rng.Int()
 	if (v % 2) == 0 {
 		return -1
 	} else {
@@ -19,6 +44,12 @@ func RandSign() int32 {
 // The probability that a segment will be selected is given by that segment's value in the probabilities array.
 // Returns segment index or -1 if something goes awfully wrong
 func SingleRouletteThrow(probabilities []float64) int {
+	return SingleRouletteThrowWith(GlobalRand, probabilities)
+}
+
+// SingleRouletteThrowWith Performs a single thrown onto a roulette wheel using provided random numbers generator.
+// See SingleRouletteThrow for details.
+func SingleRouletteThrowWith(rng *rand.Rand, probabilities []float64) int {
 	total := 0.0
 
 	// collect all probabilities
@@ -27,7 +58,7 @@ func SingleRouletteThrow(probabilities []float64) int {
 	}
 
 	// throw the ball and collect result
-	throwValue := rand.Float64() * total
+	throwValue := rng.Float64() * total
 
 	accumulator := 0.0
 	for i, v := range probabilities {
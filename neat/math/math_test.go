@@ -22,3 +22,17 @@ func TestSingleRouletteThrow(t *testing.T) {
 	}
 	t.Log(hist)
 }
+
+func TestSingleRouletteThrowWith(t *testing.T) {
+	probabilities := []float64{.1, .2, .4, .15, .15}
+
+	rng1, rng2 := rand.New(rand.NewSource(42)), rand.New(rand.NewSource(42))
+	for i := 0; i < 1000; i++ {
+		index1 := SingleRouletteThrowWith(rng1, probabilities)
+		index2 := SingleRouletteThrowWith(rng2, probabilities)
+		if index1 != index2 {
+			t.Errorf("the same seed produced different results: %d != %d at %d", index1, index2, i)
+			return
+		}
+	}
+}
@@ -7,11 +7,13 @@ import (
 	"deepneat/neat/math"
 	"fmt"
 	"github.com/pkg/errors"
+	"math/rand"
 )
 
 var (
-	ErrNoActivatorsRegistered                = errors.New("no node activators registered with NEAT options, please assign at least one to NodeActivators")
-	ErrActivatorsProbabilitiesNumberMismatch = errors.New("number of node activator probabilities doesn't match number of activators")
+	ErrNoActivatorsRegistered                 = errors.New("no node activators registered with NEAT options, please assign at least one to NodeActivators")
+	ErrActivatorsProbabilitiesNumberMismatch  = errors.New("number of node activator probabilities doesn't match number of activators")
+	ErrAggregatorsProbabilitiesNumberMismatch = errors.New("number of node aggregator probabilities doesn't match number of aggregators")
 )
 
 // GenomeCompatibilityMethod defines the method to calculate genomes compatibility
@@ -46,6 +48,186 @@ func (e EpochExecutorType) Validate() error {
 	return nil
 }
 
+// NoveltySearchMode defines how the novelty of organisms' behaviors is used for selection
+type NoveltySearchMode string
+
+const (
+	// NoveltySearchModeOff means that only objective fitness is used for selection
+	NoveltySearchModeOff NoveltySearchMode = "off"
+	// NoveltySearchModeNovelty means that the objective fitness is replaced by the novelty score
+	NoveltySearchModeNovelty NoveltySearchMode = "novelty"
+	// NoveltySearchModeBlend means that the weighted sum of the objective fitness and novelty score is used
+	NoveltySearchModeBlend NoveltySearchMode = "blend"
+)
+
+// Validate is to check if this novelty search mode is supported by algorithm
+func (n NoveltySearchMode) Validate() error {
+	if n != "" && n != NoveltySearchModeOff && n != NoveltySearchModeNovelty && n != NoveltySearchModeBlend {
+		return errors.Errorf("unsupported novelty search mode: [%s]", n)
+	}
+	return nil
+}
+
+// IsEnabled is to check if this mode requires novelty of the organisms to be evaluated
+func (n NoveltySearchMode) IsEnabled() bool {
+	return n == NoveltySearchModeNovelty || n == NoveltySearchModeBlend
+}
+
+// HallOfFameReinjection defines how the hall of fame members are re-injected into the stagnated population
+type HallOfFameReinjection string
+
+const (
+	// HallOfFameReinjectionOff means that the hall of fame members are never re-injected
+	HallOfFameReinjectionOff HallOfFameReinjection = "off"
+	// HallOfFameReinjectionReplace means that the hall of fame members are re-injected instead of delta coding
+	HallOfFameReinjectionReplace HallOfFameReinjection = "replace"
+	// HallOfFameReinjectionCombine means that the hall of fame members are re-injected along with delta coding
+	HallOfFameReinjectionCombine HallOfFameReinjection = "combine"
+)
+
+// Validate is to check if this re-injection mode is supported by algorithm
+func (h HallOfFameReinjection) Validate() error {
+	if h != "" && h != HallOfFameReinjectionOff && h != HallOfFameReinjectionReplace && h != HallOfFameReinjectionCombine {
+		return errors.Errorf("unsupported hall of fame reinjection mode: [%s]", h)
+	}
+	return nil
+}
+
+// IsEnabled is to check if this mode requires the hall of fame members to be re-injected
+func (h HallOfFameReinjection) IsEnabled() bool {
+	return h == HallOfFameReinjectionReplace || h == HallOfFameReinjectionCombine
+}
+
+// SpeciesFitnessFunc defines how the fitness of species is measured from the fitness of its organisms
+type SpeciesFitnessFunc string
+
+const (
+	// SpeciesFitnessMax the maximal fitness of species organisms
+	SpeciesFitnessMax SpeciesFitnessFunc = "max"
+	// SpeciesFitnessMean the average fitness of species organisms
+	SpeciesFitnessMean SpeciesFitnessFunc = "mean"
+	// SpeciesFitnessMedian the median fitness of species organisms
+	SpeciesFitnessMedian SpeciesFitnessFunc = "median"
+)
+
+// Validate is to check if this species fitness function is supported by algorithm
+func (s SpeciesFitnessFunc) Validate() error {
+	if s != "" && s != SpeciesFitnessMax && s != SpeciesFitnessMean && s != SpeciesFitnessMedian {
+		return errors.Errorf("unsupported species fitness function: [%s]", s)
+	}
+	return nil
+}
+
+// ParentSelection defines the scheme of parents selection among the organisms of species
+type ParentSelection string
+
+const (
+	// ParentSelectionTruncation the parents are selected uniformly among the SurvivalThresh fraction of the most fit
+	// organisms of species
+	ParentSelectionTruncation ParentSelection = "truncation"
+	// ParentSelectionTournament the most fit of TournamentSize randomly chosen organisms of species is selected
+	ParentSelectionTournament ParentSelection = "tournament"
+	// ParentSelectionRoulette the organisms of species are selected with probability proportional to their fitness
+	ParentSelectionRoulette ParentSelection = "roulette"
+	// ParentSelectionRank the organisms of species are selected with probability proportional to their linear rank
+	ParentSelectionRank ParentSelection = "rank"
+)
+
+// Validate is to check if this parent selection scheme is supported by algorithm
+func (p ParentSelection) Validate() error {
+	if p != "" && p != ParentSelectionTruncation && p != ParentSelectionTournament && p != ParentSelectionRoulette &&
+		p != ParentSelectionRank {
+		return errors.Errorf("unsupported parent selection scheme: [%s]", p)
+	}
+	return nil
+}
+
+// IsTruncation is to check if this scheme selects parents only among the most fit organisms of species
+func (p ParentSelection) IsTruncation() bool {
+	return p == "" || p == ParentSelectionTruncation
+}
+
+// FitnessAggregation defines how the fitness samples of repeated evaluations of the organism are aggregated
+type FitnessAggregation string
+
+const (
+	// FitnessAggregationMean the average of fitness samples
+	FitnessAggregationMean FitnessAggregation = "mean"
+	// FitnessAggregationMedian the median of fitness samples
+	FitnessAggregationMedian FitnessAggregation = "median"
+	// FitnessAggregationWorst the minimal fitness sample, i.e., the worst case
+	FitnessAggregationWorst FitnessAggregation = "worst"
+)
+
+// Validate is to check if this fitness aggregation is supported by algorithm
+func (f FitnessAggregation) Validate() error {
+	if f != "" && f != FitnessAggregationMean && f != FitnessAggregationMedian && f != FitnessAggregationWorst {
+		return errors.Errorf("unsupported fitness aggregation: [%s]", f)
+	}
+	return nil
+}
+
+// PlasticityRule defines the rule of runtime adaptation of the links weights by the plastic network solver
+type PlasticityRule string
+
+const (
+	// PlasticityRuleHebb the plain Hebbian rule: dw = eta * pre * post
+	PlasticityRuleHebb PlasticityRule = "hebb"
+	// PlasticityRuleOja the Oja's rule, which keeps weights bounded: dw = eta * post * (pre - post * w)
+	PlasticityRuleOja PlasticityRule = "oja"
+	// PlasticityRuleABCD the generalized Hebbian rule: dw = eta * (A * pre * post + B * pre + C * post + D)
+	PlasticityRuleABCD PlasticityRule = "abcd"
+)
+
+// Validate is to check if this plasticity rule is supported by algorithm
+func (p PlasticityRule) Validate() error {
+	if p != "" && p != PlasticityRuleHebb && p != PlasticityRuleOja && p != PlasticityRuleABCD {
+		return errors.Errorf("unsupported plasticity rule: [%s]", p)
+	}
+	return nil
+}
+
+// GradientTuningMode defines how the results of gradient fine-tuning of the phenotype are inherited
+type GradientTuningMode string
+
+const (
+	// GradientTuningLamarckian the tuned weights are written back to the genome and inherited by offspring
+	GradientTuningLamarckian GradientTuningMode = "lamarckian"
+	// GradientTuningBaldwinian the genome is kept unchanged, only the fitness of tuned phenotype is kept
+	GradientTuningBaldwinian GradientTuningMode = "baldwinian"
+)
+
+// Validate is to check if this gradient tuning mode is supported by algorithm
+func (g GradientTuningMode) Validate() error {
+	if g != "" && g != GradientTuningLamarckian && g != GradientTuningBaldwinian {
+		return errors.Errorf("unsupported gradient tuning mode: [%s]", g)
+	}
+	return nil
+}
+
+// IsEnabled is to check if the gradient fine-tuning of phenotypes is enabled
+func (g GradientTuningMode) IsEnabled() bool {
+	return g == GradientTuningLamarckian || g == GradientTuningBaldwinian
+}
+
+// GradientOptimizer defines the optimizer applying gradients to the weights during gradient fine-tuning
+type GradientOptimizer string
+
+const (
+	// GradientOptimizerSGD the plain stochastic gradient descent
+	GradientOptimizerSGD GradientOptimizer = "sgd"
+	// GradientOptimizerAdam the Adam optimizer with adaptive moments estimation
+	GradientOptimizerAdam GradientOptimizer = "adam"
+)
+
+// Validate is to check if this gradient optimizer is supported by algorithm
+func (g GradientOptimizer) Validate() error {
+	if g != "" && g != GradientOptimizerSGD && g != GradientOptimizerAdam {
+		return errors.Errorf("unsupported gradient optimizer: [%s]", g)
+	}
+	return nil
+}
+
 // Options The NEAT algorithm options.
 type Options struct {
 	// Probability of mutating a single trait param
@@ -54,6 +236,10 @@ type Options struct {
 	TraitMutationPower float64 `yaml:"trait_mutation_power"`
 	// The power of a link weight mutation
 	WeightMutPower float64 `yaml:"weight_mut_power"`
+	// The power of a neuron node bias mutation
+	BiasMutPower float64 `yaml:"bias_mut_power"`
+	// The power of a neuron node response mutation
+	ResponseMutPower float64 `yaml:"response_mut_power"`
 
 	// These 3 global coefficients are used to determine the formula for
 	// computing the compatibility between 2 genomes.  The formula is:
@@ -65,10 +251,22 @@ type Options struct {
 	DisjointCoeff float64 `yaml:"disjoint_coeff"`
 	ExcessCoeff   float64 `yaml:"excess_coeff"`
 	MutdiffCoeff  float64 `yaml:"mutdiff_coeff"`
+	// The importance of different activation functions of the matching nodes for compatibility. If set, the term
+	// activation_diff_coeff * pdan is added to the formula, where pdan is the fraction of matching neuron nodes with
+	// different activation functions. Zero value disables this term.
+	ActivationDiffCoeff float64 `yaml:"activation_diff_coeff"`
 
 	// This global tells compatibility threshold under which
 	// two Genomes are considered the same species
 	CompatThreshold float64 `yaml:"compat_threshold"`
+	// The target number of species in population. If set, the compatibility threshold is adjusted after each speciation
+	// by CompatThresholdStep to bring the number of species closer to the target, otherwise it's fixed.
+	SpeciesCountTarget int `yaml:"species_count_target"`
+	// The step of compatibility threshold adjustment
+	CompatThresholdStep float64 `yaml:"compat_threshold_step"`
+	// The bounds of adjusted compatibility threshold
+	CompatThresholdMin float64 `yaml:"compat_threshold_min"`
+	CompatThresholdMax float64 `yaml:"compat_threshold_max"`
 
 	/* Globals involved in the epoch cycle - mating, reproduction, etc.. */
 
@@ -77,6 +275,12 @@ type Options struct {
 	AgeSignificance float64 `yaml:"age_significance"`
 	// Percent of average fitness for survival, how many get to reproduce based on survival_thresh * pop_size
 	SurvivalThresh float64 `yaml:"survival_thresh"`
+	// The scheme of parents selection within species (truncation, tournament, roulette, rank). If not set, the
+	// truncation is used. With other schemes all organisms of species can become parents, and SurvivalThresh is
+	// ignored.
+	ParentSelection ParentSelection `yaml:"parent_selection"`
+	// The number of organisms competing in the tournament selection
+	TournamentSize int `yaml:"tournament_size"`
 
 	// Probabilities of a non-mating reproduction
 	MutateOnlyProb         float64 `yaml:"mutate_only_prob"`
@@ -86,10 +290,23 @@ type Options struct {
 	MutateLinkWeightsProb  float64 `yaml:"mutate_link_weights_prob"`
 	MutateToggleEnableProb float64 `yaml:"mutate_toggle_enable_prob"`
 	MutateGeneReenableProb float64 `yaml:"mutate_gene_reenable_prob"`
-	MutateAddNodeProb      float64 `yaml:"mutate_add_node_prob"`
-	MutateAddLinkProb      float64 `yaml:"mutate_add_link_prob"`
+	// Probabilities of mutating the bias, response, aggregation and activation function of neuron nodes
+	MutateNodeBiasProb        float64 `yaml:"mutate_node_bias_prob"`
+	MutateNodeResponseProb    float64 `yaml:"mutate_node_response_prob"`
+	MutateNodeAggregationProb float64 `yaml:"mutate_node_aggregation_prob"`
+	MutateNodeActivationProb  float64 `yaml:"mutate_node_activation_prob"`
+	MutateAddNodeProb         float64 `yaml:"mutate_add_node_prob"`
+	MutateAddLinkProb         float64 `yaml:"mutate_add_link_prob"`
 	// probability of mutation involving disconnected inputs connection
 	MutateConnectSensors float64 `yaml:"mutate_connect_sensors"`
+	// Probabilities of structural mutations removing a link or a hidden node
+	MutateDeleteLinkProb float64 `yaml:"mutate_delete_link_prob"`
+	MutateDeleteNodeProb float64 `yaml:"mutate_delete_node_prob"`
+	// Probabilities of structural mutations adding a new MIMO module, removing a module or rewiring one of its inputs
+	// or outputs
+	MutateAddModuleProb    float64 `yaml:"mutate_add_module_prob"`
+	MutateRemoveModuleProb float64 `yaml:"mutate_remove_module_prob"`
+	MutateRewireModuleProb float64 `yaml:"mutate_rewire_module_prob"`
 
 	// Probabilities of a mate being outside species
 	InterspeciesMateRate  float64 `yaml:"interspecies_mate_rate"`
@@ -109,12 +326,37 @@ type Options struct {
 	// Number of tries mutate_add_link will attempt to find an open link
 	NewLinkTries int `yaml:"newlink_tries"`
 
+	// The growth of the mean genome complexity (number of nodes and genes) since the start of complexification phase
+	// which switches the phased search into the simplification phase. During simplification only the deletion
+	// structural mutations are applied. Zero value disables the phased search.
+	PhasedSearchComplexityCeiling float64 `yaml:"phased_search_complexity_ceiling"`
+	// The number of generations without decrease of the mean genome complexity after which the simplification phase
+	// ends and the search returns to the complexification phase
+	PhasedSearchSimplifyGenerations int `yaml:"phased_search_simplify_generations"`
+
 	// Tells to print population to file every n generations
 	PrintEvery int `yaml:"print_every"`
+	// Tells to store checkpoint of the running experiment every n generations. Zero value disables checkpoints.
+	CheckpointEvery int `yaml:"checkpoint_every"`
 
 	// The number of babies to stolen off to the champions
 	BabiesStolen int `yaml:"babies_stolen"`
 
+	// The measure of species fitness used to detect stagnation of species and to rank species protected from it (max,
+	// mean, median). If not set, the maximal fitness of species organisms is used.
+	SpeciesFitnessFunc SpeciesFitnessFunc `yaml:"species_fitness_func"`
+	// The number of the most fit species which are protected from the stagnation penalty, thus from extinction
+	ProtectedSpecies int `yaml:"protected_species"`
+	// The number of the most fit organisms of each species expecting more than SpeciesElitismThreshold offspring,
+	// which are cloned unchanged into the next generation. If zero, the champion of each species expecting more than
+	// five offspring is cloned as in the original NEAT. Negative value disables the species elitism.
+	SpeciesElitism int `yaml:"species_elitism"`
+	// The number of offspring which species must exceed to get its most fit organisms cloned
+	SpeciesElitismThreshold int `yaml:"species_elitism_threshold"`
+	// The number of the most fit organisms of the population, which are cloned unchanged into the next generation in
+	// addition to the species elitism. The offspring for the clones are taken away from the worst species.
+	PopulationElitism int `yaml:"population_elitism"`
+
 	// The number of runs to average over in an experiment
 	NumRuns int `yaml:"num_runs"`
 
@@ -123,9 +365,69 @@ type Options struct {
 
 	// The epoch's executor type to apply (sequential, parallel)
 	EpochExecutorType EpochExecutorType `yaml:"epoch_executor"`
+	// The number of workers evaluating organisms concurrently by the parallel generation evaluator. Zero value means
+	// the number of available CPUs.
+	EvaluationWorkers int `yaml:"evaluation_workers"`
+	// The number of times each organism is evaluated in every generation when the fitness is noisy, see
+	// experiment.EvaluateOrganism. The fitness samples are aggregated by FitnessAggregation. Zero or one means the
+	// single evaluation.
+	EvaluationRepeats int `yaml:"evaluation_repeats"`
+	// The aggregation of fitness samples of repeated evaluations (mean, median, worst). If not set, the mean is used.
+	FitnessAggregation FitnessAggregation `yaml:"fitness_aggregation"`
+	// If set, the elites cloned unchanged into the next generation inherit the fitness samples of their parents, thus
+	// the fitness estimates of surviving elites accumulate over the generations in which they are re-evaluated.
+	ReevaluateElites bool `yaml:"reevaluate_elites"`
+	// The rule of runtime adaptation of the links weights used by the plastic network solver (hebb, oja, abcd). The
+	// coefficients of the rule are taken from the trait of each link. If set, the phenotypes are activated by the
+	// plastic network solver returned by Organism.PhenotypeSolver. If not set, the networks are not plastic.
+	PlasticityRule PlasticityRule `yaml:"plasticity_rule"`
+	// The bound of absolute value of the links weights adapted by the plastic network solver. Zero value means
+	// unbounded weights.
+	PlasticWeightBound float64 `yaml:"plastic_weight_bound"`
+	// The mode of gradient fine-tuning of the feed-forward phenotypes on supervised tasks (lamarckian, baldwinian). If
+	// not set, the weights are searched only by mutations.
+	GradientTuningMode GradientTuningMode `yaml:"gradient_tuning_mode"`
+	// The number of epochs of gradient descent over the training samples applied to each phenotype
+	GradientTuningEpochs int `yaml:"gradient_tuning_epochs"`
+	// The optimizer of gradient fine-tuning (sgd, adam). If not set, the SGD is used.
+	GradientOptimizer GradientOptimizer `yaml:"gradient_optimizer"`
+	// The learning rate of gradient fine-tuning
+	GradientLearningRate float64 `yaml:"gradient_learning_rate"`
 	// The genome compatibility testing method to use (linear, fast (make sense for large genomes))
 	GenCompatMethod GenomeCompatibilityMethod `yaml:"genome_compat_method"`
 
+	// The novelty search mode to apply (off, novelty, blend). If not set, the novelty search is disabled
+	NoveltySearchMode NoveltySearchMode `yaml:"novelty_search_mode"`
+	// The number of nearest neighbors to estimate sparseness of the organism's behavior
+	NoveltyKNearest int `yaml:"novelty_k_nearest"`
+	// The minimal novelty score of the behavior to be stored in the novelty archive
+	NoveltyArchiveThreshold float64 `yaml:"novelty_archive_threshold"`
+	// The maximal number of behaviors stored in the novelty archive, the oldest are dropped first. Zero value means
+	// unlimited archive size.
+	NoveltyArchiveSize int `yaml:"novelty_archive_size"`
+	// The weight of the novelty score in the blend mode [0;1], the objective fitness gets the rest
+	NoveltyFitnessBlend float64 `yaml:"novelty_fitness_blend"`
+
+	// If set, the organisms within each species are ranked by Pareto front and crowding distance of their objectives
+	// (NSGA-II) instead of the fitness to select parents. The fitness is still used to count offspring of the species
+	// and to detect stagnation. The evaluator must record objectives of each organism. Can not be combined with
+	// novelty search.
+	ParetoSelection bool `yaml:"pareto_selection"`
+
+	// If set, the parents, the mating method and the mutations of each organism are recorded into the lineage of the
+	// population, which allows exporting the ancestry of any champion. The lineage grows with each generation.
+	LineageTracking bool `yaml:"lineage_tracking"`
+
+	// The number of the most fit distinct genomes kept in the hall of fame across generations of the population and
+	// trials of the experiment. If zero, the hall of fame is not kept.
+	HallOfFameSize int `yaml:"hall_of_fame_size"`
+	// Defines whether the hall of fame members are re-injected into the population when it stagnates, instead of or
+	// along with the delta coding. The offspring of the worst species are replaced by the clones of members.
+	HallOfFameReinjection HallOfFameReinjection `yaml:"hall_of_fame_reinjection"`
+	// The maximal number of the hall of fame members re-injected into the stagnated population. If zero, all members
+	// are re-injected.
+	HallOfFameReinjectCount int `yaml:"hall_of_fame_reinject_count"`
+
 	// The neuron nodes activation functions list to choose from
 	NodeActivators []math.NodeActivationType `yaml:"-"`
 	// The probabilities of selection of the specific node activator function
@@ -134,8 +436,37 @@ type Options struct {
 	// NodeActivatorsWithProbs the list of supported node activation with probability of each one
 	NodeActivatorsWithProbs []string `yaml:"node_activators"`
 
+	// The neuron nodes aggregation functions list to choose from. If empty, only the sum aggregation is used.
+	NodeAggregators []math.NodeAggregationType `yaml:"-"`
+	// The probabilities of selection of the specific node aggregation function
+	NodeAggregatorsProb []float64 `yaml:"-"`
+
+	// NodeAggregatorsWithProbs the list of supported node aggregation with probability of each one
+	NodeAggregatorsWithProbs []string `yaml:"node_aggregators"`
+
 	// LogLevel the log output details level
 	LogLevel string `yaml:"log_level"`
+
+	// RandSource the source of randomness used by all stochastic operations of the algorithm. If not set, the global
+	// source of the math/rand package will be used. Set it with seeded generator to get reproducible evolution runs.
+	RandSource *rand.Rand `yaml:"-"`
+}
+
+// Rand Returns the random numbers generator associated with this options or the one backed by global math/rand source
+// if no random source was explicitly provided.
+func (c *Options) Rand() *rand.Rand {
+	if c.RandSource != nil {
+		return c.RandSource
+	}
+	return math.GlobalRand
+}
+
+// WithRandSource Returns shallow copy of this options with the given random numbers generator assigned. It can be
+// used to derive options holding independent random stream for concurrent operations.
+func (c *Options) WithRandSource(rng *rand.Rand) *Options {
+	opts := *c
+	opts.RandSource = rng
+	return &opts
 }
 
 // RandomNodeActivationType Returns next random node activation type among registered with this context
@@ -152,13 +483,41 @@ func (c *Options) RandomNodeActivationType() (math.NodeActivationType, error) {
 	if len(c.NodeActivators) != len(c.NodeActivatorsProb) {
 		return 0, ErrActivatorsProbabilitiesNumberMismatch
 	}
-	index := math.SingleRouletteThrow(c.NodeActivatorsProb)
+	index := math.SingleRouletteThrowWith(c.Rand(), c.NodeActivatorsProb)
 	if index < 0 || index >= len(c.NodeActivators) {
 		return 0, fmt.Errorf("unexpected error when trying to find random node activator, activator index: %d", index)
 	}
 	return c.NodeActivators[index], nil
 }
 
+// RandomNodeAggregationType Returns next random node aggregation type among registered with this context. If no
+// aggregators registered, the sum aggregation is returned.
+func (c *Options) RandomNodeAggregationType() (math.NodeAggregationType, error) {
+	if len(c.NodeAggregators) == 0 {
+		return math.SumAggregation, nil
+	}
+	// quick check for the most cases
+	if len(c.NodeAggregators) == 1 {
+		return c.NodeAggregators[0], nil
+	}
+
+	// find random aggregator
+	if len(c.NodeAggregators) != len(c.NodeAggregatorsProb) {
+		return 0, ErrAggregatorsProbabilitiesNumberMismatch
+	}
+	index := math.SingleRouletteThrowWith(c.Rand(), c.NodeAggregatorsProb)
+	if index < 0 || index >= len(c.NodeAggregators) {
+		return 0, fmt.Errorf("unexpected error when trying to find random node aggregator, aggregator index: %d", index)
+	}
+	return c.NodeAggregators[index], nil
+}
+
+// IsFitnessNoisy is to check if organisms are evaluated repeatedly or their fitness samples are accumulated over
+// generations
+func (c *Options) IsFitnessNoisy() bool {
+	return c.EvaluationRepeats > 1 || c.ReevaluateElites
+}
+
 // Validate is to validate that this options has valid values
 func (c *Options) Validate() error {
 	if err := c.EpochExecutorType.Validate(); err != nil {
@@ -168,6 +527,132 @@ func (c *Options) Validate() error {
 	if err := c.GenCompatMethod.Validate(); err != nil {
 		return err
 	}
+	if c.EvaluationWorkers < 0 {
+		return errors.Errorf("number of evaluation workers must not be negative: %d", c.EvaluationWorkers)
+	}
+	if c.EvaluationRepeats < 0 {
+		return errors.Errorf("number of evaluation repeats must not be negative: %d", c.EvaluationRepeats)
+	}
+	if err := c.FitnessAggregation.Validate(); err != nil {
+		return err
+	}
+	if err := c.PlasticityRule.Validate(); err != nil {
+		return err
+	}
+	if c.PlasticWeightBound < 0 {
+		return errors.Errorf("plastic weight bound must not be negative: %f", c.PlasticWeightBound)
+	}
+	if err := c.GradientTuningMode.Validate(); err != nil {
+		return err
+	}
+	if err := c.GradientOptimizer.Validate(); err != nil {
+		return err
+	}
+	if c.GradientTuningMode.IsEnabled() {
+		if c.GradientTuningEpochs <= 0 {
+			return errors.Errorf("number of gradient tuning epochs must be positive: %d", c.GradientTuningEpochs)
+		}
+		if c.GradientLearningRate <= 0 {
+			return errors.Errorf("gradient learning rate must be positive: %f", c.GradientLearningRate)
+		}
+	}
+
+	if err := c.NoveltySearchMode.Validate(); err != nil {
+		return err
+	}
+	if c.NoveltySearchMode.IsEnabled() && c.NoveltyKNearest <= 0 {
+		return errors.Errorf("number of nearest neighbors must be positive for novelty search: %d", c.NoveltyKNearest)
+	}
+	if c.NoveltyFitnessBlend < 0 || c.NoveltyFitnessBlend > 1 {
+		return errors.Errorf("novelty fitness blend weight out of range [0;1]: %f", c.NoveltyFitnessBlend)
+	}
+	if c.NoveltySearchMode.IsEnabled() && c.ParetoSelection {
+		return errors.New("novelty search can not be combined with Pareto selection")
+	}
+
+	if c.SpeciesCountTarget < 0 {
+		return errors.Errorf("target species count must not be negative: %d", c.SpeciesCountTarget)
+	}
+	if c.SpeciesCountTarget > 0 {
+		if c.CompatThresholdStep <= 0 {
+			return errors.Errorf("compatibility threshold step must be positive: %f", c.CompatThresholdStep)
+		}
+		if c.CompatThresholdMin <= 0 || c.CompatThresholdMax < c.CompatThresholdMin {
+			return errors.Errorf("wrong compatibility threshold bounds: [%f;%f]", c.CompatThresholdMin, c.CompatThresholdMax)
+		}
+	}
+
+	if err := c.ParentSelection.Validate(); err != nil {
+		return err
+	}
+	if c.ParentSelection == ParentSelectionTournament && c.TournamentSize <= 0 {
+		return errors.Errorf("tournament size must be positive: %d", c.TournamentSize)
+	}
+
+	if err := c.SpeciesFitnessFunc.Validate(); err != nil {
+		return err
+	}
+	if c.ProtectedSpecies < 0 {
+		return errors.Errorf("number of protected species must not be negative: %d", c.ProtectedSpecies)
+	}
+	if c.SpeciesElitismThreshold < 0 {
+		return errors.Errorf("species elitism threshold must not be negative: %d", c.SpeciesElitismThreshold)
+	}
+	if c.PopulationElitism < 0 || (c.PopulationElitism > 0 && c.PopulationElitism >= c.PopSize) {
+		return errors.Errorf("population elitism out of range [0;%d): %d", c.PopSize, c.PopulationElitism)
+	}
+
+	if err := c.HallOfFameReinjection.Validate(); err != nil {
+		return err
+	}
+	if c.HallOfFameSize < 0 {
+		return errors.Errorf("hall of fame size must not be negative: %d", c.HallOfFameSize)
+	}
+	if c.HallOfFameReinjectCount < 0 {
+		return errors.Errorf("hall of fame reinject count must not be negative: %d", c.HallOfFameReinjectCount)
+	}
+	if c.HallOfFameReinjection.IsEnabled() && c.HallOfFameSize == 0 {
+		return errors.New("hall of fame size must be positive to re-inject its members")
+	}
+
+	if c.MutateDeleteLinkProb < 0 || c.MutateDeleteLinkProb > 1 {
+		return errors.Errorf("delete link mutation probability out of range [0;1]: %f", c.MutateDeleteLinkProb)
+	}
+	if c.MutateDeleteNodeProb < 0 || c.MutateDeleteNodeProb > 1 {
+		return errors.Errorf("delete node mutation probability out of range [0;1]: %f", c.MutateDeleteNodeProb)
+	}
+	if c.MutateAddModuleProb < 0 || c.MutateAddModuleProb > 1 {
+		return errors.Errorf("add module mutation probability out of range [0;1]: %f", c.MutateAddModuleProb)
+	}
+	if c.MutateRemoveModuleProb < 0 || c.MutateRemoveModuleProb > 1 {
+		return errors.Errorf("remove module mutation probability out of range [0;1]: %f", c.MutateRemoveModuleProb)
+	}
+	if c.MutateRewireModuleProb < 0 || c.MutateRewireModuleProb > 1 {
+		return errors.Errorf("rewire module mutation probability out of range [0;1]: %f", c.MutateRewireModuleProb)
+	}
+	if c.PhasedSearchComplexityCeiling < 0 {
+		return errors.Errorf("phased search complexity ceiling must not be negative: %f", c.PhasedSearchComplexityCeiling)
+	}
+	if c.PhasedSearchComplexityCeiling > 0 && c.PhasedSearchSimplifyGenerations <= 0 {
+		return errors.Errorf("number of simplification generations must be positive for phased search: %d",
+			c.PhasedSearchSimplifyGenerations)
+	}
+
+	if c.MutateNodeBiasProb < 0 || c.MutateNodeBiasProb > 1 {
+		return errors.Errorf("node bias mutation probability out of range [0;1]: %f", c.MutateNodeBiasProb)
+	}
+	if c.MutateNodeResponseProb < 0 || c.MutateNodeResponseProb > 1 {
+		return errors.Errorf("node response mutation probability out of range [0;1]: %f", c.MutateNodeResponseProb)
+	}
+	if c.MutateNodeAggregationProb < 0 || c.MutateNodeAggregationProb > 1 {
+		return errors.Errorf("node aggregation mutation probability out of range [0;1]: %f", c.MutateNodeAggregationProb)
+	}
+	if c.MutateNodeActivationProb < 0 || c.MutateNodeActivationProb > 1 {
+		return errors.Errorf("node activation mutation probability out of range [0;1]: %f", c.MutateNodeActivationProb)
+	}
+	if c.ActivationDiffCoeff < 0 {
+		return errors.Errorf("activation difference coefficient must not be negative: %f", c.ActivationDiffCoeff)
+	}
 
 	// check activators
 	if len(c.NodeActivators) == 0 {
@@ -176,6 +661,10 @@ func (c *Options) Validate() error {
 	if len(c.NodeActivators) != len(c.NodeActivatorsProb) {
 		return ErrActivatorsProbabilitiesNumberMismatch
 	}
+	// check aggregators
+	if len(c.NodeAggregators) != len(c.NodeAggregatorsProb) {
+		return ErrAggregatorsProbabilitiesNumberMismatch
+	}
 
 	return nil
 }
@@ -0,0 +1,471 @@
+package neat
+
+import (
+	"bufio"
+	"deepneat/neat/math"
+	"fmt"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+	"io"
+	"sort"
+	"strings"
+)
+
+// The sections of the neat-python configuration file
+const (
+	iniSectionNEAT         = "NEAT"
+	iniSectionGenome       = "DefaultGenome"
+	iniSectionSpeciesSet   = "DefaultSpeciesSet"
+	iniSectionStagnation   = "DefaultStagnation"
+	iniSectionReproduction = "DefaultReproduction"
+)
+
+// iniRecurrentLinkProb The probability of adding recurrent link used when neat-python config allows recurrent networks
+const iniRecurrentLinkProb = 0.2
+
+// INIInitialConnection defines the connection scheme of the seed genome in terms of neat-python configuration
+type INIInitialConnection string
+
+const (
+	// INIConnectionUnconnected no connections are created
+	INIConnectionUnconnected INIInitialConnection = "unconnected"
+	// INIConnectionFull is the same as INIConnectionFullNoDirect, kept for compatibility with old neat-python configs
+	INIConnectionFull INIInitialConnection = "full"
+	// INIConnectionFullNoDirect every input is connected to every hidden node and every hidden node is connected to
+	// every output. The inputs are connected directly to the outputs only if there are no hidden nodes.
+	INIConnectionFullNoDirect INIInitialConnection = "full_nodirect"
+	// INIConnectionFullDirect the same as INIConnectionFullNoDirect with inputs connected to outputs directly as well
+	INIConnectionFullDirect INIInitialConnection = "full_direct"
+	// INIConnectionPartial is the same as INIConnectionPartialNoDirect
+	INIConnectionPartial INIInitialConnection = "partial"
+	// INIConnectionPartialNoDirect each connection of the INIConnectionFullNoDirect scheme is created with given probability
+	INIConnectionPartialNoDirect INIInitialConnection = "partial_nodirect"
+	// INIConnectionPartialDirect each connection of the INIConnectionFullDirect scheme is created with given probability
+	INIConnectionPartialDirect INIInitialConnection = "partial_direct"
+)
+
+// Validate is to check if this initial connection scheme is supported
+func (c INIInitialConnection) Validate() error {
+	switch c {
+	case INIConnectionUnconnected, INIConnectionFull, INIConnectionFullNoDirect, INIConnectionFullDirect,
+		INIConnectionPartial, INIConnectionPartialNoDirect, INIConnectionPartialDirect:
+		return nil
+	default:
+		return errors.Errorf("unsupported initial connection scheme: [%s]", c)
+	}
+}
+
+// IsPartial is to check if connections are created with some probability by this scheme
+func (c INIInitialConnection) IsPartial() bool {
+	return c == INIConnectionPartial || c == INIConnectionPartialNoDirect || c == INIConnectionPartialDirect
+}
+
+// IsDirect is to check if inputs are connected directly to the outputs by this scheme when hidden nodes present
+func (c INIInitialConnection) IsDirect() bool {
+	return c == INIConnectionFullDirect || c == INIConnectionPartialDirect
+}
+
+// INIGenomeConfig The parameters of the seed genome defined in the [DefaultGenome] section of neat-python configuration
+type INIGenomeConfig struct {
+	// The number of input, output and hidden nodes
+	NumInputs  int
+	NumOutputs int
+	NumHidden  int
+	// The initial connection scheme and the probability of connection for partial schemes
+	InitialConnection  INIInitialConnection
+	ConnectionFraction float64
+	// If set, no recurrent connections are allowed
+	FeedForward bool
+	// The activation and aggregation functions of the neuron nodes
+	ActivationDefault  math.NodeActivationType
+	AggregationDefault math.NodeAggregationType
+	// The normal distribution parameters to initialize the bias and response of the neuron nodes
+	BiasInitMean      float64
+	BiasInitStdev     float64
+	ResponseInitMean  float64
+	ResponseInitStdev float64
+	// The normal distribution parameters to initialize the weights of the connections
+	WeightInitMean  float64
+	WeightInitStdev float64
+	// Whether the connections are enabled initially
+	EnabledDefault bool
+}
+
+// INIConfig The configuration loaded from the neat-python INI file
+type INIConfig struct {
+	// The NEAT options mapped from the configuration
+	Options *Options
+	// The parameters of the seed genome
+	Genome *INIGenomeConfig
+	// The keys which have no counterpart in the NEAT options, formatted as "section.key" and sorted
+	UnsupportedKeys []string
+}
+
+// neatPythonActivations maps the activation function names of neat-python onto the supported activation types
+var neatPythonActivations = map[string]math.NodeActivationType{
+	"sigmoid":  math.SigmoidSteepenedActivation,
+	"tanh":     math.TanhActivation,
+	"sin":      math.SineActivation,
+	"gauss":    math.GaussianActivation,
+	"relu":     math.ReLUActivation,
+	"identity": math.LinearActivation,
+	"clamped":  math.LinearClippedActivation,
+	"abs":      math.LinearAbsActivation,
+}
+
+// neatPythonAggregations maps the aggregation function names of neat-python onto the supported aggregation types
+var neatPythonAggregations = map[string]math.NodeAggregationType{
+	"sum":     math.SumAggregation,
+	"product": math.ProductAggregation,
+	"max":     math.MaxAggregation,
+	"min":     math.MinAggregation,
+	"mean":    math.MeanAggregation,
+	"median":  math.MedianAggregation,
+}
+
+// LoadINIOptions is to load NEAT options from neat-python configuration file (.ini). The keys which have no
+// counterpart in the NEAT options are reported with warning log messages.
+func LoadINIOptions(r io.Reader) (*Options, error) {
+	config, err := LoadINIConfig(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range config.UnsupportedKeys {
+		WarnLog(fmt.Sprintf("unsupported neat-python configuration parameter ignored: %s", key))
+	}
+	return config.Options, nil
+}
+
+// LoadINIConfig is to load NEAT options and seed genome parameters from neat-python configuration file (.ini).
+// The per gene mutation rates of neat-python are mapped onto the per genome mutation probabilities, and the options
+// which are not defined by neat-python get defaults close to its reproduction scheme: offspring always produced by
+// mating followed by mutation.
+func LoadINIConfig(r io.Reader) (*INIConfig, error) {
+	sections, err := readINISections(r)
+	if err != nil {
+		return nil, err
+	}
+	config := &INIConfig{
+		Options: &Options{
+			MateMultipointProb: 1.0,
+			NewLinkTries:       20,
+			AgeSignificance:    1.0,
+			SurvivalThresh:     0.2,
+			DropOffAge:         15,
+			NumRuns:            1,
+			EpochExecutorType:  EpochExecutorTypeSequential,
+			GenCompatMethod:    GenomeCompatibilityMethodLinear,
+			LogLevel:           string(LogLevelInfo),
+		},
+		Genome: &INIGenomeConfig{
+			InitialConnection:  INIConnectionUnconnected,
+			FeedForward:        true,
+			ActivationDefault:  math.SigmoidSteepenedActivation,
+			AggregationDefault: math.SumAggregation,
+			ResponseInitMean:   1.0,
+			WeightInitStdev:    1.0,
+			EnabledDefault:     true,
+		},
+		UnsupportedKeys: make([]string, 0),
+	}
+	for section, params := range sections {
+		for key, value := range params {
+			var supported bool
+			switch section {
+			case iniSectionNEAT:
+				supported, err = config.setNEATParam(key, value)
+			case iniSectionGenome:
+				supported, err = config.setGenomeParam(key, value)
+			case iniSectionSpeciesSet:
+				supported, err = config.setSpeciesSetParam(key, value)
+			case iniSectionStagnation:
+				supported, err = config.setStagnationParam(key, value)
+			case iniSectionReproduction:
+				supported, err = config.setReproductionParam(key, value)
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read parameter: %s.%s = %s", section, key, value)
+			}
+			if !supported {
+				config.UnsupportedKeys = append(config.UnsupportedKeys, fmt.Sprintf("%s.%s", section, key))
+			}
+		}
+	}
+	sort.Strings(config.UnsupportedKeys)
+
+	if err = config.Genome.InitialConnection.Validate(); err != nil {
+		return nil, err
+	}
+	if config.Genome.NumInputs <= 0 || config.Genome.NumOutputs <= 0 {
+		return nil, errors.Errorf("number of inputs: %d and outputs: %d must be positive",
+			config.Genome.NumInputs, config.Genome.NumOutputs)
+	}
+
+	// initialize logger
+	if err = InitLogger(config.Options.LogLevel); err != nil {
+		return nil, errors.Wrap(err, "failed to initialize logger")
+	}
+	if err = config.Options.initNodeActivators(); err != nil {
+		return nil, err
+	}
+	if err = config.Options.initNodeAggregators(); err != nil {
+		return nil, err
+	}
+	if err = config.Options.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (c *INIConfig) setNEATParam(key, value string) (bool, error) {
+	var err error
+	switch key {
+	case "pop_size":
+		c.Options.PopSize, err = cast.ToIntE(value)
+	default:
+		return false, nil
+	}
+	return true, err
+}
+
+func (c *INIConfig) setGenomeParam(key, value string) (bool, error) {
+	var err error
+	opts, genome := c.Options, c.Genome
+	switch key {
+	case "num_inputs":
+		genome.NumInputs, err = cast.ToIntE(value)
+	case "num_outputs":
+		genome.NumOutputs, err = cast.ToIntE(value)
+	case "num_hidden":
+		genome.NumHidden, err = cast.ToIntE(value)
+	case "initial_connection":
+		err = genome.setInitialConnection(value)
+	case "feed_forward":
+		if genome.FeedForward, err = parseINIBool(value); err == nil && !genome.FeedForward {
+			opts.RecurOnlyProb = iniRecurrentLinkProb
+		}
+	case "activation_default":
+		genome.ActivationDefault, err = neatPythonActivation(value)
+	case "activation_options":
+		opts.NodeActivatorsWithProbs, err = neatPythonActivatorsWithProbs(value)
+	case "activation_mutate_rate":
+		opts.MutateNodeActivationProb, err = cast.ToFloat64E(value)
+	case "aggregation_default":
+		genome.AggregationDefault, err = neatPythonAggregation(value)
+	case "aggregation_options":
+		opts.NodeAggregatorsWithProbs, err = neatPythonAggregatorsWithProbs(value)
+	case "aggregation_mutate_rate":
+		opts.MutateNodeAggregationProb, err = cast.ToFloat64E(value)
+	case "bias_init_mean":
+		genome.BiasInitMean, err = cast.ToFloat64E(value)
+	case "bias_init_stdev":
+		genome.BiasInitStdev, err = cast.ToFloat64E(value)
+	case "bias_mutate_power":
+		opts.BiasMutPower, err = cast.ToFloat64E(value)
+	case "bias_mutate_rate":
+		opts.MutateNodeBiasProb, err = cast.ToFloat64E(value)
+	case "response_init_mean":
+		genome.ResponseInitMean, err = cast.ToFloat64E(value)
+	case "response_init_stdev":
+		genome.ResponseInitStdev, err = cast.ToFloat64E(value)
+	case "response_mutate_power":
+		opts.ResponseMutPower, err = cast.ToFloat64E(value)
+	case "response_mutate_rate":
+		opts.MutateNodeResponseProb, err = cast.ToFloat64E(value)
+	case "weight_init_mean":
+		genome.WeightInitMean, err = cast.ToFloat64E(value)
+	case "weight_init_stdev":
+		genome.WeightInitStdev, err = cast.ToFloat64E(value)
+	case "weight_mutate_power":
+		opts.WeightMutPower, err = cast.ToFloat64E(value)
+	case "weight_mutate_rate":
+		opts.MutateLinkWeightsProb, err = cast.ToFloat64E(value)
+	case "enabled_default":
+		genome.EnabledDefault, err = parseINIBool(value)
+	case "enabled_mutate_rate":
+		opts.MutateToggleEnableProb, err = cast.ToFloat64E(value)
+	case "compatibility_disjoint_coefficient":
+		// neat-python doesn't distinguish disjoint and excess genes
+		if opts.DisjointCoeff, err = cast.ToFloat64E(value); err == nil {
+			opts.ExcessCoeff = opts.DisjointCoeff
+		}
+	case "compatibility_weight_coefficient":
+		opts.MutdiffCoeff, err = cast.ToFloat64E(value)
+	case "conn_add_prob":
+		opts.MutateAddLinkProb, err = cast.ToFloat64E(value)
+	case "conn_delete_prob":
+		opts.MutateDeleteLinkProb, err = cast.ToFloat64E(value)
+	case "node_add_prob":
+		opts.MutateAddNodeProb, err = cast.ToFloat64E(value)
+	case "node_delete_prob":
+		opts.MutateDeleteNodeProb, err = cast.ToFloat64E(value)
+	default:
+		return false, nil
+	}
+	return true, err
+}
+
+func (c *INIConfig) setSpeciesSetParam(key, value string) (bool, error) {
+	var err error
+	switch key {
+	case "compatibility_threshold":
+		c.Options.CompatThreshold, err = cast.ToFloat64E(value)
+	default:
+		return false, nil
+	}
+	return true, err
+}
+
+func (c *INIConfig) setStagnationParam(key, value string) (bool, error) {
+	var err error
+	switch key {
+	case "max_stagnation":
+		c.Options.DropOffAge, err = cast.ToIntE(value)
+	default:
+		return false, nil
+	}
+	return true, err
+}
+
+func (c *INIConfig) setReproductionParam(key, value string) (bool, error) {
+	var err error
+	switch key {
+	case "survival_threshold":
+		c.Options.SurvivalThresh, err = cast.ToFloat64E(value)
+	default:
+		return false, nil
+	}
+	return true, err
+}
+
+// setInitialConnection parses the initial connection scheme with optional connection probability, e.g. "partial 0.5"
+func (g *INIGenomeConfig) setInitialConnection(value string) error {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return errors.New("initial connection scheme is empty")
+	}
+	g.InitialConnection = INIInitialConnection(fields[0])
+	if err := g.InitialConnection.Validate(); err != nil {
+		return err
+	}
+	if !g.InitialConnection.IsPartial() {
+		return nil
+	}
+	if len(fields) != 2 {
+		return errors.Errorf("connection probability expected for initial connection scheme: [%s]", value)
+	}
+	var err error
+	if g.ConnectionFraction, err = cast.ToFloat64E(fields[1]); err != nil {
+		return err
+	}
+	if g.ConnectionFraction < 0 || g.ConnectionFraction > 1 {
+		return errors.Errorf("connection probability out of range [0;1]: %f", g.ConnectionFraction)
+	}
+	return nil
+}
+
+func neatPythonActivation(name string) (math.NodeActivationType, error) {
+	if aType, ok := neatPythonActivations[name]; ok {
+		return aType, nil
+	}
+	return 0, errors.Errorf("unsupported neat-python activation function: %s", name)
+}
+
+func neatPythonAggregation(name string) (math.NodeAggregationType, error) {
+	if aType, ok := neatPythonAggregations[name]; ok {
+		return aType, nil
+	}
+	return 0, errors.Errorf("unsupported neat-python aggregation function: %s", name)
+}
+
+// neatPythonFunctionsWithProbs converts the list of neat-python activation functions names into the list of
+// activators with equal probabilities of selection
+func neatPythonActivatorsWithProbs(value string) ([]string, error) {
+	names := strings.Fields(value)
+	res := make([]string, len(names))
+	for i, name := range names {
+		aType, err := neatPythonActivation(name)
+		if err != nil {
+			return nil, err
+		}
+		if name, err = math.NodeActivators.ActivationNameFromType(aType); err != nil {
+			return nil, err
+		}
+		res[i] = fmt.Sprintf("%s %g", name, 1.0/float64(len(names)))
+	}
+	return res, nil
+}
+
+// neatPythonAggregatorsWithProbs converts the list of neat-python aggregation functions names into the list of
+// aggregators with equal probabilities of selection
+func neatPythonAggregatorsWithProbs(value string) ([]string, error) {
+	names := strings.Fields(value)
+	res := make([]string, len(names))
+	for i, name := range names {
+		aType, err := neatPythonAggregation(name)
+		if err != nil {
+			return nil, err
+		}
+		if name, err = math.NodeAggregators.AggregationNameFromType(aType); err != nil {
+			return nil, err
+		}
+		res[i] = fmt.Sprintf("%s %g", name, 1.0/float64(len(names)))
+	}
+	return res, nil
+}
+
+// parseINIBool parses boolean value as Python's configparser does
+func parseINIBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "1", "yes", "true", "on":
+		return true, nil
+	case "0", "no", "false", "off":
+		return false, nil
+	default:
+		return false, errors.Errorf("not a boolean: %s", value)
+	}
+}
+
+// readINISections reads the INI file into the map of sections with key-value parameters. The comment lines start
+// with '#' or ';', the indented lines continue the value of the previous key.
+func readINISections(r io.Reader) (map[string]map[string]string, error) {
+	sections := make(map[string]map[string]string)
+	var params map[string]string
+	var lastKey string
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			section := strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+			if _, ok := sections[section]; ok {
+				return nil, errors.Errorf("duplicate section: [%s] at line: %d", section, lineNum)
+			}
+			params = make(map[string]string)
+			sections[section] = params
+			lastKey = ""
+			continue
+		}
+		if params == nil {
+			return nil, errors.Errorf("parameter outside of section at line: %d", lineNum)
+		}
+		if lastKey != "" && (line[0] == ' ' || line[0] == '\t') {
+			// continuation of the multiline value
+			params[lastKey] = params[lastKey] + " " + trimmed
+			continue
+		}
+		index := strings.IndexAny(trimmed, "=:")
+		if index < 0 {
+			return nil, errors.Errorf("malformed parameter: [%s] at line: %d", trimmed, lineNum)
+		}
+		lastKey = strings.TrimSpace(trimmed[:index])
+		params[lastKey] = strings.TrimSpace(trimmed[index+1:])
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return sections, nil
+}
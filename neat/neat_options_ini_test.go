@@ -0,0 +1,139 @@
+package neat
+
+import (
+	"deepneat/neat/math"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const neatPythonConfigFile = "../config-neat.ini"
+
+func TestLoadINIConfig(t *testing.T) {
+	config, err := os.Open(neatPythonConfigFile)
+	require.NoError(t, err)
+
+	conf, err := LoadINIConfig(config)
+	require.NoError(t, err, "failed to load INI config")
+
+	opts := conf.Options
+	assert.Equal(t, 500, opts.PopSize)
+	assert.Equal(t, 0.0, opts.RecurOnlyProb)
+	assert.Equal(t, 0.1, opts.MutateNodeActivationProb)
+	assert.Equal(t, 0.2, opts.MutateNodeAggregationProb)
+	assert.Equal(t, 0.5, opts.BiasMutPower)
+	assert.Equal(t, 0.5, opts.MutateNodeBiasProb)
+	assert.Equal(t, 1.0, opts.WeightMutPower)
+	assert.Equal(t, 0.5, opts.MutateLinkWeightsProb)
+	assert.Equal(t, 0.5, opts.ResponseMutPower)
+	assert.Equal(t, 0.7, opts.MutateNodeResponseProb)
+	assert.Equal(t, 1.0, opts.DisjointCoeff)
+	assert.Equal(t, 1.0, opts.ExcessCoeff)
+	assert.Equal(t, 0.5, opts.MutdiffCoeff)
+	assert.Equal(t, 0.5, opts.MutateAddLinkProb)
+	assert.Equal(t, 0.1, opts.MutateDeleteLinkProb)
+	assert.Equal(t, 0.4, opts.MutateAddNodeProb)
+	assert.Equal(t, 0.1, opts.MutateDeleteNodeProb)
+	assert.Equal(t, 0.01, opts.MutateToggleEnableProb)
+	assert.Equal(t, 2.5, opts.CompatThreshold)
+	assert.Equal(t, 15, opts.DropOffAge)
+	assert.Equal(t, EpochExecutorTypeSequential, opts.EpochExecutorType)
+
+	expectedActivators := []math.NodeActivationType{math.ReLUActivation, math.TanhActivation, math.SigmoidSteepenedActivation}
+	assert.Equal(t, expectedActivators, opts.NodeActivators)
+	assert.Len(t, opts.NodeActivatorsProb, 3)
+	expectedAggregators := []math.NodeAggregationType{math.SumAggregation, math.ProductAggregation, math.MeanAggregation}
+	assert.Equal(t, expectedAggregators, opts.NodeAggregators)
+	assert.Len(t, opts.NodeAggregatorsProb, 3)
+
+	genome := conf.Genome
+	assert.Equal(t, 10, genome.NumInputs)
+	assert.Equal(t, 3, genome.NumOutputs)
+	assert.Equal(t, 10, genome.NumHidden)
+	assert.Equal(t, INIConnectionFullNoDirect, genome.InitialConnection)
+	assert.True(t, genome.FeedForward)
+	assert.Equal(t, math.SigmoidSteepenedActivation, genome.ActivationDefault)
+	assert.Equal(t, math.SumAggregation, genome.AggregationDefault)
+	assert.Equal(t, 1.0, genome.BiasInitStdev)
+	assert.Equal(t, 1.0, genome.ResponseInitMean)
+	assert.Equal(t, 1.0, genome.WeightInitStdev)
+	assert.True(t, genome.EnabledDefault)
+
+	expectedUnsupported := []string{
+		"DefaultGenome.aggregation_replace_rate",
+		"DefaultGenome.bias_max_value",
+		"DefaultGenome.bias_min_value",
+		"DefaultGenome.bias_replace_rate",
+		"DefaultGenome.response_max_value",
+		"DefaultGenome.response_min_value",
+		"DefaultGenome.response_replace_rate",
+		"DefaultGenome.weight_max_value",
+		"DefaultGenome.weight_min_value",
+		"DefaultGenome.weight_replace_rate",
+		"NEAT.fitness_criterion",
+		"NEAT.fitness_threshold",
+		"NEAT.reset_on_extinction",
+	}
+	assert.Equal(t, expectedUnsupported, conf.UnsupportedKeys)
+}
+
+func TestLoadINIConfig_partialConnection(t *testing.T) {
+	ini := `
+[NEAT]
+pop_size = 50
+
+[DefaultGenome]
+num_inputs = 2
+num_outputs = 1
+initial_connection = partial_direct 0.5
+feed_forward = False
+activation_options = tanh
+    gauss
+[DefaultSpeciesSet]
+compatibility_threshold = 3.0
+[DefaultStagnation]
+max_stagnation = 20
+species_elitism = 2
+[DefaultReproduction]
+survival_threshold = 0.3
+`
+	conf, err := LoadINIConfig(strings.NewReader(ini))
+	require.NoError(t, err, "failed to load INI config")
+	assert.Equal(t, INIConnectionPartialDirect, conf.Genome.InitialConnection)
+	assert.Equal(t, 0.5, conf.Genome.ConnectionFraction)
+	assert.False(t, conf.Genome.FeedForward)
+	assert.Equal(t, iniRecurrentLinkProb, conf.Options.RecurOnlyProb)
+	assert.Equal(t, []math.NodeActivationType{math.TanhActivation, math.GaussianActivation}, conf.Options.NodeActivators)
+	assert.Equal(t, []float64{0.5, 0.5}, conf.Options.NodeActivatorsProb)
+	assert.Equal(t, 3.0, conf.Options.CompatThreshold)
+	assert.Equal(t, 20, conf.Options.DropOffAge)
+	assert.Equal(t, 0.3, conf.Options.SurvivalThresh)
+	assert.Equal(t, []string{"DefaultStagnation.species_elitism"}, conf.UnsupportedKeys)
+}
+
+func TestLoadINIConfig_errors(t *testing.T) {
+	testCases := map[string]string{
+		"no section":             "pop_size = 10\n",
+		"malformed":              "[NEAT]\npop_size\n",
+		"duplicate section":      "[NEAT]\n[NEAT]\n",
+		"wrong number":           "[NEAT]\npop_size = ten\n",
+		"unsupported activation": "[DefaultGenome]\nnum_inputs = 1\nnum_outputs = 1\nactivation_default = cube\n",
+		"unsupported connection": "[DefaultGenome]\nnum_inputs = 1\nnum_outputs = 1\ninitial_connection = fs_neat_hidden\n",
+		"no probability":         "[DefaultGenome]\nnum_inputs = 1\nnum_outputs = 1\ninitial_connection = partial\n",
+		"no inputs":              "[DefaultGenome]\nnum_outputs = 1\n",
+		"wrong boolean":          "[DefaultGenome]\nnum_inputs = 1\nnum_outputs = 1\nfeed_forward = maybe\n",
+	}
+	for name, ini := range testCases {
+		_, err := LoadINIConfig(strings.NewReader(ini))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestReadNeatOptionsFromFile_ini(t *testing.T) {
+	opts, err := ReadNeatOptionsFromFile(neatPythonConfigFile)
+	require.NoError(t, err)
+	assert.Equal(t, 500, opts.PopSize)
+}
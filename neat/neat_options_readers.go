@@ -33,6 +33,10 @@ func LoadYAMLOptions(r io.Reader) (*Options, error) {
 	if err = opts.initNodeActivators(); err != nil {
 		return nil, errors.Wrap(err, "failed to read node activators")
 	}
+	// read node aggregators
+	if err = opts.initNodeAggregators(); err != nil {
+		return nil, errors.Wrap(err, "failed to read node aggregators")
+	}
 
 	if err = opts.Validate(); err != nil {
 		return nil, errors.Wrap(err, "invalid NEAT options")
@@ -61,18 +65,36 @@ func LoadNeatOptions(r io.Reader) (*Options, error) {
 			c.TraitMutationPower = cast.ToFloat64(param)
 		case "weight_mut_power":
 			c.WeightMutPower = cast.ToFloat64(param)
+		case "bias_mut_power":
+			c.BiasMutPower = cast.ToFloat64(param)
+		case "response_mut_power":
+			c.ResponseMutPower = cast.ToFloat64(param)
 		case "disjoint_coeff":
 			c.DisjointCoeff = cast.ToFloat64(param)
 		case "excess_coeff":
 			c.ExcessCoeff = cast.ToFloat64(param)
 		case "mutdiff_coeff":
 			c.MutdiffCoeff = cast.ToFloat64(param)
+		case "activation_diff_coeff":
+			c.ActivationDiffCoeff = cast.ToFloat64(param)
 		case "compat_threshold":
 			c.CompatThreshold = cast.ToFloat64(param)
+		case "species_count_target":
+			c.SpeciesCountTarget = cast.ToInt(param)
+		case "compat_threshold_step":
+			c.CompatThresholdStep = cast.ToFloat64(param)
+		case "compat_threshold_min":
+			c.CompatThresholdMin = cast.ToFloat64(param)
+		case "compat_threshold_max":
+			c.CompatThresholdMax = cast.ToFloat64(param)
 		case "age_significance":
 			c.AgeSignificance = cast.ToFloat64(param)
 		case "survival_thresh":
 			c.SurvivalThresh = cast.ToFloat64(param)
+		case "parent_selection":
+			c.ParentSelection = ParentSelection(param)
+		case "tournament_size":
+			c.TournamentSize = cast.ToInt(param)
 		case "mutate_only_prob":
 			c.MutateOnlyProb = cast.ToFloat64(param)
 		case "mutate_random_trait_prob":
@@ -87,12 +109,30 @@ func LoadNeatOptions(r io.Reader) (*Options, error) {
 			c.MutateToggleEnableProb = cast.ToFloat64(param)
 		case "mutate_gene_reenable_prob":
 			c.MutateGeneReenableProb = cast.ToFloat64(param)
+		case "mutate_node_bias_prob":
+			c.MutateNodeBiasProb = cast.ToFloat64(param)
+		case "mutate_node_response_prob":
+			c.MutateNodeResponseProb = cast.ToFloat64(param)
+		case "mutate_node_aggregation_prob":
+			c.MutateNodeAggregationProb = cast.ToFloat64(param)
+		case "mutate_node_activation_prob":
+			c.MutateNodeActivationProb = cast.ToFloat64(param)
 		case "mutate_add_node_prob":
 			c.MutateAddNodeProb = cast.ToFloat64(param)
 		case "mutate_add_link_prob":
 			c.MutateAddLinkProb = cast.ToFloat64(param)
 		case "mutate_connect_sensors":
 			c.MutateConnectSensors = cast.ToFloat64(param)
+		case "mutate_delete_link_prob":
+			c.MutateDeleteLinkProb = cast.ToFloat64(param)
+		case "mutate_delete_node_prob":
+			c.MutateDeleteNodeProb = cast.ToFloat64(param)
+		case "mutate_add_module_prob":
+			c.MutateAddModuleProb = cast.ToFloat64(param)
+		case "mutate_remove_module_prob":
+			c.MutateRemoveModuleProb = cast.ToFloat64(param)
+		case "mutate_rewire_module_prob":
+			c.MutateRewireModuleProb = cast.ToFloat64(param)
 		case "interspecies_mate_rate":
 			c.InterspeciesMateRate = cast.ToFloat64(param)
 		case "mate_multipoint_prob":
@@ -111,18 +151,74 @@ func LoadNeatOptions(r io.Reader) (*Options, error) {
 			c.DropOffAge = cast.ToInt(param)
 		case "newlink_tries":
 			c.NewLinkTries = cast.ToInt(param)
+		case "phased_search_complexity_ceiling":
+			c.PhasedSearchComplexityCeiling = cast.ToFloat64(param)
+		case "phased_search_simplify_generations":
+			c.PhasedSearchSimplifyGenerations = cast.ToInt(param)
 		case "print_every":
 			c.PrintEvery = cast.ToInt(param)
+		case "checkpoint_every":
+			c.CheckpointEvery = cast.ToInt(param)
 		case "babies_stolen":
 			c.BabiesStolen = cast.ToInt(param)
+		case "species_fitness_func":
+			c.SpeciesFitnessFunc = SpeciesFitnessFunc(param)
+		case "protected_species":
+			c.ProtectedSpecies = cast.ToInt(param)
+		case "species_elitism":
+			c.SpeciesElitism = cast.ToInt(param)
+		case "species_elitism_threshold":
+			c.SpeciesElitismThreshold = cast.ToInt(param)
+		case "population_elitism":
+			c.PopulationElitism = cast.ToInt(param)
 		case "num_runs":
 			c.NumRuns = cast.ToInt(param)
 		case "num_generations":
 			c.NumGenerations = cast.ToInt(param)
 		case "epoch_executor":
 			c.EpochExecutorType = EpochExecutorType(param)
+		case "evaluation_workers":
+			c.EvaluationWorkers = cast.ToInt(param)
+		case "evaluation_repeats":
+			c.EvaluationRepeats = cast.ToInt(param)
+		case "fitness_aggregation":
+			c.FitnessAggregation = FitnessAggregation(param)
+		case "reevaluate_elites":
+			c.ReevaluateElites = cast.ToBool(param)
+		case "plasticity_rule":
+			c.PlasticityRule = PlasticityRule(param)
+		case "plastic_weight_bound":
+			c.PlasticWeightBound = cast.ToFloat64(param)
+		case "gradient_tuning_mode":
+			c.GradientTuningMode = GradientTuningMode(param)
+		case "gradient_tuning_epochs":
+			c.GradientTuningEpochs = cast.ToInt(param)
+		case "gradient_optimizer":
+			c.GradientOptimizer = GradientOptimizer(param)
+		case "gradient_learning_rate":
+			c.GradientLearningRate = cast.ToFloat64(param)
 		case "genome_compat_method":
 			c.GenCompatMethod = GenomeCompatibilityMethod(param)
+		case "novelty_search_mode":
+			c.NoveltySearchMode = NoveltySearchMode(param)
+		case "novelty_k_nearest":
+			c.NoveltyKNearest = cast.ToInt(param)
+		case "novelty_archive_threshold":
+			c.NoveltyArchiveThreshold = cast.ToFloat64(param)
+		case "novelty_archive_size":
+			c.NoveltyArchiveSize = cast.ToInt(param)
+		case "novelty_fitness_blend":
+			c.NoveltyFitnessBlend = cast.ToFloat64(param)
+		case "pareto_selection":
+			c.ParetoSelection = cast.ToBool(param)
+		case "lineage_tracking":
+			c.LineageTracking = cast.ToBool(param)
+		case "hall_of_fame_size":
+			c.HallOfFameSize = cast.ToInt(param)
+		case "hall_of_fame_reinjection":
+			c.HallOfFameReinjection = HallOfFameReinjection(param)
+		case "hall_of_fame_reinject_count":
+			c.HallOfFameReinjectCount = cast.ToInt(param)
 		case "log_level":
 			c.LogLevel = param
 		default:
@@ -137,6 +233,9 @@ func LoadNeatOptions(r io.Reader) (*Options, error) {
 	if err := c.initNodeActivators(); err != nil {
 		return nil, err
 	}
+	if err := c.initNodeAggregators(); err != nil {
+		return nil, err
+	}
 	if err := c.Validate(); err != nil {
 		return nil, err
 	}
@@ -153,6 +252,8 @@ func ReadNeatOptionsFromFile(configFilePath string) (*Options, error) {
 	fileName := configFile.Name()
 	if strings.HasSuffix(fileName, "yml") || strings.HasSuffix(fileName, "yaml") {
 		return LoadYAMLOptions(configFile)
+	} else if strings.HasSuffix(fileName, "ini") {
+		return LoadINIOptions(configFile)
 	} else {
 		return LoadNeatOptions(configFile)
 	}
@@ -180,3 +281,29 @@ func (c *Options) initNodeActivators() (err error) {
 	}
 	return nil
 }
+
+// set default values for aggregator type and its probability of selection
+func (c *Options) initNodeAggregators() (err error) {
+	if len(c.NodeAggregatorsWithProbs) == 0 {
+		c.NodeAggregators = []math.NodeAggregationType{math.SumAggregation}
+		c.NodeAggregatorsProb = []float64{1.0}
+		return nil
+	}
+	// create aggregators
+	aggrFns := c.NodeAggregatorsWithProbs
+	c.NodeAggregators = make([]math.NodeAggregationType, len(aggrFns))
+	c.NodeAggregatorsProb = make([]float64, len(aggrFns))
+	for i, line := range aggrFns {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return errors.Errorf("node aggregator must be defined with name and probability: %s", line)
+		}
+		if c.NodeAggregators[i], err = math.NodeAggregators.AggregationTypeFromName(fields[0]); err != nil {
+			return err
+		}
+		if c.NodeAggregatorsProb[i], err = strconv.ParseFloat(fields[1], 64); err != nil {
+			return err
+		}
+	}
+	return nil
+}
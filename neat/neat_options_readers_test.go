@@ -14,6 +14,10 @@ const (
 	alwaysErrorText     = "always be failing"
 	xorOptionsFilePlain = "../data/xor_test.neat"
 	xorOptionsFileYaml  = "../data/xor_test.neat.yml"
+	// the options introduced by extensions of the algorithm, they are kept apart from the XOR options used by
+	// experiment tests to not change their behavior
+	extOptionsFilePlain = "../data/options_test.neat"
+	extOptionsFileYaml  = "../data/options_test.neat.yml"
 )
 
 var errFoo = errors.New(alwaysErrorText)
@@ -63,6 +67,33 @@ func TestLoadYAMLOptions(t *testing.T) {
 	}
 }
 
+func TestLoadNeatOptions_extensions(t *testing.T) {
+	config, err := os.Open(extOptionsFilePlain)
+	require.NoError(t, err)
+
+	opts, err := LoadNeatOptions(config)
+	require.NoError(t, err)
+	checkExtensionsNeatOptions(opts, t)
+}
+
+func TestLoadYAMLOptions_extensions(t *testing.T) {
+	config, err := os.Open(extOptionsFileYaml)
+	require.NoError(t, err)
+
+	opts, err := LoadYAMLOptions(config)
+	require.NoError(t, err, "failed to load options")
+	checkExtensionsNeatOptions(opts, t)
+
+	// check aggregators
+	require.Len(t, opts.NodeAggregators, 3, "wrong node aggregators size")
+	aggregators := []math.NodeAggregationType{math.SumAggregation, math.ProductAggregation, math.MeanAggregation}
+	probs := []float64{0.6, 0.2, 0.2}
+	for i, a := range aggregators {
+		assert.Equal(t, a, opts.NodeAggregators[i], "wrong node aggregator type at: %d", i)
+		assert.Equal(t, probs[i], opts.NodeAggregatorsProb[i], "wrong probability at: %d", i)
+	}
+}
+
 func TestLoadYAMLOptions_readError(t *testing.T) {
 	errorReader := ErrorReader(1)
 	opts, err := LoadYAMLOptions(&errorReader)
@@ -122,3 +153,17 @@ func checkNeatOptions(nc *Options, t *testing.T) {
 	assert.Equal(t, EpochExecutorTypeSequential, nc.EpochExecutorType)
 	assert.Equal(t, GenomeCompatibilityMethodFast, nc.GenCompatMethod)
 }
+
+func checkExtensionsNeatOptions(nc *Options, t *testing.T) {
+	assert.Equal(t, 0.5, nc.BiasMutPower)
+	assert.Equal(t, 0.1, nc.ResponseMutPower)
+	assert.Equal(t, 0.5, nc.MutateNodeBiasProb)
+	assert.Equal(t, 0.2, nc.MutateNodeResponseProb)
+	assert.Equal(t, 0.1, nc.MutateNodeAggregationProb)
+	assert.Equal(t, 0.05, nc.MutateNodeActivationProb)
+	assert.Equal(t, 0.5, nc.ActivationDiffCoeff)
+	assert.Equal(t, 0.01, nc.MutateAddModuleProb)
+	assert.Equal(t, 0.005, nc.MutateRemoveModuleProb)
+	assert.Equal(t, 0.02, nc.MutateRewireModuleProb)
+	assert.Equal(t, 4, nc.EvaluationWorkers)
+}
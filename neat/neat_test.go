@@ -2,6 +2,7 @@ package neat
 
 import (
 	"deepneat/neat/math"
+	"math/rand"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -65,3 +66,289 @@ func TestOptions_RandomNodeActivationType(t *testing.T) {
 	res := activator == math.SigmoidApproximationActivation || activator == math.SigmoidBipolarActivation
 	assert.True(t, res)
 }
+
+func TestOptions_RandomNodeActivationType_reproducible(t *testing.T) {
+	opts := &Options{
+		NodeActivators: []math.NodeActivationType{
+			math.SigmoidApproximationActivation, math.SigmoidBipolarActivation, math.GaussianActivation},
+		NodeActivatorsProb: []float64{0.3, 0.3, 0.4},
+	}
+	opts1 := opts.WithRandSource(rand.New(rand.NewSource(42)))
+	opts2 := opts.WithRandSource(rand.New(rand.NewSource(42)))
+	for i := 0; i < 100; i++ {
+		activator1, err := opts1.RandomNodeActivationType()
+		require.NoError(t, err)
+		activator2, err := opts2.RandomNodeActivationType()
+		require.NoError(t, err)
+		require.Equal(t, activator1, activator2, "at: %d", i)
+	}
+	// the original options must be left intact
+	assert.Nil(t, opts.RandSource)
+	assert.Equal(t, math.GlobalRand, opts.Rand())
+}
+
+func TestOptions_Validate_noveltyWithPareto(t *testing.T) {
+	opts := &Options{
+		EpochExecutorType:  EpochExecutorTypeSequential,
+		GenCompatMethod:    GenomeCompatibilityMethodFast,
+		NoveltySearchMode:  NoveltySearchModeNovelty,
+		NoveltyKNearest:    3,
+		NodeActivators:     []math.NodeActivationType{math.SigmoidSteepenedActivation},
+		NodeActivatorsProb: []float64{1.0},
+	}
+	require.NoError(t, opts.Validate())
+
+	opts.ParetoSelection = true
+	assert.Error(t, opts.Validate())
+}
+
+func TestOptions_Validate_phasedSearch(t *testing.T) {
+	opts := &Options{
+		EpochExecutorType:             EpochExecutorTypeSequential,
+		GenCompatMethod:               GenomeCompatibilityMethodFast,
+		PhasedSearchComplexityCeiling: 10,
+		NodeActivators:                []math.NodeActivationType{math.SigmoidSteepenedActivation},
+		NodeActivatorsProb:            []float64{1.0},
+	}
+	assert.Error(t, opts.Validate(), "simplification generations must be set")
+
+	opts.PhasedSearchSimplifyGenerations = 5
+	assert.NoError(t, opts.Validate())
+
+	opts.MutateDeleteNodeProb = 1.5
+	assert.Error(t, opts.Validate())
+}
+
+func TestOptions_Validate_moduleMutations(t *testing.T) {
+	opts := &Options{
+		EpochExecutorType:  EpochExecutorTypeSequential,
+		GenCompatMethod:    GenomeCompatibilityMethodFast,
+		NodeActivators:     []math.NodeActivationType{math.SigmoidSteepenedActivation},
+		NodeActivatorsProb: []float64{1.0},
+	}
+	opts.MutateAddModuleProb, opts.MutateRemoveModuleProb, opts.MutateRewireModuleProb = 0.1, 0.1, 0.1
+	assert.NoError(t, opts.Validate())
+
+	opts.MutateAddModuleProb = 1.1
+	assert.Error(t, opts.Validate())
+
+	opts.MutateAddModuleProb = 0.1
+	opts.MutateRemoveModuleProb = -0.1
+	assert.Error(t, opts.Validate())
+
+	opts.MutateRemoveModuleProb = 0.1
+	opts.MutateRewireModuleProb = 2
+	assert.Error(t, opts.Validate())
+}
+
+func TestOptions_Validate_evaluationWorkers(t *testing.T) {
+	opts := &Options{
+		EpochExecutorType:  EpochExecutorTypeParallel,
+		GenCompatMethod:    GenomeCompatibilityMethodFast,
+		NodeActivators:     []math.NodeActivationType{math.SigmoidSteepenedActivation},
+		NodeActivatorsProb: []float64{1.0},
+		EvaluationWorkers:  8,
+	}
+	assert.NoError(t, opts.Validate())
+
+	opts.EvaluationWorkers = -1
+	assert.Error(t, opts.Validate())
+}
+
+func TestOptions_Validate_nodeMutations(t *testing.T) {
+	opts := &Options{
+		EpochExecutorType:  EpochExecutorTypeSequential,
+		GenCompatMethod:    GenomeCompatibilityMethodFast,
+		NodeActivators:     []math.NodeActivationType{math.SigmoidSteepenedActivation},
+		NodeActivatorsProb: []float64{1.0},
+	}
+	assert.NoError(t, opts.Validate())
+
+	opts.MutateNodeActivationProb = -0.1
+	assert.Error(t, opts.Validate())
+
+	opts.MutateNodeActivationProb = 0.1
+	opts.ActivationDiffCoeff = -1
+	assert.Error(t, opts.Validate())
+
+	opts.ActivationDiffCoeff = 1
+	opts.NodeAggregators = []math.NodeAggregationType{math.SumAggregation, math.MaxAggregation}
+	assert.ErrorIs(t, opts.Validate(), ErrAggregatorsProbabilitiesNumberMismatch)
+}
+
+func TestOptions_Validate_hallOfFame(t *testing.T) {
+	opts := &Options{
+		EpochExecutorType:     EpochExecutorTypeSequential,
+		GenCompatMethod:       GenomeCompatibilityMethodFast,
+		HallOfFameReinjection: HallOfFameReinjectionReplace,
+		NodeActivators:        []math.NodeActivationType{math.SigmoidSteepenedActivation},
+		NodeActivatorsProb:    []float64{1.0},
+	}
+	assert.Error(t, opts.Validate(), "hall of fame size must be set")
+
+	opts.HallOfFameSize = 10
+	assert.NoError(t, opts.Validate())
+
+	opts.HallOfFameReinjectCount = -1
+	assert.Error(t, opts.Validate())
+
+	opts.HallOfFameReinjectCount = 3
+	opts.HallOfFameReinjection = "unknown"
+	assert.Error(t, opts.Validate())
+}
+
+func TestOptions_Validate_adaptiveCompatThreshold(t *testing.T) {
+	opts := &Options{
+		EpochExecutorType:  EpochExecutorTypeSequential,
+		GenCompatMethod:    GenomeCompatibilityMethodFast,
+		CompatThreshold:    3.0,
+		SpeciesCountTarget: 10,
+		NodeActivators:     []math.NodeActivationType{math.SigmoidSteepenedActivation},
+		NodeActivatorsProb: []float64{1.0},
+	}
+	assert.Error(t, opts.Validate(), "threshold step must be set")
+
+	opts.CompatThresholdStep = 0.3
+	assert.Error(t, opts.Validate(), "threshold bounds must be set")
+
+	opts.CompatThresholdMin, opts.CompatThresholdMax = 1.0, 0.5
+	assert.Error(t, opts.Validate(), "threshold bounds must be ordered")
+
+	opts.CompatThresholdMax = 5.0
+	assert.NoError(t, opts.Validate())
+
+	opts.SpeciesCountTarget = -1
+	assert.Error(t, opts.Validate())
+}
+
+func TestOptions_Validate_stagnation(t *testing.T) {
+	opts := &Options{
+		EpochExecutorType:  EpochExecutorTypeSequential,
+		GenCompatMethod:    GenomeCompatibilityMethodFast,
+		PopSize:            10,
+		SpeciesFitnessFunc: SpeciesFitnessMedian,
+		ProtectedSpecies:   2,
+		SpeciesElitism:     -1,
+		PopulationElitism:  3,
+		NodeActivators:     []math.NodeActivationType{math.SigmoidSteepenedActivation},
+		NodeActivatorsProb: []float64{1.0},
+	}
+	assert.NoError(t, opts.Validate())
+
+	opts.SpeciesFitnessFunc = "mode"
+	assert.Error(t, opts.Validate(), "unsupported species fitness function")
+	opts.SpeciesFitnessFunc = SpeciesFitnessMean
+
+	opts.ProtectedSpecies = -1
+	assert.Error(t, opts.Validate())
+	opts.ProtectedSpecies = 0
+
+	opts.SpeciesElitismThreshold = -1
+	assert.Error(t, opts.Validate())
+	opts.SpeciesElitismThreshold = 0
+
+	opts.PopulationElitism = opts.PopSize
+	assert.Error(t, opts.Validate(), "population elitism must be less than population size")
+	opts.PopulationElitism = -1
+	assert.Error(t, opts.Validate())
+}
+
+func TestOptions_Validate_parentSelection(t *testing.T) {
+	opts := &Options{
+		EpochExecutorType:  EpochExecutorTypeSequential,
+		GenCompatMethod:    GenomeCompatibilityMethodFast,
+		ParentSelection:    ParentSelectionTournament,
+		NodeActivators:     []math.NodeActivationType{math.SigmoidSteepenedActivation},
+		NodeActivatorsProb: []float64{1.0},
+	}
+	assert.Error(t, opts.Validate(), "tournament size must be set")
+
+	opts.TournamentSize = 3
+	assert.NoError(t, opts.Validate())
+
+	opts.ParentSelection = "lottery"
+	assert.Error(t, opts.Validate(), "unsupported parent selection scheme")
+
+	for _, scheme := range []ParentSelection{"", ParentSelectionTruncation, ParentSelectionRoulette, ParentSelectionRank} {
+		opts.ParentSelection = scheme
+		assert.NoError(t, opts.Validate(), scheme)
+		assert.Equal(t, scheme == "" || scheme == ParentSelectionTruncation, scheme.IsTruncation(), scheme)
+	}
+}
+
+func TestOptions_Validate_noisyFitness(t *testing.T) {
+	opts := &Options{
+		EpochExecutorType:  EpochExecutorTypeSequential,
+		GenCompatMethod:    GenomeCompatibilityMethodFast,
+		EvaluationRepeats:  5,
+		FitnessAggregation: FitnessAggregationWorst,
+		NodeActivators:     []math.NodeActivationType{math.SigmoidSteepenedActivation},
+		NodeActivatorsProb: []float64{1.0},
+	}
+	assert.NoError(t, opts.Validate())
+	assert.True(t, opts.IsFitnessNoisy())
+
+	opts.FitnessAggregation = "best"
+	assert.Error(t, opts.Validate(), "unsupported fitness aggregation")
+	opts.FitnessAggregation = ""
+
+	opts.EvaluationRepeats = -1
+	assert.Error(t, opts.Validate())
+
+	opts.EvaluationRepeats = 1
+	assert.False(t, opts.IsFitnessNoisy())
+	opts.ReevaluateElites = true
+	assert.True(t, opts.IsFitnessNoisy())
+}
+
+func TestOptions_Validate_plasticity(t *testing.T) {
+	opts := &Options{
+		EpochExecutorType:  EpochExecutorTypeSequential,
+		GenCompatMethod:    GenomeCompatibilityMethodFast,
+		PlasticityRule:     PlasticityRuleOja,
+		PlasticWeightBound: 2.0,
+		NodeActivators:     []math.NodeActivationType{math.SigmoidSteepenedActivation},
+		NodeActivatorsProb: []float64{1.0},
+	}
+	assert.NoError(t, opts.Validate())
+
+	opts.PlasticityRule = "backprop"
+	assert.Error(t, opts.Validate(), "unsupported plasticity rule")
+	opts.PlasticityRule = ""
+
+	opts.PlasticWeightBound = -1
+	assert.Error(t, opts.Validate())
+}
+
+func TestOptions_Validate_gradientTuning(t *testing.T) {
+	opts := &Options{
+		EpochExecutorType:    EpochExecutorTypeSequential,
+		GenCompatMethod:      GenomeCompatibilityMethodFast,
+		GradientTuningMode:   GradientTuningLamarckian,
+		GradientTuningEpochs: 10,
+		GradientOptimizer:    GradientOptimizerAdam,
+		GradientLearningRate: 0.01,
+		NodeActivators:       []math.NodeActivationType{math.SigmoidSteepenedActivation},
+		NodeActivatorsProb:   []float64{1.0},
+	}
+	assert.NoError(t, opts.Validate())
+
+	opts.GradientTuningMode = "darwinian"
+	assert.Error(t, opts.Validate(), "unsupported gradient tuning mode")
+	opts.GradientTuningMode = GradientTuningBaldwinian
+
+	opts.GradientOptimizer = "rmsprop"
+	assert.Error(t, opts.Validate(), "unsupported gradient optimizer")
+	opts.GradientOptimizer = ""
+
+	opts.GradientTuningEpochs = 0
+	assert.Error(t, opts.Validate())
+	opts.GradientTuningEpochs = 10
+
+	opts.GradientLearningRate = 0
+	assert.Error(t, opts.Validate())
+
+	// not validated if tuning is disabled
+	opts.GradientTuningMode = ""
+	assert.NoError(t, opts.Validate())
+}
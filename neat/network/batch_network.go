@@ -0,0 +1,408 @@
+package network
+
+import (
+	neatmath "deepneat/neat/math"
+	"errors"
+	"fmt"
+	"math"
+
+	"gonum.org/v1/gonum/mat"
+)
+
+// ErrNetRecursiveBatchUnsupported The error to be raised when recursive activation requested from the batch solver
+// of the network that can not be ordered into layers
+var ErrNetRecursiveBatchUnsupported = errors.New(
+	"recursive activation of the batch is supported only for feed-forward networks without modules")
+
+// batchLink The incoming connection of the neuron used to collect signals for aggregation functions other than sum
+type batchLink struct {
+	// The index of source neuron
+	source int
+	// The weight of connection
+	weight float64
+}
+
+// batchLayer The layer of neurons of the feed-forward network, which can be evaluated at once, because all their
+// sources belong to the previous layers
+type batchLayer struct {
+	// The indexes of neurons in this layer
+	neurons []int
+	// The indexes of source neurons connected to the neurons of this layer
+	sources []int
+	// The weights matrix of connections with rows per source and columns per neuron of this layer
+	weights *mat.Dense
+}
+
+// BatchNetworkSolver is the network solver implementation to evaluate the whole batch of inputs at once with dense matrix
+// operations. The feed-forward networks are topologically ordered into layers and each activation evaluates all layers
+// in a single pass. The networks with recurrent links or MIMO modules can not be ordered into layers and are evaluated
+// step by step with the same semantics as FastModularNetworkSolver, but for all inputs of the batch simultaneously.
+type BatchNetworkSolver struct {
+	// A network id
+	Id int
+	// Is a name of this network
+	Name string
+
+	// The solver providing the neurons parameters, connections and modules of the network
+	solver *FastModularNetworkSolver
+	// The layers of the feed-forward network, nil if network should be evaluated step-wise
+	layers []*batchLayer
+	// The weights matrix of all connections with rows per source and columns per target neuron, used for step-wise
+	// evaluation
+	weights *mat.Dense
+	// The incoming connections per neuron, used to collect signals for aggregation functions other than sum
+	incoming [][]batchLink
+
+	// The number of inputs in the currently loaded batch
+	batchSize int
+	// The current activation values with row per each input of the batch and column per each neuron
+	signals *mat.Dense
+	// This matrix is a parallel of signals and used by step-wise evaluation to test network relaxation
+	signalsBeingProcessed *mat.Dense
+	// The buffer to collect incoming signals of neurons with aggregation function other than sum
+	aggregationInputs []float64
+}
+
+// NewBatchNetworkSolver Creates new batch network solver for the network represented by provided fast network solver.
+// The created solver has batch of single input loaded.
+func NewBatchNetworkSolver(solver *FastModularNetworkSolver) *BatchNetworkSolver {
+	s := &BatchNetworkSolver{
+		Id:       solver.Id,
+		Name:     solver.Name,
+		solver:   solver,
+		incoming: make([][]batchLink, solver.totalNeuronCount),
+	}
+	for _, conn := range solver.connections {
+		s.incoming[conn.TargetIndex] = append(s.incoming[conn.TargetIndex], batchLink{source: conn.SourceIndex, weight: conn.Weight})
+	}
+
+	if len(solver.modules) == 0 {
+		s.layers = s.buildLayers()
+	}
+	if s.layers == nil {
+		s.weights = mat.NewDense(solver.totalNeuronCount, solver.totalNeuronCount, nil)
+		for _, conn := range solver.connections {
+			s.weights.Set(conn.SourceIndex, conn.TargetIndex, s.weights.At(conn.SourceIndex, conn.TargetIndex)+conn.Weight)
+		}
+	}
+	s.resize(1)
+	return s
+}
+
+// buildLayers is to order neurons of the network into layers, such that each neuron depends only on neurons of the
+// previous layers. Returns nil if network has cycles and can not be ordered.
+func (s *BatchNetworkSolver) buildLayers() []*batchLayer {
+	sensors, total := s.solver.sensorNeuronCount, s.solver.totalNeuronCount
+	// the depth of each neuron, sensors are at zero depth
+	depths := make([]int, total)
+	// the number of incoming connections from neurons which depth is not yet known
+	pending := make([]int, total)
+	outgoing := make([][]int, total)
+	for target := sensors; target < total; target++ {
+		for _, link := range s.incoming[target] {
+			if link.source >= sensors {
+				pending[target]++
+				outgoing[link.source] = append(outgoing[link.source], target)
+			}
+		}
+	}
+	queue := make([]int, 0, total-sensors)
+	for i := sensors; i < total; i++ {
+		if pending[i] == 0 {
+			depths[i] = 1
+			queue = append(queue, i)
+		}
+	}
+	maxDepth := 0
+	for next := 0; next < len(queue); next++ {
+		source := queue[next]
+		maxDepth = max(maxDepth, depths[source])
+		for _, target := range outgoing[source] {
+			depths[target] = max(depths[target], depths[source]+1)
+			if pending[target]--; pending[target] == 0 {
+				queue = append(queue, target)
+			}
+		}
+	}
+	if len(queue) != total-sensors {
+		// the network has cycles
+		return nil
+	}
+
+	layers := make([]*batchLayer, maxDepth)
+	for i := range layers {
+		layers[i] = &batchLayer{}
+	}
+	for i := sensors; i < total; i++ {
+		layer := layers[depths[i]-1]
+		layer.neurons = append(layer.neurons, i)
+	}
+	for _, layer := range layers {
+		sourceIndexes := make(map[int]int)
+		for _, neuron := range layer.neurons {
+			for _, link := range s.incoming[neuron] {
+				if _, ok := sourceIndexes[link.source]; !ok {
+					sourceIndexes[link.source] = len(layer.sources)
+					layer.sources = append(layer.sources, link.source)
+				}
+			}
+		}
+		if len(layer.sources) == 0 {
+			continue
+		}
+		layer.weights = mat.NewDense(len(layer.sources), len(layer.neurons), nil)
+		for j, neuron := range layer.neurons {
+			for _, link := range s.incoming[neuron] {
+				row := sourceIndexes[link.source]
+				layer.weights.Set(row, j, layer.weights.At(row, j)+link.weight)
+			}
+		}
+	}
+	return layers
+}
+
+// IsLayered is to check whether this solver evaluates network layer by layer in a single pass
+func (s *BatchNetworkSolver) IsLayered() bool {
+	return s.layers != nil
+}
+
+// resize is to allocate the signals matrices for the batch of given size, the bias neurons signals are set to 1
+func (s *BatchNetworkSolver) resize(batchSize int) {
+	s.batchSize = batchSize
+	s.signals = mat.NewDense(batchSize, s.solver.totalNeuronCount, nil)
+	s.signalsBeingProcessed = mat.NewDense(batchSize, s.solver.totalNeuronCount, nil)
+	for b := 0; b < batchSize; b++ {
+		row := s.signals.RawRowView(b)
+		for i := 0; i < s.solver.biasNeuronCount; i++ {
+			row[i] = 1.0 // BIAS neuron signal
+		}
+	}
+}
+
+// activate is to apply aggregation function, response, bias and activation function of the neuron at given index.
+// The sum of incoming signals is used for neurons with sum aggregation, otherwise the incoming signals are collected
+// from the provided row of the neuron signals.
+func (s *BatchNetworkSolver) activate(index int, sum float64, row []float64) (float64, error) {
+	signal := sum
+	if s.solver.biasNeuronCount > 0 {
+		// append BIAS value to the signal if appropriate
+		signal += s.solver.biasList[index]
+	}
+	if !s.solver.isSumAggregated(index) {
+		s.aggregationInputs = s.aggregationInputs[:0]
+		for _, link := range s.incoming[index] {
+			s.aggregationInputs = append(s.aggregationInputs, row[link.source]*link.weight)
+		}
+		var err error
+		if signal, err = neatmath.NodeAggregators.AggregateByType(
+			s.aggregationInputs, s.solver.aggregationFunctions[index]); err != nil {
+			return 0, err
+		}
+	}
+	if s.solver.responseList != nil {
+		signal *= s.solver.responseList[index]
+	}
+	if s.solver.neuronBiasList != nil {
+		signal += s.solver.neuronBiasList[index]
+	}
+	return neatmath.NodeActivators.ActivateByType(signal, nil, s.solver.activationFunctions[index])
+}
+
+// layeredPass is to evaluate all layers of the feed-forward network for each input of the batch
+func (s *BatchNetworkSolver) layeredPass() (err error) {
+	for _, layer := range s.layers {
+		var sums *mat.Dense
+		if layer.weights != nil {
+			// gather signals of the layer sources and multiply by weights
+			sources := mat.NewDense(s.batchSize, len(layer.sources), nil)
+			for b := 0; b < s.batchSize; b++ {
+				row, sourcesRow := s.signals.RawRowView(b), sources.RawRowView(b)
+				for k, source := range layer.sources {
+					sourcesRow[k] = row[source]
+				}
+			}
+			sums = mat.NewDense(s.batchSize, len(layer.neurons), nil)
+			sums.Mul(sources, layer.weights)
+		}
+		for b := 0; b < s.batchSize; b++ {
+			row := s.signals.RawRowView(b)
+			for j, neuron := range layer.neurons {
+				sum := 0.0
+				if sums != nil {
+					sum = sums.At(b, j)
+				}
+				if row[neuron], err = s.activate(neuron, sum, row); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// forwardStep Performs single forward step through the network for each input of the batch and tests if network
+// become relaxed for all of them. The network considered relaxed when absolute value of the change at any given point
+// is less than maxAllowedSignalDelta during activation waves propagation.
+func (s *BatchNetworkSolver) forwardStep(maxAllowedSignalDelta float64) (isRelaxed bool, err error) {
+	// Calculate the sums of incoming signals of all neurons
+	s.signalsBeingProcessed.Mul(s.signals, s.weights)
+
+	sensors, total := s.solver.sensorNeuronCount, s.solver.totalNeuronCount
+	for b := 0; b < s.batchSize; b++ {
+		row, processedRow := s.signals.RawRowView(b), s.signalsBeingProcessed.RawRowView(b)
+		// Pass the signals through the single-valued activation functions
+		for i := sensors; i < total; i++ {
+			if processedRow[i], err = s.activate(i, processedRow[i], row); err != nil {
+				return false, err
+			}
+		}
+		// Pass the signals through each module
+		for _, module := range s.solver.modules {
+			inputs := make([]float64, len(module.InputIndexes))
+			for i, inIndex := range module.InputIndexes {
+				inputs[i] = processedRow[inIndex]
+			}
+			outputs, err := neatmath.NodeActivators.ActivateModuleByType(inputs, nil, module.ActivationType)
+			if err != nil {
+				return false, err
+			}
+			for i, outIndex := range module.OutputIndexes {
+				processedRow[outIndex] = outputs[i]
+			}
+		}
+	}
+
+	// Move all the neuron signals we changed while processing this network activation into storage.
+	isRelaxed = true
+	for b := 0; b < s.batchSize; b++ {
+		row, processedRow := s.signals.RawRowView(b), s.signalsBeingProcessed.RawRowView(b)
+		for i := sensors; i < total; i++ {
+			if maxAllowedSignalDelta > 0 && math.Abs(row[i]-processedRow[i]) > maxAllowedSignalDelta {
+				isRelaxed = false
+			}
+			row[i] = processedRow[i]
+		}
+	}
+	return isRelaxed, nil
+}
+
+// ForwardSteps Propagates activation wave for each input of the batch. The feed-forward network is evaluated in a
+// single pass through all layers, which gives the same result as the number of steps equal to the network depth.
+// Otherwise, the provided number of steps is performed.
+func (s *BatchNetworkSolver) ForwardSteps(steps int) (res bool, err error) {
+	if steps <= 0 {
+		return false, nil
+	}
+	if s.layers != nil {
+		if err = s.layeredPass(); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	for i := 0; i < steps; i++ {
+		if res, err = s.forwardStep(0); err != nil {
+			return false, err
+		}
+	}
+	return res, nil
+}
+
+// RecursiveSteps Propagates activation wave for each input of the batch in a single pass through all layers of the
+// feed-forward network. Returns ErrNetRecursiveBatchUnsupported if network has recurrent links or modules.
+func (s *BatchNetworkSolver) RecursiveSteps() (bool, error) {
+	if s.layers == nil {
+		return false, ErrNetRecursiveBatchUnsupported
+	}
+	if err := s.layeredPass(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Relax Attempts to relax network for all inputs of the batch given amount of steps until giving up. The feed-forward
+// network is always relaxed after a single pass through all layers.
+func (s *BatchNetworkSolver) Relax(maxSteps int, maxAllowedSignalDelta float64) (relaxed bool, err error) {
+	if maxSteps <= 0 {
+		return false, nil
+	}
+	if s.layers != nil {
+		if err = s.layeredPass(); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	for i := 0; i < maxSteps; i++ {
+		if relaxed, err = s.forwardStep(maxAllowedSignalDelta); err != nil {
+			return false, err
+		} else if relaxed {
+			break // no need to iterate any further, already reached desired accuracy
+		}
+	}
+	return relaxed, nil
+}
+
+// Flush Flushes activations of the network for all inputs of the batch
+func (s *BatchNetworkSolver) Flush() (bool, error) {
+	for b := 0; b < s.batchSize; b++ {
+		row := s.signals.RawRowView(b)
+		for i := s.solver.biasNeuronCount; i < s.solver.totalNeuronCount; i++ {
+			row[i] = 0.0
+		}
+	}
+	return true, nil
+}
+
+// LoadSensorsBatch Set sensors values to the input nodes of the network for each input of the batch. If the size of
+// the batch changed, the current activations of the network are flushed, otherwise they are kept for each input.
+func (s *BatchNetworkSolver) LoadSensorsBatch(inputs [][]float64) error {
+	if len(inputs) == 0 {
+		return ErrNetUnsupportedSensorsArraySize
+	}
+	for i, in := range inputs {
+		if len(in) != s.solver.inputNeuronCount {
+			return fmt.Errorf("%w: %d at batch row: %d", ErrNetUnsupportedSensorsArraySize, len(in), i)
+		}
+	}
+	if len(inputs) != s.batchSize {
+		s.resize(len(inputs))
+	}
+	for b, in := range inputs {
+		copy(s.signals.RawRowView(b)[s.solver.biasNeuronCount:], in)
+	}
+	return nil
+}
+
+// ReadOutputsBatch Read output values from the output nodes of the network for each input of the batch
+func (s *BatchNetworkSolver) ReadOutputsBatch() [][]float64 {
+	outputs := make([][]float64, s.batchSize)
+	start := s.solver.sensorNeuronCount
+	for b := range outputs {
+		outputs[b] = make([]float64, s.solver.outputNeuronCount)
+		copy(outputs[b], s.signals.RawRowView(b)[start:start+s.solver.outputNeuronCount])
+	}
+	return outputs
+}
+
+// LoadSensors Set sensors values to the input nodes of the network as the batch of single input
+func (s *BatchNetworkSolver) LoadSensors(inputs []float64) error {
+	return s.LoadSensorsBatch([][]float64{inputs})
+}
+
+// ReadOutputs Read output values from the output nodes of the network for the first input of the batch
+func (s *BatchNetworkSolver) ReadOutputs() []float64 {
+	return s.ReadOutputsBatch()[0]
+}
+
+func (s *BatchNetworkSolver) NodeCount() int {
+	return s.solver.NodeCount()
+}
+
+func (s *BatchNetworkSolver) LinkCount() int {
+	return s.solver.LinkCount()
+}
+
+// Stringer
+func (s *BatchNetworkSolver) String() string {
+	return fmt.Sprintf("BatchNetwork, id: %d, name: [%s], layered: %t, batch: %d, %s",
+		s.Id, s.Name, s.IsLayered(), s.batchSize, s.solver)
+}
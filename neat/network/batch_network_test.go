@@ -0,0 +1,138 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var batchInputs = [][]float64{{0.0, 0.0}, {1.0, 0.5}, {-0.3, 2.0}, {0.9, -1.1}, {3.0, 1.0}}
+
+// fastSolverOutputs is to evaluate each input of the batch by the fast solver with given number of forward steps
+func fastSolverOutputs(t *testing.T, net *Network, inputs [][]float64, steps int) [][]float64 {
+	solver, err := net.FastNetworkSolver()
+	require.NoError(t, err, "failed to create fast network solver")
+	outputs := make([][]float64, len(inputs))
+	for i, in := range inputs {
+		_, err = solver.Flush()
+		require.NoError(t, err)
+		require.NoError(t, solver.LoadSensors(in))
+		_, err = solver.ForwardSteps(steps)
+		require.NoError(t, err)
+		outputs[i] = solver.ReadOutputs()
+	}
+	return outputs
+}
+
+func assertBatchOutputs(t *testing.T, expected, actual [][]float64, name string) {
+	require.Len(t, actual, len(expected), name)
+	for i := range expected {
+		assert.InDeltaSlice(t, expected[i], actual[i], 1e-9, "%s: outputs mismatch at batch row: %d", name, i)
+	}
+}
+
+func TestBatchNetworkSolver_layered(t *testing.T) {
+	builders := map[string]func() *Network{
+		"plain":      buildPlainNetwork,
+		"hidden":     buildNetwork,
+		"node genes": buildNetworkWithNodeGenes,
+	}
+	for name, builder := range builders {
+		net := builder()
+		solver, err := net.BatchNetworkSolver()
+		require.NoError(t, err, "failed to create batch network solver: %s", name)
+		assert.True(t, solver.(*BatchNetworkSolver).IsLayered(), name)
+		assert.Equal(t, net.NodeCount(), solver.NodeCount(), name)
+		assert.Equal(t, net.LinkCount(), solver.LinkCount(), name)
+
+		// the fast solver converges to the same outputs after the number of steps exceeding network depth
+		expected := fastSolverOutputs(t, net, batchInputs, 10)
+
+		require.NoError(t, solver.LoadSensorsBatch(batchInputs), name)
+		res, err := solver.ForwardSteps(1)
+		require.NoError(t, err, name)
+		assert.True(t, res, name)
+		assertBatchOutputs(t, expected, solver.ReadOutputsBatch(), name)
+
+		res, err = solver.RecursiveSteps()
+		require.NoError(t, err, name)
+		assert.True(t, res, name)
+		assertBatchOutputs(t, expected, solver.ReadOutputsBatch(), name)
+
+		res, err = solver.Relax(1, 1e-6)
+		require.NoError(t, err, name)
+		assert.True(t, res, name)
+		assertBatchOutputs(t, expected, solver.ReadOutputsBatch(), name)
+	}
+}
+
+func TestBatchNetworkSolver_stepWise(t *testing.T) {
+	builders := map[string]func() *Network{
+		"modular":   buildModularNetwork,
+		"recurrent": buildRecurrentNetwork,
+	}
+	for name, builder := range builders {
+		net := builder()
+		solver, err := net.BatchNetworkSolver()
+		require.NoError(t, err, "failed to create batch network solver: %s", name)
+		assert.False(t, solver.(*BatchNetworkSolver).IsLayered(), name)
+
+		require.NoError(t, solver.LoadSensorsBatch(batchInputs), name)
+		for steps := 1; steps <= 10; steps++ {
+			_, err = solver.ForwardSteps(1)
+			require.NoError(t, err, name)
+			assertBatchOutputs(t, fastSolverOutputs(t, net, batchInputs, steps), solver.ReadOutputsBatch(), name)
+		}
+
+		_, err = solver.RecursiveSteps()
+		assert.ErrorIs(t, err, ErrNetRecursiveBatchUnsupported, name)
+	}
+}
+
+func TestBatchNetworkSolver_Relax(t *testing.T) {
+	net := buildRecurrentNetwork()
+	solver, err := net.BatchNetworkSolver()
+	require.NoError(t, err, "failed to create batch network solver")
+	require.NoError(t, solver.LoadSensorsBatch(batchInputs))
+
+	relaxed, err := solver.Relax(100, 1e-6)
+	require.NoError(t, err)
+	require.True(t, relaxed, "failed to relax within given maximal steps number")
+
+	// each input of the batch must be relaxed to the same outputs as by the fast solver
+	fast, err := net.FastNetworkSolver()
+	require.NoError(t, err, "failed to create fast network solver")
+	outputs := solver.ReadOutputsBatch()
+	for i, in := range batchInputs {
+		_, err = fast.Flush()
+		require.NoError(t, err)
+		require.NoError(t, fast.LoadSensors(in))
+		_, err = fast.Relax(100, 1e-6)
+		require.NoError(t, err)
+		assert.InDeltaSlice(t, fast.ReadOutputs(), outputs[i], 1e-5, "outputs mismatch at batch row: %d", i)
+	}
+}
+
+func TestBatchNetworkSolver_LoadSensorsBatch(t *testing.T) {
+	net := buildRecurrentNetwork()
+	solver, err := net.BatchNetworkSolver()
+	require.NoError(t, err, "failed to create batch network solver")
+
+	assert.ErrorIs(t, solver.LoadSensorsBatch(nil), ErrNetUnsupportedSensorsArraySize)
+	assert.ErrorIs(t, solver.LoadSensorsBatch([][]float64{{1.0, 2.0}, {1.0}}), ErrNetUnsupportedSensorsArraySize)
+
+	// single input is the batch of one
+	require.NoError(t, solver.LoadSensors(batchInputs[1]))
+	_, err = solver.ForwardSteps(3)
+	require.NoError(t, err)
+	assert.Len(t, solver.ReadOutputsBatch(), 1)
+	assert.InDeltaSlice(t, fastSolverOutputs(t, net, batchInputs[1:2], 3)[0], solver.ReadOutputs(), 1e-9)
+
+	// the change of batch size flushes activations
+	require.NoError(t, solver.LoadSensorsBatch(batchInputs))
+	assert.Len(t, solver.ReadOutputsBatch(), len(batchInputs))
+	for _, outputs := range solver.ReadOutputsBatch() {
+		assert.Equal(t, []float64{0.0}, outputs)
+	}
+}
@@ -107,6 +107,17 @@ func ActivateNode(node *NNode, a *neatmath.NodeActivatorsFactory) error {
 	return err
 }
 
+// AggregateNode Method to calculate the activation sum of specified neuron node by combining provided incoming signals
+// with aggregation function of the node's AggregationType. The node's response and bias are applied to the aggregated
+// value. Will return error if unsupported aggregation type requested.
+func AggregateNode(node *NNode, inputs []float64, a *neatmath.NodeAggregatorsFactory) error {
+	aggregated, err := a.AggregateByType(inputs, node.AggregationType)
+	if err == nil {
+		node.ActivationSum = node.Bias + node.Response*aggregated
+	}
+	return err
+}
+
 // ActivateModule Method to activate neuron module presented by provided node. As a result of execution the activation values of all
 // input nodes will be processed by corresponding activation function and corresponding activation values of output nodes
 // will be set. Will panic if unsupported activation type requested.
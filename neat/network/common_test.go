@@ -69,6 +69,20 @@ func TestActivateNode(t *testing.T) {
 	assert.EqualError(t, err, fmt.Sprintf("unknown neuron activation type: %d", node.ActivationType))
 }
 
+func TestAggregateNode(t *testing.T) {
+	node := NewNNode(1, HiddenNeuron)
+	node.Bias = 0.5
+	node.Response = 2.0
+	node.AggregationType = math.MaxAggregation
+	err := AggregateNode(node, []float64{1.0, 3.0, -2.0}, math.NodeAggregators)
+	assert.NoError(t, err)
+	assert.Equal(t, 6.5, node.ActivationSum)
+
+	node.AggregationType = math.MedianAggregation + 1
+	err = AggregateNode(node, []float64{1.0}, math.NodeAggregators)
+	assert.EqualError(t, err, fmt.Sprintf("unknown neuron aggregation type: %d", node.AggregationType))
+}
+
 func TestActivateModule(t *testing.T) {
 	node := NewNNode(1, HiddenNeuron)
 	node.ActivationType = math.MultiplyModuleActivation
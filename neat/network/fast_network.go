@@ -46,6 +46,15 @@ type FastModularNetworkSolver struct {
 	activationFunctions []neatmath.NodeActivationType
 	// The bias values associated with neurons
 	biasList []float64
+	// The own bias values of neurons added after response applied to the aggregated signal. If nil, no own bias is set.
+	neuronBiasList []float64
+	// The response multipliers of neurons applied to the aggregated signal. If nil, the unit response is used.
+	responseList []float64
+	// The aggregation functions per neuron, must be in the same order as neuronSignals. If nil, the incoming signals
+	// of all neurons are summed.
+	aggregationFunctions []neatmath.NodeAggregationType
+	// The incoming signals collected per each neuron with aggregation function other than the sum
+	aggregationInputs [][]float64
 	// The control nodes relaying between network modules
 	modules []*FastControlNode
 	// The connections
@@ -126,6 +135,60 @@ func NewFastModularNetworkSolver(biasNeuronCount, inputNeuronCount, outputNeuron
 	return &fmm
 }
 
+// setNeuronGenes is to set own bias, response and aggregation function of each neuron. The provided lists must be in the
+// same order as neuronSignals, the nil list means default value for all neurons.
+func (s *FastModularNetworkSolver) setNeuronGenes(biases, responses []float64, aggregations []neatmath.NodeAggregationType) error {
+	if (biases != nil && len(biases) != s.totalNeuronCount) ||
+		(responses != nil && len(responses) != s.totalNeuronCount) ||
+		(aggregations != nil && len(aggregations) != s.totalNeuronCount) {
+		return fmt.Errorf("neuron genes lists size doesn't match the total number of neurons: %d", s.totalNeuronCount)
+	}
+	s.neuronBiasList = biases
+	s.responseList = responses
+	s.aggregationFunctions = aggregations
+	s.aggregationInputs = nil
+	for _, aggregation := range aggregations {
+		if aggregation != neatmath.SumAggregation {
+			s.aggregationInputs = make([][]float64, s.totalNeuronCount)
+			break
+		}
+	}
+	return nil
+}
+
+// isSumAggregated is to check whether incoming signals of the neuron at given index are summed
+func (s *FastModularNetworkSolver) isSumAggregated(index int) bool {
+	return s.aggregationInputs == nil || s.aggregationFunctions[index] == neatmath.SumAggregation
+}
+
+// collectSignal is to store incoming signal of the neuron at given index to be aggregated
+func (s *FastModularNetworkSolver) collectSignal(index int, signal float64) {
+	s.neuronSignalsBeingProcessed[index] += signal
+	if !s.isSumAggregated(index) {
+		s.aggregationInputs[index] = append(s.aggregationInputs[index], signal)
+	}
+}
+
+// aggregateSignal is to apply own aggregation function, response and bias of the neuron at given index to its incoming
+// signals. The provided sum of the incoming signals is used for neurons with sum aggregation.
+func (s *FastModularNetworkSolver) aggregateSignal(index int, sum float64) (signal float64, err error) {
+	signal = sum
+	if !s.isSumAggregated(index) {
+		signal, err = neatmath.NodeAggregators.AggregateByType(s.aggregationInputs[index], s.aggregationFunctions[index])
+		s.aggregationInputs[index] = s.aggregationInputs[index][:0]
+		if err != nil {
+			return 0, err
+		}
+	}
+	if s.responseList != nil {
+		signal *= s.responseList[index]
+	}
+	if s.neuronBiasList != nil {
+		signal += s.neuronBiasList[index]
+	}
+	return signal, nil
+}
+
 func (s *FastModularNetworkSolver) ForwardSteps(steps int) (res bool, err error) {
 	for i := 0; i < steps; i++ {
 		if res, err = s.forwardStep(0); err != nil {
@@ -185,7 +248,7 @@ func (s *FastModularNetworkSolver) recursiveActivateNode(currentNode int) (res b
 		// If this node is currently being activated then we have reached a cycle, or recurrent connection.
 		// Use the previous activation in this case
 		if s.inActivation[currentAdjNode] {
-			s.neuronSignalsBeingProcessed[currentNode] += s.lastActivation[currentAdjNode] * s.adjacencyMatrix[currentAdjNode][currentNode]
+			s.collectSignal(currentNode, s.lastActivation[currentAdjNode]*s.adjacencyMatrix[currentAdjNode][currentNode])
 		} else {
 			// Otherwise, proceed as normal
 			// Recurse if this neuron has not been activated yet
@@ -200,7 +263,7 @@ func (s *FastModularNetworkSolver) recursiveActivateNode(currentNode int) (res b
 			}
 
 			// Add it to the new activation
-			s.neuronSignalsBeingProcessed[currentNode] += s.neuronSignals[currentAdjNode] * s.adjacencyMatrix[currentAdjNode][currentNode]
+			s.collectSignal(currentNode, s.neuronSignals[currentAdjNode]*s.adjacencyMatrix[currentAdjNode][currentNode])
 		}
 	}
 
@@ -210,10 +273,13 @@ func (s *FastModularNetworkSolver) recursiveActivateNode(currentNode int) (res b
 	// This is no longer being calculated (for cycle detection)
 	s.inActivation[currentNode] = false
 
-	// Set this signal after running it through the activation function
+	// Set this signal after running it through the aggregation and activation functions
+	signal, err := s.aggregateSignal(currentNode, s.neuronSignalsBeingProcessed[currentNode])
+	if err != nil {
+		return false, err
+	}
 	if s.neuronSignals[currentNode], err = neatmath.NodeActivators.ActivateByType(
-		s.neuronSignalsBeingProcessed[currentNode], nil,
-		s.activationFunctions[currentNode]); err != nil {
+		signal, nil, s.activationFunctions[currentNode]); err != nil {
 		// failed to activate
 		res = false
 	} else {
@@ -240,7 +306,7 @@ func (s *FastModularNetworkSolver) forwardStep(maxAllowedSignalDelta float64) (i
 
 	// Calculate output signal per each connection and add the signals to the target neurons
 	for _, conn := range s.connections {
-		s.neuronSignalsBeingProcessed[conn.TargetIndex] += s.neuronSignals[conn.SourceIndex] * conn.Weight
+		s.collectSignal(conn.TargetIndex, s.neuronSignals[conn.SourceIndex]*conn.Weight)
 	}
 
 	// Pass the signals through the single-valued activation functions
@@ -250,6 +316,9 @@ func (s *FastModularNetworkSolver) forwardStep(maxAllowedSignalDelta float64) (i
 			// append BIAS value to the signal if appropriate
 			signal += s.biasList[i]
 		}
+		if signal, err = s.aggregateSignal(i, signal); err != nil {
+			return false, err
+		}
 
 		if s.neuronSignalsBeingProcessed[i], err = neatmath.NodeActivators.ActivateByType(
 			signal, nil, s.activationFunctions[i]); err != nil {
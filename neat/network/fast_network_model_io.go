@@ -35,11 +35,21 @@ func ReadFMNSModel(reader io.Reader) (*FastModularNetworkSolver, error) {
 			}
 		}
 	}
+	var aggregationFunctions []math.NodeAggregationType
+	if len(data.AggregationFunctions) > 0 {
+		aggregationFunctions = make([]math.NodeAggregationType, len(data.AggregationFunctions))
+		for i, f := range data.AggregationFunctions {
+			aggregationFunctions[i] = f.NodeAggregation
+		}
+	}
 	fmns := NewFastModularNetworkSolver(
 		data.BiasNeuronCount, data.InputNeuronCount, data.OutputNeuronCount,
 		data.TotalNeuronCount, activationFunctions,
 		data.Connections, data.BiasList, modules,
 	)
+	if err := fmns.setNeuronGenes(data.NeuronBiasList, data.ResponseList, aggregationFunctions); err != nil {
+		return nil, err
+	}
 	fmns.Name = data.Name
 	fmns.Id = data.Id
 	return fmns, nil
@@ -49,6 +59,10 @@ type NodeActivator struct {
 	NodeActivation math.NodeActivationType
 }
 
+type NodeAggregator struct {
+	NodeAggregation math.NodeAggregationType
+}
+
 type fastControlNodeData struct {
 	ActivationType NodeActivator `json:"activation_type"`
 	InputIndexes   []int         `json:"input_indexes"`
@@ -56,17 +70,20 @@ type fastControlNodeData struct {
 }
 
 type fastModularNetworkSolverData struct {
-	Id                  int                   `json:"id"`
-	Name                string                `json:"name"`
-	InputNeuronCount    int                   `json:"input_neuron_count"`
-	SensorNeuronCount   int                   `json:"sensor_neuron_count"`
-	OutputNeuronCount   int                   `json:"output_neuron_count"`
-	BiasNeuronCount     int                   `json:"bias_neuron_count"`
-	TotalNeuronCount    int                   `json:"total_neuron_count"`
-	ActivationFunctions []NodeActivator       `json:"activation_functions"`
-	BiasList            []float64             `json:"bias_list"`
-	Connections         []*FastNetworkLink    `json:"connections"`
-	Modules             []fastControlNodeData `json:"modules,omitempty"`
+	Id                   int                   `json:"id"`
+	Name                 string                `json:"name"`
+	InputNeuronCount     int                   `json:"input_neuron_count"`
+	SensorNeuronCount    int                   `json:"sensor_neuron_count"`
+	OutputNeuronCount    int                   `json:"output_neuron_count"`
+	BiasNeuronCount      int                   `json:"bias_neuron_count"`
+	TotalNeuronCount     int                   `json:"total_neuron_count"`
+	ActivationFunctions  []NodeActivator       `json:"activation_functions"`
+	BiasList             []float64             `json:"bias_list"`
+	NeuronBiasList       []float64             `json:"neuron_bias_list,omitempty"`
+	ResponseList         []float64             `json:"response_list,omitempty"`
+	AggregationFunctions []NodeAggregator      `json:"aggregation_functions,omitempty"`
+	Connections          []*FastNetworkLink    `json:"connections"`
+	Modules              []fastControlNodeData `json:"modules,omitempty"`
 }
 
 func newFastModularNetworkSolverData(n *FastModularNetworkSolver) *fastModularNetworkSolverData {
@@ -80,6 +97,8 @@ func newFastModularNetworkSolverData(n *FastModularNetworkSolver) *fastModularNe
 		TotalNeuronCount:    n.totalNeuronCount,
 		ActivationFunctions: make([]NodeActivator, len(n.activationFunctions)),
 		BiasList:            n.biasList,
+		NeuronBiasList:      n.neuronBiasList,
+		ResponseList:        n.responseList,
 		Connections:         n.connections,
 		Modules:             make([]fastControlNodeData, 0),
 	}
@@ -88,6 +107,14 @@ func newFastModularNetworkSolverData(n *FastModularNetworkSolver) *fastModularNe
 			NodeActivation: v,
 		}
 	}
+	if n.aggregationFunctions != nil {
+		data.AggregationFunctions = make([]NodeAggregator, len(n.aggregationFunctions))
+		for i, v := range n.aggregationFunctions {
+			data.AggregationFunctions[i] = NodeAggregator{
+				NodeAggregation: v,
+			}
+		}
+	}
 	if n.modules != nil {
 		for _, v := range n.modules {
 			data.Modules = append(data.Modules, fastControlNodeData{
@@ -112,3 +139,16 @@ func (n *NodeActivator) UnmarshalText(text []byte) (err error) {
 	n.NodeActivation, err = math.NodeActivators.ActivationTypeFromName(string(text))
 	return err
 }
+
+func (n *NodeAggregator) MarshalText() ([]byte, error) {
+	if aggregationName, err := math.NodeAggregators.AggregationNameFromType(n.NodeAggregation); err != nil {
+		return nil, err
+	} else {
+		return []byte(aggregationName), nil
+	}
+}
+
+func (n *NodeAggregator) UnmarshalText(text []byte) (err error) {
+	n.NodeAggregation, err = math.NodeAggregators.AggregationTypeFromName(string(text))
+	return err
+}
@@ -0,0 +1,291 @@
+package network
+
+import (
+	"deepneat/neat"
+	neatmath "deepneat/neat/math"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrNetNotDifferentiable The error to be raised when gradient fine-tuning requested for the network which is not
+// feed-forward or has neurons with not differentiable activation or aggregation
+var ErrNetNotDifferentiable = errors.New("network is not differentiable")
+
+// Optimizer is to update the weights of the network by their gradients during gradient fine-tuning
+type Optimizer interface {
+	// Step is to update the weights in place by given gradients of the loss
+	Step(weights, gradients []float64)
+}
+
+// NewOptimizer is to create the optimizer of specified type with given learning rate
+func NewOptimizer(optimizer neat.GradientOptimizer, learningRate float64) (Optimizer, error) {
+	switch optimizer {
+	case "", neat.GradientOptimizerSGD:
+		return NewSGDOptimizer(learningRate), nil
+	case neat.GradientOptimizerAdam:
+		return NewAdamOptimizer(learningRate), nil
+	default:
+		return nil, fmt.Errorf("unsupported gradient optimizer: [%s]", optimizer)
+	}
+}
+
+// SGDOptimizer The plain gradient descent optimizer
+type SGDOptimizer struct {
+	// The learning rate
+	LearningRate float64
+}
+
+// NewSGDOptimizer Creates new gradient descent optimizer with given learning rate
+func NewSGDOptimizer(learningRate float64) *SGDOptimizer {
+	return &SGDOptimizer{LearningRate: learningRate}
+}
+
+func (s *SGDOptimizer) Step(weights, gradients []float64) {
+	for i, g := range gradients {
+		weights[i] -= s.LearningRate * g
+	}
+}
+
+// AdamOptimizer The Adam optimizer, which adapts the step of each weight by the estimates of the first and second
+// moments of its gradients (Kingma & Ba, 2014)
+type AdamOptimizer struct {
+	// The learning rate
+	LearningRate float64
+	// The exponential decay rates of the moments estimates
+	Beta1, Beta2 float64
+	// The small constant for numerical stability
+	Epsilon float64
+
+	// The moments estimates per weight
+	m, v []float64
+	// The number of steps done
+	t int
+}
+
+// NewAdamOptimizer Creates new Adam optimizer with given learning rate and the default decay rates
+func NewAdamOptimizer(learningRate float64) *AdamOptimizer {
+	return &AdamOptimizer{
+		LearningRate: learningRate,
+		Beta1:        0.9,
+		Beta2:        0.999,
+		Epsilon:      1e-8,
+	}
+}
+
+func (a *AdamOptimizer) Step(weights, gradients []float64) {
+	if len(a.m) != len(gradients) {
+		a.m = make([]float64, len(gradients))
+		a.v = make([]float64, len(gradients))
+		a.t = 0
+	}
+	a.t++
+	correction1 := 1 - math.Pow(a.Beta1, float64(a.t))
+	correction2 := 1 - math.Pow(a.Beta2, float64(a.t))
+	for i, g := range gradients {
+		a.m[i] = a.Beta1*a.m[i] + (1-a.Beta1)*g
+		a.v[i] = a.Beta2*a.v[i] + (1-a.Beta2)*g*g
+		mHat := a.m[i] / correction1
+		vHat := a.v[i] / correction2
+		weights[i] -= a.LearningRate * mHat / (math.Sqrt(vHat) + a.Epsilon)
+	}
+}
+
+// gradientGraph The feed-forward network prepared for the gradient computation
+type gradientGraph struct {
+	network *Network
+	// The nodes in topological order, every node comes after the sources of its incoming links
+	nodes []*NNode
+	// The index of each node in the nodes list
+	index map[*NNode]int
+	// The trainable links in order of the weights and gradients vectors
+	links []*Link
+	// The index of each link in the links list
+	linkIndex map[*Link]int
+}
+
+// newGradientGraph is to prepare the network for the gradient computation. Returns ErrNetNotDifferentiable if network
+// has modules, recurrent or time-delayed links, or neurons with not differentiable activation or aggregation.
+func newGradientGraph(n *Network) (*gradientGraph, error) {
+	if len(n.controlNodes) > 0 {
+		return nil, fmt.Errorf("%w: network has modules", ErrNetNotDifferentiable)
+	}
+	g := &gradientGraph{
+		network:   n,
+		nodes:     make([]*NNode, 0, len(n.allNodes)),
+		index:     make(map[*NNode]int, len(n.allNodes)),
+		links:     make([]*Link, 0),
+		linkIndex: make(map[*Link]int),
+	}
+	known := make(map[*NNode]bool, len(n.allNodes))
+	for _, node := range n.allNodes {
+		known[node] = true
+	}
+
+	// depth-first topological sort with cycle detection
+	inProgress := make(map[*NNode]bool)
+	var visit func(node *NNode) error
+	visit = func(node *NNode) error {
+		if _, ok := g.index[node]; ok {
+			return nil
+		}
+		if inProgress[node] {
+			return fmt.Errorf("%w: loop at node: %d", ErrNetNotDifferentiable, node.Id)
+		}
+		if node.IsNeuron() {
+			if node.AggregationType != neatmath.SumAggregation {
+				return fmt.Errorf("%w: node: %d has not sum aggregation", ErrNetNotDifferentiable, node.Id)
+			}
+			if !neatmath.NodeActivators.IsDifferentiable(node.ActivationType) {
+				return fmt.Errorf("%w: node: %d has not differentiable activation", ErrNetNotDifferentiable, node.Id)
+			}
+		}
+		inProgress[node] = true
+		for _, link := range node.Incoming {
+			if link.IsRecurrent || link.IsTimeDelayed {
+				return fmt.Errorf("%w: recurrent link to node: %d", ErrNetNotDifferentiable, node.Id)
+			}
+			if !known[link.InNode] {
+				return fmt.Errorf("%w: link from unknown node: %d", ErrNetNotDifferentiable, link.InNode.Id)
+			}
+			if err := visit(link.InNode); err != nil {
+				return err
+			}
+		}
+		delete(inProgress, node)
+		g.index[node] = len(g.nodes)
+		g.nodes = append(g.nodes, node)
+		return nil
+	}
+	for _, node := range n.allNodes {
+		if err := visit(node); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, node := range g.nodes {
+		for _, link := range node.Incoming {
+			g.linkIndex[link] = len(g.links)
+			g.links = append(g.links, link)
+		}
+	}
+	return g, nil
+}
+
+// loadSensors is to set the values of sensors with the same semantics as Network.LoadSensors
+func (g *gradientGraph) loadSensors(inputs []float64, values []float64) error {
+	counter := 0
+	withBias := len(inputs) == len(g.network.inputs)
+	for _, node := range g.network.inputs {
+		switch {
+		case withBias && node.IsSensor(), !withBias && node.NeuronType == InputNeuron:
+			if counter >= len(inputs) {
+				return ErrNetUnsupportedSensorsArraySize
+			}
+			values[g.index[node]] = inputs[counter]
+			counter++
+		case !withBias:
+			values[g.index[node]] = 1.0 // default BIAS value
+		}
+	}
+	return nil
+}
+
+// forward is to propagate the inputs through the network, storing the aggregated inputs and the outputs of all nodes
+func (g *gradientGraph) forward(inputs []float64, sums, values []float64) error {
+	if err := g.loadSensors(inputs, values); err != nil {
+		return err
+	}
+	for i, node := range g.nodes {
+		if !node.IsNeuron() {
+			continue
+		}
+		sum := 0.0
+		for _, link := range node.Incoming {
+			sum += link.ConnectionWeight * values[g.index[link.InNode]]
+		}
+		sums[i] = node.Bias + node.Response*sum
+		out, err := neatmath.NodeActivators.ActivateByType(sums[i], nil, node.ActivationType)
+		if err != nil {
+			return err
+		}
+		values[i] = out
+	}
+	return nil
+}
+
+// lossAndGradients is to compute the mean squared error of the network outputs over all samples and its gradients
+// with respect to the weights of links
+func (g *gradientGraph) lossAndGradients(inputs, targets [][]float64, gradients []float64) (float64, error) {
+	for i := range gradients {
+		gradients[i] = 0
+	}
+	outputs := g.network.Outputs
+	sums, values := make([]float64, len(g.nodes)), make([]float64, len(g.nodes))
+	deltas := make([]float64, len(g.nodes))
+	scale := 1.0 / float64(len(inputs)*len(outputs))
+	loss := 0.0
+	for s, in := range inputs {
+		if len(targets[s]) != len(outputs) {
+			return 0, fmt.Errorf("targets size: %d doesn't match the number of outputs: %d", len(targets[s]), len(outputs))
+		}
+		if err := g.forward(in, sums, values); err != nil {
+			return 0, err
+		}
+		for i := range deltas {
+			deltas[i] = 0
+		}
+		for o, node := range outputs {
+			diff := values[g.index[node]] - targets[s][o]
+			loss += diff * diff * scale
+			deltas[g.index[node]] += 2 * diff * scale
+		}
+		// back propagate from the last node
+		for i := len(g.nodes) - 1; i >= 0; i-- {
+			node := g.nodes[i]
+			if !node.IsNeuron() || deltas[i] == 0 {
+				continue
+			}
+			derivative, err := neatmath.NodeActivators.DerivativeByType(sums[i], values[i], nil, node.ActivationType)
+			if err != nil {
+				return 0, err
+			}
+			delta := deltas[i] * derivative * node.Response
+			for _, link := range node.Incoming {
+				source := g.index[link.InNode]
+				gradients[g.linkIndex[link]] += delta * values[source]
+				deltas[source] += delta * link.ConnectionWeight
+			}
+		}
+	}
+	return loss, nil
+}
+
+// FitGradient is to fine-tune the weights of links of this feed-forward network by the full batch gradient descent
+// minimizing the mean squared error between its outputs and the targets over the given samples. The inputs of each
+// sample are loaded as by LoadSensors. Returns the mean squared error of the tuned network. Returns
+// ErrNetNotDifferentiable if network has loops, modules, or neurons with not differentiable activation or aggregation.
+func (n *Network) FitGradient(inputs, targets [][]float64, epochs int, optimizer Optimizer) (float64, error) {
+	if len(inputs) == 0 || len(inputs) != len(targets) {
+		return 0, fmt.Errorf("inputs size: %d doesn't match targets size: %d", len(inputs), len(targets))
+	}
+	g, err := newGradientGraph(n)
+	if err != nil {
+		return 0, err
+	}
+	weights := make([]float64, len(g.links))
+	gradients := make([]float64, len(g.links))
+	for epoch := 0; epoch < epochs; epoch++ {
+		if _, err = g.lossAndGradients(inputs, targets, gradients); err != nil {
+			return 0, err
+		}
+		for i, link := range g.links {
+			weights[i] = link.ConnectionWeight
+		}
+		optimizer.Step(weights, gradients)
+		for i, link := range g.links {
+			link.ConnectionWeight = weights[i]
+		}
+	}
+	return g.lossAndGradients(inputs, targets, gradients)
+}
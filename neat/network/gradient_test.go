@@ -0,0 +1,167 @@
+package network
+
+import (
+	"deepneat/neat"
+	"deepneat/neat/math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildDifferentiableNetwork builds feed-forward network with hidden nodes having own bias and response
+func buildDifferentiableNetwork() *Network {
+	allNodes := []*NNode{
+		NewNNode(1, InputNeuron),
+		NewNNode(2, InputNeuron),
+		NewNNode(3, BiasNeuron),
+		NewNNode(4, HiddenNeuron),
+		NewNNode(5, HiddenNeuron),
+		NewNNode(6, OutputNeuron),
+	}
+	allNodes[3].ActivationType = math.TanhActivation
+	allNodes[3].Bias = 0.1
+	allNodes[3].Response = 1.5
+	allNodes[4].ActivationType = math.GaussianActivation
+	allNodes[5].ActivationType = math.SigmoidSteepenedActivation
+
+	// HIDDEN 4
+	allNodes[3].ConnectFrom(allNodes[0], 0.6)
+	allNodes[3].ConnectFrom(allNodes[1], -0.4)
+	allNodes[3].ConnectFrom(allNodes[2], 0.2)
+	// HIDDEN 5
+	allNodes[4].ConnectFrom(allNodes[1], 0.9)
+	allNodes[4].ConnectFrom(allNodes[3], -0.3)
+	// OUTPUT 6
+	allNodes[5].ConnectFrom(allNodes[3], 0.7)
+	allNodes[5].ConnectFrom(allNodes[4], -0.5)
+	allNodes[5].ConnectFrom(allNodes[0], 0.3)
+	allNodes[5].ConnectFrom(allNodes[2], -0.1)
+
+	return NewNetwork(allNodes[0:3], allNodes[5:6], allNodes, 0)
+}
+
+var xorInputs = [][]float64{{0, 0}, {0, 1}, {1, 0}, {1, 1}}
+var xorTargets = [][]float64{{0}, {1}, {1}, {0}}
+
+func TestGradientGraph_forward(t *testing.T) {
+	net := buildDifferentiableNetwork()
+	g, err := newGradientGraph(net)
+	require.NoError(t, err)
+	sums, values := make([]float64, len(g.nodes)), make([]float64, len(g.nodes))
+	for _, in := range xorInputs {
+		require.NoError(t, g.forward(in, sums, values))
+
+		// the same outputs as activation of the network itself
+		require.NoError(t, net.LoadSensors(in))
+		relaxed, err := net.Relax(10, 1e-12)
+		require.NoError(t, err)
+		require.True(t, relaxed)
+		assert.InDelta(t, net.ReadOutputs()[0], values[g.index[net.Outputs[0]]], 1e-12)
+		_, err = net.Flush()
+		require.NoError(t, err)
+	}
+}
+
+func TestGradientGraph_lossAndGradients(t *testing.T) {
+	net := buildDifferentiableNetwork()
+	g, err := newGradientGraph(net)
+	require.NoError(t, err)
+	gradients := make([]float64, len(g.links))
+	loss, err := g.lossAndGradients(xorInputs, xorTargets, gradients)
+	require.NoError(t, err)
+	assert.True(t, loss > 0)
+
+	// compare with numerical gradients
+	h := 1e-6
+	for i, link := range g.links {
+		weight := link.ConnectionWeight
+		link.ConnectionWeight = weight + h
+		lossPlus, err := g.lossAndGradients(xorInputs, xorTargets, make([]float64, len(g.links)))
+		require.NoError(t, err)
+		link.ConnectionWeight = weight - h
+		lossMinus, err := g.lossAndGradients(xorInputs, xorTargets, make([]float64, len(g.links)))
+		require.NoError(t, err)
+		link.ConnectionWeight = weight
+
+		assert.InDelta(t, (lossPlus-lossMinus)/(2*h), gradients[i], 1e-6, "link: %s", link)
+	}
+
+	_, err = g.lossAndGradients(xorInputs, [][]float64{{0, 1}, {1}, {1}, {0}}, gradients)
+	assert.Error(t, err, "targets size mismatch")
+}
+
+func TestNetwork_FitGradient(t *testing.T) {
+	optimizers := map[string]Optimizer{
+		"sgd":  NewSGDOptimizer(0.5),
+		"adam": NewAdamOptimizer(0.05),
+	}
+	for name, optimizer := range optimizers {
+		net := buildDifferentiableNetwork()
+		g, err := newGradientGraph(net)
+		require.NoError(t, err)
+		initialLoss, err := g.lossAndGradients(xorInputs, xorTargets, make([]float64, len(g.links)))
+		require.NoError(t, err)
+		initialWeights := make([]float64, len(g.links))
+		for i, link := range g.links {
+			initialWeights[i] = link.ConnectionWeight
+		}
+
+		loss, err := net.FitGradient(xorInputs, xorTargets, 200, optimizer)
+		require.NoError(t, err, name)
+		assert.True(t, loss < initialLoss/2, "%s: loss: %f, initial: %f", name, loss, initialLoss)
+
+		expectedLoss, err := g.lossAndGradients(xorInputs, xorTargets, make([]float64, len(g.links)))
+		require.NoError(t, err)
+		assert.Equal(t, expectedLoss, loss, name)
+		changed := false
+		for i, link := range g.links {
+			changed = changed || link.ConnectionWeight != initialWeights[i]
+		}
+		assert.True(t, changed, name)
+	}
+
+	_, err := buildDifferentiableNetwork().FitGradient(xorInputs, xorTargets[:2], 1, NewSGDOptimizer(0.1))
+	assert.Error(t, err, "inputs and targets size mismatch")
+}
+
+func TestNetwork_FitGradient_notDifferentiable(t *testing.T) {
+	step := buildDifferentiableNetwork()
+	step.Outputs[0].ActivationType = math.StepActivation
+	product := buildDifferentiableNetwork()
+	product.Outputs[0].AggregationType = math.ProductAggregation
+	loop := buildDifferentiableNetwork()
+	loop.allNodes[3].ConnectFrom(loop.Outputs[0], 0.1)
+
+	networks := map[string]*Network{
+		"recurrent": buildRecurrentNetwork(),
+		"modular":   buildModularNetwork(),
+		"step":      step,
+		"product":   product,
+		"loop":      loop,
+	}
+	for name, net := range networks {
+		_, err := net.FitGradient(xorInputs, xorTargets, 1, NewSGDOptimizer(0.1))
+		assert.ErrorIs(t, err, ErrNetNotDifferentiable, name)
+	}
+}
+
+func TestNewOptimizer(t *testing.T) {
+	optimizer, err := NewOptimizer("", 0.1)
+	require.NoError(t, err)
+	assert.Equal(t, NewSGDOptimizer(0.1), optimizer)
+	optimizer, err = NewOptimizer(neat.GradientOptimizerAdam, 0.1)
+	require.NoError(t, err)
+	assert.Equal(t, NewAdamOptimizer(0.1), optimizer)
+	_, err = NewOptimizer("rmsprop", 0.1)
+	assert.Error(t, err)
+}
+
+func TestAdamOptimizer_Step(t *testing.T) {
+	optimizer := NewAdamOptimizer(0.1)
+	weights := []float64{1.0, -1.0}
+	// the first step of Adam moves each weight by the learning rate in the direction opposite to its gradient
+	optimizer.Step(weights, []float64{0.5, -2.0})
+	assert.InDelta(t, 0.9, weights[0], 1e-6)
+	assert.InDelta(t, -0.9, weights[1], 1e-6)
+}
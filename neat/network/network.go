@@ -2,11 +2,13 @@ package network
 
 import (
 	"bytes"
+	"deepneat/neat"
 	"deepneat/neat/math"
 	"errors"
 	"fmt"
 	"gonum.org/v1/gonum/graph/path"
 	"io"
+	gomath "math"
 )
 
 // Network is a collection of all nodes within an organism's phenotype, which effectively defines Neural Network topology.
@@ -90,6 +92,18 @@ func (n *Network) FastNetworkSolver() (Solver, error) {
 	neuronIndex = processList(neuronIndex, n.Outputs, activations, neuronLookup)
 	processList(neuronIndex, hiddenList, activations, neuronLookup)
 
+	// collect own bias, response, and aggregation function of each neuron
+	neuronBiases := make([]float64, totalNeuronCount)
+	responses := make([]float64, totalNeuronCount)
+	aggregations := make([]math.NodeAggregationType, totalNeuronCount)
+	for _, ne := range n.allNodes {
+		if index, ok := neuronLookup[ne.Id]; ok {
+			neuronBiases[index] = ne.Bias
+			responses[index] = ne.Response
+			aggregations[index] = ne.AggregationType
+		}
+	}
+
 	// walk through neurons in order: input, output, hidden and create bias and connections lists
 	biases := make([]float64, totalNeuronCount)
 	connections := make([]*FastNetworkLink, 0)
@@ -139,11 +153,31 @@ func (n *Network) FastNetworkSolver() (Solver, error) {
 
 	solver := NewFastModularNetworkSolver(biasNeuronCount, inputNeuronCount, outputNeuronCount, totalNeuronCount,
 		activations, connections, biases, modules)
+	if err := solver.setNeuronGenes(neuronBiases, responses, aggregations); err != nil {
+		return nil, err
+	}
 	solver.Id = n.Id
 	solver.Name = n.Name
 	return solver, nil
 }
 
+// BatchNetworkSolver Returns network solver to evaluate the whole batch of inputs at once. The feed-forward networks
+// are evaluated layer by layer with matrix operations, others step by step as FastNetworkSolver does.
+func (n *Network) BatchNetworkSolver() (BatchSolver, error) {
+	solver, err := n.FastNetworkSolver()
+	if err != nil {
+		return nil, err
+	}
+	return NewBatchNetworkSolver(solver.(*FastModularNetworkSolver)), nil
+}
+
+// PlasticNetworkSolver Returns network solver which adapts the weights of links of this network at runtime according
+// to provided plasticity rule with coefficients taken from the traits of links. The adapted weights are bounded by the
+// provided absolute value unless it's zero.
+func (n *Network) PlasticNetworkSolver(rule neat.PlasticityRule, weightBound float64) (Solver, error) {
+	return NewPlasticNetworkSolver(n, rule, weightBound)
+}
+
 func processList(startIndex int, nList []*NNode, activations []math.NodeActivationType, neuronLookup map[int]int) int {
 	for _, ne := range nList {
 		activations[startIndex] = ne.ActivationType
@@ -159,8 +193,8 @@ func (n *Network) processIncomingConnections(nList []*NNode, biases []float64, n
 		if targetIndex, ok := neuronLookup[ne.Id]; ok {
 			for _, in := range ne.Incoming {
 				if sourceIndex, ok := neuronLookup[in.InNode.Id]; ok {
-					if in.InNode.NeuronType == BiasNeuron {
-						// store bias for target neuron
+					if in.InNode.NeuronType == BiasNeuron && ne.AggregationType == math.SumAggregation {
+						// store bias for target neuron, it is only possible if incoming signals are summed
 						biases[targetIndex] += in.ConnectionWeight
 					} else {
 						// save connection
@@ -242,15 +276,21 @@ func (n *Network) OutputIsOff() bool {
 // Normally the maxSteps should be equal to the maximal activation depth of the network as returned by
 // MaxActivationDepth() or MaxActivationDepthWithCap()
 func (n *Network) ActivateSteps(maxSteps int) (bool, error) {
+	return n.activateSteps(maxSteps, nil, nil)
+}
+
+// activateSteps is to activate network as ActivateSteps does, skipping the signals of provided modulatory nodes and
+// calling provided afterStep function, if any, after each activation step
+func (n *Network) activateSteps(maxSteps int, modulatory map[*NNode]bool, afterStep func() error) (bool, error) {
 	if maxSteps == 0 {
 		return false, ErrZeroActivationStepsRequested
 	}
-	// For adding to the active sum
-	addAmount := 0.0
 	// Make sure we at least activate once
 	oneTime := false
 	// Used in case the output is somehow truncated from the network
 	abortCount := 0
+	// The incoming signals of the neuron node to be aggregated
+	signals := make([]float64, 0)
 
 	// Keep activating until all the outputs have become active
 	// (This only happens on the first activation, because after that they are always active)
@@ -260,51 +300,14 @@ func (n *Network) ActivateSteps(maxSteps int) (bool, error) {
 			return false, ErrNetExceededMaxActivationAttempts
 		}
 
-		// For each neuron node, compute the sum of its incoming activation
-		for _, np := range n.allNodes {
-			if np.IsNeuron() {
-				np.ActivationSum = 0.0 // reset activation value
-
-				// For each node's incoming connection, add the activity from the connection to the activesum
-				for _, link := range np.Incoming {
-					// Handle possible time delays
-					if !link.IsTimeDelayed {
-						addAmount = link.ConnectionWeight * link.InNode.GetActiveOut()
-						if link.InNode.isActive || link.InNode.IsSensor() {
-							np.isActive = true
-						}
-					} else {
-						addAmount = link.ConnectionWeight * link.InNode.GetActiveOutTd()
-					}
-					np.ActivationSum += addAmount
-				} // End {for} over incoming links
-			} // End if != SENSOR
-		} // End {for} over all nodes
-
-		// Now activate all the neuron nodes off their incoming activation
-		for _, np := range n.allNodes {
-			if np.IsNeuron() {
-				// Only activate if some active input came in
-				if np.isActive {
-					// Now run the net activation through an activation function
-					err := ActivateNode(np, math.NodeActivators)
-					if err != nil {
-						return false, err
-					}
-				}
-			}
+		var err error
+		if signals, err = n.activateStep(false, modulatory, signals); err != nil {
+			return false, err
 		}
-
-		// Now activate all MIMO control genes to propagate activation through genome modules
-		for _, cn := range n.controlNodes {
-			cn.isActive = false
-			// Activate control MIMO node as control module
-			err := ActivateModule(cn, math.NodeActivators)
-			if err != nil {
+		if afterStep != nil {
+			if err = afterStep(); err != nil {
 				return false, err
 			}
-			// mark control node as active
-			cn.isActive = true
 		}
 
 		oneTime = true
@@ -313,6 +316,73 @@ func (n *Network) ActivateSteps(maxSteps int) (bool, error) {
 	return true, nil
 }
 
+// activateStep is to propagate single activation wave through the network. If relaxing is set, the step has the same
+// semantics as the step of FastModularNetworkSolver: all neuron nodes are activated, and the time-delayed links pass
+// the activation of their source nodes from the previous step as all other links do. Otherwise, only neuron nodes that
+// got an incoming signal from active nodes or sensors are activated, and the time-delayed links pass the activation
+// from the step before the previous one. The signals of the provided modulatory nodes, if any, are not added to the
+// activation of their target nodes. The provided signals buffer is reused to collect incoming signals and returned
+// back to be used by the next step.
+func (n *Network) activateStep(relaxing bool, modulatory map[*NNode]bool, signals []float64) ([]float64, error) {
+	// For adding to the active sum
+	addAmount := 0.0
+
+	// For each neuron node, compute the aggregate of its incoming activation
+	for _, np := range n.allNodes {
+		if np.IsNeuron() {
+			signals = signals[:0] // reset incoming signals
+
+			// For each node's incoming connection, collect the activity from the connection
+			for _, link := range np.Incoming {
+				if modulatory[link.InNode] {
+					// the modulatory signals only gate the plasticity of links
+					continue
+				}
+				// Handle possible time delays
+				if !link.IsTimeDelayed || relaxing {
+					addAmount = link.ConnectionWeight * link.InNode.GetActiveOut()
+					if link.InNode.isActive || link.InNode.IsSensor() {
+						np.isActive = true
+					}
+				} else {
+					addAmount = link.ConnectionWeight * link.InNode.GetActiveOutTd()
+				}
+				signals = append(signals, addAmount)
+			} // End {for} over incoming links
+
+			// Aggregate collected signals into the activation sum of the node
+			if err := AggregateNode(np, signals, math.NodeAggregators); err != nil {
+				return signals, err
+			}
+		} // End if != SENSOR
+	} // End {for} over all nodes
+
+	// Now activate all the neuron nodes off their incoming activation
+	for _, np := range n.allNodes {
+		if np.IsNeuron() {
+			// Only activate if some active input came in
+			if np.isActive || relaxing {
+				// Now run the net activation through an activation function
+				if err := ActivateNode(np, math.NodeActivators); err != nil {
+					return signals, err
+				}
+			}
+		}
+	}
+
+	// Now activate all MIMO control genes to propagate activation through genome modules
+	for _, cn := range n.controlNodes {
+		cn.isActive = false
+		// Activate control MIMO node as control module
+		if err := ActivateModule(cn, math.NodeActivators); err != nil {
+			return signals, err
+		}
+		// mark control node as active
+		cn.isActive = true
+	}
+	return signals, nil
+}
+
 // Activate is to activate the network such that all outputs are active
 func (n *Network) Activate() (bool, error) {
 	return n.ActivateSteps(20)
@@ -339,8 +409,46 @@ func (n *Network) RecursiveSteps() (bool, error) {
 	return n.ForwardSteps(netDepth)
 }
 
-func (n *Network) Relax(_ int, _ float64) (bool, error) {
-	return false, errors.New("relax is not implemented")
+// Relax Attempts to relax network given amount of steps until giving up. It has the same semantics as the Relax of
+// FastModularNetworkSolver: every neuron node is activated at each step, and the network considered relaxed when
+// absolute value of the change of each neuron activation is not greater than maxAllowedSignalDelta. If
+// maxAllowedSignalDelta is less than or equal to 0, the network considered relaxed after the first step. As with the
+// fast solver, the time-delayed links are not delayed more than other links during relaxation.
+func (n *Network) Relax(maxSteps int, maxAllowedSignalDelta float64) (relaxed bool, err error) {
+	return n.relax(maxSteps, maxAllowedSignalDelta, nil, nil)
+}
+
+// relax is to relax network as Relax does, skipping the signals of provided modulatory nodes and calling provided
+// afterStep function, if any, after each activation step
+func (n *Network) relax(maxSteps int, maxAllowedSignalDelta float64, modulatory map[*NNode]bool, afterStep func() error) (relaxed bool, err error) {
+	previous := make([]float64, len(n.allNodes))
+	signals := make([]float64, 0)
+	for step := 0; step < maxSteps; step++ {
+		for i, np := range n.allNodes {
+			previous[i] = np.GetActiveOut()
+		}
+		if signals, err = n.activateStep(true, modulatory, signals); err != nil {
+			return false, err
+		}
+		if afterStep != nil {
+			if err = afterStep(); err != nil {
+				return false, err
+			}
+		}
+		relaxed = true
+		if maxAllowedSignalDelta > 0 {
+			for i, np := range n.allNodes {
+				if np.IsNeuron() && gomath.Abs(np.GetActiveOut()-previous[i]) > maxAllowedSignalDelta {
+					relaxed = false
+					break
+				}
+			}
+		}
+		if relaxed {
+			break // no need to iterate any further, already reached desired accuracy
+		}
+	}
+	return relaxed, nil
 }
 
 func (n *Network) LoadSensors(sensors []float64) error {
@@ -1,6 +1,7 @@
 package network
 
 import (
+	"bytes"
 	"deepneat/neat/math"
 	"testing"
 
@@ -69,6 +70,41 @@ func buildNetwork() *Network {
 	return NewNetwork(allNodes[0:3], allNodes[6:8], allNodes, 0)
 }
 
+// buildNetworkWithNodeGenes builds network with linear nodes having own bias, response, and aggregation function
+func buildNetworkWithNodeGenes() *Network {
+	allNodes := []*NNode{
+		NewNNode(1, InputNeuron),
+		NewNNode(2, InputNeuron),
+		NewNNode(3, BiasNeuron),
+		NewNNode(4, HiddenNeuron),
+		NewNNode(5, OutputNeuron),
+		NewNNode(6, OutputNeuron),
+	}
+	for _, node := range allNodes[3:] {
+		node.ActivationType = math.LinearActivation
+	}
+
+	// HIDDEN 4
+	allNodes[3].AggregationType = math.ProductAggregation
+	allNodes[3].Bias = 0.5
+	allNodes[3].Response = 2.0
+	allNodes[3].ConnectFrom(allNodes[0], 2.0)
+	allNodes[3].ConnectFrom(allNodes[1], 3.0)
+	allNodes[3].ConnectFrom(allNodes[2], 1.5)
+	// OUTPUT 5
+	allNodes[4].AggregationType = math.MaxAggregation
+	allNodes[4].Bias = -1.0
+	allNodes[4].ConnectFrom(allNodes[3], 1.0)
+	allNodes[4].ConnectFrom(allNodes[0], 0.5)
+	// OUTPUT 6
+	allNodes[5].Bias = 0.25
+	allNodes[5].Response = 0.5
+	allNodes[5].ConnectFrom(allNodes[3], 1.0)
+	allNodes[5].ConnectFrom(allNodes[2], 1.0)
+
+	return NewNetwork(allNodes[0:3], allNodes[4:6], allNodes, 0)
+}
+
 func buildModularNetwork() *Network {
 	allNodes := []*NNode{
 		NewNNode(1, InputNeuron),  // INPUT 1
@@ -111,6 +147,45 @@ func buildModularNetwork() *Network {
 	return NewModularNetwork(allNodes[0:3], allNodes[6:8], allNodes, controlNodes, 0)
 }
 
+// buildRecurrentNetwork builds network with recurrent links between hidden and output nodes
+func buildRecurrentNetwork() *Network {
+	allNodes := []*NNode{
+		NewNNode(1, InputNeuron),
+		NewNNode(2, InputNeuron),
+		NewNNode(3, BiasNeuron),
+		NewNNode(4, HiddenNeuron),
+		NewNNode(5, HiddenNeuron),
+		NewNNode(6, OutputNeuron),
+	}
+
+	// HIDDEN 4
+	allNodes[3].ConnectFrom(allNodes[0], 1.5)
+	allNodes[3].ConnectFrom(allNodes[2], -0.5)
+	allNodes[3].ConnectFrom(allNodes[3], 0.7)  // <- self
+	allNodes[3].ConnectFrom(allNodes[5], -1.2) // <- OUTPUT 6
+	// HIDDEN 5
+	allNodes[4].ConnectFrom(allNodes[1], 2.0)
+	allNodes[4].ConnectFrom(allNodes[3], 0.3)
+	// OUTPUT 6
+	allNodes[5].ConnectFrom(allNodes[3], 1.1)
+	allNodes[5].ConnectFrom(allNodes[4], -0.8)
+
+	return NewNetwork(allNodes[0:3], allNodes[5:6], allNodes, 0)
+}
+
+// buildTimeDelayedNetwork is to build the recurrent network with time-delayed links
+func buildTimeDelayedNetwork() *Network {
+	net := buildRecurrentNetwork()
+	for _, node := range net.allNodes {
+		for _, link := range node.Incoming {
+			if link.InNode == link.OutNode || link.InNode.NeuronType == OutputNeuron {
+				link.IsTimeDelayed = true
+			}
+		}
+	}
+	return net
+}
+
 func TestModularNetwork_Activate(t *testing.T) {
 	net := buildModularNetwork()
 
@@ -243,6 +318,125 @@ func TestNetwork_RecursiveSteps(t *testing.T) {
 	assert.EqualValues(t, expectedOuts, net.ReadOutputs())
 }
 
+func TestNetwork_Relax(t *testing.T) {
+	net := buildNetwork()
+
+	err := net.LoadSensors([]float64{0.5, 0.0, 1.5})
+	require.NoError(t, err, "failed to load sensors")
+
+	// the network is not relaxed until activation wave reaches outputs
+	relaxed, err := net.Relax(2, 1e-6)
+	require.NoError(t, err)
+	assert.False(t, relaxed)
+
+	relaxed, err = net.Relax(10, 1e-6)
+	require.NoError(t, err)
+	assert.True(t, relaxed)
+	assert.EqualValues(t, []float64{1.0, 1.0}, net.ReadOutputs())
+
+	// zero delta means relaxed after single step
+	relaxed, err = net.Relax(10, 0)
+	require.NoError(t, err)
+	assert.True(t, relaxed)
+}
+
+func TestNetwork_Relax_timeDelayed(t *testing.T) {
+	allNodes := []*NNode{
+		NewNNode(1, InputNeuron),
+		NewNNode(2, HiddenNeuron),
+		NewNNode(3, OutputNeuron),
+	}
+	for _, node := range allNodes[1:] {
+		node.ActivationType = math.LinearActivation
+	}
+	allNodes[1].ConnectFrom(allNodes[0], 1.0)
+	allNodes[2].ConnectFrom(allNodes[1], 1.0)
+	allNodes[2].ConnectFrom(allNodes[1], 1.0).IsTimeDelayed = true
+	net := NewNetwork(allNodes[0:1], allNodes[2:3], allNodes, 0)
+
+	err := net.LoadSensors([]float64{1.0})
+	require.NoError(t, err, "failed to load sensors")
+
+	// the time-delayed signal reaches output at the same step as the direct one, as with the fast solver
+	solver, err := net.FastNetworkSolver()
+	require.NoError(t, err, "failed to create fast network solver")
+	require.NoError(t, solver.LoadSensors([]float64{1.0}))
+	for _, s := range []Solver{net, solver} {
+		relaxed, err := s.Relax(2, 1e-6)
+		require.NoError(t, err)
+		assert.False(t, relaxed)
+		assert.Equal(t, []float64{2.0}, s.ReadOutputs())
+
+		relaxed, err = s.Relax(1, 1e-6)
+		require.NoError(t, err)
+		assert.True(t, relaxed)
+		assert.Equal(t, []float64{2.0}, s.ReadOutputs())
+	}
+
+	// the time-delayed signal reaches output one step later than the direct one when activated by steps
+	_, err = net.Flush()
+	require.NoError(t, err)
+	require.NoError(t, net.LoadSensors([]float64{1.0}))
+	_, err = net.ForwardSteps(2)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1.0}, net.ReadOutputs())
+	_, err = net.ForwardSteps(1)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{2.0}, net.ReadOutputs())
+}
+
+func TestNetwork_Relax_solversEquivalence(t *testing.T) {
+	builders := map[string]func() *Network{
+		"plain":      buildPlainNetwork,
+		"hidden":     buildNetwork,
+		"node genes": buildNetworkWithNodeGenes,
+		"modular":    buildModularNetwork,
+		"recurrent":  buildRecurrentNetwork,
+		"delayed":    buildTimeDelayedNetwork,
+	}
+	inputs := [][]float64{{0.0, 0.0}, {1.0, 0.5}, {-0.3, 2.0}, {0.9, -1.1}}
+	for name, builder := range builders {
+		net := builder()
+		solver, err := net.FastNetworkSolver()
+		require.NoError(t, err, "failed to create fast network solver: %s", name)
+
+		for _, in := range inputs {
+			_, err = net.Flush()
+			require.NoError(t, err)
+			_, err = solver.Flush()
+			require.NoError(t, err)
+			require.NoError(t, net.LoadSensors(in), name)
+			require.NoError(t, solver.LoadSensors(in), name)
+
+			// both solvers must produce the same outputs and relaxation status at each step
+			for step := 0; step < 30; step++ {
+				netRelaxed, err := net.Relax(1, 1e-4)
+				require.NoError(t, err, name)
+				solverRelaxed, err := solver.Relax(1, 1e-4)
+				require.NoError(t, err, name)
+
+				assert.Equal(t, solverRelaxed, netRelaxed, "%s: relaxation mismatch at step: %d, inputs: %v", name, step, in)
+				assert.InDeltaSlice(t, solver.ReadOutputs(), net.ReadOutputs(), 1e-9,
+					"%s: outputs mismatch at step: %d, inputs: %v", name, step, in)
+			}
+		}
+	}
+}
+
+func TestNetwork_ForwardSteps_nodeGenes(t *testing.T) {
+	net := buildNetworkWithNodeGenes()
+
+	err := net.LoadSensors([]float64{1.0, 2.0})
+	require.NoError(t, err, "failed to load sensors")
+	res, err := net.ForwardSteps(2)
+	require.NoError(t, err)
+	assert.True(t, res)
+
+	// hidden: 0.5 + 2 * (2 * 6 * 1.5) = 36.5, outputs: -1 + max(36.5, 0.5) = 35.5 and 0.25 + 0.5 * (36.5 + 1) = 19
+	expectedOuts := []float64{35.5, 19.0}
+	assert.EqualValues(t, expectedOuts, net.ReadOutputs())
+}
+
 func TestNetwork_ForwardSteps_disconnected(t *testing.T) {
 	net := buildDisconnectedNetwork()
 
@@ -341,6 +535,46 @@ func TestNetwork_FastNetworkSolver(t *testing.T) {
 	assert.Equal(t, net.LinkCount(), solver.LinkCount(), "wrong number of links")
 }
 
+func TestNetwork_FastNetworkSolver_nodeGenes(t *testing.T) {
+	net := buildNetworkWithNodeGenes()
+
+	solver, err := net.FastNetworkSolver()
+	require.NoError(t, err, "failed to create fast network solver")
+	// the bias link of the hidden node with product aggregation is kept as connection
+	assert.Equal(t, net.LinkCount(), solver.LinkCount(), "wrong number of links")
+
+	expectedOuts := []float64{35.5, 19.0}
+	err = solver.LoadSensors([]float64{1.0, 2.0})
+	require.NoError(t, err, "failed to load sensors")
+	res, err := solver.ForwardSteps(2)
+	require.NoError(t, err)
+	assert.True(t, res)
+	assert.Equal(t, expectedOuts, solver.ReadOutputs())
+
+	// check that node genes survive model serialization
+	buf := bytes.NewBuffer(nil)
+	err = solver.(*FastModularNetworkSolver).WriteModel(buf)
+	require.NoError(t, err, "failed to write model")
+	model, err := ReadFMNSModel(buf)
+	require.NoError(t, err, "failed to read model")
+
+	err = model.LoadSensors([]float64{1.0, 2.0})
+	require.NoError(t, err, "failed to load sensors")
+	_, err = model.ForwardSteps(2)
+	require.NoError(t, err)
+	assert.Equal(t, expectedOuts, model.ReadOutputs())
+
+	// check recursive activation
+	_, err = model.Flush()
+	require.NoError(t, err)
+	err = model.LoadSensors([]float64{1.0, 2.0})
+	require.NoError(t, err, "failed to load sensors")
+	res, err = model.RecursiveSteps()
+	require.NoError(t, err)
+	assert.True(t, res)
+	assert.Equal(t, 35.5, model.ReadOutputs()[0])
+}
+
 func TestNetwork_ActivateSteps_zero_activation_steps(t *testing.T) {
 	net := buildNetwork()
 
@@ -19,6 +19,12 @@ type NNode struct {
 
 	// The type of node activation function (SIGMOID, ...)
 	ActivationType math.NodeActivationType
+	// The type of function to aggregate incoming signals of the node (SUM, PRODUCT, ...)
+	AggregationType math.NodeAggregationType
+	// The bias added to the aggregated incoming signals before activation
+	Bias float64
+	// The response multiplier applied to the aggregated incoming signals before adding bias
+	Response float64
 	// The neuron type for this node (HIDDEN, INPUT, OUTPUT, BIAS)
 	NeuronType NodeNeuronType
 
@@ -72,6 +78,9 @@ func NewNNodeCopy(n *NNode, t *neat.Trait) *NNode {
 	node.Id = n.Id
 	node.NeuronType = n.NeuronType
 	node.ActivationType = n.ActivationType
+	node.AggregationType = n.AggregationType
+	node.Bias = n.Bias
+	node.Response = n.Response
 	node.Trait = t
 	return node
 }
@@ -92,10 +101,12 @@ func NewSensorNode(nodeId int, bias bool) *NNode {
 // NewNetworkNode The default constructor
 func NewNetworkNode() *NNode {
 	return &NNode{
-		NeuronType:     HiddenNeuron,
-		ActivationType: math.SigmoidSteepenedActivation,
-		Incoming:       make([]*Link, 0),
-		Outgoing:       make([]*Link, 0),
+		NeuronType:      HiddenNeuron,
+		ActivationType:  math.SigmoidSteepenedActivation,
+		AggregationType: math.SumAggregation,
+		Response:        1.0,
+		Incoming:        make([]*Link, 0),
+		Outgoing:        make([]*Link, 0),
 	}
 }
 
@@ -315,6 +326,10 @@ func (n *NNode) PrintDebug() string {
 	_, _ = fmt.Fprintf(b, "\tActivation: %f\n", n.Activation)
 	activation, _ := math.NodeActivators.ActivationNameFromType(n.ActivationType)
 	_, _ = fmt.Fprintf(b, "\tActivation Type: %s\n", activation)
+	aggregation, _ := math.NodeAggregators.AggregationNameFromType(n.AggregationType)
+	_, _ = fmt.Fprintf(b, "\tAggregation Type: %s\n", aggregation)
+	_, _ = fmt.Fprintf(b, "\tBias: %f\n", n.Bias)
+	_, _ = fmt.Fprintf(b, "\tResponse: %f\n", n.Response)
 	_, _ = fmt.Fprintf(b, "\tNeuronType: %d\n", n.NeuronType)
 	_, _ = fmt.Fprintf(b, "\tActivationsCount: %d\n", n.ActivationsCount)
 	_, _ = fmt.Fprintf(b, "\tActivationSum: %f\n", n.ActivationSum)
@@ -0,0 +1,252 @@
+package network
+
+import (
+	"deepneat/neat"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// The indexes of the trait parameters defining the plasticity of links and neurons. The learning rate is taken as is,
+// it is never negative, because trait mutation keeps parameters non-negative, but it is not bounded above. The signed
+// coefficients of the ABCD rule are decoded from the trait parameters as 2 * p - 1 clamped to the range [-1, 1], thus
+// the trait parameters in the range [0, 1] map to the coefficients in the range [-1, 1], and the greater parameters
+// produced by trait mutation saturate at 1.
+const (
+	// PlasticLearningRateParam the learning rate (eta) of the plastic link, zero value means the link is not plastic
+	PlasticLearningRateParam = iota
+	// PlasticCoefficientAParam the coefficient of the correlation term of the ABCD rule
+	PlasticCoefficientAParam
+	// PlasticCoefficientBParam the coefficient of the presynaptic term of the ABCD rule
+	PlasticCoefficientBParam
+	// PlasticCoefficientCParam the coefficient of the postsynaptic term of the ABCD rule
+	PlasticCoefficientCParam
+	// PlasticCoefficientDParam the constant term of the ABCD rule
+	PlasticCoefficientDParam
+	// ModulatoryParam marks the hidden neuron as modulatory if greater than 0.5
+	ModulatoryParam
+)
+
+// ErrPlasticityRuleNotSet The error to be raised when plastic network solver requested without plasticity rule
+var ErrPlasticityRuleNotSet = errors.New("plasticity rule is not set")
+
+// plasticLink The link of the network which weight is adapted at runtime
+type plasticLink struct {
+	link *Link
+	// The learning rate and the coefficients of the ABCD rule
+	eta, a, b, c, d float64
+	// The weight of the link as defined by genome
+	initialWeight float64
+}
+
+// PlasticNetworkSolver is the network solver which adapts the weights of links during activation according to the
+// evolvable Hebbian rule (plain Hebb, Oja, ABCD) with coefficients taken from the trait of each link. After each
+// activation step the weight of each plastic link changes by the rule applied to the activations of its source
+// (pre) and target (post) neurons.
+//
+// The hidden neurons with trait parameter ModulatoryParam greater than 0.5 are modulatory. Their signals are not added
+// to the activation of target neurons, instead, the weighted sum m of the modulatory signals incoming into the neuron
+// gates the plasticity of its other incoming links by the factor tanh(m / 2), following the neuromodulation model of
+// Soltoggio et al. (2008). The links of neurons without modulatory inputs are not gated.
+//
+// The solver takes over the network: it changes the ConnectionWeight of its links and Flush restores the weights
+// defined by genome, i.e., the lifetime learning is kept until the network is flushed.
+type PlasticNetworkSolver struct {
+	// The network which links are adapted
+	network *Network
+	// The rule of weights adaptation
+	rule neat.PlasticityRule
+	// The bound of absolute value of the adapted weights, zero value means unbounded
+	weightBound float64
+
+	// The plastic links of the network
+	links []*plasticLink
+	// The modulatory neurons of the network, their signals are not added to the activation of target neurons
+	modulatory map[*NNode]bool
+	// The modulatory links incoming into each neuron
+	modulatoryIncoming map[*NNode][]*Link
+	// The buffer of incoming signals reused between activation steps
+	signals []float64
+}
+
+// NewPlasticNetworkSolver Creates new plastic network solver for the given network with specified plasticity rule and
+// the bound of adapted weights.
+func NewPlasticNetworkSolver(network *Network, rule neat.PlasticityRule, weightBound float64) (*PlasticNetworkSolver, error) {
+	if rule == "" {
+		return nil, ErrPlasticityRuleNotSet
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+	if weightBound < 0 {
+		return nil, fmt.Errorf("plastic weight bound must not be negative: %f", weightBound)
+	}
+	s := &PlasticNetworkSolver{
+		network:            network,
+		rule:               rule,
+		weightBound:        weightBound,
+		links:              make([]*plasticLink, 0),
+		modulatory:         make(map[*NNode]bool),
+		modulatoryIncoming: make(map[*NNode][]*Link),
+		signals:            make([]float64, 0),
+	}
+
+	for _, node := range network.allNodes {
+		if node.NeuronType == HiddenNeuron && traitParam(node.Params, node.Trait, ModulatoryParam) > 0.5 {
+			s.modulatory[node] = true
+		}
+	}
+	for _, node := range network.allNodes {
+		if !node.IsNeuron() {
+			continue
+		}
+		for _, link := range node.Incoming {
+			if s.modulatory[link.InNode] {
+				s.modulatoryIncoming[node] = append(s.modulatoryIncoming[node], link)
+				continue
+			}
+			eta := traitParam(link.Params, link.Trait, PlasticLearningRateParam)
+			if eta == 0 {
+				continue
+			}
+			s.links = append(s.links, &plasticLink{
+				link:          link,
+				eta:           eta,
+				a:             plasticCoefficient(link.Params, link.Trait, PlasticCoefficientAParam),
+				b:             plasticCoefficient(link.Params, link.Trait, PlasticCoefficientBParam),
+				c:             plasticCoefficient(link.Params, link.Trait, PlasticCoefficientCParam),
+				d:             plasticCoefficient(link.Params, link.Trait, PlasticCoefficientDParam),
+				initialWeight: link.ConnectionWeight,
+			})
+		}
+	}
+	return s, nil
+}
+
+// plasticCoefficient is to decode the signed coefficient of the ABCD rule from the trait parameter at given index
+func plasticCoefficient(params []float64, trait *neat.Trait, index int) float64 {
+	return math.Max(-1, math.Min(1, 2*traitParam(params, trait, index)-1))
+}
+
+// traitParam is to get the trait parameter at given index from the parameters derived from trait or from the trait
+// itself. Returns zero if parameter is not defined.
+func traitParam(params []float64, trait *neat.Trait, index int) float64 {
+	if len(params) == 0 && trait != nil {
+		params = trait.Params
+	}
+	if index < len(params) {
+		return params[index]
+	}
+	return 0
+}
+
+// adapt is to change the weights of plastic links according to the current activations of their neurons
+func (s *PlasticNetworkSolver) adapt() {
+	modulation := make(map[*NNode]float64, len(s.modulatoryIncoming))
+	for node, links := range s.modulatoryIncoming {
+		sum := 0.0
+		for _, link := range links {
+			sum += link.ConnectionWeight * link.InNode.GetActiveOut()
+		}
+		modulation[node] = math.Tanh(sum / 2.0)
+	}
+
+	for _, pl := range s.links {
+		link := pl.link
+		pre := link.InNode.GetActiveOut()
+		if link.IsTimeDelayed {
+			pre = link.InNode.GetActiveOutTd()
+		}
+		post := link.OutNode.GetActiveOut()
+
+		var delta float64
+		switch s.rule {
+		case neat.PlasticityRuleHebb:
+			delta = pl.eta * pre * post
+		case neat.PlasticityRuleOja:
+			delta = pl.eta * post * (pre - post*link.ConnectionWeight)
+		case neat.PlasticityRuleABCD:
+			delta = pl.eta * (pl.a*pre*post + pl.b*pre + pl.c*post + pl.d)
+		}
+		if gate, ok := modulation[link.OutNode]; ok {
+			delta *= gate
+		}
+
+		weight := link.ConnectionWeight + delta
+		if s.weightBound > 0 {
+			weight = math.Max(-s.weightBound, math.Min(s.weightBound, weight))
+		}
+		link.ConnectionWeight = weight
+	}
+}
+
+// ForwardSteps Propagates activation wave through all network nodes provided number of steps adapting the weights of
+// plastic links after each step. Returns true if all outputs of the network are active.
+func (s *PlasticNetworkSolver) ForwardSteps(steps int) (res bool, err error) {
+	if steps == 0 {
+		return false, ErrZeroActivationStepsRequested
+	}
+	for i := 0; i < steps; i++ {
+		if s.signals, err = s.network.activateStep(false, s.modulatory, s.signals); err != nil {
+			return false, err
+		}
+		s.adapt()
+	}
+	return !s.network.OutputIsOff(), nil
+}
+
+// RecursiveSteps Propagates activation wave through the network the number of steps equal to its activation depth
+func (s *PlasticNetworkSolver) RecursiveSteps() (bool, error) {
+	netDepth, err := s.network.MaxActivationDepthWithCap(0)
+	if err != nil {
+		return false, err
+	}
+	return s.ForwardSteps(netDepth)
+}
+
+// Relax Attempts to relax network given amount of steps until giving up with the same semantics as Network.Relax
+// adapting the weights of plastic links after each step.
+func (s *PlasticNetworkSolver) Relax(maxSteps int, maxAllowedSignalDelta float64) (bool, error) {
+	return s.network.relax(maxSteps, maxAllowedSignalDelta, s.modulatory, func() error {
+		s.adapt()
+		return nil
+	})
+}
+
+// ActivateSteps Attempts to activate the network given number of steps before returning error with the same semantics
+// as Network.ActivateSteps adapting the weights of plastic links after each step.
+func (s *PlasticNetworkSolver) ActivateSteps(maxSteps int) (bool, error) {
+	return s.network.activateSteps(maxSteps, s.modulatory, func() error {
+		s.adapt()
+		return nil
+	})
+}
+
+// Flush Flushes the activations of the network and restores the weights of plastic links defined by genome
+func (s *PlasticNetworkSolver) Flush() (bool, error) {
+	for _, pl := range s.links {
+		pl.link.ConnectionWeight = pl.initialWeight
+	}
+	return s.network.Flush()
+}
+
+func (s *PlasticNetworkSolver) LoadSensors(inputs []float64) error {
+	return s.network.LoadSensors(inputs)
+}
+
+func (s *PlasticNetworkSolver) ReadOutputs() []float64 {
+	return s.network.ReadOutputs()
+}
+
+func (s *PlasticNetworkSolver) NodeCount() int {
+	return s.network.NodeCount()
+}
+
+func (s *PlasticNetworkSolver) LinkCount() int {
+	return s.network.LinkCount()
+}
+
+// PlasticLinksCount Returns the number of links which weights are adapted at runtime
+func (s *PlasticNetworkSolver) PlasticLinksCount() int {
+	return len(s.links)
+}
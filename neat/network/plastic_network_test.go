@@ -0,0 +1,168 @@
+package network
+
+import (
+	"deepneat/neat"
+	neatmath "deepneat/neat/math"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildPlasticNetwork is to build the network with single plastic link from input to output and the bias link
+func buildPlasticNetwork(params []float64) (*Network, *Link) {
+	allNodes := []*NNode{
+		NewNNode(1, InputNeuron),
+		NewNNode(2, BiasNeuron),
+		NewNNode(3, OutputNeuron),
+	}
+	plastic := allNodes[2].ConnectFrom(allNodes[0], 0.5)
+	plastic.Trait = &neat.Trait{Id: 1, Params: params}
+	allNodes[2].ConnectFrom(allNodes[1], -0.2)
+
+	return NewNetwork(allNodes[0:2], allNodes[2:3], allNodes, 0), plastic
+}
+
+func TestNewPlasticNetworkSolver(t *testing.T) {
+	net, _ := buildPlasticNetwork([]float64{0.1})
+	solver, err := NewPlasticNetworkSolver(net, neat.PlasticityRuleHebb, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, solver.PlasticLinksCount())
+	assert.Equal(t, net.NodeCount(), solver.NodeCount())
+	assert.Equal(t, net.LinkCount(), solver.LinkCount())
+
+	_, err = NewPlasticNetworkSolver(net, "", 0)
+	assert.ErrorIs(t, err, ErrPlasticityRuleNotSet)
+	_, err = NewPlasticNetworkSolver(net, "backprop", 0)
+	assert.Error(t, err)
+	_, err = NewPlasticNetworkSolver(net, neat.PlasticityRuleOja, -1)
+	assert.Error(t, err)
+}
+
+func TestPlasticNetworkSolver_ForwardSteps(t *testing.T) {
+	params := []float64{0.1, 1.0, 0.25, 0.75, 0.5, 0, 0, 0}
+	a, b, c, d := 1.0, -0.5, 0.5, 0.0
+	testCases := map[neat.PlasticityRule]func(pre, post, w float64) float64{
+		neat.PlasticityRuleHebb: func(pre, post, _ float64) float64 {
+			return 0.1 * pre * post
+		},
+		neat.PlasticityRuleOja: func(pre, post, w float64) float64 {
+			return 0.1 * post * (pre - post*w)
+		},
+		neat.PlasticityRuleABCD: func(pre, post, _ float64) float64 {
+			return 0.1 * (a*pre*post + b*pre + c*post + d)
+		},
+	}
+	for rule, delta := range testCases {
+		net, plastic := buildPlasticNetwork(params)
+		solver, err := net.PlasticNetworkSolver(rule, 0)
+		require.NoError(t, err, rule)
+
+		pre := 0.8
+		require.NoError(t, solver.LoadSensors([]float64{pre}), rule)
+		weight := plastic.ConnectionWeight
+		for step := 0; step < 3; step++ {
+			res, err := solver.ForwardSteps(1)
+			require.NoError(t, err, rule)
+			assert.True(t, res, rule)
+			post := solver.ReadOutputs()[0]
+			weight += delta(pre, post, weight)
+			assert.InDelta(t, weight, plastic.ConnectionWeight, 1e-12, "%s: step %d", rule, step)
+		}
+
+		// flush restores the genetic weight
+		res, err := solver.Flush()
+		require.NoError(t, err, rule)
+		assert.True(t, res, rule)
+		assert.Equal(t, 0.5, plastic.ConnectionWeight, rule)
+	}
+}
+
+func TestPlasticNetworkSolver_weightBound(t *testing.T) {
+	net, plastic := buildPlasticNetwork([]float64{10.0})
+	solver, err := net.PlasticNetworkSolver(neat.PlasticityRuleHebb, 1.5)
+	require.NoError(t, err)
+	require.NoError(t, solver.LoadSensors([]float64{1.0}))
+	_, err = solver.ForwardSteps(5)
+	require.NoError(t, err)
+	assert.Equal(t, 1.5, plastic.ConnectionWeight)
+}
+
+func TestPlasticNetworkSolver_notPlastic(t *testing.T) {
+	// the network without learning rate is activated as by the network itself
+	net, plastic := buildPlasticNetwork(make([]float64, neat.NumTraitParams))
+	expectedNet, _ := buildPlasticNetwork(nil)
+	solver, err := NewPlasticNetworkSolver(net, neat.PlasticityRuleABCD, 0)
+	require.NoError(t, err)
+	assert.Zero(t, solver.PlasticLinksCount())
+
+	inputs := []float64{0.3}
+	require.NoError(t, solver.LoadSensors(inputs))
+	require.NoError(t, expectedNet.LoadSensors(inputs))
+	relaxed, err := solver.Relax(10, 0.0001)
+	require.NoError(t, err)
+	expectedRelaxed, err := expectedNet.Relax(10, 0.0001)
+	require.NoError(t, err)
+	assert.Equal(t, expectedRelaxed, relaxed)
+	assert.Equal(t, expectedNet.ReadOutputs(), solver.ReadOutputs())
+	assert.Equal(t, 0.5, plastic.ConnectionWeight)
+}
+
+func TestPlasticNetworkSolver_modulation(t *testing.T) {
+	net, plastic := buildPlasticNetwork([]float64{0.1})
+	input, output := net.inputs[0], net.Outputs[0]
+	modulator := NewNNode(4, HiddenNeuron)
+	modulator.Trait = &neat.Trait{Id: 2, Params: []float64{0, 0, 0, 0, 0, 1.0}}
+	modulator.ConnectFrom(input, 1.0)
+	modulatory := output.ConnectFrom(modulator, 2.0)
+	net.allNodes = append(net.allNodes, modulator)
+	net.allNodesMIMO = net.allNodes
+
+	solver, err := NewPlasticNetworkSolver(net, neat.PlasticityRuleHebb, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, solver.PlasticLinksCount(), "modulatory links are not plastic")
+
+	pre := 0.8
+	require.NoError(t, solver.LoadSensors([]float64{pre}))
+	weight := plastic.ConnectionWeight
+	for step := 0; step < 3; step++ {
+		_, err = solver.ForwardSteps(1)
+		require.NoError(t, err)
+		// the modulatory signal is not added to the activation of output
+		expected, err := neatmath.NodeActivators.ActivateByType(weight*pre-0.2, nil, output.ActivationType)
+		require.NoError(t, err)
+		post := output.GetActiveOut()
+		assert.InDelta(t, expected, post, 1e-12, "step %d", step)
+
+		gate := math.Tanh(modulatory.ConnectionWeight * modulator.GetActiveOut() / 2.0)
+		weight += gate * 0.1 * pre * post
+		assert.InDelta(t, weight, plastic.ConnectionWeight, 1e-12, "step %d", step)
+	}
+	assert.Equal(t, 2.0, modulatory.ConnectionWeight)
+
+	// the network activated by itself adds the modulatory signal to the activation of output
+	_, err = solver.Flush()
+	require.NoError(t, err)
+	require.NoError(t, net.LoadSensors([]float64{pre}))
+	_, err = net.ForwardSteps(2)
+	require.NoError(t, err)
+	expected, err := neatmath.NodeActivators.ActivateByType(
+		plastic.ConnectionWeight*pre-0.2+modulatory.ConnectionWeight*modulator.GetActiveOut(), nil, output.ActivationType)
+	require.NoError(t, err)
+	assert.InDelta(t, expected, output.GetActiveOut(), 1e-12)
+}
+
+func TestPlasticNetworkSolver_coefficientsClamped(t *testing.T) {
+	// the trait parameters out of [0, 1] range produced by trait mutation
+	net, _ := buildPlasticNetwork([]float64{0.1, 3.0, -1.0, 0.75, 1.2})
+	solver, err := NewPlasticNetworkSolver(net, neat.PlasticityRuleABCD, 0)
+	require.NoError(t, err)
+	require.Len(t, solver.links, 1)
+	pl := solver.links[0]
+	assert.Equal(t, 0.1, pl.eta)
+	assert.Equal(t, 1.0, pl.a)
+	assert.Equal(t, -1.0, pl.b)
+	assert.Equal(t, 0.5, pl.c)
+	assert.Equal(t, 1.0, pl.d)
+}
@@ -32,3 +32,14 @@ type Solver interface {
 	// LinkCount Returns the total number of links between nodes in the network
 	LinkCount() int
 }
+
+// BatchSolver defines network solver interface, which allows propagation of the activation waves for the whole batch of
+// inputs at once. The methods of Solver are applied to each input of the currently loaded batch.
+type BatchSolver interface {
+	Solver
+
+	// LoadSensorsBatch Set sensors values to the input nodes of the network for each input of the batch
+	LoadSensorsBatch(inputs [][]float64) error
+	// ReadOutputsBatch Read output values from the output nodes of the network for each input of the batch
+	ReadOutputsBatch() [][]float64
+}
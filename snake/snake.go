@@ -2,7 +2,6 @@ package snake
 
 import (
 	"math/rand"
-	"time"
 )
 
 // Action represents a turn (or no turn) command for the snake.
@@ -86,27 +85,30 @@ type Game struct {
 	Snake     *Snake
 	Food      Coordinates
 	GameState GameResult
+
+	// The random numbers generator used to place food
+	rng *rand.Rand
 }
 
-// NewGame initializes a new game with a snake and random food.
-func NewGame(width, height int) *Game {
+// NewGame initializes a new game with a snake and random food placed using provided random numbers generator.
+func NewGame(width, height int, rng *rand.Rand) *Game {
 	snake := NewSnake(height/2, width/2)
 	return &Game{
 		Width:     width,
 		Height:    height,
 		Snake:     snake,
-		Food:      GenerateFood(width, height, snake),
+		Food:      GenerateFood(width, height, snake, rng),
 		GameState: Running,
+		rng:       rng,
 	}
 }
 
 // GenerateFood selects a random board cell that is not occupied by the snake.
-func GenerateFood(width, height int, snake *Snake) Coordinates {
-	rand.Seed(time.Now().UnixNano())
+func GenerateFood(width, height int, snake *Snake, rng *rand.Rand) Coordinates {
 	for {
 		food := Coordinates{
-			Row: rand.Intn(height),
-			Col: rand.Intn(width),
+			Row: rng.Intn(height),
+			Col: rng.Intn(width),
 		}
 		if !snake.Contains(food) {
 			return food
@@ -172,6 +174,6 @@ func (g *Game) Update(action Action) {
 
 	// Generate new food if it was eaten.
 	if grow {
-		g.Food = GenerateFood(g.Width, g.Height, g.Snake)
+		g.Food = GenerateFood(g.Width, g.Height, g.Snake, g.rng)
 	}
 }